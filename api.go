@@ -0,0 +1,222 @@
+package httpsuite
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// NoBody marks typed routes that take no request body or return no data.
+type NoBody struct{}
+
+// TypedHandler handles a parsed request and returns the response data. Errors
+// carrying a *ProblemDetails are written as that problem; other errors are
+// logged and answered with a generic 500 problem.
+type TypedHandler[Req, Resp any] func(ctx context.Context, req Req) (Resp, error)
+
+// APIInfo describes an API and how its routes bind path parameters.
+type APIInfo struct {
+	Title       string
+	Version     string
+	Description string
+	// ParamExtractor reads path parameters for handlers registered with Handle.
+	ParamExtractor ParamExtractor
+	// Strict validates the responses of every route registered with Handle
+	// against their documented schema, as RouteOptions.Strict does per route.
+	Strict bool
+}
+
+// RouteOptions documents a typed route and configures its handler.
+type RouteOptions struct {
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	// Status is the success status. Defaults to 200, or 204 for NoBody responses.
+	Status int
+	// Errors lists problem statuses the route can reply with besides the 400
+	// and 500 every route may produce.
+	Errors []int
+	// Parse configures request parsing for handlers registered with Handle.
+	Parse *ParseOptions
+	// Strict validates responses of handlers registered with Handle against
+	// the schema documented for them before sending. Responses that drift
+	// get a 500 problem listing the mismatches, so tests and staging catch
+	// them early. The check is skipped in production mode.
+	Strict bool
+	// Mask encodes responses with fields tagged `mask` partially masked.
+	// See Mask.
+	Mask bool
+}
+
+// RouteInfo is a registered route. Request and Response are nil for NoBody.
+type RouteInfo struct {
+	Method     string
+	Pattern    string
+	PathParams []string
+	Request    reflect.Type
+	Response   reflect.Type
+	Options    RouteOptions
+}
+
+// API collects typed routes so they can be documented and tested together.
+// Mount the handlers returned by Handle on any router.
+type API struct {
+	mu         sync.RWMutex
+	info       APIInfo
+	routes     []RouteInfo
+	middleware MiddlewareChain
+}
+
+// NewAPI returns an empty route registry.
+func NewAPI(info APIInfo) *API {
+	return &API{info: info}
+}
+
+// Info returns the API description.
+func (a *API) Info() APIInfo {
+	return a.info
+}
+
+// Routes returns the registered routes in registration order.
+func (a *API) Routes() []RouteInfo {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return append([]RouteInfo(nil), a.routes...)
+}
+
+// Use adds middleware to the handlers returned by Handle for routes
+// registered afterwards, like a router's Use. The first middleware added is
+// the outermost.
+func (a *API) Use(middlewares ...func(http.Handler) http.Handler) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.middleware = a.middleware.Append(middlewares...)
+}
+
+// Describe registers a route for documentation without wrapping a handler,
+// for handlers written with ParseRequest and SendResponse directly.
+func Describe[Req, Resp any](api *API, method, pattern string, opts *RouteOptions) RouteInfo {
+	route := RouteInfo{
+		Method:     strings.ToUpper(method),
+		Pattern:    pattern,
+		PathParams: routePathParams(pattern),
+		Request:    typedRouteType[Req](),
+		Response:   typedRouteType[Resp](),
+	}
+	if opts != nil {
+		route.Options = *opts
+		route.Options.Tags = append([]string(nil), opts.Tags...)
+		route.Options.Errors = append([]int(nil), opts.Errors...)
+	}
+	if route.Options.Status == 0 {
+		route.Options.Status = http.StatusOK
+		if route.Response == nil {
+			route.Options.Status = http.StatusNoContent
+		}
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.routes = append(api.routes, route)
+	return route
+}
+
+// Handle registers a typed route and returns its http.Handler. The handler
+// parses Req with ParseRequest, binding the path parameters named in
+// pattern, calls handler, and writes the result in the response envelope.
+func Handle[Req, Resp any](api *API, method, pattern string, handler TypedHandler[Req, Resp], opts *RouteOptions) http.Handler {
+	route := Describe[Req, Resp](api, method, pattern, opts)
+	paramExtractor := api.info.ParamExtractor
+	strict := newStrictResponse(api, route)
+	api.mu.RLock()
+	middleware := api.middleware
+	api.mu.RUnlock()
+
+	return middleware.ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = SetRoutePattern(r, route.Pattern)
+		var request Req
+		if route.Request != nil {
+			recorder := newStatusRecorder(w)
+			parsed, err := ParseRequest[Req](recorder, r, paramExtractor, route.Options.Parse, route.PathParams...)
+			if err != nil {
+				if recorder.status == 0 {
+					log.Printf("Failed to parse request: %v", err)
+					var explicit *ProblemConfig
+					if route.Options.Parse != nil {
+						explicit = route.Options.Parse.Problems
+					}
+					problems := resolveProblemConfig(r.Context(), explicit)
+					sendRequestProblem(w, r, http.StatusInternalServerError, NewProblemDetails(http.StatusInternalServerError, problems.TypeURL("server_error"), "Internal Server Error", ""))
+				}
+				return
+			}
+			request = parsed
+		}
+
+		response, err := handler(r.Context(), request)
+		if err != nil {
+			writeHandlerError(w, r, err)
+			return
+		}
+		if route.Response == nil {
+			w.WriteHeader(route.Options.Status)
+			return
+		}
+		if err := strict.check(response); err != nil {
+			writeStrictViolation(w, r, route, err)
+			return
+		}
+		Respond(response).Status(route.Options.Status).mask(route.Options.Mask).Scoped(r.Context()).Write(w)
+	})
+}
+
+// writeHandlerError is ErrorResponse with the request available for the
+// problem instance.
+func writeHandlerError(w http.ResponseWriter, r *http.Request, err error) {
+	problem, ok := AsProblem(err)
+	if !ok {
+		log.Printf("Unhandled error: %v", err)
+		problems := resolveProblemConfig(r.Context(), nil)
+		problem = NewProblemDetails(http.StatusInternalServerError, problems.TypeURL("server_error"), "Internal Server Error", "")
+	}
+	status := problem.Status
+	if status < 400 || status > 599 {
+		status = http.StatusInternalServerError
+	}
+	sendRequestProblem(w, r, status, problem)
+}
+
+func typedRouteType[T any]() reflect.Type {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t == reflect.TypeOf(NoBody{}) || t == reflect.TypeOf(&NoBody{}) {
+		return nil
+	}
+	return t
+}
+
+// routePathParams returns the parameter names of a route pattern, accepting
+// ServeMux ("{id}", "{path...}"), chi ("{id:[0-9]+}"), and gorilla/mux syntax.
+func routePathParams(pattern string) []string {
+	var params []string
+	for {
+		start := strings.Index(pattern, "{")
+		if start < 0 {
+			return params
+		}
+		end := strings.Index(pattern[start:], "}")
+		if end < 0 {
+			return params
+		}
+		name := pattern[start+1 : start+end]
+		name, _, _ = strings.Cut(name, ":")
+		name = strings.TrimSuffix(name, "...")
+		if name != "" && name != "$" {
+			params = append(params, name)
+		}
+		pattern = pattern[start+end+1:]
+	}
+}
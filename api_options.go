@@ -0,0 +1,139 @@
+package httpsuite
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// OptionsConfig configures API.OptionsMiddleware.
+type OptionsConfig struct {
+	// Describe answers with an EndpointDescription of the path's operations
+	// instead of an empty 204.
+	Describe bool
+}
+
+// EndpointDescription is the body of an OPTIONS reply when
+// OptionsConfig.Describe is set.
+type EndpointDescription struct {
+	Path       string                 `json:"path"`
+	Allow      []string               `json:"allow"`
+	Operations []OperationDescription `json:"operations"`
+}
+
+// OperationDescription summarizes a route matching an OPTIONS request.
+type OperationDescription struct {
+	Method      string   `json:"method"`
+	Pattern     string   `json:"pattern"`
+	OperationID string   `json:"operation_id,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Description string   `json:"description,omitempty"`
+	PathParams  []string `json:"path_params,omitempty"`
+	Status      int      `json:"status"`
+	Errors      []int    `json:"errors,omitempty"`
+}
+
+// Allow returns the methods of every registered route matching path, with
+// HEAD implied by GET and OPTIONS always included, or nil when no route
+// matches. The result suits an Allow header.
+func (a *API) Allow(path string) []string {
+	routes := a.matchRoutes(path)
+	if len(routes) == 0 {
+		return nil
+	}
+	return allowedMethods(routes)
+}
+
+// OptionsMiddleware answers OPTIONS requests for paths matching registered
+// routes with an Allow header listing their methods. CORS preflight
+// requests, which carry Access-Control-Request-Method, and paths with an
+// explicit OPTIONS route are passed on.
+func (a *API) OptionsMiddleware(opts *OptionsConfig) func(http.Handler) http.Handler {
+	var config OptionsConfig
+	if opts != nil {
+		config = *opts
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			routes := a.matchRoutes(r.URL.Path)
+			if len(routes) == 0 || slices.ContainsFunc(routes, func(route RouteInfo) bool { return route.Method == http.MethodOptions }) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allow := allowedMethods(routes)
+			w.Header().Set("Allow", strings.Join(allow, ", "))
+			if !config.Describe {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			description := EndpointDescription{Path: r.URL.Path, Allow: allow}
+			for _, route := range routes {
+				description.Operations = append(description.Operations, OperationDescription{
+					Method:      route.Method,
+					Pattern:     route.Pattern,
+					OperationID: route.Options.OperationID,
+					Summary:     route.Options.Summary,
+					Description: route.Options.Description,
+					PathParams:  route.PathParams,
+					Status:      route.Options.Status,
+					Errors:      route.Options.Errors,
+				})
+			}
+			OK(w, description)
+		})
+	}
+}
+
+func (a *API) matchRoutes(path string) []RouteInfo {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var routes []RouteInfo
+	for _, route := range a.routes {
+		if routeMatches(route.Pattern, path) {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+func allowedMethods(routes []RouteInfo) []string {
+	allow := []string{http.MethodOptions}
+	for _, route := range routes {
+		allow = append(allow, route.Method)
+		if route.Method == http.MethodGet {
+			allow = append(allow, http.MethodHead)
+		}
+	}
+	slices.Sort(allow)
+	return slices.Compact(allow)
+}
+
+// routeMatches reports whether path matches a route pattern in ServeMux,
+// chi, or gorilla/mux syntax. Parameters match any non-empty segment; their
+// regular expressions are not evaluated.
+func routeMatches(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range patternSegments {
+		switch {
+		case segment == "*" || strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}"):
+			return true
+		case segment == "{$}":
+			return i == len(pathSegments)
+		case i >= len(pathSegments):
+			return false
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			if pathSegments[i] == "" {
+				return false
+			}
+		case segment != pathSegments[i]:
+			return false
+		}
+	}
+	return len(patternSegments) == len(pathSegments)
+}
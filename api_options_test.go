@@ -0,0 +1,108 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRouteMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "/items/{id}", path: "/items/7", want: true},
+		{pattern: "/items/{id}", path: "/items/", want: false},
+		{pattern: "/items/{id}", path: "/items/7/parts", want: false},
+		{pattern: "/items/{id:[0-9]+}", path: "/items/7", want: true},
+		{pattern: "/files/{path...}", path: "/files/a/b/c", want: true},
+		{pattern: "/files/*", path: "/files/a/b", want: true},
+		{pattern: "/items/{$}", path: "/items/", want: true},
+		{pattern: "/items/{$}", path: "/items/7", want: false},
+		{pattern: "/", path: "/", want: true},
+		{pattern: "/orders", path: "/items", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := routeMatches(tt.pattern, tt.path); got != tt.want {
+			t.Fatalf("routeMatches(%q, %q): expected %v, got %v", tt.pattern, tt.path, tt.want, got)
+		}
+	}
+}
+
+func TestAPIOptionsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Items"})
+	Describe[NoBody, apiItem](api, http.MethodGet, "/items/{id}", &RouteOptions{OperationID: "getItem", Summary: "Get an item"})
+	Describe[apiItem, apiItem](api, http.MethodPut, "/items/{id}", &RouteOptions{Errors: []int{http.StatusNotFound}})
+	Describe[NoBody, NoBody](api, http.MethodOptions, "/custom", nil)
+
+	if got, want := api.Allow("/items/7"), []string{"GET", "HEAD", "OPTIONS", "PUT"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected Allow %v, got %v", want, got)
+	}
+	if got := api.Allow("/orders"); got != nil {
+		t.Fatalf("expected no methods for an unknown path, got %v", got)
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	tests := []struct {
+		name       string
+		config     *OptionsConfig
+		method     string
+		path       string
+		header     map[string]string
+		wantStatus int
+		wantAllow  string
+	}{
+		{name: "options", method: http.MethodOptions, path: "/items/7", wantStatus: http.StatusNoContent, wantAllow: "GET, HEAD, OPTIONS, PUT"},
+		{name: "describe", config: &OptionsConfig{Describe: true}, method: http.MethodOptions, path: "/items/7", wantStatus: http.StatusOK, wantAllow: "GET, HEAD, OPTIONS, PUT"},
+		{name: "other method", method: http.MethodGet, path: "/items/7", wantStatus: http.StatusTeapot},
+		{name: "unknown path", method: http.MethodOptions, path: "/orders", wantStatus: http.StatusTeapot},
+		{name: "explicit options route", method: http.MethodOptions, path: "/custom", wantStatus: http.StatusTeapot},
+		{name: "cors preflight", method: http.MethodOptions, path: "/items/7", header: map[string]string{"Origin": "https://app.example", "Access-Control-Request-Method": "PUT"}, wantStatus: http.StatusTeapot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			api.OptionsMiddleware(tt.config)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Fatalf("expected Allow %q, got %q", tt.wantAllow, got)
+			}
+			if tt.config == nil || !tt.config.Describe {
+				return
+			}
+
+			var response Response[EndpointDescription]
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode description: %v", err)
+			}
+			operations := response.Data.Operations
+			if len(operations) != 2 || operations[0].OperationID != "getItem" || operations[1].Method != http.MethodPut {
+				t.Fatalf("unexpected operations: %+v", operations)
+			}
+			if operations[0].Status != http.StatusOK || !reflect.DeepEqual(operations[1].Errors, []int{http.StatusNotFound}) {
+				t.Fatalf("unexpected operation details: %+v", operations)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// strictResponse checks the data returned by a strict route against the
+// schema documented for it. The schema comes from the same generator as
+// OpenAPI and is compiled on first use.
+type strictResponse struct {
+	typ    reflect.Type
+	once   sync.Once
+	schema *JSONSchema
+}
+
+func newStrictResponse(api *API, route RouteInfo) *strictResponse {
+	if !api.info.Strict && !route.Options.Strict {
+		return nil
+	}
+	if route.Response == nil || route.Options.Status == http.StatusNoContent {
+		return nil
+	}
+	return &strictResponse{typ: route.Response}
+}
+
+// check returns the ValidationErrors of data, or nil when it matches the
+// schema. Strict checks are skipped in production mode.
+func (s *strictResponse) check(data any) error {
+	if s == nil || ProductionMode() {
+		return nil
+	}
+	s.once.Do(func() {
+		schema, err := compileResponseSchema(s.typ)
+		if err != nil {
+			log.Printf("Failed to compile response schema for %s: %v", s.typ, err)
+			return
+		}
+		s.schema = schema
+	})
+	if s.schema == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		// The response writer reports encoding failures.
+		return nil
+	}
+	return s.schema.Validate(encoded)
+}
+
+func compileResponseSchema(t reflect.Type) (*JSONSchema, error) {
+	generator := newSchemaGenerator()
+	data := generator.schema(t)
+	document, err := json.Marshal(map[string]any{
+		"data":       data,
+		"components": map[string]any{"schemas": generator.components},
+	})
+	if err != nil {
+		return nil, err
+	}
+	schema, err := CompileJSONSchema(document)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Resolve("#/data")
+}
+
+// writeStrictViolation answers a response that drifted from its schema with
+// a 500 problem listing the mismatches.
+func writeStrictViolation(w http.ResponseWriter, r *http.Request, route RouteInfo, err error) {
+	log.Printf("Response of %s %s does not match its schema: %v", route.Method, route.Pattern, err)
+	problem := NewProblemDetails(
+		http.StatusInternalServerError,
+		sharedProblemConfig(r.Context()).TypeURL("server_error"),
+		"Response Schema Violation",
+		"The response does not match its documented schema.",
+	)
+	var details ValidationErrors
+	if errors.As(err, &details) {
+		problem.Extensions = map[string]interface{}{"errors": []ValidationErrorDetail(details)}
+	}
+	sendRequestProblem(w, r, http.StatusInternalServerError, problem)
+}
@@ -0,0 +1,81 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type strictItem struct {
+	ID    int64    `json:"id"`
+	Tags  []string `json:"tags"`
+	Owner *string  `json:"owner"`
+}
+
+func strictItemHandler(item strictItem) http.Handler {
+	api := NewAPI(APIInfo{Title: "Items", Version: "1.0.0", Strict: true})
+	return Handle(api, http.MethodGet, "/items", func(ctx context.Context, req NoBody) (strictItem, error) {
+		return item, nil
+	}, nil)
+}
+
+func TestStrictResponses(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	strictItemHandler(strictItem{ID: 1, Tags: []string{"a"}}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected conforming response to pass, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	strictItemHandler(strictItem{ID: 1}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 for a null array, got %d", w.Code)
+	}
+	var problem struct {
+		Title  string                  `json:"title"`
+		Errors []ValidationErrorDetail `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Title != "Response Schema Violation" || len(problem.Errors) != 1 || problem.Errors[0].Pointer != "/tags" {
+		t.Fatalf("expected violation at /tags, got %+v", problem)
+	}
+}
+
+func TestStrictResponsesSkippedInProductionMode(t *testing.T) {
+	SetProductionMode(true)
+	t.Cleanup(func() { SetProductionMode(false) })
+
+	w := httptest.NewRecorder()
+	strictItemHandler(strictItem{ID: 1}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected strict checks to be skipped in production, got %d", w.Code)
+	}
+}
+
+func TestStrictResponsesPerRoute(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Items", Version: "1.0.0"})
+	handler := func(ctx context.Context, req NoBody) (strictItem, error) {
+		return strictItem{ID: 1}, nil
+	}
+	lenient := Handle(api, http.MethodGet, "/lenient", handler, nil)
+	strict := Handle(api, http.MethodGet, "/strict", handler, &RouteOptions{Strict: true})
+
+	for _, tt := range []struct {
+		handler http.Handler
+		want    int
+	}{{lenient, http.StatusOK}, {strict, http.StatusInternalServerError}} {
+		w := httptest.NewRecorder()
+		tt.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != tt.want {
+			t.Fatalf("expected %d, got %d", tt.want, w.Code)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type apiItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestHandle(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Items", Version: "1.0.0", ParamExtractor: testParamExtractor})
+	update := Handle(api, http.MethodPut, "/items/{id}", func(ctx context.Context, req *testRequest) (apiItem, error) {
+		switch req.Name {
+		case "missing":
+			return apiItem{}, NewProblemDetails(http.StatusNotFound, GetProblemTypeURL("not_found_error"), "Not Found", "item not found")
+		case "boom":
+			return apiItem{}, errors.New("database down")
+		}
+		return apiItem{ID: req.ID, Name: req.Name}, nil
+	}, nil)
+	remove := Handle(api, http.MethodDelete, "/items/{id}", func(ctx context.Context, req *testRequest) (NoBody, error) {
+		return NoBody{}, nil
+	}, nil)
+
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "success", handler: update, method: http.MethodPut, path: "/items/7", body: `{"name":"lamp"}`, wantStatus: http.StatusOK, wantBody: `"data":{"id":7,"name":"lamp"}`},
+		{name: "invalid param", handler: update, method: http.MethodPut, path: "/items/x", body: `{"name":"lamp"}`, wantStatus: http.StatusBadRequest},
+		{name: "problem error", handler: update, method: http.MethodPut, path: "/items/7", body: `{"name":"missing"}`, wantStatus: http.StatusNotFound, wantBody: `"instance":"/items/7`},
+		{name: "plain error", handler: update, method: http.MethodPut, path: "/items/7", body: `{"name":"boom"}`, wantStatus: http.StatusInternalServerError, wantBody: `"type":"https://api.example.com/errors/server-error"`},
+		{name: "no body", handler: remove, method: http.MethodDelete, path: "/items/7", wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.path, body)
+			w := httptest.NewRecorder()
+			UseProblemConfig(ProblemConfig{BaseURL: "https://api.example.com", Instance: DefaultInstance})(tt.handler).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %s, got %s", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+
+	routes := api.Routes()
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(routes))
+	}
+	if routes[0].Options.Status != http.StatusOK || routes[1].Options.Status != http.StatusNoContent {
+		t.Fatalf("unexpected default statuses %d and %d", routes[0].Options.Status, routes[1].Options.Status)
+	}
+	if routes[1].Response != nil {
+		t.Fatalf("expected NoBody response type to be nil, got %v", routes[1].Response)
+	}
+}
+
+func TestHandleWithoutRequest(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Health"})
+	handler := Handle(api, http.MethodGet, "/health", func(ctx context.Context, _ NoBody) (map[string]string, error) {
+		return map[string]string{"status": "ok"}, nil
+	}, &RouteOptions{Status: http.StatusAccepted})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+	var body Response[map[string]string]
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Data["status"] != "ok" {
+		t.Fatalf("unexpected response %#v", body)
+	}
+}
+
+func TestRoutePathParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "/items", want: nil},
+		{pattern: "/items/{id}", want: []string{"id"}},
+		{pattern: "/orgs/{org}/items/{id:[0-9]+}", want: []string{"org", "id"}},
+		{pattern: "/files/{path...}", want: []string{"path"}},
+		{pattern: "/{$}", want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := routePathParams(tt.pattern); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.pattern, tt.want, got)
+		}
+	}
+}
+
+func TestHandleMaskedResponses(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Contacts", Version: "1.0.0"})
+	handler := Handle(api, http.MethodGet, "/contacts/me", func(ctx context.Context, req NoBody) (maskedContact, error) {
+		return maskedContact{Name: "Jo", Email: "jo@example.com"}, nil
+	}, &RouteOptions{Mask: true})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/contacts/me", nil))
+	if want := `{"data":{"email":"j***@example.com","name":"Jo"}}` + "\n"; w.Body.String() != want {
+		t.Fatalf("expected %s, got %s", want, w.Body.String())
+	}
+}
+
+func TestHandleScopedResponses(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Contacts", Version: "1.0.0"})
+	handler := Handle(api, http.MethodGet, "/contacts/me", func(ctx context.Context, req NoBody) (scopedContact, error) {
+		return scopedContact{Name: "Jo", Email: "jo@example.com"}, nil
+	}, nil)
+
+	for _, tt := range []struct {
+		scopes []string
+		want   string
+	}{
+		{want: `{"data":{"name":"Jo"}}`},
+		{scopes: []string{"admin"}, want: `{"data":{"email":"jo@example.com","name":"Jo"}}`},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/contacts/me", nil)
+		req = req.WithContext(WithScopes(req.Context(), tt.scopes...))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if want := tt.want + "\n"; w.Body.String() != want {
+			t.Fatalf("scopes %v: expected %s, got %s", tt.scopes, want, w.Body.String())
+		}
+	}
+}
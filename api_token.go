@@ -0,0 +1,337 @@
+package httpsuite
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAPITokenTTL = 5 * time.Minute
+	minAPITokenKeySize = 32
+)
+
+var (
+	// ErrInvalidAPIToken reports a token that is malformed or was not signed
+	// with any configured key.
+	ErrInvalidAPIToken = errors.New("invalid API token")
+	// ErrAPITokenExpired reports a token past its expiry.
+	ErrAPITokenExpired = errors.New("API token has expired")
+	// ErrAPITokenRevoked reports a token whose ID or session was revoked.
+	ErrAPITokenRevoked = errors.New("API token has been revoked")
+)
+
+// RevocationList records revoked token and session IDs. Entries only need to
+// be kept until the given time, after which the tokens have expired anyway.
+type RevocationList interface {
+	Revoke(ctx context.Context, id string, until time.Time) error
+	Revoked(ctx context.Context, id string) (bool, error)
+}
+
+// MemoryRevocationList is an in-memory RevocationList for single-instance
+// services.
+type MemoryRevocationList struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	now     func() time.Time
+}
+
+// NewMemoryRevocationList returns an empty MemoryRevocationList.
+func NewMemoryRevocationList() *MemoryRevocationList {
+	return &MemoryRevocationList{entries: make(map[string]time.Time), now: time.Now}
+}
+
+// Revoke records id until the given time. Expired entries are dropped.
+func (l *MemoryRevocationList) Revoke(_ context.Context, id string, until time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	for other, expires := range l.entries {
+		if !expires.After(now) {
+			delete(l.entries, other)
+		}
+	}
+	if until.After(l.entries[id]) {
+		l.entries[id] = until
+	}
+	return nil
+}
+
+// Revoked reports whether id is revoked.
+func (l *MemoryRevocationList) Revoked(_ context.Context, id string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until, ok := l.entries[id]
+	return ok && until.After(l.now()), nil
+}
+
+// APITokenOptions configures APITokens.
+type APITokenOptions struct {
+	// Keys are HMAC-SHA256 keys of at least 32 bytes. The first signs new
+	// tokens; all of them verify, so keys can be rotated by putting a new
+	// one first and dropping the old one after TTL.
+	Keys [][]byte
+	// TTL defaults to 5 minutes.
+	TTL time.Duration
+	// Revocations, when set, is consulted for every token ID and session ID.
+	Revocations RevocationList
+	Now         func() time.Time
+	// Problems overrides the problem config resolved from the request context.
+	Problems *ProblemConfig
+}
+
+// APIToken holds the claims of a verified token.
+type APIToken struct {
+	ID      string `json:"jti"`
+	Subject string `json:"sub"`
+	// Session is the session the token is bound to, or empty for tokens bound
+	// only to Subject. Revoking the session revokes every token issued for it.
+	Session   string `json:"sid,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// APITokenGrant is the body returned by the token exchange endpoint.
+type APITokenGrant struct {
+	Token     string `json:"access_token"`
+	TokenType string `json:"token_type"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
+// APITokens issues and verifies short-lived signed bearer tokens for
+// browser-to-API calls. A page authenticated by a session cookie exchanges it
+// for a token, then calls the API with "Authorization: Bearer <token>".
+// Browsers never attach that header on their own, so token-authenticated
+// routes need no CSRF protection.
+type APITokens struct {
+	options APITokenOptions
+}
+
+// NewAPITokens validates the keys and returns a token issuer. At least one
+// key is required.
+func NewAPITokens(opts *APITokenOptions) (*APITokens, error) {
+	options := APITokenOptions{TTL: defaultAPITokenTTL, Now: time.Now}
+	if opts != nil {
+		options.Keys = opts.Keys
+		options.Revocations = opts.Revocations
+		options.Problems = opts.Problems
+		if opts.TTL > 0 {
+			options.TTL = opts.TTL
+		}
+		if opts.Now != nil {
+			options.Now = opts.Now
+		}
+	}
+	if len(options.Keys) == 0 {
+		return nil, errors.New("at least one API token key is required")
+	}
+	for i, key := range options.Keys {
+		if len(key) < minAPITokenKeySize {
+			return nil, fmt.Errorf("API token key %d: must be at least %d bytes", i, minAPITokenKeySize)
+		}
+	}
+	return &APITokens{options: options}, nil
+}
+
+// Issue returns a signed token for subject, bound to session when it is not
+// empty, together with its claims.
+func (t *APITokens) Issue(subject, session string) (string, APIToken, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", APIToken{}, err
+	}
+	now := t.options.Now()
+	claims := APIToken{
+		ID:        hex.EncodeToString(id),
+		Subject:   subject,
+		Session:   session,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(t.options.TTL).Unix(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", APIToken{}, err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signAPIToken(t.options.Keys[0], encoded)), claims, nil
+}
+
+// Verify checks the signature, expiry, and revocation status of token and
+// returns its claims.
+func (t *APITokens) Verify(ctx context.Context, token string) (APIToken, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return APIToken{}, ErrInvalidAPIToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return APIToken{}, ErrInvalidAPIToken
+	}
+	valid := false
+	for _, key := range t.options.Keys {
+		if hmac.Equal(mac, signAPIToken(key, encoded)) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return APIToken{}, ErrInvalidAPIToken
+	}
+
+	var claims APIToken
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(payload, &claims) != nil || claims.ID == "" {
+		return APIToken{}, ErrInvalidAPIToken
+	}
+	if !t.options.Now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return APIToken{}, ErrAPITokenExpired
+	}
+	if t.options.Revocations != nil {
+		for _, id := range []string{claims.ID, claims.Session} {
+			if id == "" {
+				continue
+			}
+			revoked, err := t.options.Revocations.Revoked(ctx, id)
+			if err != nil {
+				return APIToken{}, fmt.Errorf("check API token revocation: %w", err)
+			}
+			if revoked {
+				return APIToken{}, ErrAPITokenRevoked
+			}
+		}
+	}
+	return claims, nil
+}
+
+// Revoke revokes a single token until it would have expired.
+func (t *APITokens) Revoke(ctx context.Context, token APIToken) error {
+	if t.options.Revocations == nil {
+		return errors.New("no revocation list is configured")
+	}
+	return t.options.Revocations.Revoke(ctx, token.ID, time.Unix(token.ExpiresAt, 0))
+}
+
+// RevokeSession revokes every token bound to session, e.g. on logout.
+func (t *APITokens) RevokeSession(ctx context.Context, session string) error {
+	if t.options.Revocations == nil {
+		return errors.New("no revocation list is configured")
+	}
+	return t.options.Revocations.Revoke(ctx, session, t.options.Now().Add(t.options.TTL))
+}
+
+// ExchangeHandler returns the token exchange endpoint. resolve identifies the
+// caller from its session, and its error, or a returned *ProblemDetails,
+// rejects the exchange with 401. Because the caller is authenticated by a
+// cookie, mount the endpoint behind the application's CSRF protection and
+// accept only POST:
+//
+//	mux.Handle("POST /auth/token", tokens.ExchangeHandler(func(r *http.Request) (string, string, error) {
+//		session, _ := httpsuite.SessionFromContext(r.Context())
+//		user, ok := session.Get("user")
+//		if !ok {
+//			return "", "", errors.New("not signed in")
+//		}
+//		sid, _ := session.Get("sid")
+//		return user, sid, nil
+//	}))
+func (t *APITokens) ExchangeHandler(resolve func(r *http.Request) (subject, session string, err error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, session, err := resolve(r)
+		if err == nil && subject == "" {
+			err = errors.New("no signed-in user")
+		}
+		if err != nil {
+			if problem, ok := AsProblem(err); ok {
+				sendRequestProblem(w, r, problem.Status, problem)
+				return
+			}
+			t.sendUnauthorized(w, r, "", sanitizedDetail(err, "a signed-in session is required"))
+			return
+		}
+
+		token, claims, err := t.Issue(subject, session)
+		if err != nil {
+			sendAPITokenServerError(w, r, err, "API token could not be issued")
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		OK(w, APITokenGrant{Token: token, TokenType: "Bearer", ExpiresIn: claims.ExpiresAt - claims.IssuedAt})
+	})
+}
+
+// Middleware returns middleware that requires a valid bearer token. The
+// claims are stored in the context, where APITokenFromContext finds them, and
+// the subject under PrincipalKey. Missing and rejected tokens reply 401 with
+// a Bearer WWW-Authenticate challenge.
+func (t *APITokens) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, ok := bearerToken(r)
+			if !ok {
+				t.sendUnauthorized(w, r, "", "a bearer token is required")
+				return
+			}
+			claims, err := t.Verify(r.Context(), raw)
+			switch {
+			case errors.Is(err, ErrInvalidAPIToken), errors.Is(err, ErrAPITokenExpired), errors.Is(err, ErrAPITokenRevoked):
+				t.sendUnauthorized(w, r, "invalid_token", err.Error())
+				return
+			case err != nil:
+				sendAPITokenServerError(w, r, err, "API token could not be verified")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), apiTokenContextKey{}, claims)
+			next.ServeHTTP(w, r.WithContext(CtxSet(ctx, PrincipalKey, claims.Subject)))
+		})
+	}
+}
+
+type apiTokenContextKey struct{}
+
+// APITokenFromContext returns the claims verified by APITokens.Middleware.
+func APITokenFromContext(ctx context.Context) (APIToken, bool) {
+	token, ok := ctx.Value(apiTokenContextKey{}).(APIToken)
+	return token, ok
+}
+
+func (t *APITokens) sendUnauthorized(w http.ResponseWriter, r *http.Request, code, detail string) {
+	challenge := "Bearer"
+	if code != "" {
+		challenge += ` error="` + code + `"`
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	problems := resolveProblemConfig(r.Context(), t.options.Problems)
+	status := http.StatusUnauthorized
+	sendRequestProblem(w, r, status, NewProblemDetails(status, problems.TypeURL("unauthorized_error"), "Unauthorized", detail))
+}
+
+func sendAPITokenServerError(w http.ResponseWriter, r *http.Request, err error, generic string) {
+	status := http.StatusInternalServerError
+	sendRequestProblem(w, r, status, NewProblemDetails(status, sharedProblemConfig(r.Context()).TypeURL("server_error"), "Internal Server Error", sanitizedDetail(err, generic)))
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func signAPIToken(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,176 @@
+package httpsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAPITokens(t *testing.T, opts *APITokenOptions) *APITokens {
+	t.Helper()
+	if opts.Keys == nil {
+		opts.Keys = [][]byte{bytes.Repeat([]byte("k"), 32)}
+	}
+	tokens, err := NewAPITokens(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return tokens
+}
+
+func TestNewAPITokensRejectsWeakKeys(t *testing.T) {
+	t.Parallel()
+
+	for _, opts := range []*APITokenOptions{nil, {Keys: [][]byte{[]byte("short")}}} {
+		if _, err := NewAPITokens(opts); err == nil {
+			t.Fatalf("expected an error for %+v", opts)
+		}
+	}
+}
+
+func TestAPITokensVerify(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	revocations := NewMemoryRevocationList()
+	oldKey, newKey := bytes.Repeat([]byte("o"), 32), bytes.Repeat([]byte("n"), 32)
+	previous := testAPITokens(t, &APITokenOptions{Keys: [][]byte{oldKey}, Now: func() time.Time { return now }})
+	tokens := testAPITokens(t, &APITokenOptions{
+		Keys:        [][]byte{newKey, oldKey},
+		TTL:         time.Minute,
+		Revocations: revocations,
+		Now:         func() time.Time { return now },
+	})
+
+	token, claims, err := tokens.Issue("ann", "sid-1")
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if claims.ExpiresAt-claims.IssuedAt != 60 || claims.Session != "sid-1" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+	verified, err := tokens.Verify(context.Background(), token)
+	if err != nil || verified != claims {
+		t.Fatalf("expected %+v, got %+v (%v)", claims, verified, err)
+	}
+
+	rotated, _, _ := previous.Issue("ann", "")
+	if _, err := tokens.Verify(context.Background(), rotated); err != nil {
+		t.Fatalf("expected tokens signed with a rotated key to verify, got %v", err)
+	}
+
+	encoded, signature, _ := strings.Cut(token, ".")
+	forged := "x" + encoded[1:] + "." + signature
+	for _, bad := range []string{"", "no-dot", encoded + ".%%", forged} {
+		if _, err := tokens.Verify(context.Background(), bad); !errors.Is(err, ErrInvalidAPIToken) {
+			t.Fatalf("expected %q to be invalid, got %v", bad, err)
+		}
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := tokens.Verify(context.Background(), token); !errors.Is(err, ErrAPITokenExpired) {
+		t.Fatalf("expected expired token, got %v", err)
+	}
+}
+
+func TestAPITokensRevocation(t *testing.T) {
+	t.Parallel()
+
+	tokens := testAPITokens(t, &APITokenOptions{Revocations: NewMemoryRevocationList()})
+	first, firstClaims, _ := tokens.Issue("ann", "sid-1")
+	second, _, _ := tokens.Issue("ann", "sid-1")
+	other, _, _ := tokens.Issue("bob", "sid-2")
+
+	if err := tokens.Revoke(context.Background(), firstClaims); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := tokens.Verify(context.Background(), first); !errors.Is(err, ErrAPITokenRevoked) {
+		t.Fatalf("expected revoked token, got %v", err)
+	}
+	if _, err := tokens.Verify(context.Background(), second); err != nil {
+		t.Fatalf("expected other tokens of the session to stay valid, got %v", err)
+	}
+
+	if err := tokens.RevokeSession(context.Background(), "sid-1"); err != nil {
+		t.Fatalf("revoke session: %v", err)
+	}
+	if _, err := tokens.Verify(context.Background(), second); !errors.Is(err, ErrAPITokenRevoked) {
+		t.Fatalf("expected session tokens to be revoked, got %v", err)
+	}
+	if _, err := tokens.Verify(context.Background(), other); err != nil {
+		t.Fatalf("expected other sessions to stay valid, got %v", err)
+	}
+
+	if err := testAPITokens(t, &APITokenOptions{}).RevokeSession(context.Background(), "sid-1"); err == nil {
+		t.Fatal("expected an error without a revocation list")
+	}
+}
+
+func TestAPITokensExchangeAndMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tokens := testAPITokens(t, &APITokenOptions{})
+	exchange := tokens.ExchangeHandler(func(r *http.Request) (string, string, error) {
+		user := r.Header.Get("X-Test-User")
+		if user == "" {
+			return "", "", errors.New("not signed in")
+		}
+		return user, "sid-" + user, nil
+	})
+
+	w := httptest.NewRecorder()
+	exchange.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/token", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a session, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/token", nil)
+	req.Header.Set("X-Test-User", "ann")
+	w = httptest.NewRecorder()
+	exchange.ServeHTTP(w, req)
+	var grant Response[APITokenGrant]
+	if err := json.NewDecoder(w.Body).Decode(&grant); err != nil {
+		t.Fatalf("decode grant: %v", err)
+	}
+	if w.Code != http.StatusOK || grant.Data.TokenType != "Bearer" || grant.Data.ExpiresIn != 300 || w.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("unexpected grant %d %+v", w.Code, grant.Data)
+	}
+
+	api := tokens.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := APITokenFromContext(r.Context())
+		principal, _ := CtxGet(r.Context(), PrincipalKey)
+		OK(w, principal+"/"+token.Session)
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantChallenge string
+	}{
+		{name: "valid", authorization: "Bearer " + grant.Data.Token, wantStatus: http.StatusOK},
+		{name: "missing", wantStatus: http.StatusUnauthorized, wantChallenge: "Bearer"},
+		{name: "basic", authorization: "Basic YW5uOnB3", wantStatus: http.StatusUnauthorized, wantChallenge: "Bearer"},
+		{name: "invalid", authorization: "Bearer nope", wantStatus: http.StatusUnauthorized, wantChallenge: `Bearer error="invalid_token"`},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		w := httptest.NewRecorder()
+		api.ServeHTTP(w, req)
+		if w.Code != tt.wantStatus || w.Header().Get("WWW-Authenticate") != tt.wantChallenge {
+			t.Fatalf("%s: expected %d %q, got %d %q", tt.name, tt.wantStatus, tt.wantChallenge, w.Code, w.Header().Get("WWW-Authenticate"))
+		}
+		if tt.wantStatus == http.StatusOK && !strings.Contains(w.Body.String(), `"ann/sid-ann"`) {
+			t.Fatalf("expected claims in context, got %s", w.Body.String())
+		}
+	}
+}
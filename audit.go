@@ -0,0 +1,141 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AuditEntry describes a handled request for compliance audit trails.
+type AuditEntry struct {
+	Time      time.Time
+	Method    string
+	Route     string
+	Path      string
+	Principal string
+	Tenant    string
+	// ClientIP is the caller address, resolved from forwarding headers when
+	// ForwardedHeaders runs before Audit.
+	ClientIP string
+	// Request is the parsed request with `audit:"-"` and `log:"-"` fields
+	// removed and `redact:"true"` fields masked.
+	Request  any
+	Status   int
+	Duration time.Duration
+}
+
+// Auditor receives an entry for every request handled by the Audit middleware.
+type Auditor interface {
+	Audit(ctx context.Context, entry AuditEntry)
+}
+
+// AuditorFunc adapts a function to the Auditor interface.
+type AuditorFunc func(ctx context.Context, entry AuditEntry)
+
+// Audit calls f(ctx, entry).
+func (f AuditorFunc) Audit(ctx context.Context, entry AuditEntry) {
+	f(ctx, entry)
+}
+
+// AuditOptions configures the Audit middleware.
+type AuditOptions struct {
+	// Principal resolves the caller identity recorded in each entry. When nil,
+	// the PrincipalKey context value is used.
+	Principal func(*http.Request) string
+	Now       func() time.Time
+}
+
+type auditContextKey struct{}
+
+type auditRecord struct {
+	mu      sync.Mutex
+	request any
+	tenant  string
+}
+
+// Audit returns middleware that reports every handled request to auditor.
+// Requests parsed with ParseRequest inside the wrapped handler are attached to
+// the entry automatically.
+func Audit(auditor Auditor, opts *AuditOptions) func(http.Handler) http.Handler {
+	options := AuditOptions{Now: time.Now}
+	if opts != nil {
+		options.Principal = opts.Principal
+		if opts.Now != nil {
+			options.Now = opts.Now
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if auditor == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			started := options.Now()
+			record := &auditRecord{}
+			recorder := newStatusRecorder(w)
+			r = withRoutePatternRecord(r.WithContext(context.WithValue(r.Context(), auditContextKey{}, record)))
+			next.ServeHTTP(recorder, r)
+
+			entry := AuditEntry{
+				Time:     started,
+				Method:   r.Method,
+				Route:    auditRoute(r),
+				Path:     r.URL.Path,
+				Tenant:   TenantID(r.Context()),
+				ClientIP: ClientIP(r),
+				Status:   recorder.Status(),
+				Duration: options.Now().Sub(started),
+			}
+			if options.Principal != nil {
+				entry.Principal = options.Principal(r)
+			} else {
+				entry.Principal, _ = CtxGet(r.Context(), PrincipalKey)
+			}
+			record.mu.Lock()
+			entry.Request = auditValue(record.request)
+			if record.tenant != "" {
+				entry.Tenant = record.tenant
+			}
+			record.mu.Unlock()
+			auditor.Audit(r.Context(), entry)
+		})
+	}
+}
+
+// auditRoute prefers the ServeMux pattern and falls back to the pattern
+// recorded by Handle, ParseRequest, or router integrations.
+func auditRoute(r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+	return RoutePattern(r.Context())
+}
+
+func recordAuditRequest(ctx context.Context, request any) {
+	record, ok := ctx.Value(auditContextKey{}).(*auditRecord)
+	if !ok {
+		return
+	}
+	record.mu.Lock()
+	defer record.mu.Unlock()
+	record.request = request
+}
+
+func recordAuditTenant(ctx context.Context, tenant string) {
+	record, ok := ctx.Value(auditContextKey{}).(*auditRecord)
+	if !ok {
+		return
+	}
+	record.mu.Lock()
+	defer record.mu.Unlock()
+	record.tenant = tenant
+}
+
+// auditValue converts a parsed request into JSON-shaped data without fields
+// tagged `audit:"-"` or `log:"-"` and with `redact:"true"` fields masked.
+func auditValue(value any) any {
+	return redactValue(value, redactMode{omitTags: []string{"audit", "log"}, redact: true, mask: true})
+}
@@ -0,0 +1,75 @@
+package httpsuite
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type auditedRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password" audit:"-"`
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	var entry AuditEntry
+	auditor := AuditorFunc(func(_ context.Context, e AuditEntry) {
+		entry = e
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sessions", func(w http.ResponseWriter, r *http.Request) {
+		req, err := ParseRequest[*auditedRequest](w, r, nil, nil)
+		if err != nil {
+			return
+		}
+		Created(w, req.Email, "")
+	})
+
+	handler := Audit(auditor, &AuditOptions{
+		Principal: func(r *http.Request) string { return r.Header.Get("X-User") },
+	})(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/sessions", bytes.NewBufferString(`{"email":"ada@example.com","password":"secret"}`))
+	req.Header.Set("X-User", "ada")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if entry.Status != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, entry.Status)
+	}
+	if entry.Route != "POST /sessions" || entry.Method != http.MethodPost || entry.Principal != "ada" {
+		t.Fatalf("unexpected audit entry: %#v", entry)
+	}
+	fields, ok := entry.Request.(map[string]any)
+	if !ok {
+		t.Fatalf("expected audited request fields, got %#v", entry.Request)
+	}
+	if fields["email"] != "ada@example.com" {
+		t.Fatalf("expected email in audit entry, got %#v", fields)
+	}
+	if _, exists := fields["password"]; exists {
+		t.Fatalf("expected password to be removed from audit entry, got %#v", fields)
+	}
+}
+
+func TestAuditRouteFromHandle(t *testing.T) {
+	t.Parallel()
+
+	var entry AuditEntry
+	api := NewAPI(APIInfo{Title: "Items"})
+	handler := Audit(AuditorFunc(func(_ context.Context, e AuditEntry) {
+		entry = e
+	}), nil)(Handle(api, http.MethodGet, "/items/{id}", func(context.Context, NoBody) (NoBody, error) {
+		return NoBody{}, nil
+	}, nil))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/7", nil))
+	if entry.Route != "/items/{id}" {
+		t.Fatalf("expected route %q, got %q", "/items/{id}", entry.Route)
+	}
+}
@@ -0,0 +1,130 @@
+package httpsuite
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultChaosEnv is the environment variable that enables Chaos.
+const DefaultChaosEnv = "HTTPSUITE_CHAOS"
+
+const chaosDetail = "fault injected for resilience testing"
+
+// ChaosOptions configures the Chaos middleware. Rates are probabilities
+// between 0 and 1, drawn independently for every request.
+type ChaosOptions struct {
+	// Env names the variable that must parse as true for faults to be
+	// injected. Defaults to DefaultChaosEnv.
+	Env string
+	// Paths selects the routes that receive faults; entries ending in "/*"
+	// match every path below the prefix. Empty selects every route.
+	Paths []string
+	// Latency delays a LatencyRate share of requests by Latency plus up to
+	// LatencyJitter. LatencyRate defaults to 1 when Latency is set.
+	Latency       time.Duration
+	LatencyJitter time.Duration
+	LatencyRate   float64
+	// ErrorRate is the share of requests answered with a problem whose status
+	// is picked from ErrorStatuses, which defaults to 500 and 503.
+	ErrorRate     float64
+	ErrorStatuses []int
+	// DropRate is the share of requests whose connection is closed without a
+	// response.
+	DropRate float64
+	// Rand returns a number in [0, 1). Defaults to math/rand/v2.
+	Rand func() float64
+}
+
+// Chaos returns opt-in fault injection middleware for resilience testing. It
+// delays, fails, or drops requests on the selected routes at the configured
+// rates. Unless the Env variable is set to a true value when Chaos is called,
+// the middleware passes every request through untouched, so it is safe to
+// leave in production builds.
+func Chaos(opts *ChaosOptions) func(http.Handler) http.Handler {
+	options := ChaosOptions{Env: DefaultChaosEnv, Rand: rand.Float64}
+	if opts != nil {
+		if opts.Env != "" {
+			options.Env = opts.Env
+		}
+		options.Paths = append([]string(nil), opts.Paths...)
+		options.Latency = opts.Latency
+		options.LatencyJitter = opts.LatencyJitter
+		options.LatencyRate = opts.LatencyRate
+		options.ErrorRate = opts.ErrorRate
+		options.ErrorStatuses = append([]int(nil), opts.ErrorStatuses...)
+		options.DropRate = opts.DropRate
+		if opts.Rand != nil {
+			options.Rand = opts.Rand
+		}
+	}
+	if options.LatencyRate <= 0 && options.Latency > 0 {
+		options.LatencyRate = 1
+	}
+	if len(options.ErrorStatuses) == 0 {
+		options.ErrorStatuses = []int{http.StatusInternalServerError, http.StatusServiceUnavailable}
+	}
+
+	enabled, _ := strconv.ParseBool(os.Getenv(options.Env))
+	return func(next http.Handler) http.Handler {
+		if !enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(options.Paths) > 0 && !matchPathPatterns(options.Paths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if options.Latency > 0 && options.Rand() < options.LatencyRate {
+				delay := options.Latency
+				if options.LatencyJitter > 0 {
+					delay += time.Duration(options.Rand() * float64(options.LatencyJitter))
+				}
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+				case <-r.Context().Done():
+					timer.Stop()
+					return
+				}
+			}
+			if options.DropRate > 0 && options.Rand() < options.DropRate {
+				dropConnection(w)
+				return
+			}
+			if options.ErrorRate > 0 && options.Rand() < options.ErrorRate {
+				status := options.ErrorStatuses[int(options.Rand()*float64(len(options.ErrorStatuses)))%len(options.ErrorStatuses)]
+				sendRequestProblem(w, r, status, chaosProblem(r, status))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func chaosProblem(r *http.Request, status int) *ProblemDetails {
+	problems := sharedProblemConfig(r.Context())
+	errorType := "server_error"
+	if status == http.StatusServiceUnavailable {
+		errorType = "service_unavailable_error"
+	}
+	return Problem(status).
+		Type(problems.TypeURL(errorType)).
+		Title(http.StatusText(status)).
+		Detail(chaosDetail).
+		Build()
+}
+
+// dropConnection closes the client connection without writing a response.
+// Writers that cannot be hijacked, such as HTTP/2 streams, are reset by
+// aborting the handler.
+func dropConnection(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	_ = conn.Close()
+}
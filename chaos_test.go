@@ -0,0 +1,113 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func chaosOK() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+func TestChaosDisabledWithoutEnv(t *testing.T) {
+	t.Setenv("TEST_CHAOS_DISABLED", "")
+
+	handler := Chaos(&ChaosOptions{Env: "TEST_CHAOS_DISABLED", ErrorRate: 1, DropRate: 1})(chaosOK())
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Fatalf("expected request to pass through, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestChaosInjectsErrors(t *testing.T) {
+	t.Setenv("TEST_CHAOS_ERRORS", "true")
+
+	tests := []struct {
+		name       string
+		rand       float64
+		paths      []string
+		target     string
+		wantStatus int
+		wantType   string
+	}{
+		{name: "first status", rand: 0, target: "/orders", wantStatus: http.StatusInternalServerError, wantType: "/errors/server-error"},
+		{name: "second status", rand: 0.4, target: "/orders", wantStatus: http.StatusServiceUnavailable, wantType: "/errors/service-unavailable"},
+		{name: "above rate", rand: 0.6, target: "/orders", wantStatus: http.StatusOK},
+		{name: "selected prefix", rand: 0, paths: []string{"/orders/*"}, target: "/orders/7", wantStatus: http.StatusInternalServerError, wantType: "/errors/server-error"},
+		{name: "unselected path", rand: 0, paths: []string{"/orders/*"}, target: "/health", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draws := []float64{tt.rand, tt.rand * 2}
+			handler := Chaos(&ChaosOptions{
+				Env:       "TEST_CHAOS_ERRORS",
+				Paths:     tt.paths,
+				ErrorRate: 0.5,
+				Rand: func() float64 {
+					value := draws[0]
+					draws = draws[1:]
+					return value
+				},
+			})(chaosOK())
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantType == "" {
+				return
+			}
+			var problem ProblemDetails
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("expected problem body, got %v", err)
+			}
+			if problem.Type != tt.wantType || problem.Detail != chaosDetail {
+				t.Fatalf("expected injected problem, got %+v", problem)
+			}
+		})
+	}
+}
+
+func TestChaosAddsLatency(t *testing.T) {
+	t.Setenv("TEST_CHAOS_LATENCY", "1")
+
+	handler := Chaos(&ChaosOptions{
+		Env:     "TEST_CHAOS_LATENCY",
+		Latency: 30 * time.Millisecond,
+		Rand:    func() float64 { return 0 },
+	})(chaosOK())
+
+	started := time.Now()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if elapsed := time.Since(started); elapsed < 30*time.Millisecond {
+		t.Fatalf("expected injected latency, got %v", elapsed)
+	}
+	if rec.Body.String() != "ok" {
+		t.Fatalf("expected handler response after delay, got %q", rec.Body.String())
+	}
+}
+
+func TestChaosDropsConnections(t *testing.T) {
+	t.Setenv("TEST_CHAOS_DROP", "true")
+
+	server := httptest.NewServer(Chaos(&ChaosOptions{
+		Env:      "TEST_CHAOS_DROP",
+		DropRate: 1,
+	})(chaosOK()))
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("expected dropped connection, got status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,221 @@
+// Package client is the consumer-side counterpart of httpsuite responses:
+// Do encodes a request, decodes the {"data": ...} envelope into a typed value,
+// and returns application/problem+json replies as *httpsuite.ProblemDetails
+// errors.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+const defaultMaxResponseBytes int64 = 10 << 20
+
+var errResponseTooLarge = errors.New("response exceeds the size limit")
+
+// Options configures a call to Do.
+type Options struct {
+	// Client sends the request. Defaults to http.DefaultClient.
+	Client *http.Client
+	// Header is added to the request.
+	Header http.Header
+	// Meta, when set, receives the decoded "meta" member of the envelope,
+	// e.g. a *httpsuite.PageMeta.
+	Meta any
+	// Raw decodes the whole body into T, for endpoints without the envelope.
+	Raw bool
+	// MaxResponseBytes caps the response body and defaults to 10 MiB.
+	MaxResponseBytes int64
+	// Retry, when set, retries transport errors and retryable statuses.
+	Retry *RetryPolicy
+	// Hedge, when set, races a second attempt against slow replies.
+	Hedge *HedgePolicy
+	// Timeout bounds the whole call, retries included, on top of any
+	// context deadline.
+	Timeout time.Duration
+	// DeadlineHeader, when set, sends the time left before the context
+	// deadline in milliseconds, e.g. "X-Request-Timeout", so servers can
+	// give up on work the caller will no longer wait for.
+	DeadlineHeader string
+}
+
+// Do sends a request and decodes the response data into T. A nil body sends
+// no content, an io.Reader is sent as is, and other values are encoded as
+// JSON. Error statuses return a *httpsuite.ProblemDetails, recoverable with
+// httpsuite.AsProblem; replies that are not problem documents are converted
+// to one with the body as detail. Use httpsuite.NoBody as T to discard data.
+func Do[T any](ctx context.Context, method, url string, body any, opts *Options) (T, error) {
+	var result T
+	options := normalizeOptions(opts)
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	req, err := newRequest(ctx, method, url, body, options.Header)
+	if err != nil {
+		return result, err
+	}
+	resp, data, err := send(req, options)
+	if err != nil {
+		return result, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return result, decodeProblem(resp, data)
+	}
+	if _, discard := any(result).(httpsuite.NoBody); discard || resp.StatusCode == http.StatusNoContent || len(bytes.TrimSpace(data)) == 0 {
+		return result, nil
+	}
+	if err := decodeData(data, &result, options); err != nil {
+		return result, fmt.Errorf("%s %s: decode response: %w", method, url, err)
+	}
+	return result, nil
+}
+
+func normalizeOptions(opts *Options) Options {
+	options := Options{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Client == nil {
+		options.Client = http.DefaultClient
+	}
+	if options.MaxResponseBytes <= 0 {
+		options.MaxResponseBytes = defaultMaxResponseBytes
+	}
+	options.Retry = normalizeRetryPolicy(options.Retry)
+	options.Hedge = normalizeHedgePolicy(options.Hedge)
+	return options
+}
+
+// send performs the request, retrying as the policy allows, and returns the
+// last reply with its body read.
+func send(req *http.Request, options Options) (*http.Response, []byte, error) {
+	policy := options.Retry
+	if policy != nil {
+		policy.Budget.deposit()
+	}
+	for attempt := 1; ; attempt++ {
+		resp, data, err := perform(req, options)
+		if policy == nil || attempt >= policy.MaxAttempts || !policy.retryable(req, resp, err) {
+			return resp, data, err
+		}
+		delay, ok := policy.delay(attempt, resp, data)
+		if deadline, hasDeadline := req.Context().Deadline(); hasDeadline && time.Until(deadline) < delay {
+			ok = false
+		}
+		if !ok || !policy.Budget.withdraw() {
+			return resp, data, err
+		}
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, err)
+		}
+
+		next := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s %s: replay body: %w", req.Method, req.URL, err)
+			}
+			next.Body = body
+		}
+		req = next
+	}
+}
+
+func roundTrip(req *http.Request, options Options) (*http.Response, []byte, error) {
+	resp, err := options.Client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, options.MaxResponseBytes+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %s: read response: %w", req.Method, req.URL, err)
+	}
+	if int64(len(data)) > options.MaxResponseBytes {
+		return nil, nil, fmt.Errorf("%s %s: %w (%d bytes)", req.Method, req.URL, errResponseTooLarge, options.MaxResponseBytes)
+	}
+	return resp, data, nil
+}
+
+func newRequest(ctx context.Context, method, url string, body any, header http.Header) (*http.Request, error) {
+	var reader io.Reader
+	isJSON := false
+	switch typed := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = typed
+	default:
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encode request body: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+		isJSON = true
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json, application/problem+json")
+	if isJSON {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	return req, nil
+}
+
+func decodeData[T any](data []byte, result *T, options Options) error {
+	if options.Raw {
+		return json.Unmarshal(data, result)
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+		Meta json.RawMessage `json:"meta"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+	if len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, result); err != nil {
+			return err
+		}
+	}
+	if options.Meta != nil && len(envelope.Meta) > 0 {
+		return json.Unmarshal(envelope.Meta, options.Meta)
+	}
+	return nil
+}
+
+// decodeProblem parses an error reply, synthesizing a problem when the
+// server did not send one.
+func decodeProblem(resp *http.Response, data []byte) *httpsuite.ProblemDetails {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if strings.HasSuffix(mediaType, "json") {
+		var problem httpsuite.ProblemDetails
+		if json.Unmarshal(data, &problem) == nil && (problem.Title != "" || problem.Type != "") {
+			if problem.Status == 0 {
+				problem.Status = resp.StatusCode
+			}
+			return &problem
+		}
+	}
+	return httpsuite.NewProblemDetails(resp.StatusCode, httpsuite.BlankURL, "", strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+		var req user
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.Header.Get("Content-Type") != "application/json" {
+			httpsuite.ProblemResponse(w, httpsuite.NewBadRequestProblem("invalid body"))
+			return
+		}
+		if r.Header.Get("X-Tenant") != "acme" {
+			httpsuite.ProblemResponse(w, httpsuite.NewProblemDetails(http.StatusForbidden, "/errors/forbidden", "Forbidden", "unknown tenant"))
+			return
+		}
+		httpsuite.Created(w, user{ID: 7, Name: req.Name}, "/users/7")
+	})
+	mux.HandleFunc("GET /users", func(w http.ResponseWriter, r *http.Request) {
+		httpsuite.Reply().Meta(httpsuite.NewPageMeta(1, 10, 1)).OK(w, []user{{ID: 7, Name: "ada"}})
+	})
+	mux.HandleFunc("GET /raw", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"raw"}`))
+	})
+	mux.HandleFunc("GET /validation", func(w http.ResponseWriter, r *http.Request) {
+		problem := httpsuite.NewProblemDetails(http.StatusBadRequest, "/errors/validation", "Validation Error", "")
+		problem.Extensions = map[string]any{"errors": []httpsuite.ValidationErrorDetail{{Field: "name", Message: "is required"}}}
+		httpsuite.ProblemResponse(w, problem)
+	})
+	mux.HandleFunc("GET /gateway", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+	})
+	mux.HandleFunc("DELETE /users/7", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+	ctx := context.Background()
+	opts := &Options{Client: server.Client(), Header: http.Header{"X-Tenant": {"acme"}}}
+
+	created, err := Do[user](ctx, http.MethodPost, server.URL+"/users", user{Name: "ada"}, opts)
+	if err != nil || created != (user{ID: 7, Name: "ada"}) {
+		t.Fatalf("unexpected result %#v, %v", created, err)
+	}
+
+	var meta httpsuite.PageMeta
+	users, err := Do[[]user](ctx, http.MethodGet, server.URL+"/users", nil, &Options{Client: server.Client(), Meta: &meta})
+	if err != nil || len(users) != 1 || meta.TotalItems != 1 {
+		t.Fatalf("unexpected result %#v, %#v, %v", users, meta, err)
+	}
+
+	raw, err := Do[user](ctx, http.MethodGet, server.URL+"/raw", nil, &Options{Client: server.Client(), Raw: true})
+	if err != nil || raw.Name != "raw" {
+		t.Fatalf("unexpected raw result %#v, %v", raw, err)
+	}
+
+	if _, err := Do[httpsuite.NoBody](ctx, http.MethodDelete, server.URL+"/users/7", nil, opts); err != nil {
+		t.Fatalf("unexpected delete error %v", err)
+	}
+
+	if _, err := Do[user](ctx, http.MethodPost, server.URL+"/users", strings.NewReader(`{"name":"ada"}`), &Options{
+		Client: server.Client(),
+		Header: http.Header{"X-Tenant": {"acme"}, "Content-Type": {"application/json"}},
+	}); err != nil {
+		t.Fatalf("unexpected error sending a reader %v", err)
+	}
+}
+
+func TestDoProblems(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		wantStatus int
+		wantType   string
+		wantDetail string
+	}{
+		{name: "problem", method: http.MethodPost, path: "/users", body: user{Name: "ada"}, wantStatus: http.StatusForbidden, wantType: "/errors/forbidden", wantDetail: "unknown tenant"},
+		{name: "validation", method: http.MethodGet, path: "/validation", wantStatus: http.StatusBadRequest, wantType: "/errors/validation"},
+		{name: "plain text error", method: http.MethodGet, path: "/gateway", wantStatus: http.StatusBadGateway, wantType: httpsuite.BlankURL, wantDetail: "upstream unavailable"},
+		{name: "not found", method: http.MethodGet, path: "/missing", wantStatus: http.StatusNotFound, wantType: httpsuite.BlankURL, wantDetail: "404 page not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Do[user](ctx, tt.method, server.URL+tt.path, tt.body, &Options{Client: server.Client()})
+			problem, ok := httpsuite.AsProblem(err)
+			if !ok {
+				t.Fatalf("expected a problem, got %v", err)
+			}
+			if problem.Status != tt.wantStatus || problem.Type != tt.wantType || problem.Detail != tt.wantDetail {
+				t.Fatalf("unexpected problem %#v", problem)
+			}
+		})
+	}
+
+	_, err := Do[user](ctx, http.MethodGet, server.URL+"/validation", nil, &Options{Client: server.Client()})
+	problem, _ := httpsuite.AsProblem(err)
+	if errs, ok := problem.Extensions["errors"].([]any); !ok || len(errs) != 1 {
+		t.Fatalf("expected validation errors extension, got %#v", problem.Extensions)
+	}
+}
+
+func TestDoFailures(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+	ctx := context.Background()
+
+	if _, err := Do[user](ctx, http.MethodGet, server.URL+"/users", nil, &Options{Client: server.Client()}); err == nil || !strings.Contains(err.Error(), "decode response") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+	if _, err := Do[user](ctx, http.MethodGet, server.URL+"/raw", nil, &Options{Client: server.Client(), Raw: true, MaxResponseBytes: 4}); err == nil || !strings.Contains(err.Error(), "exceeds the size limit") {
+		t.Fatalf("expected size error, got %v", err)
+	}
+	if _, err := Do[user](ctx, http.MethodPost, server.URL+"/users", make(chan int), nil); err == nil || !strings.Contains(err.Error(), "encode request body") {
+		t.Fatalf("expected encode error, got %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := Do[user](canceled, http.MethodGet, server.URL+"/raw", nil, &Options{Client: server.Client()})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context cancellation, got %v", err)
+	}
+	if _, ok := httpsuite.AsProblem(err); ok {
+		t.Fatal("expected transport errors not to be problems")
+	}
+}
@@ -0,0 +1,161 @@
+// Package clienttest runs an httptest server that speaks httpsuite
+// conventions, so client code can be tested against typed stubs: responses
+// are wrapped in the {"data": ...} envelope, errors are problem documents,
+// and every request is parsed into its typed form for assertions.
+package clienttest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+// TB is the part of testing.TB used by the server.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Cleanup(func())
+}
+
+// Server is a stub httpsuite server. Requests that match no stub fail the
+// test and are answered with a 404 problem.
+type Server struct {
+	// URL is the base URL of the server, without a trailing slash.
+	URL string
+
+	t      TB
+	server *httptest.Server
+	mux    *http.ServeMux
+	api    *httpsuite.API
+
+	mu       sync.RWMutex
+	handlers map[string]http.Handler
+}
+
+// NewServer starts a server that is closed when the test ends.
+func NewServer(t TB) *Server {
+	t.Helper()
+	s := &Server{
+		t:        t,
+		mux:      http.NewServeMux(),
+		api:      httpsuite.NewAPI(apiInfo()),
+		handlers: make(map[string]http.Handler),
+	}
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		s.t.Errorf("clienttest: unexpected request %s %s", r.Method, r.URL)
+		httpsuite.ProblemResponse(w, httpsuite.NewNotFoundProblem("no stub for "+r.Method+" "+r.URL.Path))
+	})
+	s.server = httptest.NewServer(s.mux)
+	s.URL = s.server.URL
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// Client returns an HTTP client configured for the server.
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// API returns the routes stubbed so far, e.g. to check them with
+// contracttest.FromAPI or to render their OpenAPI document.
+func (s *Server) API() *httpsuite.API {
+	return s.api
+}
+
+// Endpoint records the typed requests received by a stub.
+type Endpoint[Req any] struct {
+	mu       sync.Mutex
+	requests []Req
+}
+
+// Requests returns the parsed requests in arrival order.
+func (e *Endpoint[Req]) Requests() []Req {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Req(nil), e.requests...)
+}
+
+// Count returns how many requests were received.
+func (e *Endpoint[Req]) Count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.requests)
+}
+
+// Last returns the most recent request and whether there was one.
+func (e *Endpoint[Req]) Last() (Req, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.requests) == 0 {
+		var zero Req
+		return zero, false
+	}
+	return e.requests[len(e.requests)-1], true
+}
+
+func (e *Endpoint[Req]) record(req Req) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests = append(e.requests, req)
+}
+
+// Handle stubs method and pattern, in ServeMux syntax such as "/users/{id}",
+// with a typed handler run through httpsuite.Handle: path parameters are
+// bound, invalid requests get validation problems, and errors carrying a
+// *ProblemDetails are written as that problem. Stubbing the same route again
+// replaces the previous stub.
+func Handle[Req, Resp any](s *Server, method, pattern string, handler httpsuite.TypedHandler[Req, Resp], opts *httpsuite.RouteOptions) *Endpoint[Req] {
+	s.t.Helper()
+	endpoint := &Endpoint[Req]{}
+	recording := func(ctx context.Context, req Req) (Resp, error) {
+		endpoint.record(req)
+		return handler(ctx, req)
+	}
+
+	route := method + " " + pattern
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	api := s.api
+	if _, ok := s.handlers[route]; ok {
+		// Keep the shared API free of duplicate routes.
+		api = httpsuite.NewAPI(apiInfo())
+	} else {
+		s.mux.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+			s.mu.RLock()
+			current := s.handlers[route]
+			s.mu.RUnlock()
+			current.ServeHTTP(w, r)
+		})
+	}
+	s.handlers[route] = httpsuite.Handle[Req, Resp](api, method, pattern, recording, opts)
+	return endpoint
+}
+
+// Reply stubs a route that answers with status and resp in the envelope.
+func Reply[Req, Resp any](s *Server, method, pattern string, status int, resp Resp) *Endpoint[Req] {
+	s.t.Helper()
+	return Handle(s, method, pattern, func(context.Context, Req) (Resp, error) {
+		return resp, nil
+	}, &httpsuite.RouteOptions{Status: status})
+}
+
+// Fail stubs a route that answers with problem.
+func Fail[Req any](s *Server, method, pattern string, problem *httpsuite.ProblemDetails) *Endpoint[Req] {
+	s.t.Helper()
+	return Handle(s, method, pattern, func(context.Context, Req) (httpsuite.NoBody, error) {
+		return httpsuite.NoBody{}, problem
+	}, &httpsuite.RouteOptions{Errors: []int{problem.Status}})
+}
+
+func apiInfo() httpsuite.APIInfo {
+	return httpsuite.APIInfo{
+		Title:   "clienttest",
+		Version: "0.0.0",
+		ParamExtractor: func(r *http.Request, key string) string {
+			return r.PathValue(key)
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package clienttest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rluders/httpsuite/v3"
+	"github.com/rluders/httpsuite/v3/client"
+)
+
+type createUser struct {
+	Org  string `json:"-"`
+	Name string `json:"name"`
+}
+
+func (c *createUser) SetParam(fieldName, value string) error {
+	if fieldName == "org" {
+		c.Org = value
+	}
+	return nil
+}
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type recordingTB struct {
+	*testing.T
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestServerStubs(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(t)
+	ctx := context.Background()
+	opts := &client.Options{Client: server.Client()}
+
+	created := Handle(server, http.MethodPost, "/orgs/{org}/users", func(ctx context.Context, req *createUser) (user, error) {
+		return user{ID: 7, Name: req.Name + "@" + req.Org}, nil
+	}, &httpsuite.RouteOptions{Status: http.StatusCreated})
+	listed := Reply[httpsuite.NoBody](server, http.MethodGet, "/users", http.StatusOK, []user{{ID: 1, Name: "ada"}})
+	Fail[httpsuite.NoBody](server, http.MethodDelete, "/users/{id}", httpsuite.NewNotFoundProblem("user 9 not found"))
+
+	got, err := client.Do[user](ctx, http.MethodPost, server.URL+"/orgs/acme/users", createUser{Name: "ada"}, opts)
+	if err != nil || got != (user{ID: 7, Name: "ada@acme"}) {
+		t.Fatalf("unexpected created user %#v, %v", got, err)
+	}
+	last, ok := created.Last()
+	if !ok || created.Count() != 1 || last.Org != "acme" || last.Name != "ada" {
+		t.Fatalf("unexpected recorded requests %#v", created.Requests())
+	}
+
+	users, err := client.Do[[]user](ctx, http.MethodGet, server.URL+"/users", nil, opts)
+	if err != nil || len(users) != 1 || listed.Count() != 1 {
+		t.Fatalf("unexpected users %#v, %v", users, err)
+	}
+
+	_, err = client.Do[httpsuite.NoBody](ctx, http.MethodDelete, server.URL+"/users/9", nil, opts)
+	if problem, ok := httpsuite.AsProblem(err); !ok || problem.Status != http.StatusNotFound || problem.Detail != "user 9 not found" {
+		t.Fatalf("expected not found problem, got %v", err)
+	}
+
+	if routes := server.API().Routes(); len(routes) != 3 {
+		t.Fatalf("expected three described routes, got %d", len(routes))
+	}
+}
+
+func TestServerRestub(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(t)
+	ctx := context.Background()
+	opts := &client.Options{Client: server.Client()}
+
+	Fail[httpsuite.NoBody](server, http.MethodGet, "/status", httpsuite.NewServiceUnavailableProblem("warming up", 0))
+	if _, err := client.Do[string](ctx, http.MethodGet, server.URL+"/status", nil, opts); err == nil {
+		t.Fatal("expected the first stub to fail")
+	}
+
+	Reply[httpsuite.NoBody](server, http.MethodGet, "/status", http.StatusOK, "ready")
+	if got, err := client.Do[string](ctx, http.MethodGet, server.URL+"/status", nil, opts); err != nil || got != "ready" {
+		t.Fatalf("expected the replacement stub, got %q, %v", got, err)
+	}
+	if routes := server.API().Routes(); len(routes) != 1 {
+		t.Fatalf("expected restubbing not to duplicate routes, got %d", len(routes))
+	}
+}
+
+func TestServerRejectsInvalidAndUnexpectedRequests(t *testing.T) {
+	t.Parallel()
+
+	tb := &recordingTB{T: t}
+	server := NewServer(tb)
+	ctx := context.Background()
+	opts := &client.Options{Client: server.Client()}
+
+	endpoint := Reply[*createUser](server, http.MethodPost, "/orgs/{org}/users", http.StatusCreated, user{ID: 1})
+	_, err := client.Do[user](ctx, http.MethodPost, server.URL+"/orgs/acme/users", strings.NewReader("{"), opts)
+	if problem, ok := httpsuite.AsProblem(err); !ok || problem.Status != http.StatusBadRequest || endpoint.Count() != 0 {
+		t.Fatalf("expected a bad request problem without recording, got %v", err)
+	}
+
+	_, err = client.Do[user](ctx, http.MethodGet, server.URL+"/missing", nil, opts)
+	if problem, ok := httpsuite.AsProblem(err); !ok || problem.Status != http.StatusNotFound {
+		t.Fatalf("expected not found problem, got %v", err)
+	}
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "unexpected request GET /missing") {
+		t.Fatalf("expected the unexpected request to be reported, got %q", tb.errors)
+	}
+}
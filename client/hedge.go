@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const defaultHedgeAttempts = 2
+
+var defaultHedgeMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+
+// HedgePolicy sends another attempt when a reply is slower than Delay. The
+// first reply wins and the remaining attempts are canceled, trading extra
+// load for lower tail latency on latency-sensitive internal calls.
+type HedgePolicy struct {
+	// Delay is how long an attempt may run before the next one starts,
+	// typically the p95 latency of the endpoint.
+	Delay time.Duration
+	// MaxAttempts bounds concurrent attempts, the first one included.
+	// Defaults to 2.
+	MaxAttempts int
+	// Methods are hedged. Defaults to GET, HEAD, and OPTIONS.
+	Methods []string
+}
+
+type attemptResult struct {
+	resp *http.Response
+	data []byte
+	err  error
+}
+
+func normalizeHedgePolicy(policy *HedgePolicy) *HedgePolicy {
+	if policy == nil || policy.Delay <= 0 {
+		return nil
+	}
+	normalized := *policy
+	if normalized.MaxAttempts <= 1 {
+		normalized.MaxAttempts = defaultHedgeAttempts
+	}
+	if len(normalized.Methods) == 0 {
+		normalized.Methods = defaultHedgeMethods
+	}
+	return &normalized
+}
+
+// perform makes one logical attempt: a single round trip, or hedged ones
+// when the policy applies. The deadline header is refreshed every time.
+func perform(req *http.Request, options Options) (*http.Response, []byte, error) {
+	if options.DeadlineHeader != "" {
+		if deadline, ok := req.Context().Deadline(); ok {
+			req = req.Clone(req.Context())
+			req.Header.Set(options.DeadlineHeader, strconv.FormatInt(max(0, time.Until(deadline).Milliseconds()), 10))
+		}
+	}
+
+	hedge := options.Hedge
+	if hedge == nil || !slices.Contains(hedge.Methods, req.Method) || !replayable(req) {
+		return roundTrip(req, options)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	results := make(chan attemptResult, hedge.MaxAttempts)
+	launch := func(first bool) error {
+		next := req.Clone(ctx)
+		if !first && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			next.Body = body
+		}
+		go func() {
+			resp, data, err := roundTrip(next, options)
+			results <- attemptResult{resp: resp, data: data, err: err}
+		}()
+		return nil
+	}
+
+	_ = launch(true)
+	launched, inFlight := 1, 1
+	timer := time.NewTimer(hedge.Delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			if launch(false) != nil {
+				continue
+			}
+			launched++
+			inFlight++
+			if launched < hedge.MaxAttempts {
+				timer.Reset(hedge.Delay)
+			}
+		case result := <-results:
+			inFlight--
+			if result.err == nil || inFlight == 0 {
+				return result.resp, result.data, result.err
+			}
+		}
+	}
+}
+
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+func TestDoHedge(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	canceled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := calls.Add(1)
+		if call == 1 {
+			select {
+			case <-r.Context().Done():
+				close(canceled)
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		httpsuite.OK(w, user{ID: int(call)})
+	}))
+	defer server.Close()
+
+	got, err := Do[user](context.Background(), http.MethodGet, server.URL, nil, &Options{
+		Client: server.Client(),
+		Hedge:  &HedgePolicy{Delay: 20 * time.Millisecond},
+	})
+	if err != nil || got.ID != 2 {
+		t.Fatalf("expected the hedged attempt to win, got %#v, %v", got, err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the slow attempt to be canceled")
+	}
+}
+
+func TestDoHedgeSkipsUnsafeMethods(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(30 * time.Millisecond)
+		httpsuite.OK(w, user{ID: 1})
+	}))
+	defer server.Close()
+
+	if _, err := Do[user](context.Background(), http.MethodPost, server.URL, user{Name: "ada"}, &Options{
+		Client: server.Client(),
+		Hedge:  &HedgePolicy{Delay: time.Millisecond},
+	}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected POST not to be hedged, got %d calls", got)
+	}
+}
+
+func TestDoTimeoutAndDeadlineHeader(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			return
+		}
+		budget, _ := strconv.Atoi(r.Header.Get("X-Request-Timeout"))
+		httpsuite.OK(w, user{ID: budget})
+	}))
+	defer server.Close()
+
+	_, err := Do[user](context.Background(), http.MethodGet, server.URL+"/slow", nil, &Options{Client: server.Client(), Timeout: 20 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the call timeout, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got, err := Do[user](ctx, http.MethodGet, server.URL, nil, &Options{Client: server.Client(), Timeout: time.Minute, DeadlineHeader: "X-Request-Timeout"})
+	if err != nil || got.ID <= 4000 || got.ID > 5000 {
+		t.Fatalf("expected the remaining context budget, got %d ms, %v", got.ID, err)
+	}
+
+	got, err = Do[user](context.Background(), http.MethodGet, server.URL, nil, &Options{Client: server.Client(), DeadlineHeader: "X-Request-Timeout"})
+	if err != nil || got.ID != 0 {
+		t.Fatalf("expected no header without a deadline, got %d ms, %v", got.ID, err)
+	}
+}
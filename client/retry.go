@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetryAttempts  = 3
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+)
+
+var (
+	defaultRetryMethods  = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace}
+	defaultRetryStatuses = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+)
+
+// RetryPolicy configures how Do retries failed attempts. Transport errors and
+// the listed statuses are retried with exponential backoff and jitter, except
+// that 429 and 503 replies carrying Retry-After, as a header or as the
+// problem's retry_after extension, wait exactly that long.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt too. Defaults to 3.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry. Defaults to 100ms.
+	BaseDelay time.Duration
+	// MaxDelay caps backoff. A Retry-After longer than MaxDelay stops
+	// retrying and returns the reply. Defaults to 10s.
+	MaxDelay time.Duration
+	// Methods are retried. Defaults to the idempotent methods; add POST only
+	// when the server deduplicates, e.g. with Idempotency-Key.
+	Methods []string
+	// Statuses are retried. Defaults to 429, 502, 503, and 504.
+	Statuses []int
+	// Budget, when set, limits retries across every call sharing it.
+	Budget *RetryBudget
+}
+
+// RetryBudget caps retries as a share of traffic so retries cannot amplify
+// an outage: every call deposits ratio tokens, up to max, and every retry
+// spends one.
+type RetryBudget struct {
+	mu     sync.Mutex
+	tokens float64
+	max    float64
+	ratio  float64
+}
+
+// NewRetryBudget returns a full budget allowing bursts of max retries and,
+// once drained, ratio retries per call.
+func NewRetryBudget(max int, ratio float64) *RetryBudget {
+	return &RetryBudget{tokens: float64(max), max: float64(max), ratio: ratio}
+}
+
+func (b *RetryBudget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.max, b.tokens+b.ratio)
+}
+
+func (b *RetryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func normalizeRetryPolicy(policy *RetryPolicy) *RetryPolicy {
+	if policy == nil {
+		return nil
+	}
+	normalized := *policy
+	if normalized.MaxAttempts <= 0 {
+		normalized.MaxAttempts = defaultRetryAttempts
+	}
+	if normalized.BaseDelay <= 0 {
+		normalized.BaseDelay = defaultRetryBaseDelay
+	}
+	if normalized.MaxDelay <= 0 {
+		normalized.MaxDelay = defaultRetryMaxDelay
+	}
+	if len(normalized.Methods) == 0 {
+		normalized.Methods = defaultRetryMethods
+	}
+	if len(normalized.Statuses) == 0 {
+		normalized.Statuses = defaultRetryStatuses
+	}
+	return &normalized
+}
+
+func (p *RetryPolicy) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if !slices.Contains(p.Methods, req.Method) || !replayable(req) {
+		return false
+	}
+	if err != nil {
+		return req.Context().Err() == nil && !errors.Is(err, errResponseTooLarge)
+	}
+	return slices.Contains(p.Statuses, resp.StatusCode)
+}
+
+// delay returns the wait before the next attempt, or false when the server
+// asks for longer than the policy allows.
+func (p *RetryPolicy) delay(attempt int, resp *http.Response, data []byte) (time.Duration, bool) {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait, ok := retryAfter(resp, data); ok {
+			return wait, wait <= p.MaxDelay
+		}
+	}
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff <= 0 || backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1)), true
+}
+
+// retryAfter reads the Retry-After header, in seconds or as an HTTP date,
+// falling back to the problem's retry_after extension.
+func retryAfter(resp *http.Response, data []byte) (time.Duration, bool) {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(0, time.Until(at)), true
+		}
+	}
+	return decodeProblem(resp, data).RetryAfter()
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+// flakyServer fails the first failures calls with the given problem.
+func flakyServer(t *testing.T, failures int32, problem *httpsuite.ProblemDetails) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body user
+		if r.Body != http.NoBody {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				httpsuite.ProblemResponse(w, httpsuite.NewBadRequestProblem("body was not replayed"))
+				return
+			}
+		}
+		if calls.Add(1) <= failures {
+			httpsuite.ProblemResponse(w, problem)
+			return
+		}
+		httpsuite.OK(w, user{ID: int(calls.Load()), Name: body.Name})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestDoRetries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		method    string
+		body      any
+		problem   *httpsuite.ProblemDetails
+		policy    RetryPolicy
+		wantCalls int32
+		wantErr   bool
+	}{
+		{name: "retries unavailable", method: http.MethodGet, problem: httpsuite.NewServiceUnavailableProblem("down", 0), wantCalls: 3},
+		{name: "replays the body", method: http.MethodPut, body: user{Name: "ada"}, problem: httpsuite.NewProblemDetails(http.StatusBadGateway, httpsuite.BlankURL, "", ""), wantCalls: 3},
+		{name: "gives up after max attempts", method: http.MethodGet, problem: httpsuite.NewServiceUnavailableProblem("down", 0), policy: RetryPolicy{MaxAttempts: 2}, wantCalls: 2, wantErr: true},
+		{name: "skips non-idempotent methods", method: http.MethodPost, body: user{Name: "ada"}, problem: httpsuite.NewServiceUnavailableProblem("down", 0), wantCalls: 1, wantErr: true},
+		{name: "retries configured methods", method: http.MethodPost, body: user{Name: "ada"}, problem: httpsuite.NewServiceUnavailableProblem("down", 0), policy: RetryPolicy{Methods: []string{http.MethodPost}}, wantCalls: 3},
+		{name: "skips other statuses", method: http.MethodGet, problem: httpsuite.NewNotFoundProblem("missing"), wantCalls: 1, wantErr: true},
+		{name: "honors a long retry_after", method: http.MethodGet, problem: httpsuite.NewTooManyRequestsProblem("slow down", time.Minute), wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, calls := flakyServer(t, 2, tt.problem)
+			policy := tt.policy
+			policy.BaseDelay = time.Millisecond
+			_, err := Do[user](context.Background(), tt.method, server.URL, tt.body, &Options{Client: server.Client(), Retry: &policy})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Fatalf("expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestDoRetriesTransportErrors(t *testing.T) {
+	t.Parallel()
+
+	server, _ := flakyServer(t, 0, nil)
+	var attempts atomic.Int32
+	transport := server.Client().Transport
+	client := &http.Client{Transport: RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if attempts.Add(1) == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return transport.RoundTrip(r)
+	})}
+
+	got, err := Do[user](context.Background(), http.MethodGet, server.URL, nil, &Options{Client: client, Retry: &RetryPolicy{BaseDelay: time.Millisecond}})
+	if err != nil || got.ID != 1 || attempts.Load() != 2 {
+		t.Fatalf("expected a successful retry, got %#v, %v after %d attempts", got, err, attempts.Load())
+	}
+}
+
+func TestDoRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	server, calls := flakyServer(t, 100, httpsuite.NewServiceUnavailableProblem("down", 0))
+	policy := &RetryPolicy{BaseDelay: time.Millisecond, Budget: NewRetryBudget(1, 0)}
+	for range 3 {
+		_, _ = Do[user](context.Background(), http.MethodGet, server.URL, nil, &Options{Client: server.Client(), Retry: policy})
+	}
+	if got := calls.Load(); got != 4 {
+		t.Fatalf("expected three calls and one budgeted retry, got %d calls", got)
+	}
+}
+
+func TestDoRetryStopsAtDeadline(t *testing.T) {
+	t.Parallel()
+
+	server, calls := flakyServer(t, 100, httpsuite.NewServiceUnavailableProblem("down", 0))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := Do[user](ctx, http.MethodGet, server.URL, nil, &Options{Client: server.Client(), Retry: &RetryPolicy{BaseDelay: time.Second}})
+	if problem, ok := httpsuite.AsProblem(err); !ok || problem.Status != http.StatusServiceUnavailable {
+		t.Fatalf("expected the last problem, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected no retry past the deadline, got %d calls", got)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	t.Parallel()
+
+	policy := normalizeRetryPolicy(&RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second})
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		got, ok := policy.delay(attempt, nil, nil)
+		if !ok || got < want/2 || got > want {
+			t.Fatalf("attempt %d: expected a delay in [%v, %v], got %v", attempt, want/2, want, got)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		header string
+		body   string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "seconds", status: http.StatusTooManyRequests, header: "1", want: time.Second, wantOK: true},
+		{name: "problem extension", status: http.StatusServiceUnavailable, body: `{"title":"Unavailable","retry_after":0.5}`, want: 500 * time.Millisecond, wantOK: true},
+		{name: "past date", status: http.StatusServiceUnavailable, header: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOK: true},
+		{name: "beyond max delay", status: http.StatusTooManyRequests, header: "60", want: time.Minute},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Header: http.Header{"Content-Type": {"application/problem+json"}}}
+		if tt.header != "" {
+			resp.Header.Set("Retry-After", tt.header)
+		}
+		got, ok := policy.delay(1, resp, []byte(tt.body))
+		if got != tt.want || ok != tt.wantOK {
+			t.Fatalf("%s: expected %v (%v), got %v (%v)", tt.name, tt.want, tt.wantOK, got, ok)
+		}
+	}
+}
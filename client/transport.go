@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(r).
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Chain wraps base, http.DefaultTransport when nil, with client middleware.
+// Like server middleware, the first one listed sees the request first.
+func Chain(base http.RoundTripper, middleware ...func(http.RoundTripper) http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		base = middleware[i](base)
+	}
+	return base
+}
+
+// TokenFunc returns an access token. force is true after the server rejected
+// the previous token with 401, asking for a refreshed one.
+type TokenFunc func(ctx context.Context, force bool) (string, error)
+
+// CachedToken returns a TokenFunc that reuses the token from fetch until
+// shortly before it expires or until the server rejects it.
+func CachedToken(fetch func(ctx context.Context) (token string, expiry time.Time, err error)) TokenFunc {
+	var mu sync.Mutex
+	var token string
+	var expiry time.Time
+	return func(ctx context.Context, force bool) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !force && token != "" && (expiry.IsZero() || time.Until(expiry) > 10*time.Second) {
+			return token, nil
+		}
+		fresh, freshExpiry, err := fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+		token, expiry = fresh, freshExpiry
+		return token, nil
+	}
+}
+
+// BearerAuth returns middleware that sends "Authorization: Bearer <token>".
+// A 401 reply refreshes the token and retries once when the body can be
+// replayed.
+func BearerAuth(token TokenFunc) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			resp, err := sendWithToken(next, r, token, false)
+			if err != nil || resp.StatusCode != http.StatusUnauthorized || !replayable(r) {
+				return resp, err
+			}
+			_ = resp.Body.Close()
+
+			retry := r.Clone(r.Context())
+			if r.GetBody != nil {
+				if retry.Body, err = r.GetBody(); err != nil {
+					return nil, err
+				}
+			}
+			return sendWithToken(next, retry, token, true)
+		})
+	}
+}
+
+func sendWithToken(next http.RoundTripper, r *http.Request, token TokenFunc, force bool) (*http.Response, error) {
+	value, err := token(r.Context(), force)
+	if err != nil {
+		return nil, err
+	}
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+value)
+	return next.RoundTrip(r)
+}
+
+// RequestID returns middleware that sends X-Request-ID, taken from the
+// httpsuite.RequestIDKey context value or generated, so server and client
+// logs correlate. An explicit header is left untouched.
+func RequestID() func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Header.Get("X-Request-ID") != "" {
+				return next.RoundTrip(r)
+			}
+			id, ok := httpsuite.CtxGet(r.Context(), httpsuite.RequestIDKey)
+			if !ok || id == "" {
+				var buf [16]byte
+				_, _ = rand.Read(buf[:])
+				id = hex.EncodeToString(buf[:])
+			}
+			r = r.Clone(r.Context())
+			r.Header.Set("X-Request-ID", id)
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+// Exchange describes a finished round trip. Status is zero when Err is set.
+type Exchange struct {
+	Method   string
+	Host     string
+	Path     string
+	Status   int
+	Duration time.Duration
+	Err      error
+}
+
+// Observe returns middleware that reports every round trip, e.g. to record
+// request counts and latency histograms.
+func Observe(observe func(*http.Request, Exchange)) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			started := time.Now()
+			resp, err := next.RoundTrip(r)
+			exchange := Exchange{
+				Method:   r.Method,
+				Host:     r.URL.Host,
+				Path:     r.URL.Path,
+				Duration: time.Since(started),
+				Err:      err,
+			}
+			if resp != nil {
+				exchange.Status = resp.StatusCode
+			}
+			observe(r, exchange)
+			return resp, err
+		})
+	}
+}
+
+// Logging returns middleware that logs one line per round trip with
+// log.Printf, or logf when set.
+func Logging(logf func(format string, args ...any)) func(http.RoundTripper) http.RoundTripper {
+	if logf == nil {
+		logf = log.Printf
+	}
+	return Observe(func(r *http.Request, exchange Exchange) {
+		if exchange.Err != nil {
+			logf("%s %s failed after %v: %v", exchange.Method, r.URL.Redacted(), exchange.Duration, exchange.Err)
+			return
+		}
+		logf("%s %s %d %v", exchange.Method, r.URL.Redacted(), exchange.Status, exchange.Duration)
+	})
+}
+
+// Trace returns middleware that brackets each round trip with a span. start
+// may return a request carrying the span context and propagation headers,
+// such as traceparent; the returned func ends the span.
+func Trace(start func(*http.Request) (*http.Request, func(*http.Response, error))) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			traced, end := start(r)
+			if traced == nil {
+				traced = r
+			}
+			resp, err := next.RoundTrip(traced)
+			if end != nil {
+				end(resp, err)
+			}
+			return resp, err
+		})
+	}
+}
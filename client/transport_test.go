@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+func TestChainOrder(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+	tag := func(name string) func(http.RoundTripper) http.RoundTripper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(r)
+			})
+		}
+	}
+	base := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		order = append(order, "base")
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Header: http.Header{}}, nil
+	})
+
+	client := &http.Client{Transport: Chain(base, tag("first"), tag("second"))}
+	if _, err := Do[httpsuite.NoBody](context.Background(), http.MethodGet, "http://example.test", nil, &Options{Client: client}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := strings.Join(order, ","); got != "first,second,base" {
+		t.Fatalf("expected first,second,base, got %s", got)
+	}
+}
+
+func TestBearerAuth(t *testing.T) {
+	t.Parallel()
+
+	var valid atomic.Value
+	valid.Store("token-1")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+valid.Load().(string) {
+			httpsuite.ProblemResponse(w, httpsuite.NewProblemDetails(http.StatusUnauthorized, httpsuite.BlankURL, "", ""))
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost && !strings.Contains(string(data), "ada") {
+			httpsuite.ProblemResponse(w, httpsuite.NewBadRequestProblem("body was not replayed"))
+			return
+		}
+		httpsuite.OK(w, user{Name: "ada"})
+	}))
+	defer server.Close()
+
+	var fetches atomic.Int32
+	token := CachedToken(func(ctx context.Context) (string, time.Time, error) {
+		return fmt.Sprintf("token-%d", fetches.Add(1)), time.Now().Add(time.Hour), nil
+	})
+	client := &http.Client{Transport: Chain(server.Client().Transport, BearerAuth(token))}
+	opts := &Options{Client: client}
+
+	for range 2 {
+		if _, err := Do[user](context.Background(), http.MethodGet, server.URL, nil, opts); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Fatalf("expected the token to be cached, got %d fetches", got)
+	}
+
+	valid.Store("token-2")
+	if _, err := Do[user](context.Background(), http.MethodPost, server.URL, user{Name: "ada"}, opts); err != nil {
+		t.Fatalf("expected a refreshed token, got %v", err)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Fatalf("expected one refresh, got %d fetches", got)
+	}
+
+	valid.Store("never")
+	_, err := Do[user](context.Background(), http.MethodGet, server.URL, nil, opts)
+	if problem, ok := httpsuite.AsProblem(err); !ok || problem.Status != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after one refresh, got %v", err)
+	}
+
+	failing := &http.Client{Transport: Chain(server.Client().Transport, BearerAuth(func(context.Context, bool) (string, error) {
+		return "", errors.New("identity provider down")
+	}))}
+	if _, err := Do[user](context.Background(), http.MethodGet, server.URL, nil, &Options{Client: failing}); err == nil || !strings.Contains(err.Error(), "identity provider down") {
+		t.Fatalf("expected token error, got %v", err)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	var received []string
+	base := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		received = append(received, r.Header.Get("X-Request-ID"))
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Header: http.Header{}}, nil
+	})
+	client := &http.Client{Transport: Chain(base, RequestID())}
+
+	ctx := httpsuite.CtxSet(context.Background(), httpsuite.RequestIDKey, "req-123")
+	_, _ = Do[httpsuite.NoBody](ctx, http.MethodGet, "http://example.test", nil, &Options{Client: client})
+	_, _ = Do[httpsuite.NoBody](context.Background(), http.MethodGet, "http://example.test", nil, &Options{Client: client, Header: http.Header{"X-Request-Id": {"explicit"}}})
+	_, _ = Do[httpsuite.NoBody](context.Background(), http.MethodGet, "http://example.test", nil, &Options{Client: client})
+
+	if received[0] != "req-123" || received[1] != "explicit" || len(received[2]) != 32 {
+		t.Fatalf("unexpected request IDs %q", received)
+	}
+}
+
+func TestObserveLoggingAndTrace(t *testing.T) {
+	t.Parallel()
+
+	server, _ := flakyServer(t, 0, nil)
+	var exchanges []Exchange
+	var lines []string
+	var spans []string
+	transport := Chain(server.Client().Transport,
+		Trace(func(r *http.Request) (*http.Request, func(*http.Response, error)) {
+			r = r.Clone(r.Context())
+			r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			return r, func(resp *http.Response, err error) {
+				spans = append(spans, fmt.Sprintf("%s %d", r.Method, resp.StatusCode))
+			}
+		}),
+		Observe(func(r *http.Request, exchange Exchange) {
+			if r.Header.Get("traceparent") == "" {
+				t.Errorf("expected trace headers inside the span")
+			}
+			exchanges = append(exchanges, exchange)
+		}),
+		Logging(func(format string, args ...any) { lines = append(lines, fmt.Sprintf(format, args...)) }),
+	)
+
+	if _, err := Do[user](context.Background(), http.MethodGet, server.URL+"/users", nil, &Options{Client: &http.Client{Transport: transport}}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(exchanges) != 1 || exchanges[0].Status != http.StatusOK || exchanges[0].Path != "/users" || exchanges[0].Duration <= 0 {
+		t.Fatalf("unexpected exchanges %#v", exchanges)
+	}
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "GET "+server.URL+"/users 200 ") {
+		t.Fatalf("unexpected log lines %q", lines)
+	}
+	if len(spans) != 1 || spans[0] != "GET 200" {
+		t.Fatalf("unexpected spans %q", spans)
+	}
+}
@@ -0,0 +1,303 @@
+package httpsuite
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClientCertErrorKind identifies why a client certificate was rejected.
+type ClientCertErrorKind string
+
+const (
+	// ClientCertErrorMissing means the connection carried no client certificate.
+	ClientCertErrorMissing ClientCertErrorKind = "missing"
+	// ClientCertErrorUntrusted means the certificate does not chain to a trusted root.
+	ClientCertErrorUntrusted ClientCertErrorKind = "untrusted"
+	// ClientCertErrorExpired means the certificate is expired or not yet valid.
+	ClientCertErrorExpired ClientCertErrorKind = "expired"
+	// ClientCertErrorNotAllowed means the certificate is valid but its
+	// identity is not allowed.
+	ClientCertErrorNotAllowed ClientCertErrorKind = "not_allowed"
+)
+
+// ClientCertError reports a rejected client certificate.
+type ClientCertError struct {
+	Kind ClientCertErrorKind
+	// Identity is the rejected certificate identity for not_allowed errors.
+	Identity string
+	Err      error
+}
+
+func (e *ClientCertError) Error() string {
+	switch e.Kind {
+	case ClientCertErrorMissing:
+		return "a client certificate is required"
+	case ClientCertErrorExpired:
+		return "client certificate is expired or not yet valid"
+	case ClientCertErrorNotAllowed:
+		if e.Err != nil {
+			return fmt.Sprintf("client certificate %s is not allowed: %v", e.Identity, e.Err)
+		}
+		return fmt.Sprintf("client certificate %s is not allowed", e.Identity)
+	default:
+		return "client certificate is not signed by a trusted authority"
+	}
+}
+
+func (e *ClientCertError) Unwrap() error {
+	return e.Err
+}
+
+// status returns 401 for certificates that do not authenticate the caller
+// and 403 for authenticated callers that are not allowed.
+func (e *ClientCertError) status() int {
+	if e.Kind == ClientCertErrorNotAllowed {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
+
+// ClientCertificate is the identity of a verified client certificate.
+type ClientCertificate struct {
+	CommonName     string
+	DNSNames       []string
+	EmailAddresses []string
+	URIs           []string
+	// SPIFFEID is set when the certificate is an X.509 SVID, i.e. it carries
+	// exactly one spiffe:// URI.
+	SPIFFEID    string
+	Certificate *x509.Certificate
+}
+
+// Principal returns the SPIFFE ID, common name, or first DNS name, in that
+// order of preference.
+func (c ClientCertificate) Principal() string {
+	switch {
+	case c.SPIFFEID != "":
+		return c.SPIFFEID
+	case c.CommonName != "":
+		return c.CommonName
+	case len(c.DNSNames) > 0:
+		return c.DNSNames[0]
+	}
+	return ""
+}
+
+// ClientCertOptions configures client certificate authentication.
+type ClientCertOptions struct {
+	// Roots verifies the certificate chain. When nil, the chains verified by
+	// the TLS handshake are required, so the server's tls.Config must set
+	// ClientCAs and VerifyClientCertIfGiven or RequireAndVerifyClientCert.
+	Roots *x509.CertPool
+	// The allow-lists accept a certificate matching any entry of any list.
+	// When all are empty, every verified certificate is accepted.
+	// AllowedDNSNames entries may start with "*." to match one label.
+	AllowedDNSNames  []string
+	AllowedEmails    []string
+	AllowedURIs      []string
+	AllowedSPIFFEIDs []string
+	// TrustDomains accepts every SPIFFE ID in the listed trust domains,
+	// e.g. "prod.example.org".
+	TrustDomains []string
+	// Authorize runs after the allow-lists for custom checks. Its error
+	// rejects the certificate with 403, or with a returned *ProblemDetails.
+	Authorize func(r *http.Request, cert ClientCertificate) error
+	// Optional lets requests without a client certificate through.
+	// Presented certificates are still verified.
+	Optional bool
+	Now      func() time.Time
+	// Problems overrides the problem config resolved from the request context.
+	Problems *ProblemConfig
+}
+
+// WithClientCertificate returns a context carrying cert.
+func WithClientCertificate(ctx context.Context, cert ClientCertificate) context.Context {
+	return CtxSet(ctx, ClientCertificateKey, cert)
+}
+
+// ClientCertificateFromContext returns the certificate stored by
+// ClientCertAuth or WithClientCertificate.
+func ClientCertificateFromContext(ctx context.Context) (ClientCertificate, bool) {
+	return CtxGet(ctx, ClientCertificateKey)
+}
+
+// VerifyClientCertificate verifies the client certificate of r and returns
+// its identity. Errors are *ClientCertError values.
+func VerifyClientCertificate(r *http.Request, opts *ClientCertOptions) (ClientCertificate, error) {
+	options := normalizeClientCertOptions(opts)
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ClientCertificate{}, &ClientCertError{Kind: ClientCertErrorMissing}
+	}
+	leaf := r.TLS.PeerCertificates[0]
+
+	if options.Roots != nil {
+		intermediates := x509.NewCertPool()
+		for _, cert := range r.TLS.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := leaf.Verify(x509.VerifyOptions{
+			Roots:         options.Roots,
+			Intermediates: intermediates,
+			CurrentTime:   options.Now(),
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		if err != nil {
+			var invalid x509.CertificateInvalidError
+			if errors.As(err, &invalid) && invalid.Reason == x509.Expired {
+				return ClientCertificate{}, &ClientCertError{Kind: ClientCertErrorExpired, Err: err}
+			}
+			return ClientCertificate{}, &ClientCertError{Kind: ClientCertErrorUntrusted, Err: err}
+		}
+	} else if len(r.TLS.VerifiedChains) == 0 {
+		return ClientCertificate{}, &ClientCertError{Kind: ClientCertErrorUntrusted}
+	}
+
+	cert := newClientCertificate(leaf)
+	if !options.allows(cert) {
+		return ClientCertificate{}, &ClientCertError{Kind: ClientCertErrorNotAllowed, Identity: clientCertIdentity(cert)}
+	}
+	if options.Authorize != nil {
+		if err := options.Authorize(r, cert); err != nil {
+			return ClientCertificate{}, &ClientCertError{Kind: ClientCertErrorNotAllowed, Identity: clientCertIdentity(cert), Err: err}
+		}
+	}
+	return cert, nil
+}
+
+// ClientCertAuth returns middleware that authenticates callers by their TLS
+// client certificate. Verified identities are stored in the context, and
+// their Principal under PrincipalKey for audit trails, quotas, and cost
+// limits. Rejected certificates reply 401 when they do not authenticate the
+// caller and 403 when the identity is not allowed, with the reason as detail.
+func ClientCertAuth(opts *ClientCertOptions) func(http.Handler) http.Handler {
+	options := normalizeClientCertOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cert, err := VerifyClientCertificate(r, &options)
+			if err != nil {
+				var certErr *ClientCertError
+				if errors.As(err, &certErr) && certErr.Kind == ClientCertErrorMissing && options.Optional {
+					next.ServeHTTP(w, r)
+					return
+				}
+				sendClientCertError(w, r, err, options.Problems)
+				return
+			}
+
+			ctx := WithClientCertificate(r.Context(), cert)
+			if principal := cert.Principal(); principal != "" {
+				ctx = CtxSet(ctx, PrincipalKey, principal)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func normalizeClientCertOptions(opts *ClientCertOptions) ClientCertOptions {
+	var options ClientCertOptions
+	if opts != nil {
+		options = *opts
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	return options
+}
+
+func sendClientCertError(w http.ResponseWriter, r *http.Request, err error, config *ProblemConfig) {
+	if problem, ok := AsProblem(errors.Unwrap(err)); ok {
+		sendRequestProblem(w, r, problem.Status, problem)
+		return
+	}
+	certErr := err.(*ClientCertError)
+	problems := resolveProblemConfig(r.Context(), config)
+	status, key, title := certErr.status(), "unauthorized_error", "Invalid Client Certificate"
+	if status == http.StatusForbidden {
+		key, title = "forbidden_error", "Client Certificate Not Allowed"
+	}
+	problem := NewProblemDetails(status, problems.TypeURL(key), title, certErr.Error())
+	problem.Extensions = map[string]interface{}{"reason": string(certErr.Kind)}
+	sendRequestProblem(w, r, status, problem)
+}
+
+func newClientCertificate(leaf *x509.Certificate) ClientCertificate {
+	cert := ClientCertificate{
+		CommonName:     leaf.Subject.CommonName,
+		DNSNames:       leaf.DNSNames,
+		EmailAddresses: leaf.EmailAddresses,
+		Certificate:    leaf,
+	}
+	var spiffeIDs []string
+	for _, uri := range leaf.URIs {
+		cert.URIs = append(cert.URIs, uri.String())
+		if uri.Scheme == "spiffe" {
+			spiffeIDs = append(spiffeIDs, uri.String())
+		}
+	}
+	if len(spiffeIDs) == 1 {
+		cert.SPIFFEID = spiffeIDs[0]
+	}
+	return cert
+}
+
+func (o ClientCertOptions) allows(cert ClientCertificate) bool {
+	if len(o.AllowedDNSNames) == 0 && len(o.AllowedEmails) == 0 && len(o.AllowedURIs) == 0 &&
+		len(o.AllowedSPIFFEIDs) == 0 && len(o.TrustDomains) == 0 {
+		return true
+	}
+	for _, name := range cert.DNSNames {
+		for _, pattern := range o.AllowedDNSNames {
+			if dnsNameMatches(pattern, name) {
+				return true
+			}
+		}
+	}
+	for _, email := range cert.EmailAddresses {
+		if slices.ContainsFunc(o.AllowedEmails, func(allowed string) bool { return strings.EqualFold(allowed, email) }) {
+			return true
+		}
+	}
+	for _, uri := range cert.URIs {
+		if slices.Contains(o.AllowedURIs, uri) {
+			return true
+		}
+	}
+	if cert.SPIFFEID != "" {
+		if slices.Contains(o.AllowedSPIFFEIDs, cert.SPIFFEID) {
+			return true
+		}
+		if id, err := url.Parse(cert.SPIFFEID); err == nil {
+			if slices.ContainsFunc(o.TrustDomains, func(domain string) bool { return strings.EqualFold(domain, id.Host) }) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dnsNameMatches compares case-insensitively, letting "*.example.com" match
+// exactly one label in front of example.com.
+func dnsNameMatches(pattern, name string) bool {
+	pattern, name = strings.ToLower(pattern), strings.ToLower(strings.TrimSuffix(name, "."))
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		label, ok := strings.CutSuffix(name, "."+suffix)
+		return ok && label != "" && !strings.Contains(label, ".")
+	}
+	return pattern == name
+}
+
+// clientCertIdentity describes a certificate in error messages.
+func clientCertIdentity(cert ClientCertificate) string {
+	if principal := cert.Principal(); principal != "" {
+		return strconv.Quote(principal)
+	}
+	return "without a subject"
+}
@@ -0,0 +1,223 @@
+package httpsuite
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type testCertAuthority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCertAuthority(t *testing.T) *testCertAuthority {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create CA: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCertAuthority{cert: cert, key: key, pool: pool}
+}
+
+func (ca *testCertAuthority) issue(t *testing.T, template *x509.Certificate) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template.SerialNumber = big.NewInt(2)
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Minute)
+		template.NotAfter = time.Now().Add(time.Hour)
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert
+}
+
+func clientCertRequest(certs ...*x509.Certificate) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/internal", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: certs}
+	return req
+}
+
+func TestVerifyClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCertAuthority(t)
+	other := newTestCertAuthority(t)
+	spiffeID, _ := url.Parse("spiffe://prod.example.org/ns/billing/sa/worker")
+	workload := ca.issue(t, &x509.Certificate{URIs: []*url.URL{spiffeID}})
+	service := ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "reports"}, DNSNames: []string{"reports.svc.internal"}})
+	expired := ca.issue(t, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "old"},
+		NotBefore: time.Now().Add(-2 * time.Hour),
+		NotAfter:  time.Now().Add(-time.Hour),
+	})
+	foreign := other.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "reports"}})
+
+	tests := []struct {
+		name          string
+		req           *http.Request
+		opts          *ClientCertOptions
+		wantKind      ClientCertErrorKind
+		wantPrincipal string
+	}{
+		{name: "missing", req: httptest.NewRequest(http.MethodGet, "/", nil), opts: &ClientCertOptions{Roots: ca.pool}, wantKind: ClientCertErrorMissing},
+		{name: "spiffe", req: clientCertRequest(workload), opts: &ClientCertOptions{Roots: ca.pool}, wantPrincipal: spiffeID.String()},
+		{name: "untrusted", req: clientCertRequest(foreign), opts: &ClientCertOptions{Roots: ca.pool}, wantKind: ClientCertErrorUntrusted},
+		{name: "expired", req: clientCertRequest(expired), opts: &ClientCertOptions{Roots: ca.pool}, wantKind: ClientCertErrorExpired},
+		{name: "unverified handshake", req: clientCertRequest(service), wantKind: ClientCertErrorUntrusted},
+		{
+			name: "verified handshake",
+			req: func() *http.Request {
+				req := clientCertRequest(service)
+				req.TLS.VerifiedChains = [][]*x509.Certificate{{service, ca.cert}}
+				return req
+			}(),
+			wantPrincipal: "reports",
+		},
+		{name: "trust domain", req: clientCertRequest(workload), opts: &ClientCertOptions{Roots: ca.pool, TrustDomains: []string{"prod.example.org"}}, wantPrincipal: spiffeID.String()},
+		{name: "other trust domain", req: clientCertRequest(workload), opts: &ClientCertOptions{Roots: ca.pool, TrustDomains: []string{"dev.example.org"}}, wantKind: ClientCertErrorNotAllowed},
+		{name: "spiffe id", req: clientCertRequest(workload), opts: &ClientCertOptions{Roots: ca.pool, AllowedSPIFFEIDs: []string{spiffeID.String()}}, wantPrincipal: spiffeID.String()},
+		{name: "wildcard dns", req: clientCertRequest(service), opts: &ClientCertOptions{Roots: ca.pool, AllowedDNSNames: []string{"*.svc.internal"}}, wantPrincipal: "reports"},
+		{name: "dns not allowed", req: clientCertRequest(service), opts: &ClientCertOptions{Roots: ca.pool, AllowedDNSNames: []string{"*.internal"}}, wantKind: ClientCertErrorNotAllowed},
+		{
+			name: "authorize",
+			req:  clientCertRequest(service),
+			opts: &ClientCertOptions{Roots: ca.pool, Authorize: func(r *http.Request, cert ClientCertificate) error {
+				return errors.New("reports may only read")
+			}},
+			wantKind: ClientCertErrorNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert, err := VerifyClientCertificate(tt.req, tt.opts)
+			if tt.wantKind != "" {
+				var certErr *ClientCertError
+				if !errors.As(err, &certErr) || certErr.Kind != tt.wantKind {
+					t.Fatalf("expected %s error, got %v", tt.wantKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cert.Principal() != tt.wantPrincipal {
+				t.Fatalf("expected principal %q, got %q", tt.wantPrincipal, cert.Principal())
+			}
+		})
+	}
+}
+
+func TestClientCertAuth(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCertAuthority(t)
+	service := ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "reports"}, DNSNames: []string{"reports.svc.internal"}})
+	other := ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "intruder"}})
+
+	handler := ClientCertAuth(&ClientCertOptions{Roots: ca.pool, AllowedDNSNames: []string{"reports.svc.internal"}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cert, _ := ClientCertificateFromContext(r.Context())
+			principal, _ := CtxGet(r.Context(), PrincipalKey)
+			OK(w, map[string]string{"cn": cert.CommonName, "principal": principal})
+		}),
+	)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, clientCertRequest(service))
+	if w.Code != http.StatusOK || w.Body.String() != `{"data":{"cn":"reports","principal":"reports"}}`+"\n" {
+		t.Fatalf("expected identity in context, got %d %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		wantReason string
+		wantDetail string
+	}{
+		{name: "missing", req: httptest.NewRequest(http.MethodGet, "/", nil), wantStatus: http.StatusUnauthorized, wantReason: "missing", wantDetail: "a client certificate is required"},
+		{name: "not allowed", req: clientCertRequest(other), wantStatus: http.StatusForbidden, wantReason: "not_allowed", wantDetail: `client certificate "intruder" is not allowed`},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, tt.req)
+		var problem struct {
+			Detail string `json:"detail"`
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+			t.Fatalf("%s: decode problem: %v", tt.name, err)
+		}
+		if w.Code != tt.wantStatus || problem.Reason != tt.wantReason || problem.Detail != tt.wantDetail {
+			t.Fatalf("%s: expected %d %s %q, got %d %+v", tt.name, tt.wantStatus, tt.wantReason, tt.wantDetail, w.Code, problem)
+		}
+	}
+}
+
+func TestClientCertAuthOptionalAndProblems(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCertAuthority(t)
+	service := ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "reports"}})
+	teapot := NewProblemDetails(http.StatusTeapot, "about:blank", "Nope", "")
+	handler := ClientCertAuth(&ClientCertOptions{
+		Roots:     ca.pool,
+		Optional:  true,
+		Authorize: func(r *http.Request, cert ClientCertificate) error { return teapot },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := ClientCertificateFromContext(r.Context()); ok {
+			t.Error("expected no certificate for anonymous requests")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected anonymous request through, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, clientCertRequest(service))
+	if w.Code != http.StatusTeapot {
+		t.Fatalf("expected the Authorize problem, got %d", w.Code)
+	}
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// libraryTypes maps component schemas generated by httpsuite to the library
+// types they describe.
+var libraryTypes = map[string]string{
+	"ProblemDetails":        "httpsuite.ProblemDetails",
+	"ValidationErrorDetail": "httpsuite.ValidationErrorDetail",
+}
+
+type goGenerator struct {
+	decls    []string
+	declared map[string]bool
+	usesTime bool
+}
+
+// generateGo renders a typed client for doc in package pkg.
+func generateGo(doc *document, pkg string) ([]byte, error) {
+	g := &goGenerator{declared: make(map[string]bool)}
+	for _, name := range sortedKeys(doc.schemas) {
+		if _, ok := libraryTypes[name]; ok {
+			continue
+		}
+		g.declare(exportedName(name), doc.schemas[name])
+	}
+	var methods []string
+	for _, op := range doc.operations {
+		methods = append(methods, g.method(op))
+	}
+
+	var out strings.Builder
+	out.WriteString("// Code generated by httpsuite-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	out.WriteString("import (\n\t\"bytes\"\n\t\"context\"\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n\t\"net/url\"\n\t\"strings\"\n")
+	if g.usesTime {
+		out.WriteString("\t\"time\"\n")
+	}
+	out.WriteString("\n\t\"github.com/rluders/httpsuite/v3\"\n)\n\n")
+	out.WriteString(goClientRuntime)
+	for _, decl := range g.decls {
+		out.WriteString("\n" + decl)
+	}
+	for _, method := range methods {
+		out.WriteString("\n" + method)
+	}
+
+	formatted, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return nil, fmt.Errorf("format generated client: %w", err)
+	}
+	return formatted, nil
+}
+
+// declare adds a named type for schema once.
+func (g *goGenerator) declare(name string, schema map[string]any) string {
+	if g.declared[name] {
+		return name
+	}
+	g.declared[name] = true
+
+	var decl strings.Builder
+	if description, ok := schema["description"].(string); ok {
+		decl.WriteString(goComment(name+" "+lowerFirst(description), ""))
+	}
+	properties, isStruct := schema["properties"].(map[string]any)
+	if !isStruct {
+		fmt.Fprintf(&decl, "type %s %s\n", name, g.typeExpr(schema, name+"Value"))
+		g.decls = append(g.decls, decl.String())
+		return name
+	}
+
+	// Reserve the slot so nested declarations follow their parent.
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+	required := requiredSet(schema)
+	fmt.Fprintf(&decl, "type %s struct {\n", name)
+	for _, property := range sortedKeys(properties) {
+		fieldSchema, _ := properties[property].(map[string]any)
+		fieldName := exportedName(property)
+		if description, ok := fieldSchema["description"].(string); ok {
+			decl.WriteString(goComment(description, "\t"))
+		}
+		tag := property
+		if !required[property] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&decl, "\t%s %s `json:%q`\n", fieldName, g.typeExpr(fieldSchema, name+fieldName), tag)
+	}
+	decl.WriteString("}\n")
+	g.decls[index] = decl.String()
+	return name
+}
+
+// typeExpr returns the Go type of schema, declaring inline objects as hint.
+func (g *goGenerator) typeExpr(schema map[string]any, hint string) string {
+	if schema == nil {
+		return "any"
+	}
+	schema, nullable := nonNull(schema)
+	expr := g.baseTypeExpr(schema, hint)
+	if nullable && !strings.HasPrefix(expr, "[]") && !strings.HasPrefix(expr, "map[") && expr != "any" && expr != "json.RawMessage" {
+		return "*" + expr
+	}
+	return expr
+}
+
+func (g *goGenerator) baseTypeExpr(schema map[string]any, hint string) string {
+	if ref := refName(schema); ref != "" {
+		if library, ok := libraryTypes[ref]; ok {
+			return library
+		}
+		return exportedName(ref)
+	}
+	switch schemaType(schema) {
+	case "string":
+		switch schema["format"] {
+		case "date-time":
+			g.usesTime = true
+			return "time.Time"
+		case "byte":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if schema["format"] == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if schema["format"] == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		return "[]" + g.typeExpr(items, hint+"Item")
+	case "object":
+		if _, ok := schema["properties"]; ok {
+			return g.declare(hint, schema)
+		}
+		if values, ok := schema["additionalProperties"].(map[string]any); ok {
+			return "map[string]" + g.typeExpr(values, hint+"Value")
+		}
+		return "map[string]any"
+	}
+	return "json.RawMessage"
+}
+
+func (g *goGenerator) method(op operation) string {
+	var args, query, header []string
+	argNames := map[string]bool{"ctx": true, "body": true, "params": true, "c": true}
+	pathArgs := make(map[string]string)
+	var optional []parameter
+
+	for _, param := range op.params {
+		if param.in != "path" {
+			optional = append(optional, param)
+			continue
+		}
+		arg := argName(param.name, argNames)
+		pathArgs[param.name] = arg
+		args = append(args, arg+" "+g.typeExpr(param.schema, op.name+exportedName(param.name)))
+	}
+	if len(optional) > 0 {
+		paramsType := g.declareParams(op, optional)
+		args = append(args, "params "+paramsType)
+		for _, param := range optional {
+			field := "params." + exportedName(param.name)
+			target := "query"
+			if param.in == "header" {
+				target = "header"
+			}
+			line := fmt.Sprintf("%s.Set(%q, fmt.Sprint(%s))", target, param.name, field)
+			if !param.required {
+				line = fmt.Sprintf("if %s != nil {\n%s.Set(%q, fmt.Sprint(*%s))\n}", field, target, param.name, field)
+			}
+			if param.in == "header" {
+				header = append(header, line)
+			} else {
+				query = append(query, line)
+			}
+		}
+	}
+
+	bodyArg := "nil"
+	if op.body != nil {
+		bodyType := g.typeExpr(op.body, op.name+"Request")
+		if !op.bodyRequired && !strings.HasPrefix(bodyType, "*") && !strings.HasPrefix(bodyType, "[]") && !strings.HasPrefix(bodyType, "map[") {
+			bodyType = "*" + bodyType
+		}
+		args = append(args, "body "+bodyType)
+		bodyArg = "body"
+	}
+
+	resultType := "httpsuite.NoBody"
+	outArg := "nil"
+	if op.response != nil {
+		resultType = g.typeExpr(op.response, op.name+"Response")
+		outArg = "&out"
+	}
+
+	var path []string
+	for _, segment := range pathSegments(op.path) {
+		if !segment.param {
+			path = append(path, fmt.Sprintf("%q", segment.text))
+			continue
+		}
+		arg := pathArgs[segment.text]
+		path = append(path, "url.PathEscape(fmt.Sprint("+arg+"))")
+	}
+	if len(path) == 0 {
+		path = append(path, `"/"`)
+	}
+
+	var method strings.Builder
+	summary := fmt.Sprintf("%s calls %s %s.", op.name, op.method, op.path)
+	if op.summary != "" {
+		summary += "\n\n" + op.summary
+	}
+	method.WriteString(goComment(summary, ""))
+	fmt.Fprintf(&method, "func (c *Client) %s(%s) (%s, *httpsuite.ProblemDetails, error) {\n", op.name, strings.Join(append([]string{"ctx context.Context"}, args...), ", "), resultType)
+	fmt.Fprintf(&method, "\tvar out %s\n", resultType)
+	queryArg, headerArg := "nil", "nil"
+	if len(query) > 0 {
+		method.WriteString("\tquery := url.Values{}\n\t" + strings.Join(query, "\n\t") + "\n")
+		queryArg = "query"
+	}
+	if len(header) > 0 {
+		method.WriteString("\theader := http.Header{}\n\t" + strings.Join(header, "\n\t") + "\n")
+		headerArg = "header"
+	}
+	fmt.Fprintf(&method, "\tproblem, err := c.do(ctx, %q, %s, %s, %s, %s, %s, %t)\n", op.method, strings.Join(path, " + "), queryArg, headerArg, bodyArg, outArg, op.envelope)
+	method.WriteString("\treturn out, problem, err\n}\n")
+	return method.String()
+}
+
+// declareParams declares the struct carrying an operation's query and header
+// parameters. Optional parameters are pointers so zero values can be sent.
+func (g *goGenerator) declareParams(op operation, params []parameter) string {
+	name := op.name + "Params"
+	var decl strings.Builder
+	decl.WriteString(goComment(name+" holds the query and header parameters of "+op.name+".", ""))
+	fmt.Fprintf(&decl, "type %s struct {\n", name)
+	for _, param := range params {
+		fieldType := g.typeExpr(param.schema, name+exportedName(param.name))
+		if !param.required && !strings.HasPrefix(fieldType, "*") {
+			fieldType = "*" + fieldType
+		}
+		fmt.Fprintf(&decl, "\t%s %s // %s %q\n", exportedName(param.name), fieldType, param.in, param.name)
+	}
+	decl.WriteString("}\n")
+	g.decls = append(g.decls, decl.String())
+	return name
+}
+
+func argName(name string, used map[string]bool) string {
+	arg := exportedName(name)
+	if strings.ToUpper(arg) == arg {
+		arg = strings.ToLower(arg)
+	} else {
+		arg = lowerFirst(arg)
+	}
+	for token.IsKeyword(arg) || used[arg] {
+		arg += "Param"
+	}
+	used[arg] = true
+	return arg
+}
+
+func lowerFirst(value string) string {
+	if value == "" {
+		return value
+	}
+	runes := []rune(value)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+func goComment(text, indent string) string {
+	var comment strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			comment.WriteString(indent + "//\n")
+			continue
+		}
+		comment.WriteString(indent + "// " + line + "\n")
+	}
+	return comment.String()
+}
+
+const goClientRuntime = `// Client calls the API. Methods return the response data, or the problem
+// the server replied with; the error reports transport and decoding failures.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client for the API at baseURL. A nil httpClient uses
+// http.DefaultClient.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{BaseURL: baseURL, HTTPClient: httpClient}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body, out any, envelope bool) (*httpsuite.ProblemDetails, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	target := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json, application/problem+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		problem := &httpsuite.ProblemDetails{}
+		if json.Unmarshal(data, problem) != nil || problem.Status == 0 {
+			problem = httpsuite.NewProblemDetails(resp.StatusCode, httpsuite.BlankURL, "", strings.TrimSpace(string(data)))
+		}
+		return problem, nil
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent || len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	if envelope {
+		var wrapped struct {
+			Data json.RawMessage ` + "`json:\"data\"`" + `
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, fmt.Errorf("decode %s %s response: %w", method, path, err)
+		}
+		data = wrapped.Data
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, fmt.Errorf("decode %s %s response: %w", method, path, err)
+	}
+	return nil, nil
+}
+`
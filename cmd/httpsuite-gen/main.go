@@ -0,0 +1,85 @@
+// Command httpsuite-gen generates a typed Go client, and optionally
+// TypeScript types, from an OpenAPI document such as the one served by
+// API.OpenAPIHandler. Client methods return (T, *httpsuite.ProblemDetails,
+// error), so server and client models stay in sync.
+//
+// Usage:
+//
+//	httpsuite-gen -spec openapi.json -out client/client.go -package client -ts web/api.ts
+//	httpsuite-gen -spec http://localhost:8080/openapi.json -out client.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "httpsuite-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("httpsuite-gen", flag.ContinueOnError)
+	spec := flags.String("spec", "-", "OpenAPI document: a file, an http(s) URL, or - for stdin")
+	out := flags.String("out", "-", "Go client output file, or - for stdout")
+	pkg := flags.String("package", "client", "package name of the Go client")
+	ts := flags.String("ts", "", "optional TypeScript types output file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := readSpec(*spec, stdin)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(data)
+	if err != nil {
+		return err
+	}
+
+	client, err := generateGo(doc, *pkg)
+	if err != nil {
+		return err
+	}
+	if err := writeOutput(*out, client, stdout); err != nil {
+		return err
+	}
+	if *ts != "" {
+		return writeOutput(*ts, generateTypeScript(doc), stdout)
+	}
+	return nil
+}
+
+func readSpec(source string, stdin io.Reader) ([]byte, error) {
+	switch {
+	case source == "-":
+		return io.ReadAll(stdin)
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		resp, err := http.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch %s: %s", source, resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	default:
+		return os.ReadFile(source)
+	}
+}
+
+func writeOutput(target string, data []byte, stdout io.Writer) error {
+	if target == "-" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(target, data, 0o644)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+type createUserRequest struct {
+	Org     string   `json:"org"`
+	Name    string   `json:"name" validate:"required" description:"Display name"`
+	Role    string   `json:"role,omitempty" validate:"oneof=admin member"`
+	Tags    []string `json:"tags,omitempty"`
+	DryRun  bool     `json:"-" query:"dry_run"`
+	TraceID string   `json:"-" header:"X-Trace-Id"`
+}
+
+type user struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Manager   *user     `json:"manager,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func testDocument(t *testing.T) []byte {
+	t.Helper()
+	api := httpsuite.NewAPI(httpsuite.APIInfo{Title: "Users", Version: "1.0.0"})
+	httpsuite.Describe[*createUserRequest, user](api, http.MethodPost, "/orgs/{org}/users", &httpsuite.RouteOptions{
+		OperationID: "createUser",
+		Summary:     "Create a user",
+		Status:      http.StatusCreated,
+	})
+	httpsuite.Describe[httpsuite.NoBody, []user](api, http.MethodGet, "/users", nil)
+	httpsuite.Describe[httpsuite.NoBody, httpsuite.NoBody](api, http.MethodDelete, "/users/{id}", nil)
+
+	data, err := json.Marshal(api.OpenAPI())
+	if err != nil {
+		t.Fatalf("marshal document: %v", err)
+	}
+	return data
+}
+
+func TestGenerateGo(t *testing.T) {
+	t.Parallel()
+
+	doc, err := parseDocument(testDocument(t))
+	if err != nil {
+		t.Fatalf("parse document: %v", err)
+	}
+	source, err := generateGo(doc, "users")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "client.go", source, parser.AllErrors); err != nil {
+		t.Fatalf("generated client does not parse: %v\n%s", err, source)
+	}
+
+	for _, want := range []string{
+		"package users",
+		"// CreateUser calls POST /orgs/{org}/users.\n//\n// Create a user\n",
+		"func (c *Client) CreateUser(ctx context.Context, org string, params CreateUserParams, body CreateUserRequest) (User, *httpsuite.ProblemDetails, error) {",
+		"func (c *Client) GetUsers(ctx context.Context) ([]User, *httpsuite.ProblemDetails, error) {",
+		"func (c *Client) DeleteUsersID(ctx context.Context, id string) (httpsuite.NoBody, *httpsuite.ProblemDetails, error) {",
+		"DryRun   *bool   // query \"dry_run\"",
+		`header.Set("X-Trace-Id", fmt.Sprint(*params.XTraceID))`,
+		"CreatedAt time.Time `json:\"created_at,omitempty\"`",
+		"Manager   *User     `json:\"manager,omitempty\"`",
+		"// Display name",
+		"Name string   `json:\"name\"`",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Fatalf("expected generated client to contain %q\n%s", want, source)
+		}
+	}
+	if strings.Contains(string(source), "type ProblemDetails") {
+		t.Fatal("expected ProblemDetails to map to the library type")
+	}
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	t.Parallel()
+
+	doc, err := parseDocument(testDocument(t))
+	if err != nil {
+		t.Fatalf("parse document: %v", err)
+	}
+	source := string(generateTypeScript(doc))
+
+	for _, want := range []string{
+		"export interface User {\n  created_at?: string;\n  id?: number;\n  manager?: User | null;\n  name?: string;\n}",
+		"export interface CreateUserRequest {\n  /** Display name */\n  name: string;\n  role?: \"admin\" | \"member\";\n  tags?: string[];\n}",
+		"export type CreateUserResponse = User;",
+		"export type GetUsersResponse = User[];",
+		"export interface CreateUserParams {\n  org: string;\n  dry_run?: boolean;\n  \"X-Trace-Id\"?: string;\n}",
+		"export interface ProblemDetails {",
+	} {
+		if !strings.Contains(source, want) {
+			t.Fatalf("expected TypeScript to contain %q\n%s", want, source)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "openapi.json")
+	if err := os.WriteFile(spec, testDocument(t), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	ts := filepath.Join(dir, "api.ts")
+
+	var stdout bytes.Buffer
+	if err := run([]string{"-spec", spec, "-package", "api", "-ts", ts}, nil, &stdout); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "package api") {
+		t.Fatalf("expected client on stdout, got %s", stdout.String())
+	}
+	if data, err := os.ReadFile(ts); err != nil || !strings.Contains(string(data), "export interface User") {
+		t.Fatalf("expected TypeScript output, got %q (%v)", data, err)
+	}
+
+	if err := run([]string{"-spec", "-"}, strings.NewReader(`{"paths":{}}`), &stdout); err == nil {
+		t.Fatal("expected documents without an openapi version to fail")
+	}
+}
+
+// TestGeneratedClientRoundTrip compiles the generated client in a scratch
+// module and runs it against a real httpsuite server.
+func TestGeneratedClientRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles a scratch module")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not available")
+	}
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatalf("resolve module root: %v", err)
+	}
+
+	doc, err := parseDocument(testDocument(t))
+	if err != nil {
+		t.Fatalf("parse document: %v", err)
+	}
+	source, err := generateGo(doc, "users")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/users\n\ngo 1.23\n\nrequire github.com/rluders/httpsuite/v3 v3.0.0\n\nreplace github.com/rluders/httpsuite/v3 => " + root + "\n",
+		"client.go":      string(source),
+		"client_test.go": generatedClientTest,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, goTool, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod", "GOPROXY=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated client tests failed: %v\n%s", err, output)
+	}
+}
+
+const generatedClientTest = `package users
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+func TestClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /orgs/{org}/users", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("dry_run") != "true" || r.Header.Get("X-Trace-Id") != "" {
+			t.Errorf("unexpected parameters %s %v", r.URL.RawQuery, r.Header)
+		}
+		req, err := httpsuite.ParseRequest[CreateUserRequest](w, r, nil, nil)
+		if err != nil {
+			return
+		}
+		if req.Name == "" {
+			httpsuite.ProblemResponse(w, httpsuite.NewProblemDetails(http.StatusConflict, "/errors/conflict", "Conflict", "user exists"))
+			return
+		}
+		httpsuite.Created(w, User{ID: 7, Name: req.Name + "@" + r.PathValue("org")}, "")
+	})
+	mux.HandleFunc("DELETE /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL, server.Client())
+	ctx := context.Background()
+	dryRun := true
+
+	created, problem, err := client.CreateUser(ctx, "acme", CreateUserParams{DryRun: &dryRun}, CreateUserRequest{Name: "ada"})
+	if err != nil || problem != nil {
+		t.Fatalf("unexpected failure %v %v", problem, err)
+	}
+	if created.ID != 7 || created.Name != "ada@acme" {
+		t.Fatalf("unexpected user %#v", created)
+	}
+
+	_, problem, err = client.CreateUser(ctx, "acme", CreateUserParams{DryRun: &dryRun}, CreateUserRequest{})
+	if err != nil || problem == nil || problem.Status != http.StatusConflict || problem.Detail != "user exists" {
+		t.Fatalf("expected conflict problem, got %v %v", problem, err)
+	}
+
+	if _, problem, err := client.DeleteUsersID(ctx, "7"); err != nil || problem != nil {
+		t.Fatalf("unexpected delete failure %v %v", problem, err)
+	}
+}
+`
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// componentPrefix prefixes local references to component schemas.
+const componentPrefix = "#/components/schemas/"
+
+// document is the part of an OpenAPI document the generators use.
+type document struct {
+	schemas    map[string]map[string]any
+	operations []operation
+}
+
+type operation struct {
+	name         string
+	method       string
+	path         string
+	summary      string
+	params       []parameter
+	body         map[string]any
+	bodyRequired bool
+	// response is the schema of the returned data, nil for empty responses.
+	response map[string]any
+	// envelope reports whether responses wrap the data as {"data": ...}.
+	envelope bool
+}
+
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	schema   map[string]any
+}
+
+var specMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// parseDocument reads the paths and component schemas of an OpenAPI
+// document, ordering operations by path and method for stable output.
+func parseDocument(data []byte) (*document, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse OpenAPI document: %w", err)
+	}
+	if _, ok := raw["openapi"].(string); !ok {
+		return nil, fmt.Errorf("parse OpenAPI document: missing openapi version")
+	}
+
+	doc := &document{schemas: make(map[string]map[string]any)}
+	components, _ := raw["components"].(map[string]any)
+	schemas, _ := components["schemas"].(map[string]any)
+	for name, schema := range schemas {
+		if schema, ok := schema.(map[string]any); ok {
+			doc.schemas[name] = schema
+		}
+	}
+
+	paths, _ := raw["paths"].(map[string]any)
+	templates := make([]string, 0, len(paths))
+	for template := range paths {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+
+	names := make(map[string]bool)
+	for _, template := range templates {
+		item, _ := paths[template].(map[string]any)
+		shared, _ := item["parameters"].([]any)
+		for _, method := range specMethods {
+			node, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			op, err := parseOperation(strings.ToUpper(method), template, node, shared)
+			if err != nil {
+				return nil, err
+			}
+			if names[op.name] {
+				return nil, fmt.Errorf("duplicate operation name %s for %s %s", op.name, op.method, op.path)
+			}
+			names[op.name] = true
+			doc.operations = append(doc.operations, op)
+		}
+	}
+	return doc, nil
+}
+
+func parseOperation(method, template string, node map[string]any, shared []any) (operation, error) {
+	id, _ := node["operationId"].(string)
+	if id == "" {
+		id = strings.ToLower(method) + " " + template
+	}
+	op := operation{name: exportedName(id), method: method, path: template}
+	op.summary, _ = node["summary"].(string)
+
+	own, _ := node["parameters"].([]any)
+	seen := make(map[string]bool)
+	for _, list := range [][]any{own, shared} {
+		for _, item := range list {
+			item, _ := item.(map[string]any)
+			param := parameter{}
+			param.name, _ = item["name"].(string)
+			param.in, _ = item["in"].(string)
+			param.required = item["required"] == true || param.in == "path"
+			param.schema, _ = item["schema"].(map[string]any)
+			key := param.in + ":" + param.name
+			if param.name == "" || seen[key] || (param.in != "path" && param.in != "query" && param.in != "header") {
+				continue
+			}
+			seen[key] = true
+			op.params = append(op.params, param)
+		}
+	}
+	for _, segment := range pathSegments(template) {
+		if segment.param && !seen["path:"+segment.text] {
+			op.params = append(op.params, parameter{name: segment.text, in: "path", required: true, schema: map[string]any{"type": "string"}})
+		}
+	}
+
+	if body, ok := node["requestBody"].(map[string]any); ok {
+		op.body = jsonContentSchema(body)
+		op.bodyRequired = body["required"] == true
+	}
+
+	responses, _ := node["responses"].(map[string]any)
+	if response := successResponse(responses); response != nil {
+		if schema := jsonContentSchema(response); schema != nil {
+			op.response = schema
+			if data, ok := envelopeData(schema); ok {
+				op.response = data
+				op.envelope = true
+			}
+		}
+	}
+	return op, nil
+}
+
+// successResponse returns the lowest documented 2xx response.
+func successResponse(responses map[string]any) map[string]any {
+	for status := http.StatusOK; status < 300; status++ {
+		if response, ok := responses[fmt.Sprint(status)].(map[string]any); ok {
+			return response
+		}
+	}
+	response, _ := responses["2XX"].(map[string]any)
+	return response
+}
+
+func jsonContentSchema(node map[string]any) map[string]any {
+	content, _ := node["content"].(map[string]any)
+	for mediaType, media := range content {
+		if !strings.HasSuffix(strings.Split(mediaType, ";")[0], "json") {
+			continue
+		}
+		media, _ := media.(map[string]any)
+		if schema, ok := media["schema"].(map[string]any); ok {
+			return schema
+		}
+		return map[string]any{}
+	}
+	return nil
+}
+
+// envelopeData detects the {"data": T, "meta": ...} envelope written by
+// httpsuite responses and returns the schema of T.
+func envelopeData(schema map[string]any) (map[string]any, bool) {
+	properties, _ := schema["properties"].(map[string]any)
+	data, ok := properties["data"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	for name := range properties {
+		if name != "data" && name != "meta" {
+			return nil, false
+		}
+	}
+	return data, true
+}
+
+// refName returns the component referenced by schema, or "".
+func refName(schema map[string]any) string {
+	ref, _ := schema["$ref"].(string)
+	return strings.TrimPrefix(ref, componentPrefix)
+}
+
+// nonNull returns the schema without its null alternative and whether it
+// allowed null.
+func nonNull(schema map[string]any) (map[string]any, bool) {
+	if types, ok := schema["type"].([]any); ok {
+		var kept []any
+		for _, kind := range types {
+			if kind != "null" {
+				kept = append(kept, kind)
+			}
+		}
+		if len(kept) == len(types) {
+			return schema, false
+		}
+		clone := make(map[string]any, len(schema))
+		for key, value := range schema {
+			clone[key] = value
+		}
+		if len(kept) == 1 {
+			clone["type"] = kept[0]
+		} else {
+			clone["type"] = kept
+		}
+		return clone, true
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok && len(anyOf) == 2 {
+		for i, candidate := range anyOf {
+			if candidate, ok := candidate.(map[string]any); ok && candidate["type"] == "null" {
+				other, _ := anyOf[1-i].(map[string]any)
+				return other, true
+			}
+		}
+	}
+	if schema["nullable"] == true {
+		return schema, true
+	}
+	return schema, false
+}
+
+func schemaType(schema map[string]any) string {
+	kind, _ := schema["type"].(string)
+	if kind == "" {
+		if _, ok := schema["properties"]; ok {
+			return "object"
+		}
+	}
+	return kind
+}
+
+func requiredSet(schema map[string]any) map[string]bool {
+	required := make(map[string]bool)
+	list, _ := schema["required"].([]any)
+	for _, name := range list {
+		if name, ok := name.(string); ok {
+			required[name] = true
+		}
+	}
+	return required
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type segment struct {
+	text  string
+	param bool
+}
+
+// pathSegments splits a path template into literal text and parameters.
+func pathSegments(template string) []segment {
+	var segments []segment
+	for template != "" {
+		start := strings.Index(template, "{")
+		end := strings.Index(template, "}")
+		if start < 0 || end < start {
+			segments = append(segments, segment{text: template})
+			break
+		}
+		if start > 0 {
+			segments = append(segments, segment{text: template[:start]})
+		}
+		segments = append(segments, segment{text: template[start+1 : end], param: true})
+		template = template[end+1:]
+	}
+	return segments
+}
+
+var initialisms = map[string]string{
+	"api": "API", "http": "HTTP", "id": "ID", "ip": "IP", "json": "JSON",
+	"uri": "URI", "url": "URL", "uuid": "UUID", "xml": "XML",
+}
+
+// exportedName converts identifiers such as "post_orgs_org_users",
+// "created_at", or "getUser" into Go names.
+func exportedName(value string) string {
+	var name strings.Builder
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if initialism, ok := initialisms[strings.ToLower(part)]; ok {
+			name.WriteString(initialism)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		name.WriteString(string(runes))
+	}
+	result := name.String()
+	if result == "" || unicode.IsDigit([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
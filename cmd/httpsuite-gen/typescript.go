@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tsGenerator struct {
+	decls    []string
+	declared map[string]bool
+}
+
+// generateTypeScript renders interfaces for the component schemas and the
+// request, response, and parameter types of each operation.
+func generateTypeScript(doc *document) []byte {
+	g := &tsGenerator{declared: make(map[string]bool)}
+	for _, name := range sortedKeys(doc.schemas) {
+		g.declare(exportedName(name), doc.schemas[name])
+	}
+	for _, op := range doc.operations {
+		if op.body != nil {
+			g.alias(op.name+"Request", op.body)
+		}
+		if op.response != nil {
+			g.alias(op.name+"Response", op.response)
+		}
+		var params []string
+		for _, param := range op.params {
+			optional := "?"
+			if param.required {
+				optional = ""
+			}
+			params = append(params, fmt.Sprintf("  %s%s: %s;\n", tsProperty(param.name), optional, g.typeExpr(param.schema, op.name+exportedName(param.name))))
+		}
+		if len(params) > 0 {
+			g.decls = append(g.decls, "export interface "+op.name+"Params {\n"+strings.Join(params, "")+"}\n")
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString("// Code generated by httpsuite-gen. DO NOT EDIT.\n")
+	for _, decl := range g.decls {
+		out.WriteString("\n" + decl)
+	}
+	return []byte(out.String())
+}
+
+// alias names an operation type, reusing referenced components.
+func (g *tsGenerator) alias(name string, schema map[string]any) {
+	if _, ok := schema["properties"]; ok && refName(schema) == "" {
+		g.declare(name, schema)
+		return
+	}
+	g.decls = append(g.decls, fmt.Sprintf("export type %s = %s;\n", name, g.typeExpr(schema, name)))
+}
+
+func (g *tsGenerator) declare(name string, schema map[string]any) string {
+	if g.declared[name] {
+		return name
+	}
+	g.declared[name] = true
+
+	properties, isObject := schema["properties"].(map[string]any)
+	if !isObject {
+		g.decls = append(g.decls, fmt.Sprintf("export type %s = %s;\n", name, g.typeExpr(schema, name+"Value")))
+		return name
+	}
+
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+	required := requiredSet(schema)
+	var decl strings.Builder
+	if description, ok := schema["description"].(string); ok {
+		decl.WriteString("/** " + description + " */\n")
+	}
+	fmt.Fprintf(&decl, "export interface %s {\n", name)
+	for _, property := range sortedKeys(properties) {
+		fieldSchema, _ := properties[property].(map[string]any)
+		if description, ok := fieldSchema["description"].(string); ok {
+			decl.WriteString("  /** " + description + " */\n")
+		}
+		optional := "?"
+		if required[property] {
+			optional = ""
+		}
+		fmt.Fprintf(&decl, "  %s%s: %s;\n", tsProperty(property), optional, g.typeExpr(fieldSchema, name+exportedName(property)))
+	}
+	if schema["additionalProperties"] == true {
+		decl.WriteString("  [key: string]: unknown;\n")
+	}
+	decl.WriteString("}\n")
+	g.decls[index] = decl.String()
+	return name
+}
+
+func (g *tsGenerator) typeExpr(schema map[string]any, hint string) string {
+	if schema == nil {
+		return "unknown"
+	}
+	schema, nullable := nonNull(schema)
+	expr := g.baseTypeExpr(schema, hint)
+	if nullable {
+		return expr + " | null"
+	}
+	return expr
+}
+
+func (g *tsGenerator) baseTypeExpr(schema map[string]any, hint string) string {
+	if ref := refName(schema); ref != "" {
+		return exportedName(ref)
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		values := make([]string, len(enum))
+		for i, value := range enum {
+			values[i] = tsLiteral(value)
+		}
+		return strings.Join(values, " | ")
+	}
+	switch schemaType(schema) {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		item := g.typeExpr(items, hint+"Item")
+		if strings.Contains(item, " ") {
+			return "Array<" + item + ">"
+		}
+		return item + "[]"
+	case "object":
+		if _, ok := schema["properties"]; ok {
+			return g.declare(hint, schema)
+		}
+		if values, ok := schema["additionalProperties"].(map[string]any); ok {
+			return "Record<string, " + g.typeExpr(values, hint+"Value") + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+func tsProperty(name string) string {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9')) {
+			return strconv.Quote(name)
+		}
+	}
+	return name
+}
+
+func tsLiteral(value any) string {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,45 @@
+package httpsuite
+
+import "context"
+
+// ContextKey is a typed context key. Keys are compared by identity, so two
+// keys never collide even when they share a name.
+type ContextKey[T any] struct {
+	name string
+}
+
+// NewContextKey returns a new key for values of type T. The name is only used
+// for debugging.
+func NewContextKey[T any](name string) *ContextKey[T] {
+	return &ContextKey[T]{name: name}
+}
+
+// String returns the key name.
+func (k *ContextKey[T]) String() string {
+	return "httpsuite context key " + k.name
+}
+
+// Keys for the request-scoped values the suite reads and writes. Integrations
+// such as authentication or routing adapters should set them so every
+// httpsuite feature sees the same values.
+var (
+	RequestIDKey         = NewContextKey[string]("request-id")
+	PrincipalKey         = NewContextKey[string]("principal")
+	ScopesKey            = NewContextKey[[]string]("scopes")
+	TenantKey            = NewContextKey[Tenant]("tenant")
+	LocaleKey            = NewContextKey[string]("locale")
+	RoutePatternKey      = NewContextKey[string]("route-pattern")
+	ClientIPKey          = NewContextKey[string]("client-ip")
+	ClientCertificateKey = NewContextKey[ClientCertificate]("client-certificate")
+)
+
+// CtxSet returns a copy of ctx carrying value under key.
+func CtxSet[T any](ctx context.Context, key *ContextKey[T], value T) context.Context {
+	return context.WithValue(ctx, key, value)
+}
+
+// CtxGet returns the value stored under key and whether it was present.
+func CtxGet[T any](ctx context.Context, key *ContextKey[T]) (T, bool) {
+	value, ok := ctx.Value(key).(T)
+	return value, ok
+}
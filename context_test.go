@@ -0,0 +1,66 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTypedContextKeys(t *testing.T) {
+	t.Parallel()
+
+	first := NewContextKey[string]("name")
+	second := NewContextKey[string]("name")
+	count := NewContextKey[int]("count")
+
+	ctx := CtxSet(context.Background(), first, "alice")
+	ctx = CtxSet(ctx, count, 3)
+
+	if value, ok := CtxGet(ctx, first); !ok || value != "alice" {
+		t.Fatalf("expected alice, got %q (%v)", value, ok)
+	}
+	if _, ok := CtxGet(ctx, second); ok {
+		t.Fatal("expected keys with the same name not to collide")
+	}
+	if value, ok := CtxGet(ctx, count); !ok || value != 3 {
+		t.Fatalf("expected 3, got %d (%v)", value, ok)
+	}
+	if first.String() != "httpsuite context key name" {
+		t.Fatalf("unexpected key name %q", first.String())
+	}
+}
+
+func TestSuiteReadsTypedContextValues(t *testing.T) {
+	t.Parallel()
+
+	var entry AuditEntry
+	auditor := AuditorFunc(func(_ context.Context, e AuditEntry) { entry = e })
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ParseRequest[*testRequest](w, r, testParamExtractor, nil, "id")
+	})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithProblemConfig(r.Context(), ProblemConfig{Instance: DefaultInstance})
+		ctx = CtxSet(ctx, RequestIDKey, "ctx-id")
+		ctx = CtxSet(ctx, PrincipalKey, "user-1")
+		ctx = CtxSet(ctx, RoutePatternKey, "/items/{id}")
+		Audit(auditor, nil)(inner).ServeHTTP(w, r.WithContext(ctx))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/abc", nil)
+	req.Header.Set("X-Request-ID", "header-id")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var problem ProblemDetails
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Instance != "/items/abc#ctx-id" {
+		t.Fatalf("expected context request ID in instance, got %q", problem.Instance)
+	}
+	if entry.Principal != "user-1" || entry.Route != "/items/{id}" {
+		t.Fatalf("unexpected audit entry %#v", entry)
+	}
+}
@@ -0,0 +1,488 @@
+// Package contracttest checks requests and responses recorded in tests against
+// an OpenAPI document, so tests fail when handlers drift from the published
+// contract: undocumented routes or statuses, unexpected content types, and
+// bodies or parameters that do not match their schemas.
+package contracttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+const operationNotDocumented = "operation is not documented"
+
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Contract is a parsed OpenAPI document.
+type Contract struct {
+	schema     *httpsuite.JSONSchema
+	operations []operation
+}
+
+type operation struct {
+	method   string
+	template string
+	segments []string
+	literals int
+	pointer  string
+	node     map[string]any
+	params   []parameter
+}
+
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	node     map[string]any
+	pointer  string
+}
+
+// Violation is a difference between recorded traffic and the contract.
+type Violation struct {
+	Method  string
+	Path    string
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return v.Method + " " + v.Path + ": " + v.Message
+}
+
+// TB is the part of testing.TB used to report violations.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Load parses an OpenAPI 3.x document in JSON.
+func Load(document []byte) (*Contract, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("parse OpenAPI document: %w", err)
+	}
+	schema, err := httpsuite.CompileJSONSchema(document)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := &Contract{schema: schema}
+	paths, _ := doc["paths"].(map[string]any)
+	for template, item := range paths {
+		item, _ := item.(map[string]any)
+		itemPointer := "#/paths/" + escapePointer(template)
+		shared := collectParameters(item, itemPointer)
+		for _, method := range operationMethods {
+			node, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			pointer := itemPointer + "/" + method
+			op := operation{
+				method:   strings.ToUpper(method),
+				template: template,
+				segments: strings.Split(strings.Trim(template, "/"), "/"),
+				pointer:  pointer,
+				node:     node,
+				params:   mergeParameters(shared, collectParameters(node, pointer)),
+			}
+			for _, segment := range op.segments {
+				if !isTemplateSegment(segment) {
+					op.literals++
+				}
+			}
+			contract.operations = append(contract.operations, op)
+		}
+	}
+	// Prefer the most specific template when several match a path.
+	sort.SliceStable(contract.operations, func(i, j int) bool {
+		return contract.operations[i].literals > contract.operations[j].literals
+	})
+	return contract, nil
+}
+
+// FromAPI builds a contract from the document generated by api.
+func FromAPI(api *httpsuite.API) (*Contract, error) {
+	document, err := json.Marshal(api.OpenAPI())
+	if err != nil {
+		return nil, err
+	}
+	return Load(document)
+}
+
+// CheckRequest verifies that r and its body are documented: the operation
+// exists, required parameters are present, parameter values match their
+// schemas, and the body matches the request body schema.
+func (c *Contract) CheckRequest(r *http.Request, body []byte) error {
+	op, pathValues, err := c.find(r)
+	if err != nil {
+		return err
+	}
+	report := newReporter(r)
+
+	for _, param := range op.params {
+		values, present := paramValues(r, param, pathValues)
+		if !present {
+			if param.required {
+				report.add("missing required %s parameter %q", param.in, param.name)
+			}
+			continue
+		}
+		for _, value := range values {
+			c.checkValue(report, param.pointer, coerceParam(param.node, value), param.in+" parameter "+strconv.Quote(param.name))
+		}
+	}
+
+	requestBody, documented := op.node["requestBody"].(map[string]any)
+	switch {
+	case !documented && len(body) > 0:
+		report.add("request body is not documented")
+	case documented && len(body) == 0:
+		if requestBody["required"] == true {
+			report.add("request body is required")
+		}
+	case documented:
+		content, _ := requestBody["content"].(map[string]any)
+		c.checkContent(report, content, op.pointer+"/requestBody/content", r.Header.Get("Content-Type"), body, "request body")
+	}
+	return report.err()
+}
+
+// CheckResponse verifies that the status is documented for the operation
+// and that the content type and body match the documented response.
+func (c *Contract) CheckResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	op, _, err := c.find(r)
+	if err != nil {
+		return err
+	}
+	report := newReporter(r)
+
+	responses, _ := op.node["responses"].(map[string]any)
+	key := strconv.Itoa(status)
+	if _, ok := responses[key]; !ok {
+		key = strconv.Itoa(status/100) + "XX"
+		if _, ok := responses[key]; !ok {
+			key = "default"
+		}
+	}
+	response, ok := responses[key].(map[string]any)
+	if !ok {
+		report.add("status %d is not documented", status)
+		return report.err()
+	}
+
+	content, _ := response["content"].(map[string]any)
+	if len(content) == 0 {
+		if len(bytes.TrimSpace(body)) > 0 && r.Method != http.MethodHead {
+			report.add("response body for status %d is not documented", status)
+		}
+		return report.err()
+	}
+	if r.Method == http.MethodHead {
+		return report.err()
+	}
+	pointer := op.pointer + "/responses/" + escapePointer(key) + "/content"
+	c.checkContent(report, content, pointer, header.Get("Content-Type"), body, "response body")
+	return report.err()
+}
+
+// Check verifies a request and its recorded response. The request body is
+// read from r.Body when it has not been consumed yet. Requests answered with
+// a 4xx status are expected to break the contract, so only their responses
+// are checked.
+func (c *Contract) Check(r *http.Request, status int, header http.Header, responseBody []byte) error {
+	var requestBody []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		requestBody, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+	return c.check(r, requestBody, status, header, responseBody)
+}
+
+func (c *Contract) check(r *http.Request, requestBody []byte, status int, header http.Header, responseBody []byte) error {
+	responseErr := c.CheckResponse(r, status, header, responseBody)
+	if status >= 400 && status < 500 {
+		return responseErr
+	}
+	requestErr := c.CheckRequest(r, requestBody)
+	var violation *Violation
+	if errors.As(requestErr, &violation) && violation.Message == operationNotDocumented {
+		// Both checks report the missing operation.
+		return requestErr
+	}
+	return errors.Join(requestErr, responseErr)
+}
+
+// Middleware checks every request and response passing through next and
+// reports violations to t, following the rules of Check. Wrap the handler under test, or the handler of an
+// httptest.Server, to verify all traffic of a test.
+func (c *Contract) Middleware(t TB) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Helper()
+			var requestBody []byte
+			if r.Body != nil && r.Body != http.NoBody {
+				requestBody, _ = io.ReadAll(r.Body)
+				r.Body = io.NopCloser(bytes.NewReader(requestBody))
+			}
+			recorder := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if err := c.check(r, requestBody, status, w.Header(), recorder.body.Bytes()); err != nil {
+				t.Errorf("contract violation:\n%v", err)
+			}
+		})
+	}
+}
+
+func (c *Contract) find(r *http.Request) (operation, map[string]string, error) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for _, op := range c.operations {
+		if op.method != r.Method || len(op.segments) != len(segments) {
+			continue
+		}
+		values := make(map[string]string)
+		matched := true
+		for i, segment := range op.segments {
+			if isTemplateSegment(segment) {
+				if segments[i] == "" {
+					matched = false
+					break
+				}
+				values[strings.Trim(segment, "{}")] = segments[i]
+				continue
+			}
+			if segment != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return op, values, nil
+		}
+	}
+	return operation{}, nil, &Violation{Method: r.Method, Path: r.URL.Path, Message: operationNotDocumented}
+}
+
+func (c *Contract) checkContent(report *reporter, content map[string]any, pointer, contentType string, body []byte, subject string) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	key, ok := matchMediaType(content, mediaType)
+	if !ok {
+		documented := make([]string, 0, len(content))
+		for candidate := range content {
+			documented = append(documented, candidate)
+		}
+		sort.Strings(documented)
+		report.add("%s content type %q is not documented (want %s)", subject, contentType, strings.Join(documented, ", "))
+		return
+	}
+	media, _ := content[key].(map[string]any)
+	if _, hasSchema := media["schema"]; !hasSchema || !strings.HasSuffix(mediaType, "json") {
+		return
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		report.add("%s is not valid JSON: %v", subject, err)
+		return
+	}
+	c.checkValue(report, pointer+"/"+escapePointer(key)+"/schema", value, subject)
+}
+
+func (c *Contract) checkValue(report *reporter, pointer string, value any, subject string) {
+	schema, err := c.schema.Resolve(pointer)
+	if err != nil {
+		return
+	}
+	var details httpsuite.ValidationErrors
+	if !errors.As(schema.ValidateValue(value), &details) {
+		return
+	}
+	for _, detail := range details {
+		location := detail.Pointer
+		if location == "" {
+			location = "/"
+		}
+		report.add("%s %s: %s", subject, location, detail.Message)
+	}
+}
+
+func matchMediaType(content map[string]any, mediaType string) (string, bool) {
+	if _, ok := content[mediaType]; ok {
+		return mediaType, true
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, candidate := range []string{major + "/*", "*/*"} {
+		if _, ok := content[candidate]; ok {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func collectParameters(node map[string]any, pointer string) []parameter {
+	list, _ := node["parameters"].([]any)
+	params := make([]parameter, 0, len(list))
+	for i, item := range list {
+		item, _ := item.(map[string]any)
+		name, _ := item["name"].(string)
+		in, _ := item["in"].(string)
+		schema, _ := item["schema"].(map[string]any)
+		params = append(params, parameter{
+			name:     name,
+			in:       in,
+			required: item["required"] == true || in == "path",
+			node:     schema,
+			pointer:  pointer + "/parameters/" + strconv.Itoa(i) + "/schema",
+		})
+	}
+	return params
+}
+
+// mergeParameters lets operation parameters override path item parameters
+// with the same name and location.
+func mergeParameters(shared, own []parameter) []parameter {
+	merged := append([]parameter(nil), own...)
+	for _, param := range shared {
+		overridden := false
+		for _, candidate := range own {
+			if candidate.name == param.name && candidate.in == param.in {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, param)
+		}
+	}
+	return merged
+}
+
+func paramValues(r *http.Request, param parameter, pathValues map[string]string) ([]string, bool) {
+	switch param.in {
+	case "path":
+		value, ok := pathValues[param.name]
+		return []string{value}, ok
+	case "query":
+		values, ok := r.URL.Query()[param.name]
+		return values, ok
+	case "header":
+		values := r.Header.Values(param.name)
+		return values, len(values) > 0
+	case "cookie":
+		cookie, err := r.Cookie(param.name)
+		if err != nil {
+			return nil, false
+		}
+		return []string{cookie.Value}, true
+	}
+	return nil, false
+}
+
+// coerceParam converts a raw parameter to the JSON value its schema expects,
+// keeping the string when it does not parse so the schema reports it.
+func coerceParam(schema map[string]any, raw string) any {
+	kind, _ := schema["type"].(string)
+	if kinds, ok := schema["type"].([]any); ok {
+		for _, candidate := range kinds {
+			if candidate != "null" {
+				kind, _ = candidate.(string)
+				break
+			}
+		}
+	}
+	switch kind {
+	case "integer", "number":
+		if number, err := strconv.ParseFloat(raw, 64); err == nil {
+			return number
+		}
+	case "boolean":
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		var values []any
+		for _, item := range strings.Split(raw, ",") {
+			values = append(values, coerceParam(items, item))
+		}
+		return values
+	}
+	return raw
+}
+
+func isTemplateSegment(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// reporter collects violations for one request.
+type reporter struct {
+	method     string
+	path       string
+	violations []error
+}
+
+func newReporter(r *http.Request) *reporter {
+	return &reporter{method: r.Method, path: r.URL.Path}
+}
+
+func (r *reporter) add(format string, args ...any) {
+	r.violations = append(r.violations, &Violation{Method: r.method, Path: r.path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *reporter) err() error {
+	return errors.Join(r.violations...)
+}
+
+// responseRecorder copies the response body while passing it through.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.status == 0 && (code >= 200 || code == http.StatusSwitchingProtocols) {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,283 @@
+package contracttest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+type createItemRequest struct {
+	ID      int    `json:"-"`
+	Name    string `json:"name" validate:"required"`
+	Notify  bool   `json:"-" query:"notify"`
+	Channel string `json:"-" query:"channel" validate:"omitempty,oneof=email sms"`
+}
+
+func (r *createItemRequest) SetParam(fieldName, value string) error {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s", fieldName)
+	}
+	r.ID = id
+	return nil
+}
+
+type item struct {
+	ID   int     `json:"id"`
+	Name string  `json:"name"`
+	Note *string `json:"note"`
+}
+
+func pathParam(r *http.Request, key string) string {
+	return r.PathValue(key)
+}
+
+func newTestAPI(t *testing.T, status int) (*Contract, http.Handler) {
+	t.Helper()
+	api := httpsuite.NewAPI(httpsuite.APIInfo{Title: "Items", Version: "1.0.0", ParamExtractor: pathParam})
+	mux := http.NewServeMux()
+	mux.Handle("PUT /items/{id}", httpsuite.Handle(api, http.MethodPut, "/items/{id}",
+		func(ctx context.Context, req *createItemRequest) (item, error) {
+			if req.Name == "missing" {
+				return item{}, httpsuite.NewNotFoundProblem("item not found")
+			}
+			return item{ID: req.ID, Name: req.Name}, nil
+		}, &httpsuite.RouteOptions{Errors: []int{http.StatusNotFound}}))
+	mux.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"data":{"id":"7","name":"lamp"}}`))
+	})
+	httpsuite.Describe[httpsuite.NoBody, item](api, http.MethodGet, "/items/{id}", nil)
+
+	contract, err := FromAPI(api)
+	if err != nil {
+		t.Fatalf("build contract: %v", err)
+	}
+	return contract, mux
+}
+
+type recordingTB struct {
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		status  int
+		wantErr []string
+	}{
+		{name: "conforming", method: http.MethodPut, path: "/items/7?notify=true", body: `{"name":"lamp"}`},
+		{name: "documented problem", method: http.MethodPut, path: "/items/7", body: `{"name":"missing"}`},
+		{name: "validation problem", method: http.MethodPut, path: "/items/7", body: `{"name":1}`},
+		// ParseRequest answers 400, and 4xx replies only have their
+		// responses checked; TestCheck covers the request violation.
+		{name: "invalid query parameter rejected", method: http.MethodPut, path: "/items/7?notify=maybe", body: `{"name":"lamp"}`},
+		{
+			name: "response schema drift", method: http.MethodGet, path: "/items/7", status: http.StatusOK,
+			wantErr: []string{"response body /data/id: must be of type integer"},
+		},
+		{
+			name: "undocumented status", method: http.MethodGet, path: "/items/7", status: http.StatusTeapot,
+			wantErr: []string{"status 418 is not documented"},
+		},
+		{
+			name: "undocumented operation", method: http.MethodDelete, path: "/items/7",
+			wantErr: []string{"DELETE /items/7: operation is not documented"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contract, handler := newTestAPI(t, tt.status)
+			recorder := &recordingTB{}
+			handler = contract.Middleware(recorder)(handler)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if len(tt.wantErr) == 0 {
+				if len(recorder.errors) > 0 {
+					t.Fatalf("expected no violations, got %v", recorder.errors)
+				}
+				return
+			}
+			if len(recorder.errors) != 1 {
+				t.Fatalf("expected one report, got %v", recorder.errors)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(recorder.errors[0], want) {
+					t.Fatalf("expected report to contain %q, got %s", want, recorder.errors[0])
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewareQueryEnum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{name: "documented value", query: "?channel=sms"},
+		{name: "undocumented value", query: "?channel=pager", wantErr: `query parameter "channel" /: must be one of`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contract, handler := newTestAPI(t, http.StatusOK)
+			recorder := &recordingTB{}
+			handler = contract.Middleware(recorder)(handler)
+
+			req := httptest.NewRequest(http.MethodPut, "/items/7"+tt.query, strings.NewReader(`{"name":"lamp"}`))
+			req.Header.Set("Content-Type", "application/json")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.wantErr == "" {
+				if len(recorder.errors) > 0 {
+					t.Fatalf("expected no violations, got %v", recorder.errors)
+				}
+				return
+			}
+			if len(recorder.errors) != 1 || !strings.Contains(recorder.errors[0], tt.wantErr) {
+				t.Fatalf("expected a report containing %q, got %v", tt.wantErr, recorder.errors)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	contract, _ := newTestAPI(t, http.StatusOK)
+
+	tests := []struct {
+		name        string
+		query       string
+		body        string
+		contentType string
+		status      int
+		respType    string
+		respBody    string
+		want        []string
+	}{
+		{
+			name: "conforming", body: `{"name":"lamp"}`, contentType: "application/json",
+			status: http.StatusOK, respType: "application/json; charset=utf-8", respBody: `{"data":{"id":7,"name":"lamp","note":null}}`,
+		},
+		{
+			name: "missing body", status: http.StatusOK, respType: "application/json", respBody: `{"data":{"id":7,"name":"lamp","note":"x"}}`,
+			want: []string{"request body is required"},
+		},
+		{
+			name: "wrong content types", body: `name=lamp`, contentType: "application/x-www-form-urlencoded",
+			status: http.StatusOK, respType: "text/plain", respBody: `ok`,
+			want: []string{
+				`request body content type "application/x-www-form-urlencoded" is not documented (want application/json)`,
+				`response body content type "text/plain" is not documented (want application/json)`,
+			},
+		},
+		{
+			name: "invalid request accepted", body: `{}`, contentType: "application/json",
+			status: http.StatusOK, respType: "application/json", respBody: `{"data":{"id":7,"name":"","note":null}}`,
+			want: []string{"request body /name: is required"},
+		},
+		{
+			name: "invalid query parameter", query: "?notify=maybe", body: `{"name":"lamp"}`, contentType: "application/json",
+			status: http.StatusOK, respType: "application/json", respBody: `{"data":{"id":7,"name":"lamp","note":null}}`,
+			want: []string{`query parameter "notify" /: must be of type boolean`},
+		},
+		{
+			name: "invalid problem", body: `{}`, contentType: "application/json",
+			status: http.StatusNotFound, respType: "application/problem+json", respBody: `{"title":"Not Found"}`,
+			want: []string{"response body /status: is required", "response body /type: is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/items/7"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			err := contract.Check(req, tt.status, http.Header{"Content-Type": {tt.respType}}, []byte(tt.respBody))
+
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("expected no violations, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected violations %v", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("expected %q in %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	contract, err := Load([]byte(`{
+		"openapi": "3.1.0",
+		"paths": {
+			"/users/{id}": {
+				"parameters": [{"name": "id", "in": "path", "schema": {"type": "integer"}}],
+				"get": {
+					"parameters": [{"name": "X-Tenant", "in": "header", "required": true, "schema": {"type": "string"}}],
+					"responses": {"2XX": {"description": "ok"}, "default": {"description": "error", "content": {"application/*": {}}}}
+				}
+			},
+			"/users/me": {"get": {"responses": {"204": {"description": "ok"}}}}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users/abc", nil)
+	err = contract.Check(req, http.StatusNoContent, http.Header{}, nil)
+	if err == nil || !strings.Contains(err.Error(), `path parameter "id" /: must be of type integer`) ||
+		!strings.Contains(err.Error(), `missing required header parameter "X-Tenant"`) {
+		t.Fatalf("expected parameter violations, got %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Tenant", "acme")
+	if err := contract.Check(req, http.StatusInternalServerError, http.Header{"Content-Type": {"application/xml"}}, []byte("<error/>")); err != nil {
+		t.Fatalf("expected default response to match, got %v", err)
+	}
+	if err := contract.Check(httptest.NewRequest(http.MethodGet, "/users/me", nil), http.StatusNoContent, http.Header{}, nil); err != nil {
+		t.Fatalf("expected literal path to win, got %v", err)
+	}
+
+	if _, err := Load([]byte(`{`)); err == nil {
+		t.Fatal("expected malformed document to fail")
+	}
+}
@@ -0,0 +1,172 @@
+package httpsuite
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultCostWindow = time.Minute
+
+// ErrCostBudgetExceeded is returned by CostLimiter.Charge after it replied
+// with 429 Too Many Requests.
+var ErrCostBudgetExceeded = errors.New("cost budget exceeded")
+
+// CostLimiterOptions configures a CostLimiter.
+type CostLimiterOptions struct {
+	// Budget is the cost each principal may spend per Window. It refills
+	// continuously, so a drained budget recovers Budget/Window per second.
+	Budget int64
+	// Window defaults to one minute.
+	Window time.Duration
+	// Principal identifies the caller whose budget is charged. When nil, the
+	// PrincipalKey context value is used, or the client IP without one.
+	Principal func(*http.Request) string
+	Now       func() time.Time
+}
+
+// CostLimiter enforces a per-principal budget on the declared cost of
+// requests, so a batch of a hundred items weighs more than a single read.
+type CostLimiter struct {
+	mu        sync.Mutex
+	options   CostLimiterOptions
+	buckets   map[string]*costBucket
+	lastSweep time.Time
+}
+
+type costBucket struct {
+	remaining float64
+	updated   time.Time
+}
+
+// NewCostLimiter returns a limiter with an in-memory budget per principal.
+func NewCostLimiter(opts *CostLimiterOptions) *CostLimiter {
+	options := CostLimiterOptions{Window: defaultCostWindow, Now: time.Now}
+	if opts != nil {
+		options.Budget = opts.Budget
+		options.Principal = opts.Principal
+		if opts.Window > 0 {
+			options.Window = opts.Window
+		}
+		if opts.Now != nil {
+			options.Now = opts.Now
+		}
+	}
+	return &CostLimiter{options: options, buckets: make(map[string]*costBucket)}
+}
+
+// Cost returns middleware that charges every request a fixed cost before it
+// reaches the handler.
+func (l *CostLimiter) Cost(cost int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := l.Charge(w, r, cost); err != nil {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Charge spends cost from the budget of the caller of r. Handlers call it
+// with a cost computed from the parsed request, such as the batch size. When
+// the budget cannot cover cost, Charge replies with a 429 problem carrying
+// cost and remaining extensions and a Retry-After hint, and returns
+// ErrCostBudgetExceeded.
+func (l *CostLimiter) Charge(w http.ResponseWriter, r *http.Request, cost int64) error {
+	if cost <= 0 {
+		return nil
+	}
+	remaining, retryAfter, ok := l.spend(l.principal(r), cost)
+	if ok {
+		return nil
+	}
+
+	problems := sharedProblemConfig(r.Context())
+	detail := "the request cost exceeds the remaining budget"
+	if cost > l.options.Budget {
+		detail = "the request cost exceeds the total budget"
+	}
+	sendRequestProblem(w, r, http.StatusTooManyRequests, Problem(http.StatusTooManyRequests).
+		Type(problems.TypeURL("too_many_requests_error")).
+		Title("Too Many Requests").
+		Detail(detail).
+		Extension("cost", cost).
+		Extension("remaining", remaining).
+		RetryAfter(retryAfter).
+		Build())
+	return ErrCostBudgetExceeded
+}
+
+// Remaining returns the budget the caller of r has left.
+func (l *CostLimiter) Remaining(r *http.Request) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bucket := l.refill(l.principal(r), l.options.Now())
+	return int64(math.Floor(bucket.remaining))
+}
+
+func (l *CostLimiter) principal(r *http.Request) string {
+	if l.options.Principal != nil {
+		return l.options.Principal(r)
+	}
+	if principal, ok := CtxGet(r.Context(), PrincipalKey); ok && principal != "" {
+		return principal
+	}
+	return ClientIP(r)
+}
+
+// spend deducts cost when the bucket covers it. Otherwise it returns the
+// whole budget left and how long until cost would be covered, or zero when
+// cost exceeds the total budget.
+func (l *CostLimiter) spend(principal string, cost int64) (int64, time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.options.Now()
+	l.sweep(now)
+	bucket := l.refill(principal, now)
+	if bucket.remaining >= float64(cost) {
+		bucket.remaining -= float64(cost)
+		return int64(math.Floor(bucket.remaining)), 0, true
+	}
+
+	remaining := int64(math.Floor(bucket.remaining))
+	if cost > l.options.Budget || l.options.Budget <= 0 {
+		return remaining, 0, false
+	}
+	missing := float64(cost) - bucket.remaining
+	return remaining, time.Duration(missing / float64(l.options.Budget) * float64(l.options.Window)), false
+}
+
+// refill returns the bucket for principal topped up for the time elapsed
+// since it was last used. Callers hold l.mu.
+func (l *CostLimiter) refill(principal string, now time.Time) *costBucket {
+	budget := float64(l.options.Budget)
+	bucket, ok := l.buckets[principal]
+	if !ok {
+		bucket = &costBucket{remaining: budget, updated: now}
+		l.buckets[principal] = bucket
+		return bucket
+	}
+	if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+		bucket.remaining = math.Min(budget, bucket.remaining+budget*float64(elapsed)/float64(l.options.Window))
+		bucket.updated = now
+	}
+	return bucket
+}
+
+// sweep drops buckets that have refilled completely, at most once per
+// window, so idle principals do not accumulate. Callers hold l.mu.
+func (l *CostLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.options.Window {
+		return
+	}
+	l.lastSweep = now
+	for principal, bucket := range l.buckets {
+		if now.Sub(bucket.updated) >= l.options.Window {
+			delete(l.buckets, principal)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCostLimiterChargesPerPrincipal(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	limiter := NewCostLimiter(&CostLimiterOptions{
+		Budget: 10,
+		Window: 10 * time.Second,
+		Now:    func() time.Time { return now },
+	})
+	request := func(principal string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/batch", nil)
+		return r.WithContext(CtxSet(r.Context(), PrincipalKey, principal))
+	}
+
+	if err := limiter.Charge(httptest.NewRecorder(), request("alice"), 8); err != nil {
+		t.Fatalf("expected charge within budget, got %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	err := limiter.Charge(rec, request("alice"), 5)
+	if !errors.Is(err, ErrCostBudgetExceeded) || rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 and ErrCostBudgetExceeded, got %d %v", rec.Code, err)
+	}
+	var problem ProblemDetails
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("expected problem body, got %v", err)
+	}
+	if problem.Extensions["cost"] != float64(5) || problem.Extensions["remaining"] != float64(2) {
+		t.Fatalf("expected cost and remaining extensions, got %v", problem.Extensions)
+	}
+	if rec.Header().Get("Retry-After") != "3" {
+		t.Fatalf("expected Retry-After 3, got %q", rec.Header().Get("Retry-After"))
+	}
+
+	if err := limiter.Charge(httptest.NewRecorder(), request("bob"), 10); err != nil {
+		t.Fatalf("expected separate budget for bob, got %v", err)
+	}
+
+	now = now.Add(3 * time.Second)
+	if got := limiter.Remaining(request("alice")); got != 5 {
+		t.Fatalf("expected budget to refill to 5, got %d", got)
+	}
+	if err := limiter.Charge(httptest.NewRecorder(), request("alice"), 5); err != nil {
+		t.Fatalf("expected charge after refill, got %v", err)
+	}
+}
+
+func TestCostLimiterRejectsCostAboveBudget(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewCostLimiter(&CostLimiterOptions{Budget: 3})
+	rec := httptest.NewRecorder()
+	if err := limiter.Charge(rec, httptest.NewRequest(http.MethodPost, "/", nil), 4); err == nil {
+		t.Fatal("expected cost above budget to be rejected")
+	}
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "" {
+		t.Fatalf("expected 429 without Retry-After, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestCostLimiterMiddleware(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewCostLimiter(&CostLimiterOptions{
+		Budget:    5,
+		Principal: func(r *http.Request) string { return r.Header.Get("X-Key") },
+	})
+	handler := limiter.Cost(2)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	var codes []int
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/report", nil)
+		req.Header.Set("X-Key", "k1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+	if codes[0] != http.StatusNoContent || codes[1] != http.StatusNoContent || codes[2] != http.StatusTooManyRequests {
+		t.Fatalf("expected two requests within budget then 429, got %v", codes)
+	}
+}
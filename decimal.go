@@ -0,0 +1,234 @@
+package httpsuite
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxDecimalExponent bounds exponents accepted by ParseDecimal so inputs
+// such as "1e999999999" cannot allocate huge numbers.
+const maxDecimalExponent = 1000
+
+// ErrInvalidDecimal is returned when a value is not a decimal number.
+var ErrInvalidDecimal = errors.New("invalid decimal")
+
+var bigTen = big.NewInt(10)
+
+// Decimal is an exact base-10 number for amounts that must not pick up
+// float64 rounding errors. It marshals to a JSON string such as "12.30" and
+// accepts strings or JSON numbers when unmarshaling. The zero value is 0.
+// Decimals are immutable; compare them with Cmp or Equal, not ==.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// NewDecimal returns unscaled × 10^-scale, e.g. NewDecimal(1230, 2) is 12.30.
+// A negative scale multiplies by a power of ten.
+func NewDecimal(unscaled int64, scale int) Decimal {
+	value := big.NewInt(unscaled)
+	if scale < 0 {
+		value.Mul(value, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{unscaled: value, scale: scale}
+}
+
+// ParseDecimal parses a number such as "-12.30" or "1.5e3" exactly. The
+// scale of the result follows the digits written, so "12.30" has scale 2.
+func ParseDecimal(s string) (Decimal, error) {
+	text := s
+	exponent := 0
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(text[i+1:])
+		if err != nil || exp > maxDecimalExponent || exp < -maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+		}
+		exponent = exp
+		text = text[:i]
+	}
+
+	sign := ""
+	if text != "" && (text[0] == '-' || text[0] == '+') {
+		sign, text = text[:1], text[1:]
+	}
+	integer, fraction, _ := strings.Cut(text, ".")
+	if integer == "" && fraction == "" || !isDigits(integer) || !isDigits(fraction) {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	unscaled, ok := new(big.Int).SetString(sign+integer+fraction, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	scale := len(fraction) - exponent
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{unscaled: unscaled, scale: scale}, nil
+}
+
+// MustParseDecimal is ParseDecimal for constants; it panics on invalid input.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) value() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+// Sign returns -1, 0, or 1.
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// rescale returns the unscaled value of d at a scale not below d's.
+func (d Decimal) rescale(scale int) *big.Int {
+	value := new(big.Int).Set(d.value())
+	if scale > d.scale {
+		value.Mul(value, pow10(scale-d.scale))
+	}
+	return value
+}
+
+// Cmp compares d and other numerically, so 1.5 equals 1.50.
+func (d Decimal) Cmp(other Decimal) int {
+	scale := max(d.scale, other.scale)
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+// Equal reports whether d and other are numerically equal.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Add returns d + other at the larger of the two scales.
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return Decimal{unscaled: new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - other at the larger of the two scales.
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return Decimal{unscaled: new(big.Int).Sub(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Mul returns d × other exactly; the scale is the sum of both scales.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.value(), other.value()), scale: d.scale + other.scale}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.value()), scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.value()), scale: d.scale}
+}
+
+// Round returns d with exactly scale digits after the point, rounding half
+// away from zero, so 2.345 rounds to 2.35 and -2.345 to -2.35.
+func (d Decimal) Round(scale int) Decimal {
+	if scale < 0 {
+		scale = 0
+	}
+	if scale >= d.scale {
+		return Decimal{unscaled: d.rescale(scale), scale: scale}
+	}
+
+	divisor := pow10(d.scale - scale)
+	quotient, remainder := new(big.Int).QuoRem(d.value(), divisor, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(divisor) >= 0 {
+		if d.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return Decimal{unscaled: quotient, scale: scale}
+}
+
+// String formats d in plain notation with its scale, e.g. "-0.05".
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.value()).String()
+	if d.scale > 0 {
+		if len(digits) <= d.scale {
+			digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so decimals bind from
+// path and query parameters.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes d as a JSON string so clients never parse it as a
+// binary float.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON accepts a JSON string or number. Numbers are read from their
+// literal digits, never through float64. null leaves d unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	text, _ := unquoteJSON(data)
+	return d.UnmarshalText(text)
+}
@@ -0,0 +1,132 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input     string
+		want      string
+		wantScale int
+		wantErr   bool
+	}{
+		{input: "12.30", want: "12.30", wantScale: 2},
+		{input: "-0.05", want: "-0.05", wantScale: 2},
+		{input: "+7", want: "7", wantScale: 0},
+		{input: ".5", want: "0.5", wantScale: 1},
+		{input: "1.5e3", want: "1500", wantScale: 0},
+		{input: "25E-4", want: "0.0025", wantScale: 4},
+		{input: "0.1", want: "0.1", wantScale: 1},
+		{input: "123456789012345678901234567890.123456789", want: "123456789012345678901234567890.123456789", wantScale: 9},
+		{input: "", wantErr: true},
+		{input: "-", wantErr: true},
+		{input: ".", wantErr: true},
+		{input: "1,5", wantErr: true},
+		{input: "0x10", wantErr: true},
+		{input: "1e99999", wantErr: true},
+		{input: "NaN", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseDecimal(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidDecimal) {
+					t.Fatalf("expected ErrInvalidDecimal, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got.String() != tt.want || got.Scale() != tt.wantScale {
+				t.Fatalf("expected %s with scale %d, got %s with scale %d", tt.want, tt.wantScale, got, got.Scale())
+			}
+		})
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	t.Parallel()
+
+	a := MustParseDecimal("0.1")
+	b := MustParseDecimal("0.2")
+	if sum := a.Add(b); sum.String() != "0.3" || !sum.Equal(MustParseDecimal("0.30")) {
+		t.Fatalf("expected exact 0.3, got %s", sum)
+	}
+	if diff := MustParseDecimal("5").Sub(MustParseDecimal("7.25")); diff.String() != "-2.25" {
+		t.Fatalf("expected -2.25, got %s", diff)
+	}
+	if product := MustParseDecimal("19.99").Mul(MustParseDecimal("0.0825")); product.String() != "1.649175" {
+		t.Fatalf("expected 1.649175, got %s", product)
+	}
+	if neg := NewDecimal(1230, 2).Neg(); neg.String() != "-12.30" || neg.Abs().String() != "12.30" {
+		t.Fatalf("expected -12.30 and 12.30, got %s and %s", neg, neg.Abs())
+	}
+	if NewDecimal(5, -2).String() != "500" {
+		t.Fatalf("expected negative scale to multiply, got %s", NewDecimal(5, -2))
+	}
+
+	var zero Decimal
+	if !zero.IsZero() || zero.String() != "0" || zero.Add(a).String() != "0.1" {
+		t.Fatalf("expected usable zero value, got %s", zero)
+	}
+	if a.Cmp(b) != -1 || b.Cmp(a) != 1 || MustParseDecimal("1.50").Cmp(MustParseDecimal("1.5")) != 0 {
+		t.Fatal("expected numeric comparison across scales")
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		scale int
+		want  string
+	}{
+		{input: "2.345", scale: 2, want: "2.35"},
+		{input: "-2.345", scale: 2, want: "-2.35"},
+		{input: "2.344", scale: 2, want: "2.34"},
+		{input: "0.5", scale: 0, want: "1"},
+		{input: "-0.4", scale: 0, want: "0"},
+		{input: "7.1", scale: 3, want: "7.100"},
+	}
+
+	for _, tt := range tests {
+		if got := MustParseDecimal(tt.input).Round(tt.scale); got.String() != tt.want {
+			t.Fatalf("Round(%s, %d): expected %s, got %s", tt.input, tt.scale, tt.want, got)
+		}
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	t.Parallel()
+
+	var payload struct {
+		Price Decimal  `json:"price"`
+		Tax   Decimal  `json:"tax"`
+		Fee   *Decimal `json:"fee"`
+	}
+	if err := json.Unmarshal([]byte(`{"price":"19.99","tax":0.1000000000000000055511151231257827,"fee":null}`), &payload); err != nil {
+		t.Fatalf("expected decimals to decode, got %v", err)
+	}
+	if payload.Price.String() != "19.99" || payload.Tax.String() != "0.1000000000000000055511151231257827" || payload.Fee != nil {
+		t.Fatalf("expected exact values, got %s %s %v", payload.Price, payload.Tax, payload.Fee)
+	}
+
+	encoded, err := json.Marshal(map[string]Decimal{"total": MustParseDecimal("20.10")})
+	if err != nil || string(encoded) != `{"total":"20.10"}` {
+		t.Fatalf("expected string-encoded decimal, got %s %v", encoded, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"price":"abc"}`), &payload); !errors.Is(err, ErrInvalidDecimal) {
+		t.Fatalf("expected ErrInvalidDecimal, got %v", err)
+	}
+}
@@ -0,0 +1,7 @@
+module github.com/rluders/httpsuite/docsui/swaggerui
+
+go 1.25.0
+
+require github.com/rluders/httpsuite/v3 v3.0.0
+
+replace github.com/rluders/httpsuite/v3 => ../..
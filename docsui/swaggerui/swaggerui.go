@@ -0,0 +1,24 @@
+// Package swaggerui embeds the Swagger UI 5.18.2 release for
+// httpsuite.API.DocsHandler, so the documentation page needs no CDN. It is a
+// separate module so the core does not link the bundle. See dist/NOTICE.
+package swaggerui
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed dist
+var dist embed.FS
+
+// FS holds swagger-ui-bundle.js and swagger-ui.css. Pass it as
+// httpsuite.DocsOptions.Assets.
+var FS fs.FS = mustSub(dist, "dist")
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,55 @@
+package swaggerui
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+func TestFS(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"swagger-ui-bundle.js", "swagger-ui.css", "LICENSE"} {
+		if _, err := fs.Stat(FS, name); err != nil {
+			t.Fatalf("expected %s to be embedded: %v", name, err)
+		}
+	}
+}
+
+func TestDocsHandlerServesEmbeddedAssets(t *testing.T) {
+	t.Parallel()
+
+	api := httpsuite.NewAPI(httpsuite.APIInfo{Title: "Items", Version: "1.0.0"})
+	handler := http.StripPrefix("/docs", api.DocsHandler(&httpsuite.DocsOptions{Assets: FS}))
+
+	tests := []struct {
+		path        string
+		wantType    string
+		wantContain string
+	}{
+		{path: "/docs/", wantType: "text/html; charset=utf-8", wantContain: `src="/docs/assets/swagger-ui-bundle.js"`},
+		{path: "/docs/assets/swagger-ui-bundle.js", wantType: "text/javascript; charset=utf-8", wantContain: "SwaggerUIBundle"},
+		{path: "/docs/assets/swagger-ui.css", wantType: "text/css; charset=utf-8", wantContain: ".swagger-ui"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantType {
+				t.Fatalf("expected content type %q, got %q", tt.wantType, got)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantContain) {
+				t.Fatalf("expected body to contain %s", tt.wantContain)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package httpsuite
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidEnum matches errors for values outside an enum's allowed set.
+var ErrInvalidEnum = errors.New("invalid enum value")
+
+var enumValues sync.Map // reflect.Type -> []string
+
+// DefineEnum declares the allowed values of T once, typically in a package
+// variable, and returns them:
+//
+//	type OrderStatus string
+//
+//	var OrderStatuses = httpsuite.DefineEnum[OrderStatus]("pending", "paid", "shipped")
+//
+// Calling it again for the same type replaces the values.
+func DefineEnum[T ~string](values ...T) []T {
+	allowed := make([]string, len(values))
+	for i, value := range values {
+		allowed[i] = string(value)
+	}
+	enumValues.Store(reflect.TypeFor[T](), allowed)
+	return values
+}
+
+// EnumValues returns the values declared for T with DefineEnum.
+func EnumValues[T ~string]() []T {
+	allowed := enumAllowed[T]()
+	values := make([]T, len(allowed))
+	for i, value := range allowed {
+		values[i] = T(value)
+	}
+	return values
+}
+
+func enumAllowed[T ~string]() []string {
+	allowed, _ := enumValues.Load(reflect.TypeFor[T]())
+	values, _ := allowed.([]string)
+	return values
+}
+
+// enumError lists the allowed values without exposing the Go type.
+type enumError struct {
+	allowed []string
+}
+
+func (e *enumError) Error() string {
+	if len(e.allowed) == 0 {
+		return "no values are allowed"
+	}
+	return "must be one of: " + strings.Join(e.allowed, ", ")
+}
+
+func (e *enumError) Is(target error) bool {
+	return target == ErrInvalidEnum
+}
+
+// Enum holds a value of a string enum declared with DefineEnum. It encodes as
+// a plain JSON string and rejects undeclared values when decoding, binding
+// from text, or validating, with an error listing the allowed values. The
+// OpenAPI schema of Enum fields lists the values too. The zero value is
+// unset; use a required rule to demand a value.
+type Enum[T ~string] struct {
+	Value T
+}
+
+// ParseEnum returns the enum holding s, or an error listing the allowed values.
+func ParseEnum[T ~string](s string) (Enum[T], error) {
+	for _, allowed := range enumAllowed[T]() {
+		if s == allowed {
+			return Enum[T]{Value: T(s)}, nil
+		}
+	}
+	return Enum[T]{}, &enumError{allowed: enumAllowed[T]()}
+}
+
+// IsZero reports whether no value is set.
+func (e Enum[T]) IsZero() bool {
+	return e.Value == ""
+}
+
+// String returns the value.
+func (e Enum[T]) String() string {
+	return string(e.Value)
+}
+
+// Validate implements SelfValidator, rejecting values set directly on Value
+// that DefineEnum did not declare.
+func (e Enum[T]) Validate() error {
+	if e.IsZero() {
+		return nil
+	}
+	_, err := ParseEnum[T](string(e.Value))
+	return err
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e Enum[T]) MarshalText() ([]byte, error) {
+	return []byte(e.Value), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so enums bind from path
+// and query parameters and JSON strings.
+func (e *Enum[T]) UnmarshalText(text []byte) error {
+	parsed, err := ParseEnum[T](string(text))
+	if err != nil {
+		return err
+	}
+	*e = parsed
+	return nil
+}
+
+func (e Enum[T]) openAPISchema() map[string]any {
+	allowed := enumAllowed[T]()
+	values := make([]any, len(allowed))
+	for i, value := range allowed {
+		values[i] = value
+	}
+	return map[string]any{"type": "string", "enum": values}
+}
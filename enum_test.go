@@ -0,0 +1,136 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type enumTestStatus string
+
+var enumTestStatuses = DefineEnum[enumTestStatus]("pending", "paid", "shipped")
+
+func TestParseEnum(t *testing.T) {
+	t.Parallel()
+
+	if !reflect.DeepEqual(EnumValues[enumTestStatus](), enumTestStatuses) {
+		t.Fatalf("expected declared values, got %v", EnumValues[enumTestStatus]())
+	}
+
+	status, err := ParseEnum[enumTestStatus]("paid")
+	if err != nil || status.Value != "paid" || status.String() != "paid" {
+		t.Fatalf("expected paid, got %v %v", status, err)
+	}
+
+	for _, input := range []string{"refunded", "PAID", ""} {
+		_, err := ParseEnum[enumTestStatus](input)
+		if !errors.Is(err, ErrInvalidEnum) || err.Error() != "must be one of: pending, paid, shipped" {
+			t.Fatalf("ParseEnum(%q): expected ErrInvalidEnum listing values, got %v", input, err)
+		}
+	}
+
+	type undeclared string
+	if _, err := ParseEnum[undeclared]("x"); !errors.Is(err, ErrInvalidEnum) {
+		t.Fatalf("expected undeclared enum to reject values, got %v", err)
+	}
+}
+
+func TestEnumJSON(t *testing.T) {
+	t.Parallel()
+
+	var order struct {
+		Status Enum[enumTestStatus]  `json:"status"`
+		Prev   *Enum[enumTestStatus] `json:"prev,omitempty"`
+	}
+	if err := json.Unmarshal([]byte(`{"status":"shipped"}`), &order); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if order.Status.Value != "shipped" || order.Prev != nil {
+		t.Fatalf("expected shipped, got %+v", order)
+	}
+	data, err := json.Marshal(order)
+	if err != nil || string(data) != `{"status":"shipped"}` {
+		t.Fatalf("expected plain string encoding, got %s %v", data, err)
+	}
+	if err := json.Unmarshal([]byte(`{"status":"lost"}`), &order); !errors.Is(err, ErrInvalidEnum) {
+		t.Fatalf("expected ErrInvalidEnum, got %v", err)
+	}
+}
+
+func TestEnumValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   enumTestStatus
+		wantErr bool
+	}{
+		{value: ""},
+		{value: "pending"},
+		{value: "lost", wantErr: true},
+	}
+	for _, tt := range tests {
+		err := Enum[enumTestStatus]{Value: tt.value}.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("Validate(%q): expected error %v, got %v", tt.value, tt.wantErr, err)
+		}
+	}
+}
+
+type enumRequest struct {
+	Status Enum[enumTestStatus] `json:"status"`
+}
+
+func TestParseRequestRejectsUndeclaredEnum(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(`{"status":"lost"}`))
+	w := httptest.NewRecorder()
+	if _, err := ParseRequest[*enumRequest](w, req, nil, nil); err == nil {
+		t.Fatal("expected an error for an undeclared value")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	var problem struct {
+		Errors []ValidationErrorDetail `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	want := "field status is invalid: must be one of: pending, paid, shipped"
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "status" || problem.Errors[0].Message != want {
+		t.Fatalf("expected enum error on status, got %#v", problem.Errors)
+	}
+}
+
+type enumOpenAPIRequest struct {
+	Status  Enum[enumTestStatus] `json:"status"`
+	ID      UUID                 `json:"id"`
+	Day     DateOnly             `json:"day"`
+	Seen    UnixMillis           `json:"seen"`
+	Created Timestamp            `json:"created"`
+	At      time.Time            `json:"at"`
+}
+
+func TestOpenAPIValueTypeSchemas(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Orders", Version: "1.0.0"})
+	Describe[*enumOpenAPIRequest, NoBody](api, http.MethodPost, "/orders", nil)
+	doc := roundTripOpenAPI(t, api.OpenAPI())
+
+	properties := []string{"paths", "/orders", "post", "requestBody", "content", "application/json", "schema", "properties"}
+	expectJSONPath(t, doc, []any{"pending", "paid", "shipped"}, append(properties, "status", "enum")...)
+	expectJSONPath(t, doc, "string", append(properties, "status", "type")...)
+	expectJSONPath(t, doc, "uuid", append(properties, "id", "format")...)
+	expectJSONPath(t, doc, "date", append(properties, "day", "format")...)
+	expectJSONPath(t, doc, "integer", append(properties, "seen", "type")...)
+	expectJSONPath(t, doc, "date-time", append(properties, "created", "format")...)
+	expectJSONPath(t, doc, "date-time", append(properties, "at", "format")...)
+}
@@ -0,0 +1,49 @@
+package httpsuite
+
+import "net/http"
+
+// FeatureFlag reports whether a feature is on for the caller of r.
+type FeatureFlag func(r *http.Request) bool
+
+// FeatureGateOptions configures WhenEnabled.
+type FeatureGateOptions struct {
+	// Status is the reply for disabled features: 404 (default) hides the
+	// route, 403 acknowledges it.
+	Status int
+	// Detail overrides the problem detail.
+	Detail string
+}
+
+// WhenEnabled returns middleware that only lets requests through when flag
+// reports the feature as on, replying with a 404 or 403 problem otherwise.
+func WhenEnabled(flag FeatureFlag, opts *FeatureGateOptions) func(http.Handler) http.Handler {
+	status := http.StatusNotFound
+	detail := ""
+	if opts != nil {
+		if opts.Status == http.StatusForbidden {
+			status = http.StatusForbidden
+		}
+		detail = opts.Detail
+	}
+	if detail == "" {
+		detail = "the requested resource does not exist"
+		if status == http.StatusForbidden {
+			detail = "this feature is not enabled for the caller"
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if flag != nil && flag(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			problems := resolveProblemConfig(r.Context(), nil)
+			key, title := "not_found_error", "Not Found"
+			if status == http.StatusForbidden {
+				key, title = "forbidden_error", "Forbidden"
+			}
+			sendRequestProblem(w, r, status, NewProblemDetails(status, problems.TypeURL(key), title, detail))
+		})
+	}
+}
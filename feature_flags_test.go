@@ -0,0 +1,63 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWhenEnabled(t *testing.T) {
+	t.Parallel()
+
+	betaUsers := FeatureFlag(func(r *http.Request) bool {
+		return r.Header.Get("X-User") == "beta"
+	})
+
+	tests := []struct {
+		name       string
+		flag       FeatureFlag
+		opts       *FeatureGateOptions
+		user       string
+		wantStatus int
+		wantType   string
+		wantDetail string
+	}{
+		{name: "enabled", flag: betaUsers, user: "beta", wantStatus: http.StatusNoContent},
+		{
+			name: "hidden", flag: betaUsers, user: "regular",
+			wantStatus: http.StatusNotFound, wantType: GetProblemTypeURL("not_found_error"), wantDetail: "the requested resource does not exist",
+		},
+		{
+			name: "forbidden", flag: betaUsers, opts: &FeatureGateOptions{Status: http.StatusForbidden, Detail: "join the beta first"}, user: "regular",
+			wantStatus: http.StatusForbidden, wantType: GetProblemTypeURL("forbidden_error"), wantDetail: "join the beta first",
+		},
+		{name: "nil flag", wantStatus: http.StatusNotFound, wantType: GetProblemTypeURL("not_found_error"), wantDetail: "the requested resource does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := WhenEnabled(tt.flag, tt.opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/beta/reports", nil)
+			req.Header.Set("X-User", tt.user)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantType == "" {
+				return
+			}
+			var problem ProblemDetails
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Type != tt.wantType || problem.Detail != tt.wantDetail {
+				t.Fatalf("unexpected problem %#v", problem)
+			}
+		})
+	}
+}
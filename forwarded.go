@@ -0,0 +1,203 @@
+package httpsuite
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ForwardedOptions configures ForwardedHeaders.
+type ForwardedOptions struct {
+	// TrustedProxies lists the addresses or CIDR ranges of the proxies in
+	// front of the service, e.g. "10.0.0.0/8" or "127.0.0.1". Forwarding
+	// headers are only believed when they arrive from one of them.
+	TrustedProxies []string
+	// Header names the header the trusted proxies set: "Forwarded",
+	// "X-Forwarded-For", or "X-Real-IP". Only that header is read, since
+	// proxies pass the others through from the client unchanged. Empty means
+	// "X-Forwarded-For".
+	Header string
+}
+
+type clientSchemeContextKey struct{}
+
+// ForwardedHeaders returns middleware that resolves the real client IP and
+// scheme from the header named by ForwardedOptions.Header, plus
+// X-Forwarded-Proto unless that header is Forwarded, and stores them for
+// ClientIP and ClientScheme. The forwarding chain is walked from the nearest
+// hop, skipping trusted proxies, so clients cannot spoof their address by
+// sending the headers themselves. An invalid TrustedProxies entry or an
+// unsupported Header is an error.
+func ForwardedHeaders(opts *ForwardedOptions) (func(http.Handler) http.Handler, error) {
+	var trusted []netip.Prefix
+	source := "X-Forwarded-For"
+	if opts != nil {
+		if opts.Header != "" {
+			source = http.CanonicalHeaderKey(opts.Header)
+		}
+		switch source {
+		case "Forwarded", "X-Forwarded-For", "X-Real-Ip":
+		default:
+			return nil, fmt.Errorf("unsupported forwarding header %q", opts.Header)
+		}
+		for _, entry := range opts.TrustedProxies {
+			prefix, err := parseTrustedProxy(entry)
+			if err != nil {
+				return nil, err
+			}
+			trusted = append(trusted, prefix)
+		}
+	}
+	isTrusted := func(addr netip.Addr) bool {
+		for _, prefix := range trusted {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip, scheme := resolveForwarded(r, source, isTrusted)
+			ctx := r.Context()
+			if ip != "" {
+				ctx = CtxSet(ctx, ClientIPKey, ip)
+			}
+			if scheme != "" {
+				ctx = context.WithValue(ctx, clientSchemeContextKey{}, scheme)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}, nil
+}
+
+// ClientIP returns the client address resolved by ForwardedHeaders, or the
+// host of r.RemoteAddr when the middleware is not installed.
+func ClientIP(r *http.Request) string {
+	if ip, ok := CtxGet(r.Context(), ClientIPKey); ok && ip != "" {
+		return ip
+	}
+	if addr, ok := parseHopAddr(r.RemoteAddr); ok {
+		return addr.String()
+	}
+	return r.RemoteAddr
+}
+
+// ClientScheme returns "https" or "http" as seen by the client, using the
+// scheme resolved by ForwardedHeaders or, without it, whether the connection
+// uses TLS.
+func ClientScheme(r *http.Request) string {
+	if scheme, ok := r.Context().Value(clientSchemeContextKey{}).(string); ok {
+		return scheme
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// forwardedHop is one entry of the forwarding chain, nearest hop last.
+type forwardedHop struct {
+	addr  string
+	proto string
+}
+
+func resolveForwarded(r *http.Request, source string, isTrusted func(netip.Addr) bool) (ip, scheme string) {
+	peer, ok := parseHopAddr(r.RemoteAddr)
+	if !ok || !isTrusted(peer) {
+		return "", ""
+	}
+
+	hops := forwardedHops(r.Header, source)
+	client, clientProto := peer.String(), ""
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok := parseHopAddr(hops[i].addr)
+		if !ok {
+			// Obfuscated identifiers such as "unknown" end the chain at the
+			// last proxy that could be identified.
+			break
+		}
+		client, clientProto = addr.String(), hops[i].proto
+		if !isTrusted(addr) {
+			break
+		}
+	}
+
+	if clientProto == "" && source != "Forwarded" {
+		if values := r.Header.Values("X-Forwarded-Proto"); len(values) > 0 {
+			protos := strings.Split(values[len(values)-1], ",")
+			clientProto = protos[len(protos)-1]
+		}
+	}
+	switch proto := strings.ToLower(strings.TrimSpace(clientProto)); proto {
+	case "http", "https":
+		scheme = proto
+	}
+	return client, scheme
+}
+
+// forwardedHops reads the chain from the source header.
+func forwardedHops(header http.Header, source string) []forwardedHop {
+	values := header.Values(source)
+	if len(values) == 0 {
+		return nil
+	}
+	var hops []forwardedHop
+	switch source {
+	case "Forwarded":
+		for _, element := range strings.Split(strings.Join(values, ","), ",") {
+			var hop forwardedHop
+			for _, pair := range strings.Split(element, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+				value = strings.Trim(value, `"`)
+				switch strings.ToLower(key) {
+				case "for":
+					hop.addr = value
+				case "proto":
+					hop.proto = value
+				}
+			}
+			hops = append(hops, hop)
+		}
+	case "X-Forwarded-For":
+		for _, addr := range strings.Split(strings.Join(values, ","), ",") {
+			hops = append(hops, forwardedHop{addr: strings.TrimSpace(addr)})
+		}
+	default:
+		hops = append(hops, forwardedHop{addr: strings.TrimSpace(values[0])})
+	}
+	return hops
+}
+
+// parseHopAddr accepts "ip", "ip:port", "[ipv6]", and "[ipv6]:port".
+func parseHopAddr(value string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+func parseTrustedProxy(entry string) (netip.Prefix, error) {
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,95 @@
+package httpsuite
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForwardedHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		source     string
+		remoteAddr string
+		header     map[string]string
+		wantIP     string
+		wantScheme string
+	}{
+		{name: "direct client", remoteAddr: "203.0.113.9:5000", wantIP: "203.0.113.9", wantScheme: "http"},
+		{name: "untrusted peer spoofing headers", remoteAddr: "203.0.113.9:5000", header: map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Forwarded-Proto": "https"}, wantIP: "203.0.113.9", wantScheme: "http"},
+		{name: "x-forwarded-for", remoteAddr: "10.0.0.2:5000", header: map[string]string{"X-Forwarded-For": "198.51.100.7", "X-Forwarded-Proto": "https"}, wantIP: "198.51.100.7", wantScheme: "https"},
+		{name: "spoofed leftmost entry", remoteAddr: "10.0.0.2:5000", header: map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.7, 10.0.0.3"}, wantIP: "198.51.100.7", wantScheme: "http"},
+		{name: "only trusted hops", remoteAddr: "10.0.0.2:5000", header: map[string]string{"X-Forwarded-For": "10.0.0.4, 10.0.0.3"}, wantIP: "10.0.0.4", wantScheme: "http"},
+		{name: "forwarded", source: "Forwarded", remoteAddr: "10.0.0.2:5000", header: map[string]string{"Forwarded": `for=198.51.100.7;proto=https, for="[2001:db8::1]:4711"`, "X-Forwarded-For": "1.2.3.4"}, wantIP: "198.51.100.7", wantScheme: "https"},
+		{name: "spoofed forwarded behind x-forwarded-for proxy", remoteAddr: "10.0.0.2:5000", header: map[string]string{"Forwarded": "for=1.2.3.4;proto=https", "X-Forwarded-For": "198.51.100.7"}, wantIP: "198.51.100.7", wantScheme: "http"},
+		{name: "spoofed x-forwarded-for behind forwarded proxy", source: "Forwarded", remoteAddr: "10.0.0.2:5000", header: map[string]string{"Forwarded": "for=198.51.100.7", "X-Forwarded-For": "1.2.3.4", "X-Forwarded-Proto": "https"}, wantIP: "198.51.100.7", wantScheme: "http"},
+		{name: "forwarded ipv6 client", source: "Forwarded", remoteAddr: "10.0.0.2:5000", header: map[string]string{"Forwarded": `for="[2001:db8:cafe::17]:4711"`}, wantIP: "2001:db8:cafe::17", wantScheme: "http"},
+		{name: "obfuscated hop", source: "Forwarded", remoteAddr: "10.0.0.2:5000", header: map[string]string{"Forwarded": "for=unknown"}, wantIP: "10.0.0.2", wantScheme: "http"},
+		{name: "x-real-ip", source: "x-real-ip", remoteAddr: "10.0.0.2:5000", header: map[string]string{"X-Real-IP": "198.51.100.7", "X-Forwarded-For": "1.2.3.4"}, wantIP: "198.51.100.7", wantScheme: "http"},
+		{name: "x-real-ip ignored by default", remoteAddr: "10.0.0.2:5000", header: map[string]string{"X-Real-IP": "1.2.3.4"}, wantIP: "10.0.0.2", wantScheme: "http"},
+		{name: "ipv4-mapped peer", remoteAddr: "[::ffff:10.0.0.2]:5000", header: map[string]string{"X-Forwarded-For": "198.51.100.7"}, wantIP: "198.51.100.7", wantScheme: "http"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			middleware, err := ForwardedHeaders(&ForwardedOptions{TrustedProxies: []string{"10.0.0.0/8", "2001:db8::1"}, Header: tt.source})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+
+			var gotIP, gotScheme string
+			middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotIP, gotScheme = ClientIP(r), ClientScheme(r)
+			})).ServeHTTP(httptest.NewRecorder(), req)
+
+			if gotIP != tt.wantIP {
+				t.Fatalf("expected client IP %q, got %q", tt.wantIP, gotIP)
+			}
+			if gotScheme != tt.wantScheme {
+				t.Fatalf("expected scheme %q, got %q", tt.wantScheme, gotScheme)
+			}
+		})
+	}
+}
+
+func TestForwardedHeadersInvalidProxy(t *testing.T) {
+	t.Parallel()
+
+	for _, entry := range []string{"10.0.0.0/33", "proxy.internal"} {
+		if _, err := ForwardedHeaders(&ForwardedOptions{TrustedProxies: []string{entry}}); err == nil {
+			t.Fatalf("expected an error for %q", entry)
+		}
+	}
+}
+
+func TestForwardedHeadersUnsupportedHeader(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ForwardedHeaders(&ForwardedOptions{Header: "X-Client-IP"}); err == nil {
+		t.Fatal("expected an error for an unsupported header")
+	}
+}
+
+func TestClientIPWithoutMiddleware(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.RemoteAddr = "[2001:db8::5]:443"
+	req.TLS = &tls.ConnectionState{}
+	if got := ClientIP(req); got != "2001:db8::5" {
+		t.Fatalf("expected the peer address, got %q", got)
+	}
+	if got := ClientScheme(req); got != "https" {
+		t.Fatalf("expected https for TLS connections, got %q", got)
+	}
+}
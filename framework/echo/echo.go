@@ -0,0 +1,59 @@
+// Package echo adapts httpsuite's request parsing and responses to
+// echo.Context handlers.
+package echo
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rluders/httpsuite/v3"
+)
+
+// Params returns a ParamExtractor reading echo path parameters, e.g. ":id".
+func Params(c echo.Context) httpsuite.ParamExtractor {
+	return func(_ *http.Request, key string) string {
+		return c.Param(key)
+	}
+}
+
+// ParseRequest runs httpsuite.ParseRequest against c. Invalid requests have
+// already been answered with a problem when it returns an error, so handlers
+// can return nil.
+func ParseRequest[T any](c echo.Context, opts *httpsuite.ParseOptions, pathParams ...string) (T, error) {
+	return httpsuite.ParseRequest[T](c.Response(), c.Request(), Params(c), opts, pathParams...)
+}
+
+// SendResponse writes a JSON response through c, as httpsuite.SendResponse
+// does. It returns nil so handlers can return its result.
+func SendResponse[T any](c echo.Context, code int, data T, problem *httpsuite.ProblemDetails, meta any) error {
+	httpsuite.SendResponse(c.Response(), code, data, problem, meta)
+	return nil
+}
+
+// SendProblem writes problem through c using its status.
+func SendProblem(c echo.Context, problem *httpsuite.ProblemDetails) error {
+	httpsuite.ProblemResponse(c.Response(), problem)
+	return nil
+}
+
+// ErrorHandler is an echo.HTTPErrorHandler that answers returned errors with
+// problems: *httpsuite.ProblemDetails as they are, *echo.HTTPError with its
+// status and message, and anything else with a 500.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	if problem, ok := httpsuite.AsProblem(err); ok {
+		httpsuite.ProblemResponse(c.Response(), problem)
+		return
+	}
+	if httpErr, ok := err.(*echo.HTTPError); ok {
+		detail := ""
+		if message, ok := httpErr.Message.(string); ok && message != http.StatusText(httpErr.Code) {
+			detail = message
+		}
+		httpsuite.ProblemResponse(c.Response(), httpsuite.NewProblemDetails(httpErr.Code, httpsuite.BlankURL, "", detail).WithCause(err))
+		return
+	}
+	httpsuite.ErrorResponse(c.Response(), err)
+}
@@ -0,0 +1,88 @@
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rluders/httpsuite/v3"
+)
+
+type itemRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (r *itemRequest) SetParam(fieldName, value string) error {
+	if fieldName != "id" {
+		return errors.New("unknown parameter")
+	}
+	r.ID = value
+	return nil
+}
+
+func newServer() *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler
+	e.PUT("/items/:id", func(c echo.Context) error {
+		req, err := ParseRequest[*itemRequest](c, nil, "id")
+		if err != nil {
+			return nil
+		}
+		return SendResponse(c, http.StatusOK, req, nil, nil)
+	})
+	e.GET("/problem", func(c echo.Context) error {
+		return SendProblem(c, httpsuite.NewNotFoundProblem("item 7 not found"))
+	})
+	e.GET("/returned", func(c echo.Context) error {
+		return httpsuite.NewNotFoundProblem("returned problem")
+	})
+	e.GET("/http-error", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusForbidden, "no access")
+	})
+	return e
+}
+
+func TestAdapter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{name: "parse and respond", method: http.MethodPut, target: "/items/7", body: `{"name":"lamp"}`, wantStatus: http.StatusOK, wantType: "application/json", wantBody: `"id":"7","name":"lamp"`},
+		{name: "malformed body", method: http.MethodPut, target: "/items/7", body: `{`, wantStatus: http.StatusBadRequest, wantType: "application/problem+json"},
+		{name: "send problem", method: http.MethodGet, target: "/problem", wantStatus: http.StatusNotFound, wantType: "application/problem+json", wantBody: "item 7 not found"},
+		{name: "returned problem", method: http.MethodGet, target: "/returned", wantStatus: http.StatusNotFound, wantType: "application/problem+json", wantBody: "returned problem"},
+		{name: "http error", method: http.MethodGet, target: "/http-error", wantStatus: http.StatusForbidden, wantType: "application/problem+json", wantBody: "no access"},
+		{name: "unknown route", method: http.MethodGet, target: "/missing", wantStatus: http.StatusNotFound, wantType: "application/problem+json"},
+	}
+
+	e := newServer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.HasPrefix(rec.Header().Get("Content-Type"), tt.wantType) {
+				t.Fatalf("expected Content-Type %q, got %q", tt.wantType, rec.Header().Get("Content-Type"))
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
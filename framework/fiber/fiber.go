@@ -0,0 +1,100 @@
+// Package fiber adapts httpsuite's request parsing and responses to fiber
+// handlers. Fiber runs on fasthttp, so responses go through a
+// http.ResponseWriter that writes to the fiber.Ctx.
+package fiber
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/rluders/httpsuite/v3"
+)
+
+// Writer returns a http.ResponseWriter writing to c, for httpsuite helpers
+// without a fiber counterpart here. Headers set on it are copied to c when
+// the status is written.
+func Writer(c *fiber.Ctx) http.ResponseWriter {
+	return &responseWriter{ctx: c, header: make(http.Header)}
+}
+
+// Params returns a ParamExtractor reading fiber path parameters, e.g. ":id".
+func Params(c *fiber.Ctx) httpsuite.ParamExtractor {
+	return func(_ *http.Request, key string) string {
+		return c.Params(key)
+	}
+}
+
+// ParseRequest runs httpsuite.ParseRequest against c. Invalid requests have
+// already been answered with a problem when it returns an error, so handlers
+// can return nil.
+func ParseRequest[T any](c *fiber.Ctx, opts *httpsuite.ParseOptions, pathParams ...string) (T, error) {
+	var empty T
+	r, err := adaptor.ConvertRequest(c, false)
+	if err != nil {
+		return empty, err
+	}
+	return httpsuite.ParseRequest[T](Writer(c), r, Params(c), opts, pathParams...)
+}
+
+// SendResponse writes a JSON response through c, as httpsuite.SendResponse
+// does. It returns nil so handlers can return its result.
+func SendResponse[T any](c *fiber.Ctx, code int, data T, problem *httpsuite.ProblemDetails, meta any) error {
+	httpsuite.SendResponse(Writer(c), code, data, problem, meta)
+	return nil
+}
+
+// SendProblem writes problem through c using its status.
+func SendProblem(c *fiber.Ctx, problem *httpsuite.ProblemDetails) error {
+	httpsuite.ProblemResponse(Writer(c), problem)
+	return nil
+}
+
+// ErrorHandler is a fiber.ErrorHandler that answers returned errors with
+// problems: *httpsuite.ProblemDetails as they are, *fiber.Error with its
+// status and message, and anything else with a 500.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	if problem, ok := httpsuite.AsProblem(err); ok {
+		return SendProblem(c, problem)
+	}
+	if fiberErr, ok := err.(*fiber.Error); ok {
+		detail := ""
+		if fiberErr.Message != http.StatusText(fiberErr.Code) {
+			detail = fiberErr.Message
+		}
+		return SendProblem(c, httpsuite.NewProblemDetails(fiberErr.Code, httpsuite.BlankURL, "", detail).WithCause(err))
+	}
+	httpsuite.ErrorResponse(Writer(c), err)
+	return nil
+}
+
+type responseWriter struct {
+	ctx         *fiber.Ctx
+	header      http.Header
+	wroteHeader bool
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	for key, values := range w.header {
+		w.ctx.Response().Header.Del(key)
+		for _, value := range values {
+			w.ctx.Response().Header.Add(key, value)
+		}
+	}
+	w.ctx.Status(code)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ctx.Write(p)
+}
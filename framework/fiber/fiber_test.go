@@ -0,0 +1,93 @@
+package fiber
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rluders/httpsuite/v3"
+)
+
+type itemRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (r *itemRequest) SetParam(fieldName, value string) error {
+	if fieldName != "id" {
+		return errors.New("unknown parameter")
+	}
+	r.ID = value
+	return nil
+}
+
+func newApp() *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Put("/items/:id", func(c *fiber.Ctx) error {
+		req, err := ParseRequest[*itemRequest](c, nil, "id")
+		if err != nil {
+			return nil
+		}
+		return SendResponse(c, http.StatusOK, req, nil, nil)
+	})
+	app.Get("/problem", func(c *fiber.Ctx) error {
+		return SendProblem(c, httpsuite.Problem(http.StatusTooManyRequests).RetryAfter(3*time.Second).Build())
+	})
+	app.Get("/returned", func(c *fiber.Ctx) error {
+		return httpsuite.NewNotFoundProblem("returned problem")
+	})
+	return app
+}
+
+func TestAdapter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantType   string
+		wantHeader string
+		wantBody   string
+	}{
+		{name: "parse and respond", method: http.MethodPut, target: "/items/7", body: `{"name":"lamp"}`, wantStatus: http.StatusOK, wantType: "application/json", wantBody: `"id":"7","name":"lamp"`},
+		{name: "malformed body", method: http.MethodPut, target: "/items/7", body: `{`, wantStatus: http.StatusBadRequest, wantType: "application/problem+json"},
+		{name: "send problem", method: http.MethodGet, target: "/problem", wantStatus: http.StatusTooManyRequests, wantType: "application/problem+json", wantHeader: "3"},
+		{name: "returned problem", method: http.MethodGet, target: "/returned", wantStatus: http.StatusNotFound, wantType: "application/problem+json", wantBody: "returned problem"},
+		{name: "fiber error", method: http.MethodGet, target: "/missing", wantStatus: http.StatusNotFound, wantType: "application/problem+json"},
+	}
+
+	app := newApp()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, resp.StatusCode, body)
+			}
+			if !strings.HasPrefix(resp.Header.Get("Content-Type"), tt.wantType) {
+				t.Fatalf("expected Content-Type %q, got %q", tt.wantType, resp.Header.Get("Content-Type"))
+			}
+			if tt.wantHeader != "" && resp.Header.Get("Retry-After") != tt.wantHeader {
+				t.Fatalf("expected Retry-After %q, got %q", tt.wantHeader, resp.Header.Get("Retry-After"))
+			}
+			if !strings.Contains(string(body), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %q", tt.wantBody, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package gin adapts httpsuite's request parsing and responses to
+// gin.Context handlers.
+package gin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rluders/httpsuite/v3"
+)
+
+// Params returns a ParamExtractor reading gin path parameters, e.g. ":id".
+func Params(c *gin.Context) httpsuite.ParamExtractor {
+	return func(_ *http.Request, key string) string {
+		return c.Param(key)
+	}
+}
+
+// ParseRequest runs httpsuite.ParseRequest against c. Invalid requests have
+// already been answered with a problem and c aborted when it returns an
+// error.
+func ParseRequest[T any](c *gin.Context, opts *httpsuite.ParseOptions, pathParams ...string) (T, error) {
+	request, err := httpsuite.ParseRequest[T](c.Writer, c.Request, Params(c), opts, pathParams...)
+	if err != nil {
+		c.Abort()
+	}
+	return request, err
+}
+
+// SendResponse writes a JSON response through c, as httpsuite.SendResponse
+// does.
+func SendResponse[T any](c *gin.Context, code int, data T, problem *httpsuite.ProblemDetails, meta any) {
+	httpsuite.SendResponse(c.Writer, code, data, problem, meta)
+}
+
+// SendProblem writes problem through c using its status and aborts c.
+func SendProblem(c *gin.Context, problem *httpsuite.ProblemDetails) {
+	httpsuite.ProblemResponse(c.Writer, problem)
+	c.Abort()
+}
+
+// Errors returns middleware that answers the last error handlers attached
+// with c.Error as a problem, unless a response was already written.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		httpsuite.ErrorResponse(c.Writer, c.Errors.Last().Err)
+	}
+}
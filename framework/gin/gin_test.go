@@ -0,0 +1,84 @@
+package gin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rluders/httpsuite/v3"
+)
+
+type itemRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (r *itemRequest) SetParam(fieldName, value string) error {
+	if fieldName != "id" {
+		return errors.New("unknown parameter")
+	}
+	r.ID = value
+	return nil
+}
+
+func newServer() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Errors())
+	router.PUT("/items/:id", func(c *gin.Context) {
+		req, err := ParseRequest[*itemRequest](c, nil, "id")
+		if err != nil {
+			return
+		}
+		SendResponse(c, http.StatusOK, req, nil, nil)
+	})
+	router.GET("/problem", func(c *gin.Context) {
+		SendProblem(c, httpsuite.NewNotFoundProblem("item 7 not found"))
+	})
+	router.GET("/error", func(c *gin.Context) {
+		_ = c.Error(httpsuite.NewProblemDetails(http.StatusConflict, httpsuite.BlankURL, "", "version mismatch"))
+	})
+	return router
+}
+
+func TestAdapter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{name: "parse and respond", method: http.MethodPut, target: "/items/7", body: `{"name":"lamp"}`, wantStatus: http.StatusOK, wantType: "application/json", wantBody: `"id":"7","name":"lamp"`},
+		{name: "malformed body", method: http.MethodPut, target: "/items/7", body: `{`, wantStatus: http.StatusBadRequest, wantType: "application/problem+json"},
+		{name: "send problem", method: http.MethodGet, target: "/problem", wantStatus: http.StatusNotFound, wantType: "application/problem+json", wantBody: "item 7 not found"},
+		{name: "attached error", method: http.MethodGet, target: "/error", wantStatus: http.StatusConflict, wantType: "application/problem+json", wantBody: "version mismatch"},
+	}
+
+	router := newServer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.HasPrefix(rec.Header().Get("Content-Type"), tt.wantType) {
+				t.Fatalf("expected Content-Type %q, got %q", tt.wantType, rec.Header().Get("Content-Type"))
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+const graphQLResponseMediaType = "application/graphql-response+json"
+
+// GraphQLRequest is a GraphQL-over-HTTP request, read from the query string
+// of GET requests or the JSON body of POST requests.
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	Extensions    map[string]any `json:"extensions,omitempty"`
+}
+
+// GraphQLLocation points into the query document.
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLError is an entry of the errors list of a GraphQL response.
+type GraphQLError struct {
+	Message    string            `json:"message"`
+	Locations  []GraphQLLocation `json:"locations,omitempty"`
+	Path       []any             `json:"path,omitempty"`
+	Extensions map[string]any    `json:"extensions,omitempty"`
+}
+
+// GraphQLResponse is the result of executing a GraphQL request.
+type GraphQLResponse struct {
+	Data       any            `json:"data,omitempty"`
+	Errors     []GraphQLError `json:"errors,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// GraphQLResolver executes a request with any GraphQL engine. Field errors
+// belong in the response; a returned error fails the whole request and is
+// written as a problem, using its *ProblemDetails when it carries one.
+type GraphQLResolver func(ctx context.Context, req GraphQLRequest) (*GraphQLResponse, error)
+
+// GraphQLOptions configures GraphQLHandler.
+type GraphQLOptions struct {
+	// MaxBodyBytes limits POST bodies. Defaults to 1 MiB.
+	MaxBodyBytes int64
+	// Problems resolves problem type URLs. Defaults to the scoped or default
+	// ProblemConfig.
+	Problems *ProblemConfig
+}
+
+// GraphQLHandler serves GraphQL over HTTP: it reads query, operationName,
+// variables, and extensions from GET query strings or POST JSON bodies,
+// accepts only query operations over GET, and writes the resolver's response as
+// application/graphql-response+json when the client accepts it, otherwise
+// as application/json. Malformed requests get the suite's problem responses.
+func GraphQLHandler(resolver GraphQLResolver, opts *GraphQLOptions) http.Handler {
+	options := GraphQLOptions{MaxBodyBytes: defaultMaxBodyBytes}
+	if opts != nil {
+		options.Problems = opts.Problems
+		if opts.MaxBodyBytes > 0 {
+			options.MaxBodyBytes = opts.MaxBodyBytes
+		}
+	}
+	notAllowed := NewStaticProblem(
+		NewProblemDetails(http.StatusMethodNotAllowed, BlankURL, "", ""),
+		http.Header{"Allow": {"GET, POST"}},
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		problems := options.Problems
+		if problems == nil {
+			problems = sharedProblemConfig(r.Context())
+		}
+		badRequest := func(detail string) {
+			sendRequestProblem(w, r, http.StatusBadRequest, NewProblemDetails(http.StatusBadRequest, problems.TypeURL("bad_request_error"), "Invalid Request", detail))
+		}
+
+		var request GraphQLRequest
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+			request.Query = query.Get("query")
+			request.OperationName = query.Get("operationName")
+			for name, target := range map[string]*map[string]any{"variables": &request.Variables, "extensions": &request.Extensions} {
+				if raw := query.Get(name); raw != "" {
+					if err := json.Unmarshal([]byte(raw), target); err != nil {
+						badRequest(name + " must be a JSON object")
+						return
+					}
+				}
+			}
+		case http.MethodPost:
+			decoded, err := DecodeRequestBody[GraphQLRequest](r, options.MaxBodyBytes)
+			if err != nil {
+				problem, status := problemFromDecodeError(err, problems)
+				sendRequestProblem(w, r, status, problem)
+				return
+			}
+			request = decoded
+		default:
+			notAllowed.Write(w)
+			return
+		}
+
+		if strings.TrimSpace(request.Query) == "" {
+			badRequest("query is required")
+			return
+		}
+		// GET is only safe for queries. Documents whose operation cannot be
+		// told apart, e.g. several operations without operationName, are
+		// refused too, so a parser mismatch cannot run a mutation.
+		if r.Method == http.MethodGet && graphQLOperationType(request.Query, request.OperationName) != "query" {
+			w.Header().Set("Allow", "POST")
+			sendRequestProblem(w, r, http.StatusMethodNotAllowed, NewProblemDetails(http.StatusMethodNotAllowed, BlankURL, "", "only query operations can be sent with GET; use POST"))
+			return
+		}
+
+		response, err := resolver(r.Context(), request)
+		if err != nil {
+			writeHandlerError(w, r, err)
+			return
+		}
+		if response == nil {
+			response = &GraphQLResponse{}
+		}
+		writeGraphQLResponse(w, r, response)
+	})
+}
+
+// GraphQLErrorFrom converts err into a GraphQL error for the field at path.
+// Problems keep their type, title, status, and extensions under the error's
+// extensions, so GraphQL and REST clients see the same error model.
+func GraphQLErrorFrom(err error, path ...any) GraphQLError {
+	graphQLErr := GraphQLError{Message: err.Error(), Path: path}
+	problem, ok := AsProblem(err)
+	if !ok {
+		return graphQLErr
+	}
+	graphQLErr.Message = problem.Detail
+	if graphQLErr.Message == "" {
+		graphQLErr.Message = problem.Title
+	}
+	graphQLErr.Extensions = map[string]any{"type": problem.Type, "title": problem.Title, "status": problem.Status}
+	for key, value := range problem.Extensions {
+		if _, reserved := graphQLErr.Extensions[key]; !reserved {
+			graphQLErr.Extensions[key] = Redact(value)
+		}
+	}
+	return graphQLErr
+}
+
+func writeGraphQLResponse(w http.ResponseWriter, r *http.Request, response *GraphQLResponse) {
+	mediaType := "application/json"
+	status := http.StatusOK
+	if strings.Contains(r.Header.Get("Accept"), graphQLResponseMediaType) {
+		mediaType = graphQLResponseMediaType
+		// The newer media type reports requests that never executed, which
+		// have errors but no data, with a client error status.
+		if response.Data == nil && len(response.Errors) > 0 {
+			status = http.StatusBadRequest
+		}
+	}
+
+	buffer := getEncodeBuffer()
+	defer putEncodeBuffer(buffer)
+	if err := json.NewEncoder(buffer).Encode(response); err != nil {
+		log.Printf("Error writing response: %v", err)
+		writeHandlerError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+	w.WriteHeader(status)
+	if _, err := w.Write(buffer.Bytes()); err != nil {
+		log.Printf("Failed to write response body (status=%d): %v", status, err)
+	}
+}
+
+// graphQLOperationType returns "query", "mutation", or "subscription" for
+// the operation that operationName, or the only operation, selects in a
+// document, or "" when it cannot tell. It only scans top-level tokens;
+// validating the document is left to the resolver.
+func graphQLOperationType(document, operationName string) string {
+	var found []string
+	depth := 0
+	for i := 0; i < len(document); {
+		c := document[i]
+		switch {
+		case c == '#':
+			for i < len(document) && document[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(document[i:], `"""`):
+			end := strings.Index(document[i+3:], `"""`)
+			if end < 0 {
+				return ""
+			}
+			i += end + 6
+		case c == '"':
+			i++
+			for i < len(document) && document[i] != '"' {
+				if document[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+		case c == '{' || c == '(' || c == '[':
+			if depth == 0 && c == '{' {
+				// A bare selection set is an anonymous query.
+				found = append(found, "query", "")
+			}
+			depth++
+			i++
+		case c == '}' || c == ')' || c == ']':
+			depth--
+			i++
+		case depth == 0 && isGraphQLNameStart(c):
+			start := i
+			for i < len(document) && isGraphQLNameChar(document[i]) {
+				i++
+			}
+			keyword := document[start:i]
+			if keyword != "query" && keyword != "mutation" && keyword != "subscription" && keyword != "fragment" {
+				continue
+			}
+			for i < len(document) && strings.IndexByte(" \t\r\n,", document[i]) >= 0 {
+				i++
+			}
+			nameStart := i
+			for i < len(document) && isGraphQLNameChar(document[i]) {
+				i++
+			}
+			if keyword != "fragment" {
+				found = append(found, keyword, document[nameStart:i])
+			}
+			// Skip to the selection set so it is not counted as another
+			// anonymous query.
+			parens := 0
+			for i < len(document) && (document[i] != '{' || parens > 0) {
+				switch document[i] {
+				case '(':
+					parens++
+				case ')':
+					parens--
+				}
+				i++
+			}
+			if i < len(document) {
+				depth++
+				i++
+			}
+		default:
+			i++
+		}
+	}
+
+	if len(found) == 2 && operationName == "" {
+		return found[0]
+	}
+	for j := 0; j+1 < len(found); j += 2 {
+		if operationName != "" && found[j+1] == operationName {
+			return found[j]
+		}
+	}
+	return ""
+}
+
+func isGraphQLNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isGraphQLNameChar(c byte) bool {
+	return isGraphQLNameStart(c) || c >= '0' && c <= '9'
+}
@@ -0,0 +1,125 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGraphQLOperationType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		document      string
+		operationName string
+		want          string
+	}{
+		{document: "{ items { id } }", want: "query"},
+		{document: "query Items { items { id } }", want: "query"},
+		{document: "mutation { addItem(name: \"x\") { id } }", want: "mutation"},
+		{document: "# mutation in a comment\nquery { items }", want: "query"},
+		{document: `query Q($f: In = {a: 1}) { items(filter: $f) { id } }`, want: "query"},
+		{document: "query A { a } mutation B { b }", operationName: "B", want: "mutation"},
+		{document: "query A { a } mutation B { b }", want: ""},
+		{document: "fragment F on Item { id } mutation { add { ...F } }", want: "mutation"},
+		{document: `query { search(text: "mutation { x }") { id } }`, want: "query"},
+		{document: "subscription OnItem { item { id } }", want: "subscription"},
+	}
+
+	for _, tt := range tests {
+		if got := graphQLOperationType(tt.document, tt.operationName); got != tt.want {
+			t.Fatalf("graphQLOperationType(%q, %q): expected %q, got %q", tt.document, tt.operationName, tt.want, got)
+		}
+	}
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := GraphQLHandler(func(ctx context.Context, req GraphQLRequest) (*GraphQLResponse, error) {
+		switch req.OperationName {
+		case "Fail":
+			return nil, NewProblemDetails(http.StatusServiceUnavailable, BlankURL, "Service Unavailable", "resolver down")
+		case "Invalid":
+			return &GraphQLResponse{Errors: []GraphQLError{{Message: "unknown field"}}}, nil
+		}
+		return &GraphQLResponse{Data: map[string]any{"echo": req.Variables["name"]}}, nil
+	}, &GraphQLOptions{MaxBodyBytes: 128})
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		accept     string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{name: "post", method: http.MethodPost, target: "/graphql", body: `{"query":"query Q($name: String) { echo(name: $name) }","variables":{"name":"lamp"}}`, wantStatus: http.StatusOK, wantType: "application/json; charset=utf-8", wantBody: `{"data":{"echo":"lamp"}}`},
+		{name: "get", method: http.MethodGet, target: "/graphql?" + url.Values{"query": {"{ echo }"}, "variables": {`{"name":"desk"}`}}.Encode(), wantStatus: http.StatusOK, wantBody: `"echo":"desk"`},
+		{name: "get mutation", method: http.MethodGet, target: "/graphql?" + url.Values{"query": {"mutation { add }"}}.Encode(), wantStatus: http.StatusMethodNotAllowed, wantType: problemContentType},
+		{name: "get subscription", method: http.MethodGet, target: "/graphql?" + url.Values{"query": {"subscription { added }"}}.Encode(), wantStatus: http.StatusMethodNotAllowed, wantType: problemContentType},
+		{name: "get ambiguous operation", method: http.MethodGet, target: "/graphql?" + url.Values{"query": {"query A { a } mutation B { add }"}}.Encode(), wantStatus: http.StatusMethodNotAllowed, wantType: problemContentType, wantBody: "only query operations"},
+		{name: "get unparseable document", method: http.MethodGet, target: "/graphql?" + url.Values{"query": {`mutation { add(note: """unterminated) }`}}.Encode(), wantStatus: http.StatusMethodNotAllowed, wantType: problemContentType},
+		{name: "get named query", method: http.MethodGet, target: "/graphql?" + url.Values{"query": {"query A { echo } mutation B { add }"}, "operationName": {"A"}}.Encode(), wantStatus: http.StatusOK},
+		{name: "get bad variables", method: http.MethodGet, target: "/graphql?" + url.Values{"query": {"{ echo }"}, "variables": {"[1]"}}.Encode(), wantStatus: http.StatusBadRequest, wantType: problemContentType, wantBody: "variables must be a JSON object"},
+		{name: "missing query", method: http.MethodPost, target: "/graphql", body: `{}`, wantStatus: http.StatusBadRequest, wantType: problemContentType, wantBody: "query is required"},
+		{name: "malformed body", method: http.MethodPost, target: "/graphql", body: `{`, wantStatus: http.StatusBadRequest, wantType: problemContentType},
+		{name: "body too large", method: http.MethodPost, target: "/graphql", body: `{"query":"` + strings.Repeat("x", 200) + `"}`, wantStatus: http.StatusRequestEntityTooLarge, wantType: problemContentType},
+		{name: "unsupported method", method: http.MethodPut, target: "/graphql", wantStatus: http.StatusMethodNotAllowed, wantType: problemContentType},
+		{name: "resolver problem", method: http.MethodPost, target: "/graphql", body: `{"query":"{ a }","operationName":"Fail"}`, wantStatus: http.StatusServiceUnavailable, wantType: problemContentType, wantBody: "resolver down"},
+		{name: "graphql response media type", method: http.MethodPost, target: "/graphql", body: `{"query":"{ a }","operationName":"Invalid"}`, accept: graphQLResponseMediaType, wantStatus: http.StatusBadRequest, wantType: graphQLResponseMediaType + "; charset=utf-8", wantBody: "unknown field"},
+		{name: "legacy media type", method: http.MethodPost, target: "/graphql", body: `{"query":"{ a }","operationName":"Invalid"}`, wantStatus: http.StatusOK, wantBody: "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantType != "" && rec.Header().Get("Content-Type") != tt.wantType {
+				t.Fatalf("expected Content-Type %q, got %q", tt.wantType, rec.Header().Get("Content-Type"))
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGraphQLErrorFrom(t *testing.T) {
+	t.Parallel()
+
+	problem := NewNotFoundProblem("item 7 not found")
+	problem.Extensions = map[string]interface{}{"item_id": 7, "status": "shadowed"}
+	got := GraphQLErrorFrom(problem, "item", 0)
+
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `{"message":"item 7 not found","path":["item",0],"extensions":{"item_id":7,"status":404,"title":"Not Found","type":"` + problem.Type + `"}}`
+	if string(encoded) != want {
+		t.Fatalf("expected %s, got %s", want, encoded)
+	}
+
+	plain := GraphQLErrorFrom(errors.New("boom"))
+	if plain.Message != "boom" || plain.Extensions != nil || plain.Path != nil {
+		t.Fatalf("unexpected plain error: %+v", plain)
+	}
+}
@@ -0,0 +1,349 @@
+package httpsuite
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLoginFreeAttempts    = 3
+	defaultLoginBaseDelay       = time.Second
+	defaultLoginMaxDelay        = time.Minute
+	defaultLoginLockoutAfter    = 10
+	defaultLoginLockoutDuration = 15 * time.Minute
+)
+
+// ErrTooManyAttempts is returned by LoginGuard.Check after it replied with
+// 429 Too Many Requests.
+var ErrTooManyAttempts = errors.New("too many failed attempts")
+
+// LoginAttempts is the failure history of one identifier. Pending counts
+// the attempts admitted by Check whose outcome is not known yet.
+type LoginAttempts struct {
+	Failures    int
+	Pending     int
+	LastFailure time.Time
+}
+
+// LoginAttemptStore keeps failure counts per key. Keys are hashes of the
+// identifiers, so stores never see account names. Entries may be dropped
+// ttl after the last change.
+//
+// Reserve adds a pending attempt and returns the history including it, in
+// one atomic step, so parallel guesses see each other. RecordFailure turns a
+// pending attempt into a failure, Release drops one, and Reset forgets
+// everything. Pending never goes below zero.
+type LoginAttemptStore interface {
+	Get(ctx context.Context, key string) (LoginAttempts, error)
+	Reserve(ctx context.Context, key string, at time.Time, ttl time.Duration) (LoginAttempts, error)
+	Release(ctx context.Context, key string) error
+	RecordFailure(ctx context.Context, key string, at time.Time, ttl time.Duration) (LoginAttempts, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// MemoryLoginAttemptStore is an in-memory LoginAttemptStore for
+// single-instance services.
+type MemoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryLoginAttempts
+	lastSweep time.Time
+}
+
+type memoryLoginAttempts struct {
+	LoginAttempts
+	expires time.Time
+}
+
+// NewMemoryLoginAttemptStore returns an empty MemoryLoginAttemptStore.
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{entries: make(map[string]memoryLoginAttempts)}
+}
+
+// Get returns the failures recorded for key.
+func (s *MemoryLoginAttemptStore) Get(_ context.Context, key string) (LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries[key].LoginAttempts, nil
+}
+
+// Reserve adds a pending attempt at the given time.
+func (s *MemoryLoginAttemptStore) Reserve(_ context.Context, key string, at time.Time, ttl time.Duration) (LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := s.live(key, at, ttl)
+	entry.Pending++
+	entry.expires = at.Add(ttl)
+	s.entries[key] = entry
+	return entry.LoginAttempts, nil
+}
+
+// Release drops a pending attempt.
+func (s *MemoryLoginAttemptStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[key]; ok && entry.Pending > 0 {
+		entry.Pending--
+		s.entries[key] = entry
+	}
+	return nil
+}
+
+// RecordFailure turns a pending attempt into a failure at the given time.
+// Failures older than ttl are forgotten.
+func (s *MemoryLoginAttemptStore) RecordFailure(_ context.Context, key string, at time.Time, ttl time.Duration) (LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := s.live(key, at, ttl)
+	entry.Pending = max(entry.Pending-1, 0)
+	entry.Failures++
+	entry.LastFailure = at
+	entry.expires = at.Add(ttl)
+	s.entries[key] = entry
+	return entry.LoginAttempts, nil
+}
+
+// live returns the unexpired entry for key. Expired entries are dropped at
+// most once per ttl. The caller holds s.mu.
+func (s *MemoryLoginAttemptStore) live(key string, at time.Time, ttl time.Duration) memoryLoginAttempts {
+	if at.Sub(s.lastSweep) >= ttl {
+		s.lastSweep = at
+		for other, entry := range s.entries {
+			if !entry.expires.After(at) {
+				delete(s.entries, other)
+			}
+		}
+	}
+	entry := s.entries[key]
+	if !entry.expires.After(at) {
+		entry = memoryLoginAttempts{}
+	}
+	return entry
+}
+
+// Reset forgets the failures recorded for key.
+func (s *MemoryLoginAttemptStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// LoginGuardOptions configures a LoginGuard. After FreeAttempts failures,
+// each further attempt must wait BaseDelay, doubling per failure up to
+// MaxDelay. After LockoutAfter failures the identifier is locked for
+// LockoutDuration. Failures are forgotten LockoutDuration after the last one.
+type LoginGuardOptions struct {
+	// FreeAttempts defaults to 3.
+	FreeAttempts int
+	// BaseDelay defaults to one second and MaxDelay to one minute.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// LockoutAfter defaults to 10 failures and LockoutDuration to 15 minutes.
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	// Store defaults to a MemoryLoginAttemptStore.
+	Store LoginAttemptStore
+	Now   func() time.Time
+	// Problems overrides the problem config resolved from the request context.
+	Problems *ProblemConfig
+}
+
+// LoginGuard slows down password and OTP guessing per identifier, such as
+// an email address or a pending sign-in. It counts failures for every
+// identifier, whether or not an account exists, and its responses never
+// mention accounts, so they cannot be used to discover them.
+type LoginGuard struct {
+	options LoginGuardOptions
+}
+
+// NewLoginGuard returns a guard with the given policy.
+func NewLoginGuard(opts *LoginGuardOptions) *LoginGuard {
+	options := LoginGuardOptions{
+		FreeAttempts:    defaultLoginFreeAttempts,
+		BaseDelay:       defaultLoginBaseDelay,
+		MaxDelay:        defaultLoginMaxDelay,
+		LockoutAfter:    defaultLoginLockoutAfter,
+		LockoutDuration: defaultLoginLockoutDuration,
+		Now:             time.Now,
+	}
+	if opts != nil {
+		options.Store = opts.Store
+		options.Problems = opts.Problems
+		if opts.FreeAttempts > 0 {
+			options.FreeAttempts = opts.FreeAttempts
+		}
+		if opts.BaseDelay > 0 {
+			options.BaseDelay = opts.BaseDelay
+		}
+		if opts.MaxDelay > 0 {
+			options.MaxDelay = opts.MaxDelay
+		}
+		if opts.LockoutAfter > 0 {
+			options.LockoutAfter = opts.LockoutAfter
+		}
+		if opts.LockoutDuration > 0 {
+			options.LockoutDuration = opts.LockoutDuration
+		}
+		if opts.Now != nil {
+			options.Now = opts.Now
+		}
+	}
+	if options.Store == nil {
+		options.Store = NewMemoryLoginAttemptStore()
+	}
+	return &LoginGuard{options: options}
+}
+
+// Check rejects the attempt while identifier is backing off or locked out,
+// replying with a 429 problem and Retry-After and returning
+// ErrTooManyAttempts. Store errors are logged and let the attempt through.
+//
+// An admitted attempt is reserved, and attempts still in flight count as
+// failures, so parallel guesses cannot all pass before one of them fails.
+// End every admitted attempt with RejectCredentials, RecordFailure,
+// RecordSuccess, or Release; otherwise it counts until the entry expires.
+//
+//	if err := guard.Check(w, r, req.Email); err != nil {
+//		return
+//	}
+//	if !users.Authenticate(req.Email, req.Password) {
+//		guard.RejectCredentials(w, r, req.Email)
+//		return
+//	}
+//	_ = guard.RecordSuccess(r.Context(), req.Email)
+func (g *LoginGuard) Check(w http.ResponseWriter, r *http.Request, identifier string) error {
+	key := loginGuardKey(identifier)
+	now := g.options.Now()
+	attempts, err := g.options.Store.Reserve(r.Context(), key, now, g.options.LockoutDuration)
+	if err != nil {
+		log.Printf("Failed to reserve login attempt: %v", err)
+		return nil
+	}
+	if inFlight := attempts.Pending - 1; inFlight > 0 {
+		attempts.Failures += inFlight
+		attempts.LastFailure = now
+	}
+	wait := g.wait(attempts)
+	if wait <= 0 {
+		return nil
+	}
+	if err := g.options.Store.Release(r.Context(), key); err != nil {
+		log.Printf("Failed to release login attempt: %v", err)
+	}
+
+	problems := resolveProblemConfig(r.Context(), g.options.Problems)
+	sendRequestProblem(w, r, http.StatusTooManyRequests, Problem(http.StatusTooManyRequests).
+		Type(problems.TypeURL("too_many_requests_error")).
+		Title("Too Many Requests").
+		Detail("too many failed attempts; try again later").
+		RetryAfter(wait).
+		Build())
+	return ErrTooManyAttempts
+}
+
+// RecordFailure counts a failed attempt for identifier.
+func (g *LoginGuard) RecordFailure(ctx context.Context, identifier string) error {
+	_, err := g.options.Store.RecordFailure(ctx, loginGuardKey(identifier), g.options.Now(), g.options.LockoutDuration)
+	return err
+}
+
+// Release ends an attempt admitted by Check without counting it, for outcomes
+// that are neither a failure nor a success.
+func (g *LoginGuard) Release(ctx context.Context, identifier string) error {
+	return g.options.Store.Release(ctx, loginGuardKey(identifier))
+}
+
+// RecordSuccess forgets the failures of identifier after a successful attempt.
+func (g *LoginGuard) RecordSuccess(ctx context.Context, identifier string) error {
+	return g.options.Store.Reset(ctx, loginGuardKey(identifier))
+}
+
+// RejectCredentials counts a failed attempt and replies with a 401 problem
+// that reads the same for unknown accounts and wrong secrets.
+func (g *LoginGuard) RejectCredentials(w http.ResponseWriter, r *http.Request, identifier string) {
+	if err := g.RecordFailure(r.Context(), identifier); err != nil {
+		log.Printf("Failed to record login attempt: %v", err)
+	}
+	problems := resolveProblemConfig(r.Context(), g.options.Problems)
+	status := http.StatusUnauthorized
+	sendRequestProblem(w, r, status, NewProblemDetails(status, problems.TypeURL("unauthorized_error"), "Invalid Credentials", "the credentials are invalid"))
+}
+
+// Middleware guards routes that answer failed attempts with 401 or 403 and
+// successful ones with 2xx, such as OTP verification. identify returns the
+// identifier from the request; requests without one pass through unguarded.
+// Other statuses, and handlers that panic, release the attempt.
+func (g *LoginGuard) Middleware(identify func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identifier := identify(r)
+			if identifier == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err := g.Check(w, r, identifier); err != nil {
+				return
+			}
+
+			completed := false
+			defer func() {
+				if !completed {
+					if err := g.Release(r.Context(), identifier); err != nil {
+						log.Printf("Failed to release login attempt: %v", err)
+					}
+				}
+			}()
+			recorder := newStatusRecorder(w)
+			next.ServeHTTP(recorder, r)
+			completed = true
+
+			var err error
+			switch status := recorder.Status(); {
+			case status == http.StatusUnauthorized || status == http.StatusForbidden:
+				err = g.RecordFailure(r.Context(), identifier)
+			case status >= 200 && status < 300:
+				err = g.RecordSuccess(r.Context(), identifier)
+			default:
+				err = g.Release(r.Context(), identifier)
+			}
+			if err != nil {
+				log.Printf("Failed to record login attempt: %v", err)
+			}
+		})
+	}
+}
+
+// wait returns how long the next attempt must wait after attempts.
+func (g *LoginGuard) wait(attempts LoginAttempts) time.Duration {
+	var delay time.Duration
+	switch {
+	case attempts.Failures >= g.options.LockoutAfter:
+		delay = g.options.LockoutDuration
+	case attempts.Failures > g.options.FreeAttempts:
+		delay = g.options.BaseDelay
+		for range attempts.Failures - g.options.FreeAttempts - 1 {
+			if delay >= g.options.MaxDelay {
+				break
+			}
+			delay *= 2
+		}
+		delay = min(delay, g.options.MaxDelay)
+	default:
+		return 0
+	}
+	return attempts.LastFailure.Add(delay).Sub(g.options.Now())
+}
+
+// loginGuardKey normalizes identifier and hashes it, so "Ann@Example.com"
+// and "ann@example.com " share a counter and stores hold no account names.
+func loginGuardKey(identifier string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(identifier))))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,229 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoginGuardBackoffAndLockout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	guard := NewLoginGuard(&LoginGuardOptions{
+		FreeAttempts:    2,
+		BaseDelay:       time.Second,
+		MaxDelay:        4 * time.Second,
+		LockoutAfter:    6,
+		LockoutDuration: time.Hour,
+		Now:             func() time.Time { return now },
+	})
+	ctx := context.Background()
+
+	wantWaits := []time.Duration{0, 0, time.Second, 2 * time.Second, 4 * time.Second, time.Hour}
+	for i, want := range wantWaits {
+		if err := guard.RecordFailure(ctx, "ann@example.com"); err != nil {
+			t.Fatalf("record failure: %v", err)
+		}
+		attempts, _ := guard.options.Store.Get(ctx, loginGuardKey("ann@example.com"))
+		if got := guard.wait(attempts); got != want {
+			t.Fatalf("failure %d: expected wait %s, got %s", i+1, want, got)
+		}
+	}
+
+	now = now.Add(30 * time.Minute)
+	w := httptest.NewRecorder()
+	if err := guard.Check(w, httptest.NewRequest(http.MethodPost, "/login", nil), " Ann@Example.com"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("expected the normalized identifier to be locked, got %v", err)
+	}
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1800" {
+		t.Fatalf("expected 429 with Retry-After 1800, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	if err := guard.RecordSuccess(ctx, "ann@example.com"); err != nil {
+		t.Fatalf("record success: %v", err)
+	}
+	if err := guard.Check(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", nil), "ann@example.com"); err != nil {
+		t.Fatalf("expected success to reset the failures, got %v", err)
+	}
+}
+
+func TestLoginGuardResponsesDoNotLeakAccounts(t *testing.T) {
+	t.Parallel()
+
+	guard := NewLoginGuard(&LoginGuardOptions{FreeAttempts: 1, LockoutAfter: 2})
+
+	// Only ann@example.com exists; the guard cannot tell, and must not.
+	var lockouts []ProblemDetails
+	for _, identifier := range []string{"ann@example.com", "nobody@example.com"} {
+		for range 2 {
+			w := httptest.NewRecorder()
+			guard.RejectCredentials(w, httptest.NewRequest(http.MethodPost, "/login", nil), identifier)
+			var problem ProblemDetails
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if w.Code != http.StatusUnauthorized || problem.Detail != "the credentials are invalid" {
+				t.Fatalf("expected generic 401, got %d %+v", w.Code, problem)
+			}
+		}
+		w := httptest.NewRecorder()
+		if err := guard.Check(w, httptest.NewRequest(http.MethodPost, "/login", nil), identifier); err == nil {
+			t.Fatalf("expected %s to be locked out", identifier)
+		}
+		var problem ProblemDetails
+		if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+			t.Fatalf("decode problem: %v", err)
+		}
+		problem.Instance = ""
+		lockouts = append(lockouts, problem)
+	}
+	first, second := lockouts[0], lockouts[1]
+	if first.Status != second.Status || first.Title != second.Title || first.Detail != second.Detail || first.Extensions["retry_after"] != second.Extensions["retry_after"] {
+		t.Fatalf("expected identical lockout responses, got %+v and %+v", first, second)
+	}
+}
+
+func TestLoginGuardMiddleware(t *testing.T) {
+	t.Parallel()
+
+	guard := NewLoginGuard(&LoginGuardOptions{FreeAttempts: 2, LockoutAfter: 2})
+	handler := guard.Middleware(func(r *http.Request) string {
+		return r.Header.Get("X-Pending-Login")
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("code") != "123456" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	attempt := func(code string) int {
+		req := httptest.NewRequest(http.MethodPost, "/otp?code="+code, nil)
+		req.Header.Set("X-Pending-Login", "pending-1")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, tt := range []struct {
+		code string
+		want int
+	}{
+		{"000000", http.StatusUnauthorized},
+		{"123456", http.StatusNoContent},
+		{"000000", http.StatusUnauthorized},
+		{"111111", http.StatusUnauthorized},
+		{"123456", http.StatusTooManyRequests},
+	} {
+		if got := attempt(tt.code); got != tt.want {
+			t.Fatalf("code %s: expected %d, got %d", tt.code, tt.want, got)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/otp?code=123456", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected requests without an identifier to pass, got %d", w.Code)
+	}
+}
+
+func TestLoginGuardCountsParallelGuesses(t *testing.T) {
+	t.Parallel()
+
+	guard := NewLoginGuard(&LoginGuardOptions{FreeAttempts: 3})
+	var admitted atomic.Int32
+	release := make(chan struct{})
+	handler := guard.Middleware(func(r *http.Request) string {
+		return "ann@example.com"
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		admitted.Add(1)
+		<-release
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	const guesses = 20
+	codes := make([]int, guesses)
+	var finished atomic.Int32
+	var wg sync.WaitGroup
+	for i := range guesses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+			codes[i] = w.Code
+			finished.Add(1)
+		}()
+	}
+	// Every guess has passed Check or been limited before any fails.
+	waitForCalls(t, &admitted, 4)
+	waitForCalls(t, &finished, guesses-4)
+	close(release)
+	wg.Wait()
+
+	if admitted.Load() != 4 {
+		t.Fatalf("expected the free attempts plus one to reach the handler, got %d", admitted.Load())
+	}
+	limited := 0
+	for _, code := range codes {
+		if code == http.StatusTooManyRequests {
+			limited++
+		}
+	}
+	if limited != guesses-4 {
+		t.Fatalf("expected %d guesses to be limited, got %d", guesses-4, limited)
+	}
+	attempts, _ := guard.options.Store.Get(context.Background(), loginGuardKey("ann@example.com"))
+	if attempts.Failures != 4 || attempts.Pending != 0 {
+		t.Fatalf("expected 4 failures and no pending attempts, got %+v", attempts)
+	}
+}
+
+func TestLoginGuardMiddlewareReleasesOtherOutcomes(t *testing.T) {
+	t.Parallel()
+
+	guard := NewLoginGuard(&LoginGuardOptions{FreeAttempts: 1})
+	handler := guard.Middleware(func(r *http.Request) string {
+		return "ann@example.com"
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("panic") != "" {
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+
+	func() {
+		defer func() { _ = recover() }()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login?panic=1", nil))
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", nil))
+
+	attempts, _ := guard.options.Store.Get(context.Background(), loginGuardKey("ann@example.com"))
+	if attempts.Failures != 0 || attempts.Pending != 0 {
+		t.Fatalf("expected released attempts, got %+v", attempts)
+	}
+}
+
+func TestMemoryLoginAttemptStoreExpiry(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryLoginAttemptStore()
+	ctx := context.Background()
+	start := time.Unix(1700000000, 0)
+	_, _ = store.RecordFailure(ctx, "a", start, time.Minute)
+	_, _ = store.RecordFailure(ctx, "b", start, time.Minute)
+	attempts, _ := store.RecordFailure(ctx, "a", start.Add(2*time.Minute), time.Minute)
+	if attempts.Failures != 1 {
+		t.Fatalf("expected expired failures to be forgotten, got %d", attempts.Failures)
+	}
+	if _, ok := store.entries["b"]; ok {
+		t.Fatal("expected expired entries to be swept")
+	}
+}
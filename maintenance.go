@@ -0,0 +1,124 @@
+package httpsuite
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaintenanceDetail     = "the service is undergoing maintenance"
+	defaultMaintenanceRetryAfter = time.Minute
+)
+
+// MaintenanceOptions configures a MaintenanceSwitch.
+type MaintenanceOptions struct {
+	// AllowPaths stay reachable during maintenance. Entries ending in "/*"
+	// match every path below the prefix, e.g. "/health/*".
+	AllowPaths []string
+	// Detail is the default problem detail. Defaults to a generic message.
+	Detail string
+	// RetryAfter is the default Retry-After hint. Defaults to one minute.
+	RetryAfter time.Duration
+}
+
+// MaintenanceSwitch toggles maintenance mode at runtime. Use one switch for
+// the whole service or separate switches for route groups.
+type MaintenanceSwitch struct {
+	mu         sync.RWMutex
+	enabled    bool
+	detail     string
+	retryAfter time.Duration
+	options    MaintenanceOptions
+}
+
+// NewMaintenanceSwitch returns a disabled switch.
+func NewMaintenanceSwitch(opts *MaintenanceOptions) *MaintenanceSwitch {
+	options := MaintenanceOptions{Detail: defaultMaintenanceDetail, RetryAfter: defaultMaintenanceRetryAfter}
+	if opts != nil {
+		options.AllowPaths = append([]string(nil), opts.AllowPaths...)
+		if opts.Detail != "" {
+			options.Detail = opts.Detail
+		}
+		if opts.RetryAfter > 0 {
+			options.RetryAfter = opts.RetryAfter
+		}
+	}
+	return &MaintenanceSwitch{options: options}
+}
+
+// Enable turns maintenance mode on. Empty detail and zero retryAfter fall
+// back to the switch defaults.
+func (m *MaintenanceSwitch) Enable(detail string, retryAfter time.Duration) {
+	if detail == "" {
+		detail = m.options.Detail
+	}
+	if retryAfter <= 0 {
+		retryAfter = m.options.RetryAfter
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = true
+	m.detail = detail
+	m.retryAfter = retryAfter
+}
+
+// Disable turns maintenance mode off.
+func (m *MaintenanceSwitch) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = false
+}
+
+// Enabled reports whether maintenance mode is on.
+func (m *MaintenanceSwitch) Enabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled
+}
+
+// Middleware short-circuits requests with a 503 problem and Retry-After while
+// maintenance mode is on, except for allow-listed paths.
+func (m *MaintenanceSwitch) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.mu.RLock()
+			enabled, detail, retryAfter := m.enabled, m.detail, m.retryAfter
+			m.mu.RUnlock()
+			if !enabled || m.allowed(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			problems := resolveProblemConfig(r.Context(), nil)
+			sendRequestProblem(w, r, http.StatusServiceUnavailable, Problem(http.StatusServiceUnavailable).
+				Type(problems.TypeURL("service_unavailable_error")).
+				Title("Service Unavailable").
+				Detail(detail).
+				RetryAfter(retryAfter).
+				Build())
+		})
+	}
+}
+
+func (m *MaintenanceSwitch) allowed(path string) bool {
+	return matchPathPatterns(m.options.AllowPaths, path)
+}
+
+// matchPathPatterns reports whether path equals one of patterns or, for
+// patterns ending in "/*", lies below the prefix.
+func matchPathPatterns(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if path == pattern {
+			return true
+		}
+	}
+	return false
+}
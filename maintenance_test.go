@@ -0,0 +1,63 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceSwitch(t *testing.T) {
+	t.Parallel()
+
+	maintenance := NewMaintenanceSwitch(&MaintenanceOptions{AllowPaths: []string{"/healthz", "/admin/*"}})
+	handler := maintenance.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	if w := serve("/orders"); w.Code != http.StatusNoContent {
+		t.Fatalf("expected requests to pass while disabled, got %d", w.Code)
+	}
+
+	maintenance.Enable("database migration in progress", 90*time.Second)
+	if !maintenance.Enabled() {
+		t.Fatal("expected maintenance to be enabled")
+	}
+
+	w := serve("/orders")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "90" {
+		t.Fatalf("expected 503 with Retry-After 90, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	var problem ProblemDetails
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Detail != "database migration in progress" || problem.Type != GetProblemTypeURL("service_unavailable_error") {
+		t.Fatalf("unexpected problem %#v", problem)
+	}
+
+	for _, path := range []string{"/healthz", "/admin", "/admin/jobs"} {
+		if w := serve(path); w.Code != http.StatusNoContent {
+			t.Fatalf("expected %s to stay reachable, got %d", path, w.Code)
+		}
+	}
+	if w := serve("/administrator"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected prefix match to respect path segments, got %d", w.Code)
+	}
+
+	maintenance.Enable("", 0)
+	if w := serve("/orders"); w.Header().Get("Retry-After") != "60" {
+		t.Fatalf("expected default Retry-After, got %q", w.Header().Get("Retry-After"))
+	}
+
+	maintenance.Disable()
+	if w := serve("/orders"); w.Code != http.StatusNoContent {
+		t.Fatalf("expected requests to pass after disabling, got %d", w.Code)
+	}
+}
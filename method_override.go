@@ -0,0 +1,78 @@
+package httpsuite
+
+import (
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const defaultMethodOverrideHeader = "X-HTTP-Method-Override"
+
+var defaultOverrideMethods = []string{http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// MethodOverrideOptions configures MethodOverride.
+type MethodOverrideOptions struct {
+	// Methods a POST may be turned into. Defaults to PUT, PATCH, and DELETE.
+	Methods []string
+	// Header carries the method. Defaults to X-HTTP-Method-Override.
+	Header string
+	// FormField, e.g. "_method", is also read from URL-encoded POST bodies
+	// when set. Reading it parses the form, consuming the body.
+	FormField string
+}
+
+// MethodOverride returns middleware that rewrites the method of POST
+// requests to the one named by the override header or form field, for
+// clients behind proxies that only let GET and POST through. Mount it before
+// the router so routing and parsing see the rewritten method. Overrides to
+// methods outside Methods are rejected with a 400 problem; other requests
+// pass unchanged.
+func MethodOverride(opts *MethodOverrideOptions) func(http.Handler) http.Handler {
+	options := MethodOverrideOptions{Methods: defaultOverrideMethods, Header: defaultMethodOverrideHeader}
+	if opts != nil {
+		if len(opts.Methods) > 0 {
+			options.Methods = make([]string, len(opts.Methods))
+			for i, method := range opts.Methods {
+				options.Methods[i] = strings.ToUpper(method)
+			}
+		}
+		if opts.Header != "" {
+			options.Header = opts.Header
+		}
+		options.FormField = opts.FormField
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				next.ServeHTTP(w, r)
+				return
+			}
+			method := r.Header.Get(options.Header)
+			if method == "" && options.FormField != "" && isURLEncodedForm(r) {
+				method = r.PostFormValue(options.FormField)
+			}
+			if method == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			method = strings.ToUpper(method)
+			if !slices.Contains(options.Methods, method) {
+				problems := resolveProblemConfig(r.Context(), nil)
+				sendRequestProblem(w, r, http.StatusBadRequest, NewProblemDetails(http.StatusBadRequest, problems.TypeURL("bad_request_error"), "Bad Request", "method override "+method+" is not allowed"))
+				return
+			}
+			r = r.Clone(r.Context())
+			r.Method = method
+			r.Header.Del(options.Header)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isURLEncodedForm(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/x-www-form-urlencoded"
+}
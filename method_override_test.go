@@ -0,0 +1,69 @@
+package httpsuite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestMethodOverride(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        *MethodOverrideOptions
+		method      string
+		header      string
+		form        url.Values
+		wantStatus  int
+		wantMethod  string
+		wantFormKey string
+	}{
+		{name: "header", method: http.MethodPost, header: "delete", wantStatus: http.StatusOK, wantMethod: http.MethodDelete},
+		{name: "no override", method: http.MethodPost, wantStatus: http.StatusOK, wantMethod: http.MethodPost},
+		{name: "only post is overridden", method: http.MethodGet, header: "DELETE", wantStatus: http.StatusOK, wantMethod: http.MethodGet},
+		{name: "disallowed method", method: http.MethodPost, header: "TRACE", wantStatus: http.StatusBadRequest},
+		{name: "custom methods", opts: &MethodOverrideOptions{Methods: []string{"patch"}}, method: http.MethodPost, header: "PUT", wantStatus: http.StatusBadRequest},
+		{name: "form field ignored without opt-in", method: http.MethodPost, form: url.Values{"_method": {"PUT"}}, wantStatus: http.StatusOK, wantMethod: http.MethodPost},
+		{name: "form field", opts: &MethodOverrideOptions{FormField: "_method"}, method: http.MethodPost, form: url.Values{"_method": {"PUT"}, "name": {"lamp"}}, wantStatus: http.StatusOK, wantMethod: http.MethodPut, wantFormKey: "lamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotMethod, gotHeader, gotName string
+			handler := MethodOverride(tt.opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotMethod = r.Method
+				gotHeader = r.Header.Get("X-HTTP-Method-Override")
+				gotName = r.FormValue("name")
+			}))
+
+			req := httptest.NewRequest(tt.method, "/items/7", nil)
+			if tt.form != nil {
+				req = httptest.NewRequest(tt.method, "/items/7", strings.NewReader(tt.form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			if tt.header != "" {
+				req.Header.Set("X-HTTP-Method-Override", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if gotMethod != tt.wantMethod {
+				t.Fatalf("expected method %q, got %q", tt.wantMethod, gotMethod)
+			}
+			if tt.wantMethod != "" && tt.wantMethod != tt.method && gotHeader != "" {
+				t.Fatalf("expected the override header to be removed, got %q", gotHeader)
+			}
+			if gotName != tt.wantFormKey {
+				t.Fatalf("expected form value %q, got %q", tt.wantFormKey, gotName)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package httpsuite
+
+import "net/http"
+
+// MiddlewareChain is an immutable list of middleware, applied in order.
+type MiddlewareChain struct {
+	middlewares []func(http.Handler) http.Handler
+}
+
+// Chain composes middlewares so the first one listed is the outermost, for
+// stacking the suite's middleware on a plain http.ServeMux:
+//
+//	handler := httpsuite.Chain(httpsuite.MethodOverride(nil), httpsuite.HTMLProblems(nil)).Then(mux)
+//
+// Nil middlewares are skipped.
+func Chain(middlewares ...func(http.Handler) http.Handler) MiddlewareChain {
+	return MiddlewareChain{}.Append(middlewares...)
+}
+
+// Append returns a new chain running middlewares after the existing ones.
+// The receiver is left unchanged, so a base chain can be extended per route.
+func (c MiddlewareChain) Append(middlewares ...func(http.Handler) http.Handler) MiddlewareChain {
+	combined := make([]func(http.Handler) http.Handler, 0, len(c.middlewares)+len(middlewares))
+	combined = append(combined, c.middlewares...)
+	for _, middleware := range middlewares {
+		if middleware != nil {
+			combined = append(combined, middleware)
+		}
+	}
+	return MiddlewareChain{middlewares: combined}
+}
+
+// Then wraps handler with the chain. A nil handler means
+// http.DefaultServeMux.
+func (c MiddlewareChain) Then(handler http.Handler) http.Handler {
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+	return handler
+}
+
+// ThenFunc wraps fn with the chain.
+func (c MiddlewareChain) ThenFunc(fn http.HandlerFunc) http.Handler {
+	if fn == nil {
+		return c.Then(nil)
+	}
+	return c.Then(fn)
+}
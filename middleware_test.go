@@ -0,0 +1,88 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func tagMiddleware(tag string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Order", tag)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	base := Chain(tagMiddleware("a"), nil, tagMiddleware("b"))
+	extended := base.Append(tagMiddleware("c"))
+
+	tests := []struct {
+		name  string
+		chain MiddlewareChain
+		want  string
+	}{
+		{name: "empty", chain: Chain(), want: "handler"},
+		{name: "order", chain: base, want: "a,b,handler"},
+		{name: "append", chain: extended, want: "a,b,c,handler"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := tt.chain.ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Order", "handler")
+			})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if got := strings.Join(rec.Header().Values("X-Order"), ","); got != tt.want {
+				t.Fatalf("expected order %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestChainNilHandler(t *testing.T) {
+	t.Parallel()
+
+	if handler := Chain().Then(nil); handler != http.DefaultServeMux {
+		t.Fatalf("expected http.DefaultServeMux, got %T", handler)
+	}
+}
+
+func TestAPIUse(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Items"})
+	before := Handle(api, http.MethodGet, "/before", func(ctx context.Context, _ NoBody) (NoBody, error) {
+		return NoBody{}, nil
+	}, nil)
+	api.Use(tagMiddleware("outer"))
+	api.Use(tagMiddleware("inner"))
+	after := Handle(api, http.MethodGet, "/after", func(ctx context.Context, _ NoBody) (NoBody, error) {
+		return NoBody{}, nil
+	}, nil)
+
+	rec := httptest.NewRecorder()
+	after.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/after", nil))
+	if got := strings.Join(rec.Header().Values("X-Order"), ","); got != "outer,inner" {
+		t.Fatalf("expected order %q, got %q", "outer,inner", got)
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	before.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/before", nil))
+	if got := rec.Header().Values("X-Order"); len(got) != 0 {
+		t.Fatalf("expected no middleware on earlier routes, got %v", got)
+	}
+}
@@ -0,0 +1,201 @@
+package httpsuite
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrCurrencyMismatch is returned by Money arithmetic on different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// currencyMinorUnits lists active ISO 4217 currency codes with their number
+// of minor units, e.g. 2 for cents.
+var currencyMinorUnits = func() map[string]int {
+	units := make(map[string]int)
+	for digits, codes := range map[int]string{
+		0: "BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF",
+		2: "AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BOV BRL BSD BTN BWP BYN BZD " +
+			"CAD CDF CHE CHF CHW CNY COP COU CRC CUP CVE CZK DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS " +
+			"GIP GMD GTQ GYD HKD HNL HTG HUF IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD LSL " +
+			"MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD PAB PEN PGK " +
+			"PHP PKR PLN QAR RON RSD RUB SAR SBD SCR SDG SEK SGD SHP SLE SLL SOS SRD SSP STN SVC SYP SZL THB " +
+			"TJS TMT TOP TRY TTD TWD TZS UAH USD USN UYU UZS VED VES WST XCD XCG YER ZAR ZMW ZWG ZWL",
+		3: "BHD IQD JOD KWD LYD OMR TND",
+		4: "CLF UYW",
+	} {
+		for _, code := range strings.Fields(codes) {
+			units[code] = digits
+		}
+	}
+	return units
+}()
+
+// CurrencyMinorUnits returns the number of decimal places of an ISO 4217
+// currency code, and false for unknown codes.
+func CurrencyMinorUnits(currency string) (int, bool) {
+	units, ok := currencyMinorUnits[currency]
+	return units, ok
+}
+
+// Money is an exact amount in an ISO 4217 currency, encoded in JSON as
+// {"amount":"12.30","currency":"USD"}. It implements SelfValidator, so
+// ParseRequest rejects unknown currencies and amounts with more decimal
+// places than the currency allows.
+type Money struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// NewMoney parses amount and returns a valid Money in currency.
+func NewMoney(amount, currency string) (Money, error) {
+	value, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	money := Money{Amount: value, Currency: strings.ToUpper(currency)}
+	if err := money.Validate(); err != nil {
+		return Money{}, err
+	}
+	return money, nil
+}
+
+// MoneyFromMinorUnits converts an integer count of minor units, such as
+// cents, into Money.
+func MoneyFromMinorUnits(units int64, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	digits, ok := CurrencyMinorUnits(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("unknown currency %q", currency)
+	}
+	return Money{Amount: NewDecimal(units, digits), Currency: currency}, nil
+}
+
+// Validate reports an unknown currency or an amount whose scale exceeds the
+// currency's minor units as ValidationErrors on the currency and amount
+// fields.
+func (m Money) Validate() error {
+	digits, ok := CurrencyMinorUnits(m.Currency)
+	if !ok {
+		return ValidationErrors{{Field: "currency", Message: "must be an ISO 4217 currency code"}}
+	}
+	if m.Amount.Scale() > digits && !m.Amount.Equal(m.Amount.Round(digits)) {
+		return ValidationErrors{{Field: "amount", Message: fmt.Sprintf("must have at most %d decimal places for %s", digits, m.Currency)}}
+	}
+	return nil
+}
+
+// MinorUnits returns the amount as an integer count of minor units, as
+// payment processors expect. It fails when the amount has sub-unit digits or
+// does not fit in an int64.
+func (m Money) MinorUnits() (int64, error) {
+	digits, ok := CurrencyMinorUnits(m.Currency)
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %q", m.Currency)
+	}
+	rounded := m.Amount.Round(digits)
+	if !rounded.Equal(m.Amount) {
+		return 0, fmt.Errorf("amount %s has more than %d decimal places", m.Amount, digits)
+	}
+	if !rounded.value().IsInt64() {
+		return 0, fmt.Errorf("amount %s overflows minor units", m.Amount)
+	}
+	return rounded.value().Int64(), nil
+}
+
+// Add returns m + other, or ErrCurrencyMismatch.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount.Add(other.Amount), Currency: m.Currency}, nil
+}
+
+// Sub returns m - other, or ErrCurrencyMismatch.
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount.Sub(other.Amount), Currency: m.Currency}, nil
+}
+
+// Mul returns m × factor exactly, e.g. for quantities or tax rates. Call
+// Round before storing or charging the result.
+func (m Money) Mul(factor Decimal) Money {
+	return Money{Amount: m.Amount.Mul(factor), Currency: m.Currency}
+}
+
+// Round rounds the amount half away from zero to the currency's minor units.
+func (m Money) Round() Money {
+	digits, ok := CurrencyMinorUnits(m.Currency)
+	if !ok {
+		return m
+	}
+	return Money{Amount: m.Amount.Round(digits), Currency: m.Currency}
+}
+
+// Allocate splits m into parts proportional to weights without losing minor
+// units: the remainder left by rounding down is handed out one unit at a
+// time from the first part on.
+func (m Money) Allocate(weights ...int64) ([]Money, error) {
+	units, err := m.MinorUnits()
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	for _, weight := range weights {
+		if weight < 0 {
+			return nil, errors.New("allocation weights must not be negative")
+		}
+		total += weight
+	}
+	if total == 0 {
+		return nil, errors.New("allocation weights must not all be zero")
+	}
+
+	digits, _ := CurrencyMinorUnits(m.Currency)
+	shares := make([]int64, len(weights))
+	remaining := units
+	for i, weight := range weights {
+		share := new(big.Int).Mul(big.NewInt(units), big.NewInt(weight))
+		shares[i] = share.Quo(share, big.NewInt(total)).Int64()
+		remaining -= shares[i]
+	}
+	step := int64(1)
+	if remaining < 0 {
+		step = -1
+	}
+	for i := 0; remaining != 0; i = (i + 1) % len(shares) {
+		if weights[i] == 0 {
+			continue
+		}
+		shares[i] += step
+		remaining -= step
+	}
+
+	parts := make([]Money, len(shares))
+	for i, share := range shares {
+		parts[i] = Money{Amount: NewDecimal(share, digits), Currency: m.Currency}
+	}
+	return parts, nil
+}
+
+// Cmp compares two amounts in the same currency, or returns
+// ErrCurrencyMismatch.
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return m.Amount.Cmp(other.Amount), nil
+}
+
+// IsZero reports whether the amount is 0.
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// String formats m as "12.30 USD".
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
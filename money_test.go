@@ -0,0 +1,165 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMoneyValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		money     Money
+		wantField string
+	}{
+		{name: "valid", money: Money{Amount: MustParseDecimal("12.30"), Currency: "USD"}},
+		{name: "trailing zeros", money: Money{Amount: MustParseDecimal("12.300"), Currency: "USD"}},
+		{name: "three places", money: Money{Amount: MustParseDecimal("1.234"), Currency: "KWD"}},
+		{name: "unknown currency", money: Money{Amount: MustParseDecimal("1"), Currency: "usd"}, wantField: "currency"},
+		{name: "too many places", money: Money{Amount: MustParseDecimal("1.005"), Currency: "USD"}, wantField: "amount"},
+		{name: "yen has no minor units", money: Money{Amount: MustParseDecimal("100.5"), Currency: "JPY"}, wantField: "amount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.money.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("expected valid money, got %v", err)
+				}
+				return
+			}
+			var details ValidationErrors
+			if !errors.As(err, &details) || len(details) != 1 || details[0].Field != tt.wantField {
+				t.Fatalf("expected error on %q, got %v", tt.wantField, err)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	t.Parallel()
+
+	price, err := NewMoney("19.99", "usd")
+	if err != nil {
+		t.Fatalf("expected valid money, got %v", err)
+	}
+	shipping, _ := MoneyFromMinorUnits(499, "USD")
+	total, err := price.Add(shipping)
+	if err != nil || total.String() != "24.98 USD" {
+		t.Fatalf("expected 24.98 USD, got %s %v", total, err)
+	}
+	if refund, _ := total.Sub(price); refund.String() != "4.99 USD" {
+		t.Fatalf("expected 4.99 USD, got %s", refund)
+	}
+
+	tax := price.Mul(MustParseDecimal("0.0825"))
+	if tax.Amount.String() != "1.649175" || tax.Round().String() != "1.65 USD" {
+		t.Fatalf("expected exact tax rounded to 1.65, got %s", tax)
+	}
+	if _, err := tax.MinorUnits(); err == nil {
+		t.Fatal("expected unrounded amount to have no minor units")
+	}
+	if units, err := total.MinorUnits(); err != nil || units != 2498 {
+		t.Fatalf("expected 2498 minor units, got %d %v", units, err)
+	}
+
+	euros, _ := NewMoney("1", "EUR")
+	if _, err := price.Add(euros); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := price.Cmp(euros); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	if cmp, _ := total.Cmp(price); cmp != 1 {
+		t.Fatalf("expected total to exceed price, got %d", cmp)
+	}
+	if _, err := NewMoney("1.001", "USD"); err == nil {
+		t.Fatal("expected NewMoney to validate the scale")
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		amount  string
+		weights []int64
+		want    []string
+	}{
+		{amount: "100.00", weights: []int64{1, 1, 1}, want: []string{"33.34", "33.33", "33.33"}},
+		{amount: "0.05", weights: []int64{3, 7}, want: []string{"0.02", "0.03"}},
+		{amount: "-10.00", weights: []int64{1, 2}, want: []string{"-3.34", "-6.66"}},
+		{amount: "1.00", weights: []int64{0, 1, 1}, want: []string{"0.00", "0.50", "0.50"}},
+	}
+
+	for _, tt := range tests {
+		money, _ := NewMoney(tt.amount, "USD")
+		parts, err := money.Allocate(tt.weights...)
+		if err != nil {
+			t.Fatalf("Allocate(%s): unexpected error %v", tt.amount, err)
+		}
+		for i, part := range parts {
+			if part.Amount.String() != tt.want[i] {
+				t.Fatalf("Allocate(%s, %v): expected %v, got %v", tt.amount, tt.weights, tt.want, parts)
+			}
+		}
+	}
+
+	money, _ := NewMoney("1", "USD")
+	if _, err := money.Allocate(0, 0); err == nil {
+		t.Fatal("expected error for zero weights")
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	t.Parallel()
+
+	money, _ := NewMoney("12.30", "EUR")
+	encoded, err := json.Marshal(money)
+	if err != nil || string(encoded) != `{"amount":"12.30","currency":"EUR"}` {
+		t.Fatalf("expected string amount, got %s %v", encoded, err)
+	}
+
+	var decoded Money
+	if err := json.Unmarshal([]byte(`{"amount":12.3,"currency":"EUR"}`), &decoded); err != nil {
+		t.Fatalf("expected numeric amount to decode, got %v", err)
+	}
+	if cmp, _ := decoded.Cmp(money); cmp != 0 {
+		t.Fatalf("expected %s, got %s", money, decoded)
+	}
+}
+
+type chargeRequest struct {
+	Price Money `json:"price"`
+}
+
+func TestParseRequestValidatesMoney(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	req := httptest.NewRequest(http.MethodPost, "/charges", bytes.NewBufferString(`{"price":{"amount":"9.999","currency":"USD"}}`))
+	w := httptest.NewRecorder()
+	if _, err := ParseRequest[*chargeRequest](w, req, nil, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var problem struct {
+		Errors []ValidationErrorDetail `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "price.amount" {
+		t.Fatalf("expected one error for price.amount, got %#v", problem.Errors)
+	}
+}
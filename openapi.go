@@ -0,0 +1,231 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIVersion is the OpenAPI version of generated documents.
+const OpenAPIVersion = "3.1.0"
+
+// OpenAPI generates an OpenAPI 3.1 document from the registered routes.
+// Schemas come from struct tags: json for names, validate for required
+// fields and constraints, and doc (or description), default, and example
+// for docs. Path parameters come from the route pattern; fields tagged
+// query:"name" or header:"Name" are documented as query and header
+// parameters. Every error response uses the ProblemDetails schema.
+func (a *API) OpenAPI() map[string]any {
+	generator := newSchemaGenerator()
+	paths := make(map[string]any)
+	for _, route := range a.Routes() {
+		path := openAPIPath(route.Pattern)
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = make(map[string]any)
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = generator.operation(route)
+	}
+	generator.problemRef()
+
+	info := map[string]any{"title": a.info.Title, "version": a.info.Version}
+	if a.info.Description != "" {
+		info["description"] = a.info.Description
+	}
+	return map[string]any{
+		"openapi":    OpenAPIVersion,
+		"info":       info,
+		"paths":      paths,
+		"components": map[string]any{"schemas": generator.components},
+	}
+}
+
+// OpenAPIHandler serves the generated document as JSON, typically mounted at
+// /openapi.json.
+func (a *API) OpenAPIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := json.Marshal(a.OpenAPI())
+		if err != nil {
+			log.Printf("Failed to encode OpenAPI document: %v", err)
+			ProblemResponse(w, nil)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(body); err != nil {
+			log.Printf("Failed to write OpenAPI document: %v", err)
+		}
+	})
+}
+
+func (g *schemaGenerator) operation(route RouteInfo) map[string]any {
+	options := route.Options
+	operationID := options.OperationID
+	if operationID == "" {
+		operationID = defaultOperationID(route.Method, route.Pattern)
+	}
+	operation := map[string]any{"operationId": operationID}
+	if options.Summary != "" {
+		operation["summary"] = options.Summary
+	}
+	if options.Description != "" {
+		operation["description"] = options.Description
+	}
+	if len(options.Tags) > 0 {
+		operation["tags"] = options.Tags
+	}
+
+	request := route.Request
+	for request != nil && request.Kind() == reflect.Pointer {
+		request = request.Elem()
+	}
+	if parameters := g.parameters(route, request); len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if request != nil && request.Kind() == reflect.Struct && methodHasBody(route.Method) {
+		exclude := make(map[string]bool, len(route.PathParams))
+		for _, param := range route.PathParams {
+			exclude[param] = true
+		}
+		body := g.structSchema(request, exclude)
+		if properties, _ := body["properties"].(map[string]any); len(properties) > 0 {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": body}},
+			}
+		}
+	}
+
+	responses := make(map[string]any)
+	success := map[string]any{"description": http.StatusText(options.Status)}
+	if route.Response != nil && options.Status != http.StatusNoContent {
+		envelope := map[string]any{
+			"type":     "object",
+			"required": []string{"data"},
+			"properties": map[string]any{
+				"data": g.schema(route.Response),
+				"meta": map[string]any{},
+			},
+		}
+		success["content"] = map[string]any{"application/json": map[string]any{"schema": envelope}}
+	}
+	responses[strconv.Itoa(options.Status)] = success
+
+	errorStatuses := append([]int{http.StatusInternalServerError}, options.Errors...)
+	if route.Request != nil {
+		errorStatuses = append(errorStatuses, http.StatusBadRequest)
+	}
+	for _, status := range errorStatuses {
+		responses[strconv.Itoa(status)] = map[string]any{
+			"description": http.StatusText(status),
+			"content":     map[string]any{"application/problem+json": map[string]any{"schema": g.problemRef()}},
+		}
+	}
+	operation["responses"] = responses
+	return operation
+}
+
+func (g *schemaGenerator) parameters(route RouteInfo, request reflect.Type) []any {
+	var parameters []any
+	for _, name := range route.PathParams {
+		schema := map[string]any{"type": "string"}
+		if field, ok := paramField(request, "path", name); ok {
+			schema = g.schema(field.Type)
+			applyFieldTags(schema, field)
+		}
+		parameters = append(parameters, map[string]any{"name": name, "in": "path", "required": true, "schema": schema})
+	}
+	if request == nil || request.Kind() != reflect.Struct {
+		return parameters
+	}
+
+	for _, location := range []string{"query", "header"} {
+		for _, field := range reflect.VisibleFields(request) {
+			name := field.Tag.Get(location)
+			if name == "" || !field.IsExported() {
+				continue
+			}
+			schema := g.schema(field.Type)
+			applyFieldTags(schema, field)
+			name, _, _ = strings.Cut(name, ",")
+			parameter := map[string]any{"name": name, "in": location, "schema": schema}
+			if location == "query" && field.Type.Kind() == reflect.Map {
+				parameter["style"] = "deepObject"
+				parameter["explode"] = true
+			}
+			if hasValidateRule(field, "required") {
+				parameter["required"] = true
+			}
+			if description, ok := schema["description"]; ok {
+				parameter["description"] = description
+			}
+			parameters = append(parameters, parameter)
+		}
+	}
+	return parameters
+}
+
+// paramField finds the field bound to a path parameter: a path:"name" tag
+// first, then a matching JSON name, then a case-insensitive field name.
+func paramField(request reflect.Type, location, name string) (reflect.StructField, bool) {
+	if request == nil || request.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	fields := reflect.VisibleFields(request)
+	for _, field := range fields {
+		if field.Tag.Get(location) == name {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if jsonName, ok := jsonFieldName(field); ok && jsonName == name || strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// openAPIPath rewrites router patterns to OpenAPI templates, dropping chi
+// regular expressions and ServeMux wildcards.
+func openAPIPath(pattern string) string {
+	var path strings.Builder
+	for {
+		start := strings.Index(pattern, "{")
+		if start < 0 {
+			path.WriteString(pattern)
+			break
+		}
+		end := strings.Index(pattern[start:], "}")
+		if end < 0 {
+			path.WriteString(pattern)
+			break
+		}
+		name := pattern[start+1 : start+end]
+		name, _, _ = strings.Cut(name, ":")
+		name = strings.TrimSuffix(name, "...")
+		path.WriteString(pattern[:start])
+		if name != "$" {
+			path.WriteString("{" + name + "}")
+		}
+		pattern = pattern[start+end+1:]
+	}
+	return path.String()
+}
+
+func defaultOperationID(method, pattern string) string {
+	parts := []string{strings.ToLower(method)}
+	for _, segment := range strings.Split(openAPIPath(pattern), "/") {
+		segment = strings.Trim(segment, "{}")
+		if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+func methodHasBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
@@ -0,0 +1,182 @@
+package httpsuite
+
+import (
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DocsUI selects the interactive documentation renderer.
+type DocsUI string
+
+const (
+	DocsSwaggerUI DocsUI = "swagger-ui"
+	DocsElements  DocsUI = "elements"
+)
+
+// docsAssetsPath is where DocsHandler serves DocsOptions.Assets, relative
+// to the page.
+const docsAssetsPath = "/assets/"
+
+var docsTemplates = map[DocsUI]*template.Template{
+	DocsSwaggerUI: template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Assets}}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="{{.Assets}}/swagger-ui-bundle.js"></script>
+<script>window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`)),
+	DocsElements: template.Must(template.New("elements").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Assets}}/styles.min.css">
+<script src="{{.Assets}}/web-components.min.js"></script>
+</head>
+<body>
+<elements-api apiDescriptionUrl="{{.SpecURL}}" router="hash" layout="sidebar"></elements-api>
+</body>
+</html>
+`)),
+}
+
+// DocsOptions configures API.DocsHandler.
+type DocsOptions struct {
+	// UI defaults to DocsSwaggerUI.
+	UI DocsUI
+	// Title defaults to the API title.
+	Title string
+	// Assets holds the UI's JavaScript and CSS, served under "assets/" next
+	// to the page: swagger-ui-bundle.js and swagger-ui.css for Swagger UI,
+	// web-components.min.js and styles.min.css for Elements. The
+	// docsui/swaggerui module provides Swagger UI.
+	Assets fs.FS
+	// AssetsURL is the base URL of the UI's JavaScript and CSS, such as a
+	// CDN, and takes precedence over Assets. One of them is required.
+	AssetsURL string
+	// SpecURL overrides where the page loads the document from, e.g. behind a
+	// proxy that rewrites paths.
+	SpecURL string
+	// Authorize, when set, guards both the page and the document. Rejected
+	// requests get a 401 problem.
+	Authorize func(r *http.Request) bool
+	// Realm, when set, adds a Basic WWW-Authenticate challenge to 401 replies
+	// so browsers prompt for credentials.
+	Realm string
+}
+
+// DocsHandler serves an interactive documentation page backed by OpenAPI.
+// Mount it under a prefix such as "/docs/": paths ending in "openapi.json"
+// serve the document and every other path serves the page.
+func (a *API) DocsHandler(opts *DocsOptions) http.Handler {
+	options := DocsOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if _, ok := docsTemplates[options.UI]; !ok {
+		options.UI = DocsSwaggerUI
+	}
+	if options.Assets == nil && options.AssetsURL == "" {
+		log.Printf("DocsHandler: neither Assets nor AssetsURL is set; the documentation page will reply 500")
+	}
+	if options.Title == "" {
+		options.Title = a.info.Title
+	}
+	options.AssetsURL = strings.TrimSuffix(options.AssetsURL, "/")
+	spec := a.OpenAPIHandler()
+	notAllowed := readOnlyMethodNotAllowed()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			notAllowed.Write(w)
+			return
+		}
+		if options.Authorize != nil && !options.Authorize(r) {
+			if options.Realm != "" {
+				w.Header().Set("WWW-Authenticate", "Basic realm="+strconv.Quote(options.Realm))
+			}
+			problems := resolveProblemConfig(r.Context(), nil)
+			sendRequestProblem(w, r, http.StatusUnauthorized, NewProblemDetails(http.StatusUnauthorized, problems.TypeURL("unauthorized_error"), "Unauthorized", "credentials are required to view the API documentation"))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "openapi.json") {
+			spec.ServeHTTP(w, r)
+			return
+		}
+		if options.AssetsURL == "" {
+			if options.Assets == nil {
+				status := http.StatusInternalServerError
+				problems := resolveProblemConfig(r.Context(), nil)
+				sendRequestProblem(w, r, status, NewProblemDetails(status, problems.TypeURL("server_error"), "Internal Server Error", "API documentation assets are not configured"))
+				return
+			}
+			if i := strings.LastIndex(r.URL.Path, docsAssetsPath); i >= 0 {
+				serveDocsAsset(w, r, options.Assets, r.URL.Path[i+len(docsAssetsPath):])
+				return
+			}
+		}
+
+		pageURL := docsPageURL(r)
+		specURL, assetsURL := options.SpecURL, options.AssetsURL
+		if specURL == "" {
+			specURL = pageURL + "/openapi.json"
+		}
+		if assetsURL == "" {
+			assetsURL = pageURL + strings.TrimSuffix(docsAssetsPath, "/")
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := docsTemplates[options.UI].Execute(w, struct {
+			Title, Assets, SpecURL string
+		}{Title: options.Title, Assets: assetsURL, SpecURL: specURL})
+		if err != nil {
+			log.Printf("Failed to render API docs: %v", err)
+		}
+	})
+}
+
+// serveDocsAsset serves the file name from assets. Empty names and
+// directories reply 404 so the handler never lists a directory.
+func serveDocsAsset(w http.ResponseWriter, r *http.Request, assets fs.FS, name string) {
+	if name == "" || !fs.ValidPath(name) {
+		sendDocsAssetNotFound(w, r)
+		return
+	}
+	info, err := fs.Stat(assets, name)
+	if err != nil || info.IsDir() {
+		sendDocsAssetNotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.ServeFileFS(w, r, assets, name)
+}
+
+func sendDocsAssetNotFound(w http.ResponseWriter, r *http.Request) {
+	problems := resolveProblemConfig(r.Context(), nil)
+	sendRequestProblem(w, r, http.StatusNotFound, NewProblemDetails(http.StatusNotFound, problems.TypeURL("not_found_error"), "Not Found", "documentation asset not found"))
+}
+
+// docsPageURL returns the directory the page is served from, without a
+// trailing slash, using the original request path so http.StripPrefix
+// mounts resolve the document and assets correctly.
+func docsPageURL(r *http.Request) string {
+	pagePath := r.URL.Path
+	if parsed, err := url.ParseRequestURI(r.RequestURI); err == nil && parsed.Path != "" {
+		pagePath = parsed.Path
+	}
+	pagePath = strings.TrimSuffix(pagePath, "index.html")
+	return strings.TrimSuffix(pagePath, "/")
+}
@@ -0,0 +1,140 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var testDocsAssets = fstest.MapFS{
+	"swagger-ui-bundle.js": {Data: []byte("window.SwaggerUIBundle = function() {};")},
+	"swagger-ui.css":       {Data: []byte(".swagger-ui {}")},
+	"fonts/ui.woff2":       {Data: []byte("font")},
+}
+
+func TestAPIDocsHandler(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Items", Version: "1.0.0"})
+	Describe[NoBody, []apiItem](api, http.MethodGet, "/items", nil)
+	authorized := func(r *http.Request) bool {
+		user, password, ok := r.BasicAuth()
+		return ok && user == "docs" && password == "secret"
+	}
+
+	tests := []struct {
+		name        string
+		opts        *DocsOptions
+		mount       func(http.Handler) http.Handler
+		method      string
+		path        string
+		auth        bool
+		wantStatus  int
+		wantType    string
+		wantContain []string
+		wantHeader  string
+	}{
+		{
+			name: "swagger ui", opts: &DocsOptions{Assets: testDocsAssets}, path: "/docs/", wantStatus: http.StatusOK, wantType: "text/html; charset=utf-8",
+			wantContain: []string{"<title>Items</title>", `src="/docs/assets/swagger-ui-bundle.js"`, `href="/docs/assets/swagger-ui.css"`, `url: "/docs/openapi.json"`},
+		},
+		{
+			name: "asset script", opts: &DocsOptions{Assets: testDocsAssets}, path: "/docs/assets/swagger-ui-bundle.js", wantStatus: http.StatusOK, wantType: "text/javascript; charset=utf-8",
+			wantContain: []string{"SwaggerUIBundle"},
+		},
+		{
+			name: "asset stylesheet behind strip prefix", opts: &DocsOptions{Assets: testDocsAssets}, mount: func(h http.Handler) http.Handler { return http.StripPrefix("/reference", h) },
+			path: "/reference/assets/swagger-ui.css", wantStatus: http.StatusOK, wantType: "text/css; charset=utf-8",
+			wantContain: []string{".swagger-ui"},
+		},
+		{name: "missing asset", opts: &DocsOptions{Assets: testDocsAssets}, path: "/docs/assets/missing.js", wantStatus: http.StatusNotFound},
+		{name: "empty asset name", opts: &DocsOptions{Assets: testDocsAssets}, path: "/docs/assets/", wantStatus: http.StatusNotFound},
+		{name: "asset directory", opts: &DocsOptions{Assets: testDocsAssets}, path: "/docs/assets/fonts", wantStatus: http.StatusNotFound},
+		{name: "no asset source", path: "/docs/", wantStatus: http.StatusInternalServerError, wantType: "application/problem+json; charset=utf-8"},
+		{
+			name: "no asset source still serves the document", path: "/docs/openapi.json", wantStatus: http.StatusOK,
+			wantContain: []string{`"/items"`},
+		},
+		{
+			name: "elements behind strip prefix", opts: &DocsOptions{UI: DocsElements, Title: "Item API", AssetsURL: "/static/elements/"},
+			mount: func(h http.Handler) http.Handler { return http.StripPrefix("/reference", h) },
+			path:  "/reference", wantStatus: http.StatusOK, wantType: "text/html; charset=utf-8",
+			wantContain: []string{"<title>Item API</title>", `src="/static/elements/web-components.min.js"`, `apiDescriptionUrl="/reference/openapi.json"`},
+		},
+		{
+			name: "assets url takes precedence", opts: &DocsOptions{Assets: testDocsAssets, AssetsURL: "https://cdn.example.com/swagger-ui"}, path: "/docs/",
+			wantStatus: http.StatusOK, wantContain: []string{`src="https://cdn.example.com/swagger-ui/swagger-ui-bundle.js"`},
+		},
+		{
+			name: "spec url override", opts: &DocsOptions{Assets: testDocsAssets, SpecURL: "/api/v1/openapi.json"}, path: "/docs/index.html",
+			wantStatus: http.StatusOK, wantContain: []string{`url: "/api/v1/openapi.json"`},
+		},
+		{
+			name: "document", path: "/docs/openapi.json", wantStatus: http.StatusOK, wantType: "application/json",
+			wantContain: []string{`"openapi":"3.1.0"`, `"/items"`},
+		},
+		{
+			name: "unauthorized", opts: &DocsOptions{Authorize: authorized, Realm: "docs"}, path: "/docs/openapi.json",
+			wantStatus: http.StatusUnauthorized, wantType: "application/problem+json; charset=utf-8", wantHeader: `Basic realm="docs"`,
+		},
+		{
+			name: "authorized", opts: &DocsOptions{Assets: testDocsAssets, Authorize: authorized}, path: "/docs/", auth: true,
+			wantStatus: http.StatusOK, wantContain: []string{"swagger-ui"},
+		},
+		{name: "method not allowed", opts: &DocsOptions{Assets: testDocsAssets}, method: http.MethodPost, path: "/docs/", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := api.DocsHandler(tt.opts)
+			if tt.mount != nil {
+				handler = tt.mount(handler)
+			}
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, nil)
+			if tt.auth {
+				req.SetBasicAuth("docs", "secret")
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantType != "" && w.Header().Get("Content-Type") != tt.wantType {
+				t.Fatalf("expected content type %q, got %q", tt.wantType, w.Header().Get("Content-Type"))
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != tt.wantHeader {
+				t.Fatalf("expected WWW-Authenticate %q, got %q", tt.wantHeader, got)
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Fatalf("expected body to contain %s, got %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestAPIDocsHandlerUnauthorizedProblem(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Items"})
+	handler := api.DocsHandler(&DocsOptions{Authorize: func(*http.Request) bool { return false }})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/", nil))
+
+	var problem ProblemDetails
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Type != GetProblemTypeURL("unauthorized_error") || problem.Status != http.StatusUnauthorized {
+		t.Fatalf("unexpected problem %#v", problem)
+	}
+}
@@ -0,0 +1,357 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	rawMessageType      = reflect.TypeOf(json.RawMessage(nil))
+	problemDetailsType  = reflect.TypeOf(ProblemDetails{})
+	validateFormatRules = map[string]string{
+		"email":    "email",
+		"url":      "uri",
+		"uri":      "uri",
+		"uuid":     "uuid",
+		"uuid4":    "uuid",
+		"rfc3339":  "date-time",
+		"ipv4":     "ipv4",
+		"ipv6":     "ipv6",
+		"hostname": "hostname",
+	}
+)
+
+// schemaProvider is implemented by suite value types whose JSON form needs a
+// more precise schema than their kind suggests, such as Enum values.
+type schemaProvider interface {
+	openAPISchema() map[string]any
+}
+
+var schemaProviderType = reflect.TypeOf((*schemaProvider)(nil)).Elem()
+
+// schemaGenerator converts Go types into JSON Schema objects, collecting named
+// struct types under components/schemas.
+type schemaGenerator struct {
+	components map[string]any
+	names      map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{components: make(map[string]any), names: make(map[reflect.Type]string)}
+}
+
+// schema returns the schema of t, referencing named structs.
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return nullableSchema(g.schema(t.Elem()))
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]any{}
+	case t == problemDetailsType:
+		return g.problemRef()
+	case t.Implements(schemaProviderType):
+		return reflect.Zero(t).Interface().(schemaProvider).openAPISchema()
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]any{"type": "string"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32:
+		return map[string]any{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t, nil)
+		}
+		return g.ref(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+// nullableSchema allows null in addition to schema, as Go encodes nil
+// pointers.
+func nullableSchema(schema map[string]any) map[string]any {
+	if _, ok := schema["$ref"]; ok {
+		return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+	}
+	if kind, ok := schema["type"].(string); ok {
+		schema["type"] = []string{kind, "null"}
+	}
+	return schema
+}
+
+// schemaKind returns the non-null type of schema, or "".
+func schemaKind(schema map[string]any) string {
+	switch kind := schema["type"].(type) {
+	case string:
+		return kind
+	case []string:
+		for _, name := range kind {
+			if name != "null" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// ref registers a named struct under components/schemas and references it.
+func (g *schemaGenerator) ref(t reflect.Type) map[string]any {
+	name, ok := g.names[t]
+	if !ok {
+		name = g.componentName(t)
+		g.names[t] = name
+		g.components[name] = map[string]any{}
+		g.components[name] = g.structSchema(t, nil)
+	}
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func (g *schemaGenerator) componentName(t reflect.Type) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, t.Name())
+	name = strings.Trim(name, "_")
+	candidate := name
+	for i := 2; ; i++ {
+		if _, taken := g.components[candidate]; !taken {
+			return candidate
+		}
+		candidate = name + strconv.Itoa(i)
+	}
+}
+
+// structSchema builds an object schema for t, skipping fields whose JSON
+// names are in exclude.
+func (g *schemaGenerator) structSchema(t reflect.Type, exclude map[string]bool) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	g.collectFields(t, exclude, properties, &required)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (g *schemaGenerator) collectFields(t reflect.Type, exclude map[string]bool, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+		tagName, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && tagName == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.collectFields(embedded, exclude, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() || exclude[name] || isParamField(field) {
+			continue
+		}
+
+		schema := g.schema(field.Type)
+		if strings.Contains(options, "string") {
+			schema = map[string]any{"type": "string"}
+		}
+		applyFieldTags(schema, field)
+		properties[name] = schema
+		if hasValidateRule(field, "required") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// isParamField reports whether field is documented as a path, query, or
+// header parameter instead of a body property.
+func isParamField(field reflect.StructField) bool {
+	return field.Tag.Get("path") != "" || field.Tag.Get("query") != "" || field.Tag.Get("header") != ""
+}
+
+func hasValidateRule(field reflect.StructField, rule string) bool {
+	for _, token := range strings.Split(field.Tag.Get("validate"), ",") {
+		if token == "dive" {
+			return false
+		}
+		if token == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFieldTags adds description, default, example, and validate-derived
+// constraints to schema.
+func applyFieldTags(schema map[string]any, field reflect.StructField) {
+	if description := fieldDescription(field); description != "" {
+		schema["description"] = description
+	}
+	if value, ok := field.Tag.Lookup("default"); ok {
+		schema["default"] = schemaValue(field.Type, value)
+	}
+	if value, ok := field.Tag.Lookup("example"); ok {
+		schema["examples"] = []any{schemaValue(field.Type, value)}
+	}
+
+	target := schema
+	for _, token := range strings.Split(field.Tag.Get("validate"), ",") {
+		if token == "dive" {
+			items, ok := target["items"].(map[string]any)
+			if !ok {
+				return
+			}
+			target = items
+			continue
+		}
+		applyValidateRule(target, field.Type, token)
+	}
+}
+
+// fieldDescription returns the doc tag, or the description tag for
+// compatibility.
+func fieldDescription(field reflect.StructField) string {
+	if doc := field.Tag.Get("doc"); doc != "" {
+		return doc
+	}
+	return field.Tag.Get("description")
+}
+
+func applyValidateRule(schema map[string]any, t reflect.Type, token string) {
+	rule, param, _ := strings.Cut(token, "=")
+	if format, ok := validateFormatRules[rule]; ok {
+		schema["format"] = format
+		return
+	}
+
+	kind := schemaKind(schema)
+	switch rule {
+	case "min", "max", "len", "gte", "lte":
+		number, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		lower := rule == "min" || rule == "gte" || rule == "len"
+		upper := rule == "max" || rule == "lte" || rule == "len"
+		switch kind {
+		case "string":
+			setBound(schema, lower, upper, "minLength", "maxLength", int(number))
+		case "array":
+			setBound(schema, lower, upper, "minItems", "maxItems", int(number))
+		case "object":
+			setBound(schema, lower, upper, "minProperties", "maxProperties", int(number))
+		case "integer", "number":
+			setBound(schema, lower, upper, "minimum", "maximum", number)
+		}
+	case "gt", "lt":
+		number, err := strconv.ParseFloat(param, 64)
+		if err != nil || (kind != "integer" && kind != "number") {
+			return
+		}
+		if rule == "gt" {
+			schema["exclusiveMinimum"] = number
+		} else {
+			schema["exclusiveMaximum"] = number
+		}
+	case "oneof":
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		var values []any
+		for _, value := range strings.Fields(param) {
+			values = append(values, schemaValue(t, value))
+		}
+		schema["enum"] = values
+	}
+}
+
+func setBound(schema map[string]any, lower, upper bool, minKey, maxKey string, value any) {
+	if lower {
+		schema[minKey] = value
+	}
+	if upper {
+		schema[maxKey] = value
+	}
+}
+
+// schemaValue converts a tag value to the JSON value of t, keeping the raw
+// string when it does not parse.
+func schemaValue(t reflect.Type, value string) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case reflect.Float32, reflect.Float64:
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+// problemRef registers the RFC 9457 problem schema.
+func (g *schemaGenerator) problemRef() map[string]any {
+	if _, ok := g.components["ProblemDetails"]; !ok {
+		g.components["ProblemDetails"] = map[string]any{
+			"type":     "object",
+			"required": []string{"type", "title", "status"},
+			"properties": map[string]any{
+				"type":     map[string]any{"type": "string", "format": "uri-reference"},
+				"title":    map[string]any{"type": "string"},
+				"status":   map[string]any{"type": "integer"},
+				"detail":   map[string]any{"type": "string"},
+				"instance": map[string]any{"type": "string", "format": "uri-reference"},
+				"errors":   map[string]any{"type": "array", "items": g.schema(reflect.TypeOf(ValidationErrorDetail{}))},
+			},
+			"additionalProperties": true,
+		}
+	}
+	return map[string]any{"$ref": "#/components/schemas/ProblemDetails"}
+}
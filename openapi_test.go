@@ -0,0 +1,184 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type openAPIAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type openAPICreateUser struct {
+	OrgID     string            `json:"org" validate:"required"`
+	Name      string            `json:"name" validate:"required,min=2,max=64" description:"Display name" example:"Ada"`
+	Email     string            `json:"email" validate:"required,email" doc:"Login email"`
+	Role      string            `json:"role" validate:"oneof=admin member" default:"member"`
+	Tags      []string          `json:"tags,omitempty" validate:"dive,min=1"`
+	Addresses []openAPIAddress  `json:"addresses"`
+	Trace     string            `json:"-" header:"X-Trace-Id"`
+	DryRun    bool              `json:"-" query:"dry_run"`
+	Filter    map[string]string `json:"-" query:"filter"`
+	internal  string
+}
+
+type openAPIUser struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func TestAPIOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Users", Version: "2.0.0", Description: "User management"})
+	Describe[*openAPICreateUser, openAPIUser](api, http.MethodPost, "/orgs/{org}/users", &RouteOptions{
+		Summary: "Create a user",
+		Tags:    []string{"users"},
+		Status:  http.StatusCreated,
+		Errors:  []int{http.StatusConflict},
+	})
+	Describe[NoBody, NoBody](api, http.MethodDelete, "/users/{id:[0-9]+}", &RouteOptions{OperationID: "deleteUser"})
+
+	doc := roundTripOpenAPI(t, api.OpenAPI())
+
+	expectJSONPath(t, doc, "3.1.0", "openapi")
+	expectJSONPath(t, doc, "User management", "info", "description")
+
+	create := []string{"paths", "/orgs/{org}/users", "post"}
+	expectJSONPath(t, doc, "post_orgs_org_users", append(create, "operationId")...)
+	expectJSONPath(t, doc, "Create a user", append(create, "summary")...)
+
+	parameters := jsonPath(t, doc, append(create, "parameters")...).([]any)
+	if len(parameters) != 4 {
+		t.Fatalf("expected 4 parameters, got %v", parameters)
+	}
+	wantParams := [][2]string{{"org", "path"}, {"dry_run", "query"}, {"filter", "query"}, {"X-Trace-Id", "header"}}
+	for i, want := range wantParams {
+		param := parameters[i].(map[string]any)
+		if param["name"] != want[0] || param["in"] != want[1] {
+			t.Fatalf("parameter %d: expected %v, got %v", i, want, param)
+		}
+	}
+	if filter := parameters[2].(map[string]any); filter["style"] != "deepObject" || filter["explode"] != true {
+		t.Fatalf("expected deepObject filter parameter, got %v", filter)
+	}
+
+	body := append(create, "requestBody", "content", "application/json", "schema")
+	properties := jsonPath(t, doc, append(body, "properties")...).(map[string]any)
+	for _, excluded := range []string{"org", "internal", "Trace", "DryRun"} {
+		if _, ok := properties[excluded]; ok {
+			t.Fatalf("expected %s to be excluded from the body, got %v", excluded, properties)
+		}
+	}
+	expectJSONPath(t, doc, []any{"name", "email"}, append(body, "required")...)
+	expectJSONPath(t, doc, float64(2), append(body, "properties", "name", "minLength")...)
+	expectJSONPath(t, doc, float64(64), append(body, "properties", "name", "maxLength")...)
+	expectJSONPath(t, doc, "Display name", append(body, "properties", "name", "description")...)
+	expectJSONPath(t, doc, []any{"Ada"}, append(body, "properties", "name", "examples")...)
+	expectJSONPath(t, doc, "email", append(body, "properties", "email", "format")...)
+	expectJSONPath(t, doc, "Login email", append(body, "properties", "email", "description")...)
+	expectJSONPath(t, doc, []any{"admin", "member"}, append(body, "properties", "role", "enum")...)
+	expectJSONPath(t, doc, "member", append(body, "properties", "role", "default")...)
+	expectJSONPath(t, doc, float64(1), append(body, "properties", "tags", "items", "minLength")...)
+	expectJSONPath(t, doc, "#/components/schemas/openAPIAddress", append(body, "properties", "addresses", "items", "$ref")...)
+
+	success := append(create, "responses", "201", "content", "application/json", "schema", "properties", "data", "$ref")
+	expectJSONPath(t, doc, "#/components/schemas/openAPIUser", success...)
+	expectJSONPath(t, doc, "date-time", "components", "schemas", "openAPIUser", "properties", "created_at", "format")
+	for _, status := range []string{"400", "409", "500"} {
+		expectJSONPath(t, doc, "#/components/schemas/ProblemDetails", append(create, "responses", status, "content", "application/problem+json", "schema", "$ref")...)
+	}
+
+	remove := []string{"paths", "/users/{id}", "delete"}
+	expectJSONPath(t, doc, "deleteUser", append(remove, "operationId")...)
+	expectJSONPath(t, doc, "No Content", append(remove, "responses", "204", "description")...)
+	responses := jsonPath(t, doc, append(remove, "responses")...).(map[string]any)
+	if _, ok := responses["400"]; ok {
+		t.Fatalf("expected no 400 response for a NoBody request, got %v", responses)
+	}
+	if _, ok := responses["204"].(map[string]any)["content"]; ok {
+		t.Fatalf("expected no content for 204, got %v", responses["204"])
+	}
+}
+
+func TestAPIOpenAPIHandler(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Items", Version: "1.0.0"})
+	Handle(api, http.MethodGet, "/items", func(ctx context.Context, _ NoBody) ([]apiItem, error) {
+		return nil, nil
+	}, nil)
+
+	w := httptest.NewRecorder()
+	api.OpenAPIHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("expected application/json, got %q", got)
+	}
+	var doc map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+	expectJSONPath(t, doc, "array", "paths", "/items", "get", "responses", "200", "content", "application/json", "schema", "properties", "data", "type")
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"/items":                       "/items",
+		"/items/{id:[0-9]+}":           "/items/{id}",
+		"/files/{path...}":             "/files/{path}",
+		"/{$}":                         "/",
+		"/orgs/{org}/items/{id}/notes": "/orgs/{org}/items/{id}/notes",
+	}
+	for pattern, want := range tests {
+		if got := openAPIPath(pattern); got != want {
+			t.Fatalf("%s: expected %s, got %s", pattern, want, got)
+		}
+	}
+}
+
+func roundTripOpenAPI(t *testing.T, doc map[string]any) map[string]any {
+	t.Helper()
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal document: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal document: %v", err)
+	}
+	return decoded
+}
+
+func jsonPath(t *testing.T, doc map[string]any, path ...string) any {
+	t.Helper()
+	var current any = doc
+	for _, key := range path {
+		object, ok := current.(map[string]any)
+		if !ok {
+			t.Fatalf("expected object at %v, got %T", path, current)
+		}
+		if current, ok = object[key]; !ok {
+			t.Fatalf("missing %q in %v", key, path)
+		}
+	}
+	return current
+}
+
+func expectJSONPath(t *testing.T, doc map[string]any, want any, path ...string) {
+	t.Helper()
+	if got := jsonPath(t, doc, path...); !reflect.DeepEqual(got, want) {
+		t.Fatalf("%v: expected %#v, got %#v", path, want, got)
+	}
+}
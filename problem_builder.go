@@ -1,5 +1,10 @@
 package httpsuite
 
+import (
+	"net/http"
+	"time"
+)
+
 // ProblemBuilder builds ProblemDetails declaratively.
 type ProblemBuilder struct {
 	problem *ProblemDetails
@@ -36,6 +41,28 @@ func (b *ProblemBuilder) Instance(instance string) *ProblemBuilder {
 	return b
 }
 
+// InstanceFromRequest sets the problem instance to the request URI of r.
+func (b *ProblemBuilder) InstanceFromRequest(r *http.Request) *ProblemBuilder {
+	if r != nil && r.URL != nil {
+		b.problem.Instance = r.URL.RequestURI()
+	}
+	return b
+}
+
+// With sets a single problem extension. It is shorthand for Extension.
+func (b *ProblemBuilder) With(key string, value any) *ProblemBuilder {
+	return b.Extension(key, value)
+}
+
+// RetryAfter sets the retry_after extension to the delay in whole seconds,
+// rounded up. Writing the problem also sets the Retry-After header.
+func (b *ProblemBuilder) RetryAfter(delay time.Duration) *ProblemBuilder {
+	if delay <= 0 {
+		return b
+	}
+	return b.Extension(retryAfterExtension, retryAfterSeconds(delay))
+}
+
 // Extension sets a single problem extension.
 func (b *ProblemBuilder) Extension(key string, value any) *ProblemBuilder {
 	if b.problem.Extensions == nil {
@@ -53,6 +80,12 @@ func (b *ProblemBuilder) Extensions(values map[string]any) *ProblemBuilder {
 	return b
 }
 
+// Cause wraps err as the underlying cause of the problem.
+func (b *ProblemBuilder) Cause(err error) *ProblemBuilder {
+	b.problem.cause = err
+	return b
+}
+
 // Build returns the configured ProblemDetails.
 func (b *ProblemBuilder) Build() *ProblemDetails {
 	clone := *b.problem
@@ -2,6 +2,7 @@ package httpsuite
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -33,3 +34,28 @@ func TestProblemBuilder(t *testing.T) {
 		t.Fatalf("expected trace_id extension")
 	}
 }
+
+func TestProblemBuilderFluentExtensions(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/42/cancel?force=true", nil)
+	builder := Problem(http.StatusConflict).
+		Title("Order Already Shipped").
+		Detail("order 42 cannot be cancelled").
+		With("order_id", 42).
+		With("state", "shipped").
+		InstanceFromRequest(req)
+
+	problem := builder.Build()
+	if problem.Instance != "/orders/42/cancel?force=true" {
+		t.Fatalf("unexpected instance %q", problem.Instance)
+	}
+	if problem.Extensions["order_id"] != 42 || problem.Extensions["state"] != "shipped" {
+		t.Fatalf("unexpected extensions %#v", problem.Extensions)
+	}
+
+	builder.With("state", "delivered")
+	if problem.Extensions["state"] != "shipped" {
+		t.Fatal("expected built problem to be isolated from later builder changes")
+	}
+}
@@ -0,0 +1,206 @@
+package httpsuite
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProblemType declares an application error type once so every instance shares
+// the same type URL, title, and status.
+type ProblemType struct {
+	Key    string
+	Title  string
+	Status int
+	// Path is the documentation path of the type, joined with the catalog BaseURL.
+	Path string
+	// Description explains when the problem occurs; it is shown on the
+	// documentation page served by ProblemDocsHandler.
+	Description string
+}
+
+// ProblemCatalog is a registry of ProblemTypes. It is safe for concurrent use.
+type ProblemCatalog struct {
+	mu      sync.RWMutex
+	baseURL string
+	types   map[string]ProblemType
+}
+
+// Problems is the default catalog, preloaded with the built-in problem types.
+var Problems = NewProblemCatalog(nil)
+
+var errEmptyProblemKey = errors.New("problem type key is required")
+
+var builtinProblemTypes = []ProblemType{
+	{
+		Key: "validation_error", Title: "Validation Error", Status: http.StatusBadRequest,
+		Description: "One or more fields failed validation. The errors extension lists each field and message.",
+	},
+	{
+		Key: "not_found_error", Title: "Not Found", Status: http.StatusNotFound,
+		Description: "The requested resource does not exist.",
+	},
+	{
+		Key: "server_error", Title: "Internal Server Error", Status: http.StatusInternalServerError,
+		Description: "The server failed to complete the request. Retrying later may succeed.",
+	},
+	{
+		Key: "bad_request_error", Title: "Bad Request", Status: http.StatusBadRequest,
+		Description: "The request body or parameters could not be parsed.",
+	},
+	{
+		Key: "unauthorized_error", Title: "Unauthorized", Status: http.StatusUnauthorized,
+		Description: "The request is missing valid credentials or a valid signature.",
+	},
+	{
+		Key: "forbidden_error", Title: "Forbidden", Status: http.StatusForbidden,
+		Description: "The caller is not allowed to use this resource or feature.",
+	},
+	{
+		Key: "too_many_requests_error", Title: "Too Many Requests", Status: http.StatusTooManyRequests,
+		Description: "The client sent too many requests. Wait for the number of seconds in retry_after before retrying.",
+	},
+	{
+		Key: "service_unavailable_error", Title: "Service Unavailable", Status: http.StatusServiceUnavailable,
+		Description: "The service is temporarily unavailable. Wait for the number of seconds in retry_after before retrying.",
+	},
+	{
+		Key: "precondition_failed_error", Title: "Precondition Failed", Status: http.StatusPreconditionFailed,
+		Description: "The resource changed since the client last retrieved it. Fetch the current version and retry the write.",
+	},
+	{
+		Key: "precondition_required_error", Title: "Precondition Required", Status: http.StatusPreconditionRequired,
+		Description: "The write must be conditional. Send If-Match with the resource ETag or If-Unmodified-Since.",
+	},
+	{
+		Key: "range_not_satisfiable_error", Title: "Range Not Satisfiable", Status: http.StatusRequestedRangeNotSatisfiable,
+		Description: "No requested byte range overlaps the content. The Content-Range header carries the content length.",
+	},
+	{
+		Key: "upload_rejected_error", Title: "Upload Rejected", Status: http.StatusUnprocessableEntity,
+		Description: "A content scanner rejected one or more uploaded files. The errors extension names each part and the reason.",
+	},
+	{
+		Key: "quota_exceeded_error", Title: "Quota Exceeded", Status: http.StatusTooManyRequests,
+		Description: "The caller used up its request quota for the current period. Some APIs reply 402 instead; Retry-After and the reset extension give the end of the period.",
+	},
+	{
+		Key: "request_timeout_error", Title: "Request Timeout", Status: http.StatusRequestTimeout,
+		Description: "The request deadline passed before the server finished processing it. Retrying later may succeed.",
+	},
+	{
+		Key: "client_closed_request_error", Title: "Client Closed Request", Status: StatusClientClosedRequest,
+		Description: "The client canceled the request before the response was ready.",
+	},
+	{
+		Key: "upgrade_required_error", Title: "Upgrade Required", Status: http.StatusUpgradeRequired,
+		Description: "The endpoint only speaks another protocol, such as WebSocket. The Upgrade header names the protocol to switch to.",
+	},
+}
+
+// NewProblemCatalog returns a catalog preloaded with the built-in problem
+// types. The BaseURL and paths of config, or of the default config when nil,
+// are used for type URLs. An empty BaseURL follows SetProblemBaseURL.
+func NewProblemCatalog(config *ProblemConfig) *ProblemCatalog {
+	merged := mergeProblemConfig(config)
+	catalog := &ProblemCatalog{types: make(map[string]ProblemType, len(merged.ErrorTypePaths))}
+	if config != nil {
+		catalog.baseURL = strings.TrimRight(config.BaseURL, "/")
+	}
+	for key, path := range merged.ErrorTypePaths {
+		catalog.types[key] = ProblemType{Key: key, Path: path}
+	}
+	for _, builtin := range builtinProblemTypes {
+		builtin.Path = catalog.types[builtin.Key].Path
+		catalog.types[builtin.Key] = builtin
+	}
+	return catalog
+}
+
+// Register adds or replaces problem types in the catalog.
+func (c *ProblemCatalog) Register(types ...ProblemType) error {
+	for _, problemType := range types {
+		if problemType.Key == "" {
+			return errEmptyProblemKey
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, problemType := range types {
+		problemType.Path = normalizeProblemPath(problemType.Path)
+		c.types[problemType.Key] = problemType
+	}
+	return nil
+}
+
+// Lookup returns the registered problem type for key.
+func (c *ProblemCatalog) Lookup(key string) (ProblemType, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	problemType, ok := c.types[key]
+	return problemType, ok
+}
+
+// Types returns the registered problem types sorted by key.
+func (c *ProblemCatalog) Types() []ProblemType {
+	c.mu.RLock()
+	types := make([]ProblemType, 0, len(c.types))
+	for _, problemType := range c.types {
+		types = append(types, problemType)
+	}
+	c.mu.RUnlock()
+	sort.Slice(types, func(i, j int) bool { return types[i].Key < types[j].Key })
+	return types
+}
+
+// New returns a problem of the registered type with the given detail and
+// extensions. Unknown keys produce a 500 problem with an about:blank type.
+func (c *ProblemCatalog) New(key, detail string, extensions ...map[string]any) *ProblemDetails {
+	builder := c.Problem(key).Detail(detail)
+	for _, values := range extensions {
+		builder.Extensions(values)
+	}
+	return builder.Build()
+}
+
+// Problem starts a ProblemBuilder preset with the registered type, title, and status.
+func (c *ProblemCatalog) Problem(key string) *ProblemBuilder {
+	problemType, ok := c.Lookup(key)
+	if !ok {
+		log.Printf("Unknown problem type %q", key)
+		return Problem(http.StatusInternalServerError)
+	}
+	config := ProblemConfig{BaseURL: c.resolvedBaseURL(), ErrorTypePaths: map[string]string{key: problemType.Path}}
+	builder := Problem(problemType.Status).Type(config.TypeURL(key))
+	if problemType.Title != "" {
+		builder.Title(problemType.Title)
+	}
+	return builder
+}
+
+// Config returns a ProblemConfig holding the catalog BaseURL and type paths,
+// suitable for ParseOptions.Problems and other option structs.
+func (c *ProblemCatalog) Config() ProblemConfig {
+	baseURL := c.resolvedBaseURL()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	config := ProblemConfig{
+		BaseURL:        baseURL,
+		ErrorTypePaths: make(map[string]string, len(c.types)),
+	}
+	for key, problemType := range c.types {
+		config.ErrorTypePaths[key] = problemType.Path
+	}
+	return config
+}
+
+func (c *ProblemCatalog) resolvedBaseURL() string {
+	if c.baseURL != "" {
+		return c.baseURL
+	}
+	return DefaultProblemConfig().BaseURL
+}
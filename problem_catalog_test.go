@@ -0,0 +1,67 @@
+package httpsuite
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestProblemCatalog(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewProblemCatalog(&ProblemConfig{BaseURL: "https://api.example.com"})
+	err := catalog.Register(ProblemType{
+		Key:    "quota_exceeded",
+		Title:  "Quota Exceeded",
+		Status: http.StatusTooManyRequests,
+		Path:   "errors/quota-exceeded",
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	problem := catalog.New("quota_exceeded", "monthly quota used up", map[string]any{"limit": 1000})
+	if problem.Status != http.StatusTooManyRequests || problem.Title != "Quota Exceeded" {
+		t.Fatalf("unexpected problem %#v", problem)
+	}
+	if problem.Type != "https://api.example.com/errors/quota-exceeded" {
+		t.Fatalf("unexpected type %q", problem.Type)
+	}
+	if problem.Detail != "monthly quota used up" || problem.Extensions["limit"] != 1000 {
+		t.Fatalf("unexpected detail or extensions %#v", problem)
+	}
+
+	config := catalog.Config()
+	if config.TypeURL("quota_exceeded") != problem.Type {
+		t.Fatalf("expected config to share catalog paths, got %q", config.TypeURL("quota_exceeded"))
+	}
+	if config.TypeURL("not_found_error") != "https://api.example.com/errors/not-found" {
+		t.Fatalf("unexpected built-in type %q", config.TypeURL("not_found_error"))
+	}
+}
+
+func TestProblemCatalogBuiltinsAndUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewProblemCatalog(nil)
+
+	notFound := catalog.New("not_found_error", "user 42 does not exist")
+	if notFound.Status != http.StatusNotFound || notFound.Type != GetProblemTypeURL("not_found_error") {
+		t.Fatalf("unexpected built-in problem %#v", notFound)
+	}
+
+	unknown := catalog.New("missing", "boom")
+	if unknown.Status != http.StatusInternalServerError || unknown.Type != BlankURL {
+		t.Fatalf("unexpected unknown problem %#v", unknown)
+	}
+
+	if err := catalog.Register(ProblemType{Key: "undocumented", Status: http.StatusConflict}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if problem := catalog.New("undocumented", ""); problem.Type != BlankURL || problem.Title != "Conflict" {
+		t.Fatalf("expected undocumented type to use about:blank, got %#v", problem)
+	}
+
+	if err := catalog.Register(ProblemType{Title: "No Key"}); err == nil {
+		t.Fatal("expected error for empty key")
+	}
+}
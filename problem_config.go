@@ -1,32 +1,93 @@
 package httpsuite
 
-import "strings"
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
 
-var defaultProblemConfig = NewProblemConfig()
+var (
+	defaultProblemConfigMu sync.RWMutex
+	defaultProblemConfig   = NewProblemConfig()
+)
+
+type problemConfigContextKey struct{}
 
 // ProblemConfig controls how problem type URLs are generated.
 type ProblemConfig struct {
 	BaseURL        string
 	ErrorTypePaths map[string]string
+	// Instance, when set, fills the instance of suite-generated problems
+	// that do not set one, e.g. DefaultInstance. Problems carry no instance
+	// by default.
+	Instance InstanceFunc
 }
 
 // NewProblemConfig returns a config preloaded with the default problem type paths.
 func NewProblemConfig() ProblemConfig {
 	return ProblemConfig{
 		ErrorTypePaths: map[string]string{
-			"validation_error":  "/errors/validation-error",
-			"not_found_error":   "/errors/not-found",
-			"server_error":      "/errors/server-error",
-			"bad_request_error": "/errors/bad-request",
+			"validation_error":   "/errors/validation-error",
+			"not_found_error":    "/errors/not-found",
+			"server_error":       "/errors/server-error",
+			"bad_request_error":  "/errors/bad-request",
+			"unauthorized_error": "/errors/unauthorized",
+			"forbidden_error":    "/errors/forbidden",
+
+			"too_many_requests_error":   "/errors/too-many-requests",
+			"service_unavailable_error": "/errors/service-unavailable",
+
+			"precondition_failed_error":   "/errors/precondition-failed",
+			"precondition_required_error": "/errors/precondition-required",
+
+			"range_not_satisfiable_error": "/errors/range-not-satisfiable",
+			"upload_rejected_error":       "/errors/upload-rejected",
+			"quota_exceeded_error":        "/errors/quota-exceeded",
+
+			"request_timeout_error":       "/errors/request-timeout",
+			"client_closed_request_error": "/errors/client-closed-request",
+			"upgrade_required_error":      "/errors/upgrade-required",
 		},
 	}
 }
 
 // DefaultProblemConfig returns a copy of the package default config.
 func DefaultProblemConfig() ProblemConfig {
+	defaultProblemConfigMu.RLock()
+	defer defaultProblemConfigMu.RUnlock()
 	return defaultProblemConfig.Clone()
 }
 
+// SetProblemBaseURL sets the base URL that the package default config, and
+// therefore every suite-generated problem, prefixes to problem type paths.
+func SetProblemBaseURL(baseURL string) {
+	defaultProblemConfigMu.Lock()
+	defer defaultProblemConfigMu.Unlock()
+	defaultProblemConfig.BaseURL = strings.TrimRight(baseURL, "/")
+}
+
+// SetProblemInstance sets how the package default config fills the instance
+// of suite-generated problems, e.g. SetProblemInstance(DefaultInstance). A
+// nil fn, the default, leaves Instance empty.
+func SetProblemInstance(fn InstanceFunc) {
+	defaultProblemConfigMu.Lock()
+	defer defaultProblemConfigMu.Unlock()
+	defaultProblemConfig.Instance = fn
+}
+
+// SetProblemTypePath registers or replaces a problem type path in the package default config.
+func SetProblemTypePath(errorType, path string) {
+	defaultProblemConfigMu.Lock()
+	defer defaultProblemConfigMu.Unlock()
+	paths := make(map[string]string, len(defaultProblemConfig.ErrorTypePaths)+1)
+	for key, value := range defaultProblemConfig.ErrorTypePaths {
+		paths[key] = value
+	}
+	paths[errorType] = normalizeProblemPath(path)
+	defaultProblemConfig.ErrorTypePaths = paths
+}
+
 func mergeProblemConfig(config *ProblemConfig) ProblemConfig {
 	merged := DefaultProblemConfig()
 	if config == nil {
@@ -36,6 +97,9 @@ func mergeProblemConfig(config *ProblemConfig) ProblemConfig {
 	if config.BaseURL != "" {
 		merged.BaseURL = config.BaseURL
 	}
+	if config.Instance != nil {
+		merged.Instance = config.Instance
+	}
 	for key, value := range config.ErrorTypePaths {
 		merged.ErrorTypePaths[key] = value
 	}
@@ -47,6 +111,7 @@ func (c ProblemConfig) Clone() ProblemConfig {
 	clone := ProblemConfig{
 		BaseURL:        strings.TrimRight(c.BaseURL, "/"),
 		ErrorTypePaths: make(map[string]string, len(c.ErrorTypePaths)),
+		Instance:       c.Instance,
 	}
 	for key, value := range c.ErrorTypePaths {
 		clone.ErrorTypePaths[key] = normalizeProblemPath(value)
@@ -57,7 +122,7 @@ func (c ProblemConfig) Clone() ProblemConfig {
 // TypeURL builds the full type URL for a known error type.
 func (c ProblemConfig) TypeURL(errorType string) string {
 	path, exists := c.ErrorTypePaths[errorType]
-	if !exists {
+	if !exists || path == "" || path == BlankURL {
 		return BlankURL
 	}
 
@@ -71,9 +136,73 @@ func (c ProblemConfig) TypeURL(errorType string) string {
 
 // GetProblemTypeURL returns the default problem type URL for a known error type.
 func GetProblemTypeURL(errorType string) string {
+	defaultProblemConfigMu.RLock()
+	defer defaultProblemConfigMu.RUnlock()
 	return defaultProblemConfig.TypeURL(errorType)
 }
 
+// WithProblemConfig returns a context whose suite-generated problems use config
+// instead of the package default. Missing paths and an empty BaseURL fall back
+// to the default config. Option structs with Problems set still take precedence.
+func WithProblemConfig(ctx context.Context, config ProblemConfig) context.Context {
+	clone := config.Clone()
+	return context.WithValue(ctx, problemConfigContextKey{}, &clone)
+}
+
+// UseProblemConfig returns middleware that scopes config to every request it
+// wraps, so several APIs in one process can document errors under different bases.
+func UseProblemConfig(config ProblemConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithProblemConfig(r.Context(), config)))
+		})
+	}
+}
+
+// RequestProblemConfig returns the problem config scoped to r, or the package default.
+func RequestProblemConfig(r *http.Request) ProblemConfig {
+	if r == nil {
+		return DefaultProblemConfig()
+	}
+	return resolveProblemConfig(r.Context(), nil)
+}
+
+// ProblemConfigFromContext returns the problem config scoped to ctx by
+// WithProblemConfig, or the package default. Validators and other adapters
+// use it to type the problems they build.
+func ProblemConfigFromContext(ctx context.Context) ProblemConfig {
+	if ctx == nil {
+		return DefaultProblemConfig()
+	}
+	return resolveProblemConfig(ctx, nil)
+}
+
+// resolveProblemConfig merges explicit, or else the context config, over the package default.
+func resolveProblemConfig(ctx context.Context, explicit *ProblemConfig) ProblemConfig {
+	if explicit != nil {
+		return mergeProblemConfig(explicit)
+	}
+	if scoped, ok := ctx.Value(problemConfigContextKey{}).(*ProblemConfig); ok {
+		return mergeProblemConfig(scoped)
+	}
+	return DefaultProblemConfig()
+}
+
+// sharedProblemConfig is resolveProblemConfig for read-only use on hot paths:
+// without a scoped config it returns the package default without cloning,
+// which is safe because the setters replace ErrorTypePaths instead of
+// mutating it.
+func sharedProblemConfig(ctx context.Context) *ProblemConfig {
+	if scoped, ok := ctx.Value(problemConfigContextKey{}).(*ProblemConfig); ok {
+		merged := mergeProblemConfig(scoped)
+		return &merged
+	}
+	defaultProblemConfigMu.RLock()
+	defer defaultProblemConfigMu.RUnlock()
+	shared := defaultProblemConfig
+	return &shared
+}
+
 func normalizeProblemPath(path string) string {
 	if path == "" {
 		return BlankURL
@@ -1,6 +1,12 @@
 package httpsuite
 
-import "testing"
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
 
 func TestProblemConfigTypeURL(t *testing.T) {
 	t.Parallel()
@@ -38,3 +44,88 @@ func TestDefaultProblemConfigReturnsCopy(t *testing.T) {
 		t.Fatalf("expected default config to stay unchanged, got %q", got)
 	}
 }
+
+func TestSetProblemBaseURLAppliesToGeneratedProblems(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+	SetProblemBaseURL("https://api.example.com/")
+	SetProblemTypePath("quota_exceeded", "errors/quota-exceeded")
+	t.Cleanup(func() {
+		defaultProblemConfigMu.Lock()
+		defaultProblemConfig = NewProblemConfig()
+		defaultProblemConfigMu.Unlock()
+	})
+
+	if got := GetProblemTypeURL("quota_exceeded"); got != "https://api.example.com/errors/quota-exceeded" {
+		t.Fatalf("unexpected quota type %q", got)
+	}
+	if got := Problems.New("not_found_error", "").Type; got != "https://api.example.com/errors/not-found" {
+		t.Fatalf("expected catalog to follow the base URL, got %q", got)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/test/123", bytes.NewBufferString(`{invalid-json}`))
+	w := httptest.NewRecorder()
+	_, _ = ParseRequest[*testRequest](w, req, testParamExtractor, nil, "id")
+
+	var problem ProblemDetails
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Type != "https://api.example.com/errors/bad-request" {
+		t.Fatalf("expected configured base URL, got %q", problem.Type)
+	}
+}
+
+func TestUseProblemConfigScopesGeneratedProblems(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	parse := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ParseRequest[*testRequest](w, r, testParamExtractor, nil, "id")
+	})
+	billing := UseProblemConfig(ProblemConfig{BaseURL: "https://billing.example.com"})(parse)
+	catalog := UseProblemConfig(ProblemConfig{
+		BaseURL:        "https://catalog.example.com",
+		ErrorTypePaths: map[string]string{"bad_request_error": "/problems/bad-input"},
+	})(parse)
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		want    string
+	}{
+		{name: "default", handler: parse, want: "/errors/bad-request"},
+		{name: "billing", handler: billing, want: "https://billing.example.com/errors/bad-request"},
+		{name: "catalog", handler: catalog, want: "https://catalog.example.com/problems/bad-input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/test/123", bytes.NewBufferString(`{invalid-json}`))
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, req)
+
+			var problem ProblemDetails
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Type != tt.want {
+				t.Fatalf("expected type %q, got %q", tt.want, problem.Type)
+			}
+		})
+	}
+}
+
+func TestRequestProblemConfig(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if got := RequestProblemConfig(req).TypeURL("not_found_error"); got != "/errors/not-found" {
+		t.Fatalf("unexpected default type %q", got)
+	}
+
+	req = req.WithContext(WithProblemConfig(req.Context(), ProblemConfig{BaseURL: "https://api.example.com"}))
+	if got := RequestProblemConfig(req).TypeURL("not_found_error"); got != "https://api.example.com/errors/not-found" {
+		t.Fatalf("unexpected scoped type %q", got)
+	}
+}
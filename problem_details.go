@@ -2,11 +2,16 @@ package httpsuite
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 )
 
 const BlankURL = "about:blank"
 
+// retryAfterExtension carries the retry delay in seconds, mirroring Retry-After.
+const retryAfterExtension = "retry_after"
+
 // ProblemDetails conforms to RFC 9457, providing a standard format for describing errors in HTTP APIs.
 type ProblemDetails struct {
 	Type       string                 `json:"type"`
@@ -15,15 +20,22 @@ type ProblemDetails struct {
 	Detail     string                 `json:"detail,omitempty"`
 	Instance   string                 `json:"instance,omitempty"`
 	Extensions map[string]interface{} `json:"extensions,omitempty"`
+
+	// cause is the underlying error; it is never serialized.
+	cause error
 }
 
 // ValidationErrorDetail provides structured details about a single validation error.
 type ValidationErrorDetail struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
+	// Pointer is the RFC 6901 JSON pointer of the offending value, set by
+	// JSON Schema validation.
+	Pointer string `json:"pointer,omitempty"`
 }
 
 // MarshalJSON serializes RFC 9457 extension members at the top level.
+// Extension values are passed through Redact so tagged fields never leak.
 func (p ProblemDetails) MarshalJSON() ([]byte, error) {
 	payload := map[string]any{
 		"type":   p.Type,
@@ -41,12 +53,50 @@ func (p ProblemDetails) MarshalJSON() ([]byte, error) {
 		case "", "type", "title", "status", "detail", "instance", "extensions":
 			continue
 		default:
-			payload[key] = value
+			payload[key] = Redact(value)
 		}
 	}
 	return json.Marshal(payload)
 }
 
+// UnmarshalJSON reads a problem written by MarshalJSON, collecting members
+// other than the standard ones into Extensions.
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	var standard struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
+	}
+	if err := json.Unmarshal(data, &standard); err != nil {
+		return err
+	}
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	*p = ProblemDetails{
+		Type:     standard.Type,
+		Title:    standard.Title,
+		Status:   standard.Status,
+		Detail:   standard.Detail,
+		Instance: standard.Instance,
+	}
+	for key, value := range members {
+		switch key {
+		case "type", "title", "status", "detail", "instance":
+			continue
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions[key] = value
+	}
+	return nil
+}
+
 // NewProblemDetails creates a ProblemDetails instance with standard fields.
 func NewProblemDetails(status int, problemType, title, detail string) *ProblemDetails {
 	if status < 100 || status > 599 {
@@ -68,3 +118,66 @@ func NewProblemDetails(status int, problemType, title, detail string) *ProblemDe
 		Detail: detail,
 	}
 }
+
+// Error implements error so problems can be returned through normal Go error
+// paths and recovered with AsProblem at the response boundary. A nil problem
+// stored in an error interface reports "<nil problem>".
+func (p *ProblemDetails) Error() string {
+	if p == nil {
+		return "<nil problem>"
+	}
+	if p.Detail == "" {
+		return p.Title
+	}
+	return p.Title + ": " + p.Detail
+}
+
+// Unwrap returns the underlying cause, if any.
+func (p *ProblemDetails) Unwrap() error {
+	if p == nil {
+		return nil
+	}
+	return p.cause
+}
+
+// WithCause returns a copy of the problem that wraps err. The cause is
+// available to errors.Is/As and logging but is never sent to clients.
+func (p *ProblemDetails) WithCause(err error) *ProblemDetails {
+	clone := *p
+	clone.cause = err
+	return &clone
+}
+
+// AsProblem returns the first *ProblemDetails in err's chain.
+func AsProblem(err error) (*ProblemDetails, bool) {
+	var problem *ProblemDetails
+	if !errors.As(err, &problem) || problem == nil {
+		return nil, false
+	}
+	return problem, true
+}
+
+// RetryAfter returns the delay from the retry_after extension.
+func (p *ProblemDetails) RetryAfter() (time.Duration, bool) {
+	if p == nil {
+		return 0, false
+	}
+	switch seconds := p.Extensions[retryAfterExtension].(type) {
+	case int64:
+		return time.Duration(seconds) * time.Second, true
+	case int:
+		return time.Duration(seconds) * time.Second, true
+	case float64:
+		return time.Duration(seconds * float64(time.Second)), true
+	default:
+		return 0, false
+	}
+}
+
+func retryAfterSeconds(delay time.Duration) int64 {
+	seconds := int64(delay / time.Second)
+	if delay%time.Second != 0 {
+		seconds++
+	}
+	return seconds
+}
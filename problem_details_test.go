@@ -1,7 +1,12 @@
 package httpsuite
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -51,3 +56,95 @@ func TestProblemDetailsMarshalJSONFlattensExtensions(t *testing.T) {
 		t.Fatalf("expected trace_id extension, got %#v", payload["trace_id"])
 	}
 }
+
+func TestProblemDetailsUnmarshalJSONCollectsExtensions(t *testing.T) {
+	t.Parallel()
+
+	var problem ProblemDetails
+	body := `{"type":"/errors/validation","title":"Validation Error","status":400,"instance":"/users","trace_id":"trace-123","errors":[{"field":"name","message":"is required"}]}`
+	if err := json.Unmarshal([]byte(body), &problem); err != nil {
+		t.Fatalf("unmarshal problem details: %v", err)
+	}
+
+	if problem.Type != "/errors/validation" || problem.Status != 400 || problem.Instance != "/users" {
+		t.Fatalf("unexpected standard members %#v", problem)
+	}
+	if problem.Extensions["trace_id"] != "trace-123" {
+		t.Fatalf("expected trace_id extension, got %#v", problem.Extensions)
+	}
+	if errs, ok := problem.Extensions["errors"].([]any); !ok || len(errs) != 1 {
+		t.Fatalf("expected errors extension, got %#v", problem.Extensions["errors"])
+	}
+}
+
+func TestProblemConstructorsAgree(t *testing.T) {
+	t.Parallel()
+
+	want := NewProblemDetails(http.StatusNotFound, GetProblemTypeURL("not_found_error"), "Not Found", "user 42 does not exist")
+	constructed := map[string]*ProblemDetails{
+		"builder": Problem(http.StatusNotFound).
+			Type(GetProblemTypeURL("not_found_error")).
+			Title("Not Found").
+			Detail("user 42 does not exist").
+			Build(),
+		"helper":  NewNotFoundProblem("user 42 does not exist"),
+		"catalog": NewProblemCatalog(nil).New("not_found_error", "user 42 does not exist"),
+	}
+
+	for name, got := range constructed {
+		if got.Type != want.Type || got.Title != want.Title || got.Status != want.Status || got.Detail != want.Detail {
+			t.Fatalf("%s: expected %#v, got %#v", name, want, got)
+		}
+	}
+}
+
+func TestProblemDetailsAsError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("sql: no rows in result set")
+	problem := NewNotFoundProblem("user 42 does not exist").WithCause(cause)
+	err := fmt.Errorf("load user: %w", problem)
+
+	if err.Error() != "load user: Not Found: user 42 does not exist" {
+		t.Fatalf("unexpected error message %q", err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Fatal("expected errors.Is to reach the cause")
+	}
+
+	got, ok := AsProblem(err)
+	if !ok || got.Status != http.StatusNotFound {
+		t.Fatalf("expected wrapped problem, got %#v", got)
+	}
+
+	body, marshalErr := json.Marshal(got)
+	if marshalErr != nil {
+		t.Fatalf("marshal problem: %v", marshalErr)
+	}
+	if strings.Contains(string(body), "sql") {
+		t.Fatalf("expected cause to stay out of the payload, got %s", body)
+	}
+
+	if _, ok := AsProblem(cause); ok {
+		t.Fatal("expected plain error not to be a problem")
+	}
+}
+
+func TestNilProblemDetailsAsError(t *testing.T) {
+	t.Parallel()
+
+	var problem *ProblemDetails
+	var err error = problem
+	if err.Error() != "<nil problem>" {
+		t.Fatalf("unexpected error message %q", err.Error())
+	}
+	if errors.Unwrap(err) != nil || errors.Is(err, context.Canceled) {
+		t.Fatal("expected a nil problem to wrap nothing")
+	}
+	if _, ok := AsProblem(err); ok {
+		t.Fatal("expected a nil problem not to be returned")
+	}
+	if _, ok := problem.RetryAfter(); ok {
+		t.Fatal("expected no retry delay")
+	}
+}
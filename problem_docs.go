@@ -0,0 +1,120 @@
+package httpsuite
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ProblemDoc documents a problem type.
+type ProblemDoc struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Status      int    `json:"status"`
+	Description string `json:"description,omitempty"`
+}
+
+var problemDocsTemplate = template.Must(template.New("problem-docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+{{range .Docs}}<section id="{{.Key}}">
+<h1>{{.Title}}</h1>
+<p>Status: {{.Status}}</p>
+<p>Type: <a href="{{.Type}}"><code>{{.Type}}</code></a></p>
+{{with .Description}}<p>{{.}}</p>
+{{end}}</section>
+{{end}}</body>
+</html>
+`))
+
+// ProblemDocsHandler serves documentation for every type registered in
+// catalog, or in Problems when catalog is nil, at the path of its type URL so
+// the type URIs in responses dereference to something useful. Clients that
+// accept JSON get ProblemDoc data and browsers get HTML. The parent path of
+// the types, e.g. "/errors/", lists all of them.
+func ProblemDocsHandler(catalog *ProblemCatalog) http.Handler {
+	if catalog == nil {
+		catalog = Problems
+	}
+	notAllowed := readOnlyMethodNotAllowed()
+	notFound := NewStaticProblem(NewNotFoundProblem("problem type not found"), nil)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			notAllowed.Write(w)
+			return
+		}
+
+		docs := catalog.docs()
+		requestPath := strings.TrimSuffix(r.URL.Path, "/")
+		for _, doc := range docs {
+			if typePath(doc.Type) == requestPath {
+				writeProblemDocs(w, r, doc.Title, doc, []ProblemDoc{doc})
+				return
+			}
+		}
+		for _, doc := range docs {
+			if path.Dir(typePath(doc.Type)) == requestPath || requestPath == "" {
+				writeProblemDocs(w, r, "Problem Types", docs, docs)
+				return
+			}
+		}
+		notFound.Write(w)
+	})
+}
+
+func (c *ProblemCatalog) docs() []ProblemDoc {
+	config := c.Config()
+	var docs []ProblemDoc
+	for _, problemType := range c.Types() {
+		typeURL := config.TypeURL(problemType.Key)
+		if typeURL == BlankURL {
+			continue
+		}
+		title := problemType.Title
+		if title == "" {
+			title = http.StatusText(problemType.Status)
+		}
+		docs = append(docs, ProblemDoc{
+			Key:         problemType.Key,
+			Type:        typeURL,
+			Title:       title,
+			Status:      problemType.Status,
+			Description: problemType.Description,
+		})
+	}
+	return docs
+}
+
+// typePath returns the path component of a type URL.
+func typePath(typeURL string) string {
+	parsed, err := url.Parse(typeURL)
+	if err != nil {
+		return typeURL
+	}
+	return strings.TrimSuffix(parsed.Path, "/")
+}
+
+func writeProblemDocs(w http.ResponseWriter, r *http.Request, title string, data any, docs []ProblemDoc) {
+	accept := r.Header.Get("Accept")
+	if strings.Contains(accept, "json") && !strings.Contains(accept, "text/html") {
+		OK(w, data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := problemDocsTemplate.Execute(w, struct {
+		Title string
+		Docs  []ProblemDoc
+	}{Title: title, Docs: docs})
+	if err != nil {
+		log.Printf("Failed to render problem docs: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestProblemDocsHandler(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewProblemCatalog(&ProblemConfig{BaseURL: "https://api.example.com/docs"})
+	if err := catalog.Register(ProblemType{
+		Key:         "quota_exceeded",
+		Title:       "Quota Exceeded",
+		Status:      http.StatusTooManyRequests,
+		Path:        "/errors/quota-exceeded",
+		Description: "The monthly <request> quota has been used up.",
+	}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	handler := ProblemDocsHandler(catalog)
+
+	t.Run("html page", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/docs/errors/quota-exceeded", nil)
+		req.Header.Set("Accept", "text/html")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, "<h1>Quota Exceeded</h1>") || !strings.Contains(body, "&lt;request&gt;") {
+			t.Fatalf("unexpected html %s", body)
+		}
+		if strings.Contains(body, "Not Found") {
+			t.Fatalf("expected a single type page, got %s", body)
+		}
+	})
+
+	t.Run("json page", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/docs/errors/quota-exceeded", nil)
+		req.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var response Response[ProblemDoc]
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("decode docs: %v", err)
+		}
+		if response.Data.Type != "https://api.example.com/docs/errors/quota-exceeded" || response.Data.Status != http.StatusTooManyRequests {
+			t.Fatalf("unexpected doc %#v", response.Data)
+		}
+	})
+
+	t.Run("index", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/docs/errors/", nil)
+		req.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var response Response[[]ProblemDoc]
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("decode docs: %v", err)
+		}
+		config := catalog.Config()
+		var want []string
+		for _, problemType := range catalog.Types() {
+			if config.TypeURL(problemType.Key) != BlankURL {
+				want = append(want, problemType.Key)
+			}
+		}
+		var got []string
+		for _, doc := range response.Data {
+			got = append(got, doc.Key)
+		}
+		if !slices.Contains(got, "quota_exceeded") || !slices.Equal(got, want) {
+			t.Fatalf("expected documented types %v, got %v", want, got)
+		}
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/docs/errors/unknown", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package httpsuite
+
+import (
+	"net/http"
+	"strings"
+)
+
+// NotFoundHandler returns a handler that replies 404 with a problem, for a
+// router's not-found hook: chi's Router.NotFound, gorilla/mux's
+// Router.NotFoundHandler, or ProblemFallbacks for http.ServeMux. The problem
+// type URL is resolved when the handler is created.
+func NotFoundHandler() http.Handler {
+	return NewStaticProblem(NewNotFoundProblem("no resource matches the request path"), nil)
+}
+
+// MethodNotAllowedHandler returns a handler that replies 405 with a problem,
+// for chi's Router.MethodNotAllowed or gorilla/mux's
+// Router.MethodNotAllowedHandler. allow sets the Allow header; when it is
+// empty, an Allow header already set by the router, as chi does, is kept.
+func MethodNotAllowedHandler(allow ...string) http.Handler {
+	var headers http.Header
+	if len(allow) > 0 {
+		headers = http.Header{"Allow": {strings.Join(allow, ", ")}}
+	}
+	return NewStaticProblem(NewProblemDetails(http.StatusMethodNotAllowed, BlankURL, "", "the request method is not supported by this resource"), headers)
+}
+
+// ProblemFallbacks wraps mux so the plain-text 404 and 405 replies it sends
+// for unmatched requests become problems. The Allow header ServeMux computes
+// from its method patterns is kept. Handlers registered on mux are not
+// affected, even when they reply 404 themselves.
+func ProblemFallbacks(mux *http.ServeMux) http.Handler {
+	notFound := NotFoundHandler()
+	notAllowed := MethodNotAllowedHandler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		handler.ServeHTTP(&fallbackWriter{ResponseWriter: w, request: r, notFound: notFound, notAllowed: notAllowed}, r)
+	})
+}
+
+// fallbackWriter replaces ServeMux's own 404 and 405 replies.
+type fallbackWriter struct {
+	http.ResponseWriter
+	request    *http.Request
+	notFound   http.Handler
+	notAllowed http.Handler
+	replaced   bool
+}
+
+func (w *fallbackWriter) WriteHeader(code int) {
+	switch code {
+	case http.StatusNotFound:
+		w.replaced = true
+		w.notFound.ServeHTTP(w.ResponseWriter, w.request)
+	case http.StatusMethodNotAllowed:
+		w.replaced = true
+		w.notAllowed.ServeHTTP(w.ResponseWriter, w.request)
+	default:
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *fallbackWriter) Write(p []byte) (int, error) {
+	if w.replaced {
+		return len(p), nil
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *fallbackWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
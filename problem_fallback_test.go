@@ -0,0 +1,114 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFallbackHandlers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		preset     string
+		wantStatus int
+		wantAllow  string
+	}{
+		{name: "not found", handler: NotFoundHandler(), wantStatus: http.StatusNotFound},
+		{name: "method not allowed", handler: MethodNotAllowedHandler("GET", "POST"), wantStatus: http.StatusMethodNotAllowed, wantAllow: "GET, POST"},
+		{name: "router allow header", handler: MethodNotAllowedHandler(), preset: "PUT", wantStatus: http.StatusMethodNotAllowed, wantAllow: "PUT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			if tt.preset != "" {
+				rec.Header().Set("Allow", tt.preset)
+			}
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/missing", nil))
+
+			assertFallbackProblem(t, rec, tt.wantStatus)
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Fatalf("expected Allow %q, got %q", tt.wantAllow, got)
+			}
+		})
+	}
+}
+
+func TestProblemFallbacks(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "0" {
+			http.NotFound(w, r)
+			return
+		}
+		OK(w, testResponse{Key: r.PathValue("id")})
+	})
+	mux.HandleFunc("PUT /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := ProblemFallbacks(mux)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantAllow  string
+		wantText   bool
+	}{
+		{name: "match", method: http.MethodGet, path: "/items/7", wantStatus: http.StatusOK},
+		{name: "unknown path", method: http.MethodGet, path: "/orders", wantStatus: http.StatusNotFound},
+		{name: "wrong method", method: http.MethodPost, path: "/items/7", wantStatus: http.StatusMethodNotAllowed, wantAllow: "GET, HEAD, PUT"},
+		{name: "handler not found is untouched", method: http.MethodGet, path: "/items/0", wantStatus: http.StatusNotFound, wantText: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if tt.wantStatus < 400 || tt.wantText {
+				if rec.Code != tt.wantStatus {
+					t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+				}
+				if tt.wantText && !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+					t.Fatalf("expected the handler's own reply, got %q", rec.Header().Get("Content-Type"))
+				}
+				return
+			}
+			assertFallbackProblem(t, rec, tt.wantStatus)
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Fatalf("expected Allow %q, got %q", tt.wantAllow, got)
+			}
+		})
+	}
+}
+
+func assertFallbackProblem(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+
+	if rec.Code != status {
+		t.Fatalf("expected status %d, got %d", status, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != problemContentType {
+		t.Fatalf("expected problem content type, got %q", got)
+	}
+	var problem ProblemDetails
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("expected a single problem document, got %q: %v", rec.Body.String(), err)
+	}
+	if problem.Status != status {
+		t.Fatalf("expected problem status %d, got %d", status, problem.Status)
+	}
+}
@@ -1,6 +1,9 @@
 package httpsuite
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 // ProblemBadRequest returns a bad request problem builder.
 func ProblemBadRequest(detail string) *ProblemBuilder {
@@ -18,6 +21,24 @@ func ProblemNotFound(detail string) *ProblemBuilder {
 		Detail(detail)
 }
 
+// ProblemTooManyRequests returns a 429 problem builder with retry guidance.
+func ProblemTooManyRequests(detail string, retryAfter time.Duration) *ProblemBuilder {
+	return Problem(http.StatusTooManyRequests).
+		Type(GetProblemTypeURL("too_many_requests_error")).
+		Title("Too Many Requests").
+		Detail(detail).
+		RetryAfter(retryAfter)
+}
+
+// ProblemServiceUnavailable returns a 503 problem builder with retry guidance.
+func ProblemServiceUnavailable(detail string, retryAfter time.Duration) *ProblemBuilder {
+	return Problem(http.StatusServiceUnavailable).
+		Type(GetProblemTypeURL("service_unavailable_error")).
+		Title("Service Unavailable").
+		Detail(detail).
+		RetryAfter(retryAfter)
+}
+
 // NewBadRequestProblem returns a ready-to-use bad request problem.
 func NewBadRequestProblem(detail string) *ProblemDetails {
 	return ProblemBadRequest(detail).Build()
@@ -27,3 +48,13 @@ func NewBadRequestProblem(detail string) *ProblemDetails {
 func NewNotFoundProblem(detail string) *ProblemDetails {
 	return ProblemNotFound(detail).Build()
 }
+
+// NewTooManyRequestsProblem returns a ready-to-use 429 problem with retry guidance.
+func NewTooManyRequestsProblem(detail string, retryAfter time.Duration) *ProblemDetails {
+	return ProblemTooManyRequests(detail, retryAfter).Build()
+}
+
+// NewServiceUnavailableProblem returns a ready-to-use 503 problem with retry guidance.
+func NewServiceUnavailableProblem(detail string, retryAfter time.Duration) *ProblemDetails {
+	return ProblemServiceUnavailable(detail, retryAfter).Build()
+}
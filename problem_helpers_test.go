@@ -1,8 +1,11 @@
 package httpsuite
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestProblemBuilderHelpers(t *testing.T) {
@@ -32,3 +35,65 @@ func TestDirectProblemHelpers(t *testing.T) {
 		t.Fatalf("expected not found status, got %d", notFound.Status)
 	}
 }
+
+func TestRetryAfterProblems(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		problem    *ProblemDetails
+		wantStatus int
+		wantType   string
+	}{
+		{
+			name:       "too many requests",
+			problem:    NewTooManyRequestsProblem("rate limit exceeded", 1500*time.Millisecond),
+			wantStatus: http.StatusTooManyRequests,
+			wantType:   GetProblemTypeURL("too_many_requests_error"),
+		},
+		{
+			name:       "service unavailable",
+			problem:    NewServiceUnavailableProblem("down for maintenance", 2*time.Second),
+			wantStatus: http.StatusServiceUnavailable,
+			wantType:   GetProblemTypeURL("service_unavailable_error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ProblemResponse(w, tt.problem)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if got := w.Header().Get("Retry-After"); got != "2" {
+				t.Fatalf("expected Retry-After 2, got %q", got)
+			}
+
+			var body struct {
+				Type       string `json:"type"`
+				RetryAfter int    `json:"retry_after"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if body.Type != tt.wantType || body.RetryAfter != 2 {
+				t.Fatalf("unexpected body %#v", body)
+			}
+		})
+	}
+}
+
+func TestRetryAfterHeaderNotOverridden(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	RespondProblem(NewTooManyRequestsProblem("slow down", time.Second)).
+		Header("Retry-After", "Wed, 21 Oct 2015 07:28:00 GMT").
+		Write(w)
+
+	if got := w.Header().Get("Retry-After"); got != "Wed, 21 Oct 2015 07:28:00 GMT" {
+		t.Fatalf("expected explicit Retry-After to win, got %q", got)
+	}
+}
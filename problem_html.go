@@ -0,0 +1,130 @@
+package httpsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"html/template"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// HTMLProblems returns middleware that renders problem responses as HTML
+// error pages, with tmpl or the built-in page when tmpl is nil, for
+// requests whose Accept header prefers text/html over JSON, as browsers'
+// does. API clients keep receiving application/problem+json. The template
+// receives the *ProblemDetails, so Templates.Page("error") fits.
+func HTMLProblems(tmpl *template.Template) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !prefersHTML(r.Header.Get("Accept")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			writer := &htmlProblemWriter{ResponseWriter: w}
+			next.ServeHTTP(writer, r)
+			writer.finish(r.Context(), tmpl)
+		})
+	}
+}
+
+// htmlProblemWriter holds back problem+json bodies so they can be rendered
+// as HTML once the handler returns; other responses pass through.
+type htmlProblemWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	status      int
+	problem     *bytes.Buffer
+}
+
+func (w *htmlProblemWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	if isInformational(code) {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.wroteHeader = true
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if code >= 400 && mediaType == "application/problem+json" {
+		w.status = code
+		w.problem = &bytes.Buffer{}
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *htmlProblemWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.problem != nil {
+		return w.problem.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *htmlProblemWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// FlushError is used by http.ResponseController. Problems being held back
+// are not flushed.
+func (w *htmlProblemWriter) FlushError() error {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.problem != nil {
+		return nil
+	}
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *htmlProblemWriter) finish(ctx context.Context, tmpl *template.Template) {
+	if w.problem == nil {
+		return
+	}
+	var problem ProblemDetails
+	if err := json.Unmarshal(w.problem.Bytes(), &problem); err != nil {
+		log.Printf("Failed to decode problem for HTML rendering: %v", err)
+		w.ResponseWriter.WriteHeader(w.status)
+		_, _ = w.ResponseWriter.Write(w.problem.Bytes())
+		return
+	}
+	problem.Status = w.status
+	w.Header().Del("Content-Length")
+	w.Header().Add("Vary", "Accept")
+	sendProblemHTML(ctx, w.ResponseWriter, &problem, tmpl)
+}
+
+// prefersHTML reports whether an Accept header names text/html with a
+// higher quality than any JSON type. Wildcards alone never select HTML.
+func prefersHTML(accept string) bool {
+	htmlQuality, jsonQuality := -1.0, -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				parsed, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					parsed = 0
+				}
+				quality = parsed
+			}
+		}
+		switch {
+		case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+			htmlQuality = max(htmlQuality, quality)
+		case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+			jsonQuality = max(jsonQuality, quality)
+		}
+	}
+	return htmlQuality > 0 && htmlQuality > jsonQuality
+}
@@ -0,0 +1,113 @@
+package httpsuite
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrefersHTML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", want: true},
+		{accept: "application/json", want: false},
+		{accept: "*/*", want: false},
+		{accept: "", want: false},
+		{accept: "application/problem+json, text/html;q=0.5", want: false},
+		{accept: "text/html;q=0", want: false},
+		{accept: "application/json;q=0.5, text/html", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := prefersHTML(tt.accept); got != tt.want {
+			t.Fatalf("prefersHTML(%q): expected %v, got %v", tt.accept, tt.want, got)
+		}
+	}
+}
+
+func TestHTMLProblems(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/limited", func(w http.ResponseWriter, r *http.Request) {
+		ProblemResponse(w, NewTooManyRequestsProblem("slow <down>", 10*time.Second))
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		OK(w, testResponse{Key: "value"})
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	})
+	custom := template.Must(template.New("error").Parse(`<h1>{{.Status}}: {{.Detail}}</h1>`))
+
+	tests := []struct {
+		name        string
+		tmpl        *template.Template
+		path        string
+		accept      string
+		wantStatus  int
+		wantType    string
+		wantBody    string
+		wantHeaders map[string]string
+	}{
+		{name: "browser", path: "/limited", accept: "text/html", wantStatus: http.StatusTooManyRequests, wantType: htmlContentType, wantBody: "slow &lt;down&gt;", wantHeaders: map[string]string{"Retry-After": "10", "Vary": "Accept"}},
+		{name: "custom template", tmpl: custom, path: "/limited", accept: "text/html", wantStatus: http.StatusTooManyRequests, wantType: htmlContentType, wantBody: "<h1>429: slow &lt;down&gt;</h1>"},
+		{name: "api client", path: "/limited", accept: "application/json", wantStatus: http.StatusTooManyRequests, wantType: problemContentType, wantBody: `"detail":"slow \u003cdown\u003e"`},
+		{name: "success untouched", path: "/ok", accept: "text/html", wantStatus: http.StatusOK, wantType: "application/json; charset=utf-8", wantBody: `"key":"value"`},
+		{name: "router fallback", path: "/missing", accept: "text/html", wantStatus: http.StatusNotFound, wantType: "text/plain; charset=utf-8"},
+		{name: "non-problem error", path: "/plain", accept: "text/html", wantStatus: http.StatusForbidden, wantType: "text/plain; charset=utf-8", wantBody: "nope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			HTMLProblems(tt.tmpl)(mux).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantType {
+				t.Fatalf("expected Content-Type %q, got %q", tt.wantType, got)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %q", tt.wantBody, rec.Body.String())
+			}
+			for key, want := range tt.wantHeaders {
+				if got := rec.Header().Get(key); got != want {
+					t.Fatalf("expected %s %q, got %q", key, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHTMLProblemsFallbackUsesScopedProblemConfig(t *testing.T) {
+	t.Parallel()
+
+	broken := template.Must(template.New("error").Parse(`{{.Missing}}`))
+	handler := UseProblemConfig(ProblemConfig{BaseURL: "https://shop.example.com"})(HTMLProblems(broken)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ProblemResponse(w, NewNotFoundProblem("no such page"))
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/html")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	if want := `href="https://shop.example.com/errors/server-error"`; !strings.Contains(rec.Body.String(), want) {
+		t.Fatalf("expected body to contain %s, got %s", want, rec.Body.String())
+	}
+}
@@ -0,0 +1,111 @@
+package httpsuite
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// InstanceFunc builds the RFC 9457 instance URI of problems generated while
+// handling r.
+type InstanceFunc func(r *http.Request) string
+
+type instanceFuncContextKey struct{}
+
+// maxRequestIDLength bounds request IDs copied into problem instances.
+const maxRequestIDLength = 128
+
+// DefaultInstance returns the request path with the request ID as fragment,
+// e.g. "/users/42#req-123". The ID is taken from X-Request-ID, then from the
+// W3C traceparent trace ID, and is generated when neither is present; a
+// RequestIDKey value in the request context takes precedence. IDs longer
+// than 128 characters or with characters other than letters, digits, '.',
+// '_', ':', and '-' are ignored. Tenants resolved from a header or subdomain
+// are added as "?tenant=<id>". Enable it with SetProblemInstance or
+// ProblemConfig.Instance.
+func DefaultInstance(r *http.Request) string {
+	if r == nil || r.URL == nil {
+		return ""
+	}
+	return r.URL.EscapedPath() + tenantInstanceQuery(r) + "#" + requestID(r)
+}
+
+// WithInstanceFunc returns a context whose suite-generated problems use fn to
+// populate ProblemDetails.Instance instead of ProblemConfig.Instance. A nil
+// fn leaves Instance empty.
+func WithInstanceFunc(ctx context.Context, fn InstanceFunc) context.Context {
+	return context.WithValue(ctx, instanceFuncContextKey{}, fn)
+}
+
+// UseInstanceFunc returns middleware that scopes fn to every request it wraps.
+func UseInstanceFunc(fn InstanceFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithInstanceFunc(r.Context(), fn)))
+		})
+	}
+}
+
+// sendRequestProblem writes a problem generated while handling r, filling
+// Instance when the problem does not set one.
+func sendRequestProblem(w http.ResponseWriter, r *http.Request, status int, problem *ProblemDetails) {
+	if problem != nil && problem.Instance == "" {
+		if instance := problemInstance(r); instance != "" {
+			withInstance := *problem
+			withInstance.Instance = instance
+			problem = &withInstance
+		}
+	}
+	SendResponse[any](w, status, nil, problem, nil)
+}
+
+func problemInstance(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	value := r.Context().Value(instanceFuncContextKey{})
+	if value == nil {
+		value = sharedProblemConfig(r.Context()).Instance
+	}
+	fn, _ := value.(InstanceFunc)
+	if fn == nil {
+		return ""
+	}
+	return fn(r)
+}
+
+func requestID(r *http.Request) string {
+	if id, ok := CtxGet(r.Context(), RequestIDKey); ok && validRequestID(id) {
+		return id
+	}
+	if id := r.Header.Get("X-Request-ID"); validRequestID(id) {
+		return id
+	}
+	// traceparent: version-traceid-parentid-flags
+	if parts := strings.Split(r.Header.Get("traceparent"), "-"); len(parts) == 4 && len(parts[1]) == 32 && validRequestID(parts[1]) {
+		return parts[1]
+	}
+	return newRandomID()
+}
+
+// validRequestID reports whether id is safe to echo in an instance URI.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '.' || c == '_' || c == ':' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRandomID() string {
+	var buf [16]byte
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,93 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDefaultInstance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{name: "request id header", headers: map[string]string{"X-Request-ID": "req-123"}, want: "/users/42#req-123"},
+		{
+			name:    "traceparent",
+			headers: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			want:    "/users/42#4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/42?expand=true", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			if got := DefaultInstance(req); got != tt.want {
+				t.Fatalf("expected instance %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	generated := DefaultInstance(httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	if !strings.HasPrefix(generated, "/users/42#") || len(generated) <= len("/users/42#") {
+		t.Fatalf("expected generated request id, got %q", generated)
+	}
+
+	for _, unsafe := range []string{"<script>alert(1)</script>", "a b", strings.Repeat("a", maxRequestIDLength+1)} {
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		req.Header.Set("X-Request-ID", unsafe)
+		if got := DefaultInstance(req); strings.Contains(got, unsafe) || len(got) != len(generated) {
+			t.Fatalf("expected %q to be replaced by a generated id, got %q", unsafe, got)
+		}
+	}
+}
+
+func TestParseRequestPopulatesInstance(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	tests := []struct {
+		name string
+		wrap func(http.Handler) http.Handler
+		want string
+	}{
+		{name: "off by default", wrap: func(next http.Handler) http.Handler { return next }, want: ""},
+		{name: "config", wrap: UseProblemConfig(ProblemConfig{Instance: DefaultInstance}), want: "/test/123#req-123"},
+		{
+			name: "custom func",
+			wrap: UseInstanceFunc(func(r *http.Request) string { return "urn:request:" + r.Header.Get("X-Request-ID") }),
+			want: "urn:request:req-123",
+		},
+		{name: "disabled", wrap: UseInstanceFunc(nil), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := tt.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = ParseRequest[*testRequest](w, r, testParamExtractor, nil, "id")
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/test/123", bytes.NewBufferString(`{invalid-json}`))
+			req.Header.Set("X-Request-ID", "req-123")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			var problem ProblemDetails
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Instance != tt.want {
+				t.Fatalf("expected instance %q, got %q", tt.want, problem.Instance)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package httpsuite
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// MultipleProblems returns a problem that reports several sub-problems, such as
+// the failed items of a batch operation, as an "errors" extension array of
+// nested problems. The status is the one the sub-problems share, 400 when they
+// are all client errors, and 500 otherwise.
+func MultipleProblems(problems ...*ProblemDetails) *ProblemDetails {
+	var nested []*ProblemDetails
+	for _, problem := range problems {
+		if problem != nil {
+			nested = append(nested, problem)
+		}
+	}
+
+	status := http.StatusInternalServerError
+	if len(nested) > 0 {
+		status = nested[0].Status
+		for _, problem := range nested[1:] {
+			if problem.Status == status {
+				continue
+			}
+			if status >= 400 && status <= 499 && problem.Status >= 400 && problem.Status <= 499 {
+				status = http.StatusBadRequest
+				continue
+			}
+			status = http.StatusInternalServerError
+			break
+		}
+	}
+
+	detail := strconv.Itoa(len(nested)) + " problems occurred"
+	if len(nested) == 1 {
+		detail = "1 problem occurred"
+	}
+	return Problem(status).Detail(detail).SubProblems(nested...).Build()
+}
+
+// SubProblems appends nested problems to the "errors" extension.
+func (b *ProblemBuilder) SubProblems(problems ...*ProblemDetails) *ProblemBuilder {
+	existing, _ := b.problem.Extensions["errors"].([]*ProblemDetails)
+	nested := append([]*ProblemDetails(nil), existing...)
+	for _, problem := range problems {
+		if problem != nil {
+			nested = append(nested, problem)
+		}
+	}
+	return b.Extension("errors", nested)
+}
+
+// SubProblems returns the nested problems attached with MultipleProblems or
+// ProblemBuilder.SubProblems.
+func (p *ProblemDetails) SubProblems() []*ProblemDetails {
+	nested, _ := p.Extensions["errors"].([]*ProblemDetails)
+	return nested
+}
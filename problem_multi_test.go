@@ -0,0 +1,73 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestMultipleProblems(t *testing.T) {
+	t.Parallel()
+
+	notFound := Problem(http.StatusNotFound).Detail("item 3 not found").With("index", 3).Build()
+	conflict := Problem(http.StatusConflict).Detail("item 5 already exists").With("index", 5).Build()
+	failure := Problem(http.StatusBadGateway).Detail("item 7 could not be stored").Build()
+
+	tests := []struct {
+		name       string
+		problems   []*ProblemDetails
+		wantStatus int
+		wantDetail string
+		wantCount  int
+	}{
+		{name: "same status", problems: []*ProblemDetails{notFound, notFound}, wantStatus: http.StatusNotFound, wantDetail: "2 problems occurred", wantCount: 2},
+		{name: "client errors", problems: []*ProblemDetails{notFound, conflict}, wantStatus: http.StatusBadRequest, wantDetail: "2 problems occurred", wantCount: 2},
+		{name: "server error", problems: []*ProblemDetails{notFound, failure}, wantStatus: http.StatusInternalServerError, wantDetail: "2 problems occurred", wantCount: 2},
+		{name: "single", problems: []*ProblemDetails{conflict, nil}, wantStatus: http.StatusConflict, wantDetail: "1 problem occurred", wantCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := MultipleProblems(tt.problems...)
+			if problem.Status != tt.wantStatus || problem.Detail != tt.wantDetail {
+				t.Fatalf("unexpected problem %#v", problem)
+			}
+			if len(problem.SubProblems()) != tt.wantCount {
+				t.Fatalf("expected %d sub-problems, got %d", tt.wantCount, len(problem.SubProblems()))
+			}
+		})
+	}
+}
+
+func TestMultipleProblemsEncoding(t *testing.T) {
+	t.Parallel()
+
+	problem := Problem(http.StatusBadRequest).
+		Title("Batch Failed").
+		SubProblems(Problem(http.StatusNotFound).Detail("item 3 not found").With("index", 3).Build()).
+		SubProblems(Problem(http.StatusConflict).Detail("item 5 already exists").With("index", 5).Build()).
+		Build()
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatalf("marshal problem: %v", err)
+	}
+
+	var decoded struct {
+		Title  string `json:"title"`
+		Errors []struct {
+			Status int    `json:"status"`
+			Detail string `json:"detail"`
+			Index  int    `json:"index"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("unmarshal problem: %v", err)
+	}
+	if decoded.Title != "Batch Failed" || len(decoded.Errors) != 2 {
+		t.Fatalf("unexpected payload %s", body)
+	}
+	if decoded.Errors[1].Status != http.StatusConflict || decoded.Errors[1].Index != 5 {
+		t.Fatalf("expected flattened nested extensions, got %s", body)
+	}
+}
@@ -0,0 +1,30 @@
+package httpsuite
+
+import (
+	"log"
+	"sync/atomic"
+)
+
+var productionMode atomic.Bool
+
+// SetProductionMode enables or disables sanitized error details. In production
+// mode, problems built from JSON decoder errors and SetParam failures carry a
+// generic detail, and the underlying error is only logged server-side.
+func SetProductionMode(enabled bool) {
+	productionMode.Store(enabled)
+}
+
+// ProductionMode reports whether error details are sanitized.
+func ProductionMode() bool {
+	return productionMode.Load()
+}
+
+// sanitizedDetail returns err's message, or generic in production mode after
+// logging err.
+func sanitizedDetail(err error, generic string) string {
+	if !ProductionMode() {
+		return err.Error()
+	}
+	log.Printf("Request error: %v", err)
+	return generic
+}
@@ -0,0 +1,62 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProductionModeSanitizesDetails(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+	SetProductionMode(true)
+	t.Cleanup(func() { SetProductionMode(false) })
+
+	tests := []struct {
+		name       string
+		body       string
+		path       string
+		wantDetail string
+		leaked     string
+	}{
+		{
+			name:       "decoder error",
+			body:       `{invalid-json}`,
+			path:       "/test/123",
+			wantDetail: "Request body is not valid JSON",
+			leaked:     "invalid character",
+		},
+		{
+			name:       "set param error",
+			body:       `{"name":"Test"}`,
+			path:       "/test/nope",
+			wantDetail: "Failed to bind parameter id",
+			leaked:     "invalid id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			if _, err := ParseRequest[*testRequest](w, req, testParamExtractor, nil, "id"); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if strings.Contains(w.Body.String(), tt.leaked) {
+				t.Fatalf("expected response not to contain %q, got %s", tt.leaked, w.Body.String())
+			}
+
+			var problem ProblemDetails
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Detail != tt.wantDetail {
+				t.Fatalf("expected detail %q, got %q", tt.wantDetail, problem.Detail)
+			}
+		})
+	}
+}
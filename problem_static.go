@@ -0,0 +1,78 @@
+package httpsuite
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+)
+
+// StaticProblem is a problem response encoded once, when it is created, for
+// error paths hot enough that encoding JSON per request matters, such as a
+// router's 404 and 405 replies or a rate limiter turning away a flood of
+// requests. Writing it copies cached headers and bytes without allocating.
+//
+// The body is the same for every request, so it carries no per-request
+// instance; use ProblemResponse for problems that need one.
+type StaticProblem struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// NewStaticProblem encodes problem, with headers such as Allow sent
+// alongside it. A problem carrying retry_after also sets Retry-After, as
+// ProblemResponse does.
+func NewStaticProblem(problem *ProblemDetails, headers http.Header) *StaticProblem {
+	var buffer bytes.Buffer
+	status, retryAfter, ok := encodeProblemDetail(&buffer, 0, problem)
+
+	header := headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if !ok {
+		status = http.StatusInternalServerError
+		buffer.Reset()
+		buffer.WriteString(http.StatusText(status) + "\n")
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		header.Set("Content-Type", problemContentType)
+		if retryAfter != "" && header.Get("Retry-After") == "" {
+			header.Set("Retry-After", retryAfter)
+		}
+	}
+	return &StaticProblem{status: status, header: header, body: buffer.Bytes()}
+}
+
+// Status returns the HTTP status the problem is sent with.
+func (p *StaticProblem) Status() int {
+	return p.status
+}
+
+// Write sends the problem. Its headers replace any of the same name already
+// set on w; the others are kept.
+func (p *StaticProblem) Write(w http.ResponseWriter) {
+	header := w.Header()
+	for key, values := range p.header {
+		header[key] = values
+	}
+	w.WriteHeader(p.status)
+	if _, err := w.Write(p.body); err != nil {
+		log.Printf("Failed to write problem details response body: %v", err)
+	}
+}
+
+// ServeHTTP writes the problem, so a StaticProblem can be mounted directly
+// as a fallback handler.
+func (p *StaticProblem) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	p.Write(w)
+}
+
+// readOnlyMethodNotAllowed is the 405 reply of handlers that only serve GET
+// and HEAD.
+func readOnlyMethodNotAllowed() *StaticProblem {
+	return NewStaticProblem(
+		NewProblemDetails(http.StatusMethodNotAllowed, BlankURL, "", ""),
+		http.Header{"Allow": {"GET, HEAD"}},
+	)
+}
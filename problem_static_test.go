@@ -0,0 +1,107 @@
+package httpsuite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStaticProblemMatchesProblemResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		problem *ProblemDetails
+	}{
+		{name: "not found", problem: NewNotFoundProblem("order 7 not found")},
+		{name: "too many requests", problem: NewTooManyRequestsProblem("rate limit exceeded", 1500*time.Millisecond)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want := httptest.NewRecorder()
+			ProblemResponse(want, tt.problem)
+			got := httptest.NewRecorder()
+			NewStaticProblem(tt.problem, nil).Write(got)
+
+			if got.Code != want.Code {
+				t.Fatalf("expected status %d, got %d", want.Code, got.Code)
+			}
+			if got.Body.String() != want.Body.String() {
+				t.Fatalf("expected body %q, got %q", want.Body.String(), got.Body.String())
+			}
+			for _, key := range []string{"Content-Type", "Retry-After"} {
+				if got.Header().Get(key) != want.Header().Get(key) {
+					t.Fatalf("expected %s %q, got %q", key, want.Header().Get(key), got.Header().Get(key))
+				}
+			}
+		})
+	}
+}
+
+func TestStaticProblemHeaders(t *testing.T) {
+	t.Parallel()
+
+	problem := NewStaticProblem(NewProblemDetails(http.StatusMethodNotAllowed, BlankURL, "", ""), http.Header{"Allow": {"GET, HEAD"}})
+	if problem.Status() != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status 405, got %d", problem.Status())
+	}
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Allow", "POST")
+	rec.Header().Set("X-Request-ID", "abc")
+	problem.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+
+	if rec.Header().Get("Allow") != "GET, HEAD" {
+		t.Fatalf("expected Allow to be replaced, got %q", rec.Header().Values("Allow"))
+	}
+	if rec.Header().Get("X-Request-ID") != "abc" {
+		t.Fatalf("expected unrelated headers to be kept, got %q", rec.Header().Get("X-Request-ID"))
+	}
+
+	// Appending to a written header must not leak into later responses.
+	rec.Header().Add("Allow", "POST")
+	next := httptest.NewRecorder()
+	problem.Write(next)
+	if values := next.Header().Values("Allow"); len(values) != 1 {
+		t.Fatalf("expected a single Allow value, got %q", values)
+	}
+}
+
+func TestStaticProblemUnencodable(t *testing.T) {
+	t.Parallel()
+
+	problem := NewProblemDetails(http.StatusConflict, BlankURL, "Conflict", "")
+	problem.Extensions = map[string]interface{}{"bad": make(chan int)}
+
+	rec := httptest.NewRecorder()
+	NewStaticProblem(problem, nil).Write(rec)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", rec.Code)
+	}
+}
+
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w *discardResponseWriter) WriteHeader(int)             {}
+
+func TestStaticProblemWriteDoesNotAllocate(t *testing.T) {
+	problem := NewStaticProblem(NewTooManyRequestsProblem("rate limit exceeded", time.Second), nil)
+	w := &discardResponseWriter{header: make(http.Header)}
+	problem.Write(w)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		clear(w.header)
+		problem.Write(w)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
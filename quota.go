@@ -0,0 +1,186 @@
+package httpsuite
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultQuotaLimit = 1000
+
+// QuotaPeriod is the interval after which quota counters reset.
+type QuotaPeriod int
+
+const (
+	// QuotaDaily resets counters at midnight.
+	QuotaDaily QuotaPeriod = iota
+	// QuotaMonthly resets counters on the first day of each month.
+	QuotaMonthly
+)
+
+// QuotaStore counts usage per key. Keys include the period, and reset is
+// when the period ends, so stores with native expiry can drop the counter
+// then.
+type QuotaStore interface {
+	Increment(ctx context.Context, key string, n int64, reset time.Time) (int64, error)
+}
+
+// MemoryQuotaStore is an in-memory QuotaStore for single-instance services.
+type MemoryQuotaStore struct {
+	mu          sync.Mutex
+	counters    map[string]*quotaCounter
+	latestReset time.Time
+}
+
+type quotaCounter struct {
+	count int64
+	reset time.Time
+}
+
+// NewMemoryQuotaStore returns an empty MemoryQuotaStore.
+func NewMemoryQuotaStore() *MemoryQuotaStore {
+	return &MemoryQuotaStore{counters: make(map[string]*quotaCounter)}
+}
+
+// Increment adds n to the counter for key and returns the new total. Counters
+// whose period has ended are dropped when a new period begins.
+func (s *MemoryQuotaStore) Increment(_ context.Context, key string, n int64, reset time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counter, ok := s.counters[key]
+	if !ok {
+		if reset.After(s.latestReset) {
+			s.latestReset = reset
+			now := time.Now()
+			for other, previous := range s.counters {
+				if !previous.reset.After(now) {
+					delete(s.counters, other)
+				}
+			}
+		}
+		counter = &quotaCounter{reset: reset}
+		s.counters[key] = counter
+	}
+	counter.count += n
+	return counter.count, nil
+}
+
+// QuotaOptions configures the Quota middleware.
+type QuotaOptions struct {
+	// Limit is the number of requests each principal may make per period.
+	// Defaults to 1000.
+	Limit int64
+	// LimitFor overrides Limit per request, e.g. by the caller's plan. A
+	// negative limit means unlimited.
+	LimitFor func(*http.Request) int64
+	// Period defaults to QuotaDaily.
+	Period QuotaPeriod
+	// Location sets where periods start. Defaults to UTC.
+	Location *time.Location
+	// Store defaults to a MemoryQuotaStore.
+	Store QuotaStore
+	// Principal identifies the caller. When nil, the PrincipalKey context
+	// value is used, or the client IP without one.
+	Principal func(*http.Request) string
+	// ExceededStatus is 429 Too Many Requests (default) or 402 Payment
+	// Required.
+	ExceededStatus int
+	Now            func() time.Time
+}
+
+// Quota returns middleware that counts requests per principal over daily or
+// monthly periods. Every response carries X-Quota-Limit, X-Quota-Remaining,
+// and X-Quota-Reset (Unix seconds) headers; requests beyond the limit receive
+// a quota_exceeded_error problem with Retry-After until the reset. Store
+// failures are logged and let the request through.
+func Quota(opts *QuotaOptions) func(http.Handler) http.Handler {
+	options := QuotaOptions{Limit: defaultQuotaLimit, Location: time.UTC, ExceededStatus: http.StatusTooManyRequests, Now: time.Now}
+	if opts != nil {
+		if opts.Limit != 0 {
+			options.Limit = opts.Limit
+		}
+		options.LimitFor = opts.LimitFor
+		options.Period = opts.Period
+		options.Store = opts.Store
+		options.Principal = opts.Principal
+		if opts.Location != nil {
+			options.Location = opts.Location
+		}
+		if opts.ExceededStatus == http.StatusPaymentRequired {
+			options.ExceededStatus = http.StatusPaymentRequired
+		}
+		if opts.Now != nil {
+			options.Now = opts.Now
+		}
+	}
+	if options.Store == nil {
+		options.Store = NewMemoryQuotaStore()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := options.Limit
+			if options.LimitFor != nil {
+				limit = options.LimitFor(r)
+			}
+			if limit < 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := options.Now()
+			start, reset := quotaPeriodBounds(options.Period, now.In(options.Location))
+			key := quotaPrincipal(r, options) + "\n" + start.Format(time.RFC3339)
+			used, err := options.Store.Increment(r.Context(), key, 1, reset)
+			if err != nil {
+				log.Printf("Quota store failed: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set("X-Quota-Limit", strconv.FormatInt(limit, 10))
+			header.Set("X-Quota-Remaining", strconv.FormatInt(max(limit-used, 0), 10))
+			header.Set("X-Quota-Reset", strconv.FormatInt(reset.Unix(), 10))
+			if used <= limit {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			problems := sharedProblemConfig(r.Context())
+			sendRequestProblem(w, r, options.ExceededStatus, Problem(options.ExceededStatus).
+				Type(problems.TypeURL("quota_exceeded_error")).
+				Title("Quota Exceeded").
+				Detail("the request quota for this period is exhausted").
+				Extension("limit", limit).
+				Extension("reset", reset.UTC().Format(time.RFC3339)).
+				RetryAfter(reset.Sub(now)).
+				Build())
+		})
+	}
+}
+
+func quotaPrincipal(r *http.Request, options QuotaOptions) string {
+	if options.Principal != nil {
+		return options.Principal(r)
+	}
+	if principal, ok := CtxGet(r.Context(), PrincipalKey); ok && principal != "" {
+		return principal
+	}
+	return ClientIP(r)
+}
+
+// quotaPeriodBounds returns the start of the period containing now and the
+// start of the next one.
+func quotaPeriodBounds(period QuotaPeriod, now time.Time) (time.Time, time.Time) {
+	year, month, day := now.Date()
+	if period == QuotaMonthly {
+		start := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+	start := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(0, 0, 1)
+}
@@ -0,0 +1,149 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQuotaTracksUsagePerPrincipal(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 14, 22, 0, 0, 0, time.UTC)
+	handler := Quota(&QuotaOptions{
+		Limit: 2,
+		Now:   func() time.Time { return now },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	call := func(principal string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/items", nil)
+		r = r.WithContext(CtxSet(r.Context(), PrincipalKey, principal))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec
+	}
+
+	reset := "1773532800" // 2026-03-15T00:00:00Z
+	for i, want := range []string{"1", "0"} {
+		rec := call("alice")
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d: expected 204, got %d", i, rec.Code)
+		}
+		if rec.Header().Get("X-Quota-Limit") != "2" || rec.Header().Get("X-Quota-Remaining") != want || rec.Header().Get("X-Quota-Reset") != reset {
+			t.Fatalf("request %d: unexpected quota headers %v", i, rec.Header())
+		}
+	}
+
+	exceeded := call("alice")
+	if exceeded.Code != http.StatusTooManyRequests || exceeded.Header().Get("X-Quota-Remaining") != "0" {
+		t.Fatalf("expected 429 with no remaining quota, got %d %v", exceeded.Code, exceeded.Header())
+	}
+	if exceeded.Header().Get("Retry-After") != "7200" {
+		t.Fatalf("expected Retry-After until midnight, got %q", exceeded.Header().Get("Retry-After"))
+	}
+	var problem ProblemDetails
+	if err := json.Unmarshal(exceeded.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("expected problem body, got %v", err)
+	}
+	if problem.Type != "/errors/quota-exceeded" || problem.Extensions["reset"] != "2026-03-15T00:00:00Z" {
+		t.Fatalf("unexpected problem %+v", problem)
+	}
+
+	if rec := call("bob"); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected separate quota for bob, got %d", rec.Code)
+	}
+
+	now = now.Add(3 * time.Hour)
+	if rec := call("alice"); rec.Code != http.StatusNoContent || rec.Header().Get("X-Quota-Remaining") != "1" {
+		t.Fatalf("expected quota to reset the next day, got %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestQuotaMonthlyPaymentRequiredAndPlans(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 12, 31, 12, 0, 0, 0, time.UTC)
+	handler := Quota(&QuotaOptions{
+		Period:         QuotaMonthly,
+		ExceededStatus: http.StatusPaymentRequired,
+		LimitFor: func(r *http.Request) int64 {
+			if r.Header.Get("X-Plan") == "enterprise" {
+				return -1
+			}
+			return 1
+		},
+		Principal: func(r *http.Request) string { return "acme" },
+		Now:       func() time.Time { return now },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	codes := make([]int, 0, 3)
+	for _, plan := range []string{"free", "free", "enterprise"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Plan", plan)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		codes = append(codes, rec.Code)
+		if plan == "free" && rec.Header().Get("X-Quota-Reset") != "1798761600" {
+			t.Fatalf("expected reset at the start of 2027, got %q", rec.Header().Get("X-Quota-Reset"))
+		}
+		if plan == "enterprise" && rec.Header().Get("X-Quota-Limit") != "" {
+			t.Fatal("expected unlimited plans to skip quota headers")
+		}
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusPaymentRequired || codes[2] != http.StatusOK {
+		t.Fatalf("expected 200, 402, 200, got %v", codes)
+	}
+}
+
+type failingQuotaStore struct{}
+
+func (failingQuotaStore) Increment(context.Context, string, int64, time.Time) (int64, error) {
+	return 0, errors.New("store down")
+}
+
+func TestQuotaFailsOpenOnStoreError(t *testing.T) {
+	t.Parallel()
+
+	handler := Quota(&QuotaOptions{Limit: 1, Store: failingQuotaStore{}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusAccepted || rec.Header().Get("X-Quota-Limit") != "" {
+		t.Fatalf("expected request to pass without quota headers, got %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestMemoryQuotaStoreDropsEndedPeriods(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryQuotaStore()
+	ctx := context.Background()
+	_, _ = store.Increment(ctx, "old", 1, time.Now().Add(-time.Hour))
+	if count, _ := store.Increment(ctx, "old", 2, time.Now().Add(-time.Hour)); count != 3 {
+		t.Fatalf("expected counter to accumulate, got %d", count)
+	}
+	_, _ = store.Increment(ctx, "new", 1, time.Now().Add(time.Hour))
+	if _, ok := store.counters["old"]; ok {
+		t.Fatal("expected ended period to be dropped")
+	}
+}
+
+func TestQuotaDefaultLimit(t *testing.T) {
+	t.Parallel()
+
+	handler := Quota(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	if rec.Code != http.StatusNoContent || rec.Header().Get("X-Quota-Limit") != "1000" || rec.Header().Get("X-Quota-Remaining") != "999" {
+		t.Fatalf("expected the default limit to admit the request, got %d %v", rec.Code, rec.Header())
+	}
+}
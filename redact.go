@@ -0,0 +1,324 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactedValue replaces the value of fields tagged `redact:"true"`.
+const RedactedValue = "[REDACTED]"
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Redact converts value into JSON-shaped data that is safe to log: fields
+// tagged `redact:"true"` are replaced with RedactedValue, fields tagged
+// `log:"-"` are removed, and fields tagged `mask` are partially masked.
+func Redact(value any) any {
+	return redactValue(value, redactMode{omitTags: []string{"log"}, redact: true, mask: true})
+}
+
+// Mask converts value into JSON-shaped data with fields tagged `mask`
+// partially masked, so one struct can serve internal and customer-facing
+// responses. Other fields are encoded as usual:
+//
+//	type Customer struct {
+//		Email string `json:"email" mask:"email"` // j***@example.com
+//		Card  string `json:"card" mask:"last4"`  // ****1111
+//	}
+//
+// Unknown mask names hide the whole value. Empty values stay empty.
+func Mask(value any) any {
+	return redactValue(value, redactMode{mask: true})
+}
+
+// redactMode selects what the redaction walker does.
+type redactMode struct {
+	// omitTags removes fields tagged `<name>:"-"`.
+	omitTags []string
+	// redact replaces fields tagged `redact:"true"` with RedactedValue.
+	redact bool
+	// mask applies `mask` tags.
+	mask bool
+	// scoped removes fields tagged `scope` unless scopes grants one of the
+	// listed scopes.
+	scoped bool
+	scopes []string
+}
+
+func redactValue(value any, mode redactMode) any {
+	if value == nil {
+		return nil
+	}
+	return redactReflect(reflect.ValueOf(value), mode)
+}
+
+func redactReflect(value reflect.Value, mode redactMode) any {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	metadata := metadataFor(value.Type())
+	if !metadata.redaction {
+		return value.Interface()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		fields := make(map[string]any, len(metadata.fields))
+		var promoted []map[string]any
+		for _, field := range metadata.fields {
+			if !field.json || omittedField(field.tag, mode.omitTags) {
+				continue
+			}
+			if mode.scoped && field.scopes != nil && !grantsScope(mode.scopes, field.scopes) {
+				continue
+			}
+			fieldValue := value.Field(field.index)
+			if field.omitEmpty && isEmptyValue(fieldValue) {
+				continue
+			}
+			switch {
+			case field.redacted && mode.redact:
+				fields[field.name] = RedactedValue
+			case field.mask != "" && mode.mask:
+				fields[field.name] = maskReflect(field.mask, fieldValue)
+			default:
+				walked := redactReflect(fieldValue, mode)
+				if field.inline {
+					if walked == nil {
+						continue
+					}
+					if members, ok := inlineMembers(walked); ok {
+						promoted = append(promoted, members)
+						continue
+					}
+				}
+				fields[field.name] = walked
+			}
+		}
+		// Embedded structs' members are promoted like encoding/json does,
+		// without shadowing the outer struct's own fields.
+		for _, members := range promoted {
+			for name, member := range members {
+				if _, ok := fields[name]; !ok {
+					fields[name] = member
+				}
+			}
+		}
+		return fields
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		items := make([]any, value.Len())
+		for i := range items {
+			items[i] = redactReflect(value.Index(i), mode)
+		}
+		return items
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		items := make(map[string]any, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			items[mapKeyString(iter.Key())] = redactReflect(iter.Value(), mode)
+		}
+		return items
+	default:
+		return value.Interface()
+	}
+}
+
+// inlineMembers returns the JSON members of an embedded struct. Structs
+// without redaction tags come back from the walker unchanged and are
+// converted through encoding/json.
+func inlineMembers(walked any) (map[string]any, bool) {
+	if members, ok := walked.(map[string]any); ok {
+		return members, true
+	}
+	encoded, err := json.Marshal(walked)
+	if err != nil {
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var members map[string]any
+	if err := decoder.Decode(&members); err != nil {
+		return nil, false
+	}
+	return members, true
+}
+
+// maskReflect masks the text of value, or of each element of a slice.
+func maskReflect(strategy string, value reflect.Value) any {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return maskedValue
+		}
+		return maskString(strategy, string(text))
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return maskString(strategy, value.String())
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		items := make([]any, value.Len())
+		for i := range items {
+			items[i] = maskReflect(strategy, value.Index(i))
+		}
+		return items
+	default:
+		return maskString(strategy, fmt.Sprint(value.Interface()))
+	}
+}
+
+const maskedValue = "****"
+
+// maskString applies a mask strategy: "email" keeps the first character of
+// the local part and the domain, "last4" keeps the last four characters, and
+// anything else hides the whole value.
+func maskString(strategy, s string) string {
+	if s == "" {
+		return ""
+	}
+	switch strategy {
+	case "email":
+		local, domain, ok := strings.Cut(s, "@")
+		if !ok || local == "" {
+			return maskedValue
+		}
+		first, _ := utf8.DecodeRuneInString(local)
+		return string(first) + "***@" + domain
+	case "last4":
+		runes := []rune(s)
+		if len(runes) <= 4 {
+			return maskedValue
+		}
+		return maskedValue + string(runes[len(runes)-4:])
+	default:
+		return maskedValue
+	}
+}
+
+// scanRedactionTags reports whether t, or any type reachable from it, declares
+// redaction tags. Types without tags are returned unchanged by the walker.
+func scanRedactionTags(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return scanRedactionTags(t.Elem(), visiting)
+	case reflect.Interface:
+		return true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if isRedactedField(field) || field.Tag.Get("mask") != "" || field.Tag.Get("scope") != "" || field.Tag.Get("log") == "-" || field.Tag.Get("audit") == "-" {
+				return true
+			}
+			if scanRedactionTags(field.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isRedactedField(field reflect.StructField) bool {
+	return field.Tag.Get("redact") == "true"
+}
+
+func omittedField(tag reflect.StructTag, omitTags []string) bool {
+	for _, name := range omitTags {
+		if tag.Get(name) == "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonFieldName returns the wire name for a struct field, or false when the
+// field is excluded from JSON.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name, true
+	}
+	return name, true
+}
+
+func jsonOmitEmpty(field reflect.StructField) bool {
+	_, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue reports whether omitempty drops v, following encoding/json:
+// false, 0, nil pointers and interfaces, and empty arrays, maps, slices, and
+// strings are empty, while structs never are.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+func mapKeyString(key reflect.Value) string {
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+	encoded, err := json.Marshal(key.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
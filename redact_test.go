@@ -0,0 +1,170 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type redactCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password" redact:"true"`
+	Token    string `json:"token" log:"-"`
+	Note     string `json:"note,omitempty"`
+}
+
+type redactEnvelope struct {
+	Credentials []redactCredentials `json:"credentials"`
+	Count       int                 `json:"count"`
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	got := Redact(&redactEnvelope{
+		Credentials: []redactCredentials{{Username: "ada", Password: "secret", Token: "abc"}},
+		Count:       1,
+	})
+
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal redacted value: %v", err)
+	}
+	want := `{"count":1,"credentials":[{"password":"[REDACTED]","username":"ada"}]}`
+	if string(encoded) != want {
+		t.Fatalf("expected %s, got %s", want, encoded)
+	}
+}
+
+type redactOmitEmpty struct {
+	Password string            `json:"password" redact:"true"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Window   [0]int            `json:"window,omitempty"`
+	Since    struct{ Day int } `json:"since,omitempty"`
+	Nothing  []string          `json:"nothing,omitempty"`
+}
+
+func TestRedactOmitEmptyMatchesJSON(t *testing.T) {
+	t.Parallel()
+
+	value := redactOmitEmpty{Password: "secret", Tags: []string{}, Labels: map[string]string{}}
+	plain, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal value: %v", err)
+	}
+	redacted, err := json.Marshal(Redact(value))
+	if err != nil {
+		t.Fatalf("marshal redacted value: %v", err)
+	}
+	var plainFields, redactedFields map[string]any
+	_ = json.Unmarshal(plain, &plainFields)
+	_ = json.Unmarshal(redacted, &redactedFields)
+	if len(plainFields) != len(redactedFields) {
+		t.Fatalf("expected the fields of %s, got %s", plain, redacted)
+	}
+	for key := range plainFields {
+		if _, ok := redactedFields[key]; !ok {
+			t.Fatalf("expected the fields of %s, got %s", plain, redacted)
+		}
+	}
+}
+
+func TestRedactUntaggedValueUnchanged(t *testing.T) {
+	t.Parallel()
+
+	value := testRequest{ID: 1, Name: "Ada"}
+	if got, ok := Redact(value).(testRequest); !ok || got != value {
+		t.Fatalf("expected untagged value to be returned unchanged, got %#v", got)
+	}
+}
+
+func TestProblemDetailsRedactsExtensions(t *testing.T) {
+	t.Parallel()
+
+	problem := NewBadRequestProblem("invalid credentials")
+	problem.Extensions = map[string]interface{}{
+		"input": redactCredentials{Username: "ada", Password: "secret"},
+	}
+
+	encoded, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatalf("marshal problem: %v", err)
+	}
+	if strings.Contains(string(encoded), "secret") {
+		t.Fatalf("expected password to be redacted, got %s", encoded)
+	}
+}
+
+type MaskTestAudit struct {
+	ID        int64 `json:"id"`
+	CreatedBy string
+}
+
+type maskCustomer struct {
+	MaskTestAudit
+	Name    string   `json:"name"`
+	Email   string   `json:"email" mask:"email"`
+	Card    *string  `json:"card,omitempty" mask:"last4"`
+	Phones  []string `json:"phones" mask:"last4"`
+	Account UUID     `json:"account" mask:"last4"`
+	PIN     int      `json:"pin" mask:"secret"`
+	Secret  string   `json:"secret" redact:"true"`
+}
+
+func TestMask(t *testing.T) {
+	t.Parallel()
+
+	card := "4111111111111111"
+	account, err := ParseUUID("0190a8a6-6d52-7c6b-8f0e-5a1c2f3b4d5e")
+	if err != nil {
+		t.Fatalf("parse uuid: %v", err)
+	}
+	got := Mask([]maskCustomer{{
+		MaskTestAudit: MaskTestAudit{ID: 9007199254740993, CreatedBy: "ops"},
+		Name:          "Jo",
+		Email:         "jo@example.com",
+		Card:          &card,
+		Phones:        []string{"+1 555 0100", "12"},
+		Account:       account,
+		PIN:           1234,
+		Secret:        "kept",
+	}})
+
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal masked value: %v", err)
+	}
+	want := `[{"CreatedBy":"ops","account":"****4d5e","card":"****1111","email":"j***@example.com","id":9007199254740993,"name":"Jo","phones":["****0100","****"],"pin":"****","secret":"kept"}]`
+	if string(encoded) != want {
+		t.Fatalf("expected %s, got %s", want, encoded)
+	}
+
+	logged, _ := json.Marshal(Redact(maskCustomer{Email: "jo@example.com", Secret: "hidden"}))
+	if !strings.Contains(string(logged), `"email":"j***@example.com"`) || !strings.Contains(string(logged), `"secret":"[REDACTED]"`) {
+		t.Fatalf("expected Redact to mask and redact, got %s", logged)
+	}
+}
+
+func TestMaskString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		strategy string
+		value    string
+		want     string
+	}{
+		{"email", "jane.doe@example.com", "j***@example.com"},
+		{"email", "émile@example.fr", "é***@example.fr"},
+		{"email", "not-an-email", "****"},
+		{"email", "", ""},
+		{"last4", "1234", "****"},
+		{"last4", "DE89370400440532013000", "****3000"},
+		{"unknown", "anything", "****"},
+	}
+	for _, tt := range tests {
+		if got := maskString(tt.strategy, tt.value); got != tt.want {
+			t.Fatalf("%s(%q): expected %q, got %q", tt.strategy, tt.value, tt.want, got)
+		}
+	}
+}
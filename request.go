@@ -2,13 +2,28 @@ package httpsuite
 
 import (
 	"errors"
+	"log"
 	"net/http"
+	"reflect"
 )
 
 // ParseRequest parses the incoming HTTP request into a specified struct type,
-// handling JSON decoding, request body limits, path parameter binding, and
-// optional validation. Invalid inputs return regular errors instead of panicking.
+// handling JSON decoding, request body limits, path and query parameter
+// binding, and optional validation. Invalid inputs return regular errors instead of panicking.
 func ParseRequest[T any](w http.ResponseWriter, r *http.Request, paramExtractor ParamExtractor, opts *ParseOptions, pathParams ...string) (T, error) {
+	return parseRequest[T](w, r, paramExtractor, opts, nil, pathParams)
+}
+
+// ParseRequestRaw is ParseRequest that also returns the raw request bytes
+// read while parsing, up to ParseOptions.MaxRawBodyBytes. The raw body is
+// returned even when parsing fails, e.g. to log rejected payloads.
+func ParseRequestRaw[T any](w http.ResponseWriter, r *http.Request, paramExtractor ParamExtractor, opts *ParseOptions, pathParams ...string) (T, *RawBody, error) {
+	raw := &RawBody{}
+	request, err := parseRequest[T](w, r, paramExtractor, opts, raw, pathParams)
+	return request, raw, err
+}
+
+func parseRequest[T any](w http.ResponseWriter, r *http.Request, paramExtractor ParamExtractor, opts *ParseOptions, raw *RawBody, pathParams []string) (T, error) {
 	var empty T
 	if r == nil {
 		return empty, errNilHTTPRequest
@@ -16,37 +31,93 @@ func ParseRequest[T any](w http.ResponseWriter, r *http.Request, paramExtractor
 	if r.Body != nil {
 		defer func() { _ = r.Body.Close() }()
 	}
+	recordRoutePattern(r.Context(), r.Pattern, false)
+	timing := serverTimingFrom(r.Context())
+	timing.begin(TimingParse)
+	defer timing.end()
 
 	options := normalizeParseOptions(opts)
-
-	request, err := DecodeRequestBody[T](r, options.MaxBodyBytes)
-	if err != nil {
-		var decodeErr *BodyDecodeError
-		if !errors.As(err, &decodeErr) {
-			return empty, err
+	if opts == nil || opts.Validator == nil {
+		if validator, ok := validatorFromContext(r.Context()); ok {
+			options.Validator = validator
 		}
-		problem, status := problemFromDecodeError(err, options.Problems)
-		SendResponse[any](w, status, nil, problem, nil)
+	}
+	if options.Problems == nil {
+		options.Problems = sharedProblemConfig(r.Context())
+	}
+	captureRawBody(r, raw, options.MaxRawBodyBytes)
+	if err := checkRequestSchema(w, r, options); err != nil {
 		return empty, err
 	}
 
-	request, err = BindPathParams(request, r, paramExtractor, pathParams...)
-	if err != nil {
-		var pathErr *PathParamError
-		if !errors.As(err, &pathErr) {
-			return empty, err
+	request, bodyErr := decodeRequestBody[T](r, options.MaxBodyBytes, jsonLimits{
+		maxDepth:        options.MaxJSONDepth,
+		maxNumberLength: options.MaxJSONNumberLength,
+	})
+	if bodyErr != nil {
+		if problem := contextProblem(r.Context(), options.Problems); problem != nil {
+			sendRequestProblem(w, r, problem.Status, problem)
+			return empty, errors.Join(bodyErr, r.Context().Err())
+		}
+		var decodeErr *BodyDecodeError
+		if !errors.As(bodyErr, &decodeErr) {
+			return empty, bodyErr
+		}
+		// Field-level errors are aggregated with parameter errors below;
+		// malformed or oversized bodies stop parsing immediately.
+		if decodeErr.Kind != BodyDecodeErrorInvalidType && decodeErr.Kind != BodyDecodeErrorInvalidValue {
+			problem, status := problemFromDecodeError(bodyErr, options.Problems)
+			sendRequestProblem(w, r, status, problem)
+			return empty, bodyErr
 		}
-		problem, status := problemFromPathParamError(err, options.Problems)
-		SendResponse[any](w, status, nil, problem, nil)
-		return empty, err
+	}
+
+	request, pathErr := BindPathParams(request, r, paramExtractor, pathParams...)
+	if pathErr != nil && len(pathParamErrors(pathErr)) == 0 {
+		sendParseSetupError(w, r, pathErr, options.Problems)
+		return empty, pathErr
+	}
+	request, queryErr := BindQueryParams(request, r)
+	if queryErr != nil && len(queryParamErrors(queryErr)) == 0 {
+		sendParseSetupError(w, r, queryErr, options.Problems)
+		return empty, queryErr
+	}
+	if bodyErr != nil || pathErr != nil || queryErr != nil {
+		problem, status := problemFromBindingErrors(bodyErr, pathErr, queryErr, options.Problems)
+		sendRequestProblem(w, r, status, withFieldExamples(problem, reflect.TypeFor[T]()))
+		return empty, errors.Join(bodyErr, pathErr, queryErr)
 	}
 
 	if !options.SkipValidation {
-		if problem := ValidateRequest(request, options.Validator); problem != nil {
-			SendResponse[any](w, validationProblemStatus(problem), nil, problem, nil)
+		timing.begin(TimingValidate)
+		problem := validateParsedRequest(r, request, options.Validator)
+		problem = applyValueValidation(request, problem, options.Problems)
+		problem = applyRequestRules(r.Context(), request, problem, options.Problems)
+		problem = withValidationStatus(problem, options.ValidationStatus)
+		if problem == nil {
+			problem = runFieldChecks(r.Context(), request, options.MaxConcurrentChecks, options.Problems)
+		}
+		if problem != nil {
+			sendRequestProblem(w, r, validationProblemStatus(problem), withFieldExamples(problem, reflect.TypeFor[T]()))
 			return empty, errValidationFailed
 		}
 	}
 
+	recordAuditRequest(r.Context(), request)
+	recordResponseHookRequest(r.Context(), request)
 	return request, nil
 }
+
+// sendParseSetupError reports binding errors that are not the client's
+// fault, such as a missing param extractor or a request type that cannot
+// receive parameters, as a 500 problem.
+func sendParseSetupError(w http.ResponseWriter, r *http.Request, err error, problems *ProblemConfig) {
+	log.Printf("Failed to bind request parameters: %v", err)
+	status := http.StatusInternalServerError
+	sendRequestProblem(w, r, status, NewProblemDetails(
+		status,
+		problems.TypeURL("server_error"),
+		"Internal Server Error",
+		"The request could not be processed.",
+	))
+}
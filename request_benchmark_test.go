@@ -0,0 +1,82 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+type benchmarkItem struct {
+	SKU      string   `json:"sku"`
+	Quantity int      `json:"quantity"`
+	Price    float64  `json:"price"`
+	Tags     []string `json:"tags"`
+}
+
+type benchmarkOrder struct {
+	ID       int             `json:"id"`
+	Customer string          `json:"customer"`
+	Items    []benchmarkItem `json:"items"`
+}
+
+func (o *benchmarkOrder) SetParam(fieldName, value string) error {
+	id, err := strconv.Atoi(value)
+	o.ID = id
+	return err
+}
+
+func benchmarkPayload(b *testing.B, items int) []byte {
+	b.Helper()
+	order := benchmarkOrder{Customer: "ada"}
+	for i := 0; i < items; i++ {
+		order.Items = append(order.Items, benchmarkItem{SKU: "sku-" + strconv.Itoa(i), Quantity: i, Price: 9.99, Tags: []string{"a", "b"}})
+	}
+	data, err := json.Marshal(order)
+	if err != nil {
+		b.Fatalf("marshal payload: %v", err)
+	}
+	return data
+}
+
+func BenchmarkParseRequest(b *testing.B) {
+	for _, size := range []struct {
+		name  string
+		items int
+	}{{"small", 1}, {"large", 500}} {
+		payload := benchmarkPayload(b, size.items)
+		for _, validation := range []struct {
+			name string
+			opts *ParseOptions
+		}{
+			{"validation", &ParseOptions{Validator: stubValidator{}}},
+			{"skip-validation", &ParseOptions{SkipValidation: true}},
+		} {
+			b.Run(size.name+"/"+validation.name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(payload)))
+				for i := 0; i < b.N; i++ {
+					w := httptest.NewRecorder()
+					r := httptest.NewRequest(http.MethodPost, "/orders/7", bytes.NewReader(payload))
+					if _, err := ParseRequest[*benchmarkOrder](w, r, testParamExtractor, validation.opts, "id"); err != nil {
+						b.Fatalf("unexpected error %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkValidateValues(b *testing.B) {
+	request := valueObjectRequest{
+		Total: testMoney{Amount: 10, Currency: "EUR"},
+		Items: []testLineItem{{Price: testMoney{Amount: 5}}, {Price: testMoney{Amount: 7}}},
+		Phone: "+4912345",
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = ValidateValues(&request)
+	}
+}
@@ -28,6 +28,8 @@ func (e *PathParamError) Unwrap() error {
 }
 
 // BindPathParams applies extracted path params to a request object without writing HTTP responses.
+// Every parameter is attempted; when several fail, the returned error joins one
+// *PathParamError per parameter.
 func BindPathParams[T any](request T, r *http.Request, paramExtractor ParamExtractor, pathParams ...string) (T, error) {
 	if len(pathParams) == 0 {
 		return request, nil
@@ -54,24 +56,50 @@ func BindPathParams[T any](request T, r *http.Request, paramExtractor ParamExtra
 		return empty, errors.Join(errInvalidRequestType, errors.New("request type does not implement RequestParamSetter"))
 	}
 
+	var bindErrs []error
 	for _, key := range pathParams {
 		value := paramExtractor(r, key)
 		if value == "" {
-			var empty T
-			return empty, &PathParamError{
+			bindErrs = append(bindErrs, &PathParamError{
 				Param:   key,
 				Missing: true,
-			}
+			})
+			continue
 		}
 
 		if err := setter.SetParam(key, value); err != nil {
-			var empty T
-			return empty, &PathParamError{
+			bindErrs = append(bindErrs, &PathParamError{
 				Param: key,
 				Err:   err,
-			}
+			})
 		}
 	}
 
-	return request, nil
+	switch len(bindErrs) {
+	case 0:
+		return request, nil
+	case 1:
+		var empty T
+		return empty, bindErrs[0]
+	default:
+		var empty T
+		return empty, errors.Join(bindErrs...)
+	}
+}
+
+// pathParamErrors returns every *PathParamError contained in err.
+func pathParamErrors(err error) []*PathParamError {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var result []*PathParamError
+		for _, inner := range joined.Unwrap() {
+			result = append(result, pathParamErrors(inner)...)
+		}
+		return result
+	}
+
+	var pathErr *PathParamError
+	if errors.As(err, &pathErr) {
+		return []*PathParamError{pathErr}
+	}
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 )
 
@@ -75,3 +76,43 @@ func TestBindPathParams(t *testing.T) {
 		}
 	})
 }
+
+type multiParamRequest struct {
+	OrgID  int
+	UserID int
+}
+
+func (r *multiParamRequest) SetParam(fieldName, value string) error {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.New("must be an integer")
+	}
+	if fieldName == "org" {
+		r.OrgID = id
+	} else {
+		r.UserID = id
+	}
+	return nil
+}
+
+func TestBindPathParamsAggregatesErrors(t *testing.T) {
+	t.Parallel()
+
+	params := map[string]string{"org": "acme"}
+	extractor := func(_ *http.Request, key string) string { return params[key] }
+
+	req := httptest.NewRequest(http.MethodGet, "/orgs/acme/users", nil)
+	_, err := BindPathParams[*multiParamRequest](nil, req, extractor, "org", "user")
+	pathErrs := pathParamErrors(err)
+	if len(pathErrs) != 2 {
+		t.Fatalf("expected 2 path errors, got %v", err)
+	}
+	if pathErrs[0].Param != "org" || pathErrs[0].Missing || pathErrs[1].Param != "user" || !pathErrs[1].Missing {
+		t.Fatalf("unexpected path errors: %#v %#v", pathErrs[0], pathErrs[1])
+	}
+
+	var pathErr *PathParamError
+	if !errors.As(err, &pathErr) {
+		t.Fatalf("expected joined error to expose PathParamError, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package httpsuite
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+)
+
+const defaultMaxConcurrentChecks = 4
+
+// FieldCheck is an I/O-bound check for a single field, such as a uniqueness
+// lookup against a database.
+type FieldCheck struct {
+	Field string
+	Check func(ctx context.Context) error
+}
+
+// FieldChecker is implemented by request types that need expensive checks.
+// ParseRequest runs them concurrently once cheaper validation has passed.
+type FieldChecker interface {
+	FieldChecks() []FieldCheck
+}
+
+// FieldCheckError reports a failed field check with the status it maps to.
+type FieldCheckError struct {
+	Status  int
+	Message string
+}
+
+func (e *FieldCheckError) Error() string {
+	return e.Message
+}
+
+// ConflictError marks a field check failure as a 409 Conflict, e.g. "email already registered".
+func ConflictError(message string) error {
+	return &FieldCheckError{Status: http.StatusConflict, Message: message}
+}
+
+// InvalidError marks a field check failure as a 422 Unprocessable Entity.
+func InvalidError(message string) error {
+	return &FieldCheckError{Status: http.StatusUnprocessableEntity, Message: message}
+}
+
+// runFieldChecks runs the request's field checks with bounded parallelism and
+// converts failures into a validation problem. Checks that stop because ctx
+// ended are reported as 499 or 408 without logging; other errors that are not
+// FieldCheckErrors are logged and reported as a 500 problem.
+func runFieldChecks(ctx context.Context, request any, limit int, problems *ProblemConfig) *ProblemDetails {
+	checker, ok := request.(FieldChecker)
+	if !ok || isRequestNil(request) {
+		return nil
+	}
+	checks := checker.FieldChecks()
+	if len(checks) == 0 {
+		return nil
+	}
+	if limit <= 0 {
+		limit = defaultMaxConcurrentChecks
+	}
+
+	results := make([]error, len(checks))
+	semaphore := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		if check.Check == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, check FieldCheck) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+				results[i] = check.Check(ctx)
+			case <-ctx.Done():
+				results[i] = ctx.Err()
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	status := http.StatusUnprocessableEntity
+	var details []ValidationErrorDetail
+	for i, err := range results {
+		if err == nil {
+			continue
+		}
+		if problem := contextProblem(ctx, problems); problem != nil {
+			return problem
+		}
+		var checkErr *FieldCheckError
+		if !errors.As(err, &checkErr) {
+			log.Printf("Field check for %s failed: %v", checks[i].Field, err)
+			return NewProblemDetails(
+				http.StatusInternalServerError,
+				problems.TypeURL("server_error"),
+				"Internal Server Error",
+				"The request could not be validated.",
+			)
+		}
+		if checkErr.Status == http.StatusConflict {
+			status = http.StatusConflict
+		}
+		details = append(details, ValidationErrorDetail{Field: checks[i].Field, Message: checkErr.Message})
+	}
+	if len(details) == 0 {
+		return nil
+	}
+
+	problem := NewProblemDetails(
+		status,
+		problems.TypeURL("validation_error"),
+		"Validation Error",
+		"One or more fields failed validation.",
+	)
+	problem.Extensions = map[string]interface{}{"errors": details}
+	return problem
+}
@@ -0,0 +1,209 @@
+package httpsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type signupRequest struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+
+	inFlight *atomic.Int32
+	peak     *atomic.Int32
+	lookup   func(field, value string) error
+}
+
+func (s *signupRequest) FieldChecks() []FieldCheck {
+	check := func(field, value string) FieldCheck {
+		return FieldCheck{Field: field, Check: func(context.Context) error {
+			current := s.inFlight.Add(1)
+			defer s.inFlight.Add(-1)
+			for {
+				peak := s.peak.Load()
+				if current <= peak || s.peak.CompareAndSwap(peak, current) {
+					break
+				}
+			}
+			return s.lookup(field, value)
+		}}
+	}
+	return []FieldCheck{
+		check("email", s.Email),
+		check("username", s.Username),
+		check("nickname", s.Username),
+	}
+}
+
+func TestRunFieldChecks(t *testing.T) {
+	t.Parallel()
+
+	problems := DefaultProblemConfig()
+	tests := []struct {
+		name       string
+		lookup     func(field, value string) error
+		wantStatus int
+		wantFields int
+	}{
+		{
+			name:   "all pass",
+			lookup: func(string, string) error { return nil },
+		},
+		{
+			name: "conflict wins",
+			lookup: func(field, _ string) error {
+				if field == "email" {
+					return ConflictError("email already registered")
+				}
+				return InvalidError(field + " is reserved")
+			},
+			wantStatus: http.StatusConflict,
+			wantFields: 3,
+		},
+		{
+			name: "invalid only",
+			lookup: func(field, _ string) error {
+				if field == "username" {
+					return InvalidError("username is reserved")
+				}
+				return nil
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: 1,
+		},
+		{
+			name:       "infrastructure error",
+			lookup:     func(string, string) error { return errors.New("db down") },
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, peak atomic.Int32
+			request := &signupRequest{inFlight: &inFlight, peak: &peak, lookup: tt.lookup}
+
+			problem := runFieldChecks(context.Background(), request, 2, &problems)
+			if peak.Load() > 2 {
+				t.Fatalf("expected at most 2 concurrent checks, got %d", peak.Load())
+			}
+			if tt.wantStatus == 0 {
+				if problem != nil {
+					t.Fatalf("expected no problem, got %#v", problem)
+				}
+				return
+			}
+			if problem == nil || problem.Status != tt.wantStatus {
+				t.Fatalf("expected status %d, got %#v", tt.wantStatus, problem)
+			}
+			if tt.wantFields > 0 {
+				details := problem.Extensions["errors"].([]ValidationErrorDetail)
+				if len(details) != tt.wantFields {
+					t.Fatalf("expected %d errors, got %#v", tt.wantFields, details)
+				}
+			}
+		})
+	}
+}
+
+type uniqueEmailRequest struct {
+	Email string `json:"email"`
+}
+
+func (u *uniqueEmailRequest) FieldChecks() []FieldCheck {
+	return []FieldCheck{{Field: "email", Check: func(context.Context) error {
+		if u.Email == "taken@example.com" {
+			return ConflictError("email already registered")
+		}
+		return nil
+	}}}
+}
+
+func TestParseRequestRunsFieldChecks(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	req := httptest.NewRequest(http.MethodPost, "/signup", bytes.NewBufferString(`{"email":"taken@example.com"}`))
+	w := httptest.NewRecorder()
+
+	if _, err := ParseRequest[*uniqueEmailRequest](w, req, nil, nil); err == nil {
+		t.Fatal("expected field check error, got nil")
+	}
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	var problem struct {
+		Errors []ValidationErrorDetail `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "email" {
+		t.Fatalf("unexpected errors %#v", problem.Errors)
+	}
+}
+
+func TestRunFieldChecksEndedContext(t *testing.T) {
+	t.Parallel()
+
+	problems := DefaultProblemConfig()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancelExpired()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantStatus int
+		wantType   string
+	}{
+		{name: "client canceled", ctx: canceled, wantStatus: StatusClientClosedRequest, wantType: problems.TypeURL("client_closed_request_error")},
+		{name: "deadline passed", ctx: expired, wantStatus: http.StatusRequestTimeout, wantType: problems.TypeURL("request_timeout_error")},
+	}
+	for _, tt := range tests {
+		var inFlight, peak atomic.Int32
+		request := &signupRequest{inFlight: &inFlight, peak: &peak, lookup: func(string, string) error {
+			return tt.ctx.Err()
+		}}
+		problem := runFieldChecks(tt.ctx, request, 1, &problems)
+		if problem == nil || problem.Status != tt.wantStatus || problem.Type != tt.wantType {
+			t.Fatalf("%s: expected status %d, got %#v", tt.name, tt.wantStatus, problem)
+		}
+	}
+}
+
+func TestParseRequestCanceledBodyRead(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	body := io.MultiReader(bytes.NewBufferString(`{"email":"a`), readerFunc(func([]byte) (int, error) {
+		cancel()
+		return 0, io.ErrUnexpectedEOF
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/signup", body).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	_, err := ParseRequest[*uniqueEmailRequest](w, req, nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if w.Code != StatusClientClosedRequest {
+		t.Fatalf("expected status %d, got %d", StatusClientClosedRequest, w.Code)
+	}
+}
+
+type readerFunc func([]byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
@@ -1,11 +1,13 @@
 package httpsuite
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 )
 
 // BodyDecodeErrorKind identifies the decode failure category.
@@ -13,15 +15,33 @@ type BodyDecodeErrorKind string
 
 const (
 	BodyDecodeErrorInvalidJSON       BodyDecodeErrorKind = "invalid_json"
+	BodyDecodeErrorInvalidType       BodyDecodeErrorKind = "invalid_type"
+	BodyDecodeErrorInvalidValue      BodyDecodeErrorKind = "invalid_value"
 	BodyDecodeErrorBodyTooLarge      BodyDecodeErrorKind = "body_too_large"
 	BodyDecodeErrorMultipleDocuments BodyDecodeErrorKind = "multiple_documents"
+	BodyDecodeErrorInvalidUTF8       BodyDecodeErrorKind = "invalid_utf8"
+	BodyDecodeErrorTooDeep           BodyDecodeErrorKind = "too_deep"
+	BodyDecodeErrorNumberTooLong     BodyDecodeErrorKind = "number_too_long"
 )
 
 // BodyDecodeError represents a request body parsing error.
 type BodyDecodeError struct {
-	Kind  BodyDecodeErrorKind
-	Err   error
+	Kind BodyDecodeErrorKind
+	Err  error
+	// Limit is the exceeded size, depth, or number length.
 	Limit int64
+	// Field is the JSON path of the offending value for invalid_type and
+	// invalid_value errors.
+	Field string
+	// Expected is the JSON type the field requires for invalid_type errors.
+	Expected string
+	// Offset is the byte offset where decoding failed, when known.
+	Offset int64
+	// Line and Column locate the failure for syntax errors, incomplete
+	// bodies, and guard violations. Columns count bytes. Both are 0 when
+	// unknown.
+	Line   int64
+	Column int64
 }
 
 func (e *BodyDecodeError) Error() string {
@@ -30,20 +50,56 @@ func (e *BodyDecodeError) Error() string {
 		return fmt.Sprintf("request body exceeds the limit of %d bytes", e.Limit)
 	case BodyDecodeErrorMultipleDocuments:
 		return "request body must contain a single JSON document"
+	case BodyDecodeErrorInvalidUTF8:
+		return "request body contains invalid UTF-8"
+	case BodyDecodeErrorTooDeep:
+		return fmt.Sprintf("request body exceeds the maximum nesting depth of %d", e.Limit)
+	case BodyDecodeErrorNumberTooLong:
+		return fmt.Sprintf("request body contains a number longer than %d characters", e.Limit)
+	case BodyDecodeErrorInvalidType:
+		if e.Field == "" {
+			return "request body must be a JSON " + e.Expected
+		}
+		return "field " + e.Field + " must be a JSON " + e.Expected
+	case BodyDecodeErrorInvalidValue:
+		if e.Field == "" {
+			return "request body is invalid: " + e.Err.Error()
+		}
+		return "field " + e.Field + " is invalid: " + e.Err.Error()
 	default:
-		if e.Err != nil {
-			return e.Err.Error()
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.Is(e.Err, io.ErrUnexpectedEOF):
+			return "request body contains incomplete JSON" + e.at()
+		case errors.As(e.Err, &syntaxErr):
+			return "request body is not valid JSON" + e.at()
+		case e.Err != nil:
+			return "request body could not be read"
 		}
 		return "invalid request body"
 	}
 }
 
+// at describes the position of the error, when known.
+func (e *BodyDecodeError) at() string {
+	if e.Line == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" at line %d, column %d", e.Line, e.Column)
+}
+
 func (e *BodyDecodeError) Unwrap() error {
 	return e.Err
 }
 
-// DecodeRequestBody decodes a JSON request body into T without writing HTTP responses.
+// DecodeRequestBody decodes a JSON request body into T without writing HTTP
+// responses. Bodies with invalid UTF-8, nesting deeper than 128 levels, or
+// numbers longer than 256 characters are rejected.
 func DecodeRequestBody[T any](r *http.Request, maxBodyBytes int64) (T, error) {
+	return decodeRequestBody[T](r, maxBodyBytes, defaultJSONLimits)
+}
+
+func decodeRequestBody[T any](r *http.Request, maxBodyBytes int64, limits jsonLimits) (T, error) {
 	var request T
 	if r == nil {
 		return request, errNilHTTPRequest
@@ -60,10 +116,25 @@ func DecodeRequestBody[T any](r *http.Request, maxBodyBytes int64) (T, error) {
 		limit = defaultMaxBodyBytes
 	}
 
-	body := http.MaxBytesReader(nilResponseWriter{}, r.Body, limit)
+	guard := newJSONGuard(http.MaxBytesReader(nilResponseWriter{}, r.Body, limit), limits)
+	var body io.Reader = guard
+	// Value objects reject input from UnmarshalJSON/UnmarshalText without any
+	// field context, so keep the consumed bytes, up to the body limit, to
+	// locate the failing field.
+	var consumed *cappedBuffer
+	requestType := reflect.TypeOf((*T)(nil)).Elem()
+	if metadataFor(requestType).valueUnmarshaler {
+		consumed = &cappedBuffer{limit: limit}
+		body = io.TeeReader(body, consumed)
+	}
 	decoder := json.NewDecoder(body)
 
 	if err := decoder.Decode(&request); err != nil {
+		var guardErr *BodyDecodeError
+		if errors.As(err, &guardErr) {
+			locateDecodeError(guard, guardErr, guardErr.Offset)
+			return request, guardErr
+		}
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
 			return request, &BodyDecodeError{
@@ -73,10 +144,25 @@ func DecodeRequestBody[T any](r *http.Request, maxBodyBytes int64) (T, error) {
 			}
 		}
 
-		return request, &BodyDecodeError{
-			Kind: BodyDecodeErrorInvalidJSON,
-			Err:  err,
+		decodeErr := newInvalidJSONError(err)
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.As(err, &syntaxErr):
+			// The offset counts the offending byte.
+			locateDecodeError(guard, decodeErr, syntaxErr.Offset-1)
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			locateDecodeError(guard, decodeErr, guard.offset)
 		}
+		if consumed != nil && isValueUnmarshalError(err) {
+			if field, valueErr, ok := locateValueDecodeError(requestType, consumed.Bytes(), ""); ok {
+				decodeErr = &BodyDecodeError{
+					Kind:  BodyDecodeErrorInvalidValue,
+					Err:   valueErr,
+					Field: field,
+				}
+			}
+		}
+		return request, decodeErr
 	}
 
 	var trailing json.RawMessage
@@ -84,6 +170,10 @@ func DecodeRequestBody[T any](r *http.Request, maxBodyBytes int64) (T, error) {
 		if errors.Is(err, io.EOF) {
 			return request, nil
 		}
+		var guardErr *BodyDecodeError
+		if errors.As(err, &guardErr) {
+			return request, guardErr
+		}
 
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
@@ -102,6 +192,116 @@ func DecodeRequestBody[T any](r *http.Request, maxBodyBytes int64) (T, error) {
 	return request, &BodyDecodeError{Kind: BodyDecodeErrorMultipleDocuments}
 }
 
+func newInvalidJSONError(err error) *BodyDecodeError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &BodyDecodeError{
+			Kind:     BodyDecodeErrorInvalidType,
+			Err:      err,
+			Field:    typeErr.Field,
+			Expected: jsonTypeName(typeErr.Type),
+			Offset:   typeErr.Offset,
+		}
+	}
+
+	decodeErr := &BodyDecodeError{Kind: BodyDecodeErrorInvalidJSON, Err: err}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		decodeErr.Offset = syntaxErr.Offset
+	}
+	return decodeErr
+}
+
+// cappedBuffer keeps up to limit bytes written to it and drops the rest.
+type cappedBuffer struct {
+	bytes.Buffer
+	limit int64
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - int64(b.Len()); remaining < int64(len(p)) {
+		_, _ = b.Buffer.Write(p[:max(remaining, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// locateDecodeError sets the line and column of the byte at index, when the
+// guard can still tell them.
+func locateDecodeError(guard *jsonGuard, decodeErr *BodyDecodeError, index int64) {
+	if line, column, ok := guard.position(index); ok {
+		decodeErr.Line, decodeErr.Column = line, column
+	}
+}
+
+// isValueUnmarshalError reports whether err came from a custom unmarshaler
+// rather than from encoding/json itself.
+func isValueUnmarshalError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) && !errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// jsonTypeName describes a Go type by the JSON type clients must send.
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "value"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Pointer:
+		return jsonTypeName(t.Elem())
+	default:
+		return "value"
+	}
+}
+
+// readRequestBody reads the full request body up to limit bytes and restores
+// r.Body so the payload can still be decoded afterwards.
+func readRequestBody(r *http.Request, limit int64) ([]byte, error) {
+	if r == nil {
+		return nil, errNilHTTPRequest
+	}
+	if r.Body == nil {
+		return nil, errNilRequestBody
+	}
+	if r.Body == http.NoBody {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = defaultMaxBodyBytes
+	}
+
+	raw, err := io.ReadAll(http.MaxBytesReader(nilResponseWriter{}, r.Body, limit))
+	_ = r.Body.Close()
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, &BodyDecodeError{
+				Kind:  BodyDecodeErrorBodyTooLarge,
+				Err:   err,
+				Limit: maxBytesErr.Limit,
+			}
+		}
+		return nil, err
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(raw))
+	return raw, nil
+}
+
 type nilResponseWriter struct{}
 
 func (nilResponseWriter) Header() http.Header {
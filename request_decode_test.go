@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -51,6 +52,52 @@ func TestDecodeRequestBody(t *testing.T) {
 		}
 	})
 
+	t.Run("invalid field type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(`{"id":"forty-two"}`))
+		_, err := DecodeRequestBody[*testRequest](req, defaultMaxBodyBytes)
+		var decodeErr *BodyDecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Fatalf("expected BodyDecodeError, got %v", err)
+		}
+		if decodeErr.Kind != BodyDecodeErrorInvalidType || decodeErr.Field != "id" || decodeErr.Expected != "integer" {
+			t.Fatalf("unexpected invalid type error: %#v", decodeErr)
+		}
+		if decodeErr.Error() != "field id must be a JSON integer" {
+			t.Fatalf("unexpected message %q", decodeErr.Error())
+		}
+	})
+
+	t.Run("truncated json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(`{"id":4`))
+		_, err := DecodeRequestBody[*testRequest](req, defaultMaxBodyBytes)
+		if err == nil || err.Error() != "request body contains incomplete JSON at line 1, column 8" {
+			t.Fatalf("expected incomplete json error, got %v", err)
+		}
+	})
+
+	t.Run("syntax error position", func(t *testing.T) {
+		tests := []struct {
+			body              string
+			wantLine, wantCol int64
+			wantMessageSuffix string
+		}{
+			{body: "{\n  \"id\": x\n}", wantLine: 2, wantCol: 9, wantMessageSuffix: "not valid JSON at line 2, column 9"},
+			{body: "{\n\"name\": \"a\",\n\"id\": 1,}", wantLine: 3, wantCol: 9, wantMessageSuffix: "not valid JSON at line 3, column 9"},
+			{body: "{\"name\":\n\"\xff\"}", wantLine: 2, wantCol: 2, wantMessageSuffix: "contains invalid UTF-8"},
+		}
+		for _, tt := range tests {
+			req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(tt.body))
+			_, err := DecodeRequestBody[*testRequest](req, defaultMaxBodyBytes)
+			var decodeErr *BodyDecodeError
+			if !errors.As(err, &decodeErr) || decodeErr.Line != tt.wantLine || decodeErr.Column != tt.wantCol {
+				t.Fatalf("%q: expected line %d column %d, got %#v", tt.body, tt.wantLine, tt.wantCol, err)
+			}
+			if !strings.HasSuffix(err.Error(), tt.wantMessageSuffix) || strings.Contains(err.Error(), "invalid character") {
+				t.Fatalf("%q: unexpected message %q", tt.body, err.Error())
+			}
+		}
+	})
+
 	t.Run("body too large", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(`{"name":"TooLarge"}`))
 		_, err := DecodeRequestBody[*testRequest](req, 8)
@@ -99,3 +146,17 @@ func BenchmarkParseRequestBody(b *testing.B) {
 		}
 	}
 }
+
+func TestCappedBuffer(t *testing.T) {
+	t.Parallel()
+
+	buffer := &cappedBuffer{limit: 5}
+	for _, chunk := range []string{"abc", "def", "ghi"} {
+		if n, err := buffer.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Fatalf("expected the whole chunk to be accepted, got %d %v", n, err)
+		}
+	}
+	if buffer.String() != "abcde" {
+		t.Fatalf("expected the first 5 bytes, got %q", buffer.String())
+	}
+}
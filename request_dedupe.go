@@ -0,0 +1,142 @@
+package httpsuite
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultDedupeMaxBodyBytes     = 1 << 20
+	defaultDedupeMaxResponseBytes = 1 << 20
+)
+
+// DedupeOptions configures the Dedupe middleware.
+type DedupeOptions struct {
+	// Methods limits deduplication to these methods. Defaults to all.
+	Methods []string
+	// MaxBodyBytes is the largest request body hashed for the key; larger
+	// requests are never coalesced. Defaults to 1 MiB.
+	MaxBodyBytes int64
+	// MaxResponseBytes is the largest response shared with waiting requests;
+	// when the first response is larger they run the handler themselves.
+	// Defaults to 1 MiB.
+	MaxResponseBytes int64
+	// Principal identifies the caller. When nil, the PrincipalKey context
+	// value is used.
+	Principal func(*http.Request) string
+	// CredentialHeaders lists request headers besides Authorization and
+	// Cookie that carry credentials, such as X-API-Key. Their values are
+	// always part of the key, so callers who authenticate by header are
+	// never merged, even without a principal.
+	CredentialHeaders []string
+}
+
+// Dedupe returns middleware that coalesces identical in-flight requests,
+// single-flight style: requests with the same method, URL, body, and
+// principal as one being handled wait for it and receive a copy of its
+// response, marked with X-Deduplicated: true, instead of running the handler
+// again. It protects expensive endpoints from client retry storms; requests
+// arriving after the first one finished are handled normally.
+func Dedupe(opts *DedupeOptions) func(http.Handler) http.Handler {
+	options := DedupeOptions{MaxBodyBytes: defaultDedupeMaxBodyBytes, MaxResponseBytes: defaultDedupeMaxResponseBytes}
+	var methods map[string]bool
+	if opts != nil {
+		options.Principal = opts.Principal
+		options.CredentialHeaders = append([]string(nil), opts.CredentialHeaders...)
+		if opts.MaxBodyBytes > 0 {
+			options.MaxBodyBytes = opts.MaxBodyBytes
+		}
+		if opts.MaxResponseBytes > 0 {
+			options.MaxResponseBytes = opts.MaxResponseBytes
+		}
+		if len(opts.Methods) > 0 {
+			methods = make(map[string]bool, len(opts.Methods))
+			for _, method := range opts.Methods {
+				methods[strings.ToUpper(method)] = true
+			}
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		var flights flightGroup
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if methods != nil && !methods[r.Method] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key, ok := dedupeKey(r, options)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			call, leader := flights.join(key)
+			if !leader {
+				response, ok := call.wait(r.Context())
+				if !ok {
+					return
+				}
+				if response == nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+				replayResponse(w, response, "X-Deduplicated", "true")
+				return
+			}
+
+			var response *CachedResponse
+			defer func() { flights.finish(key, call, response) }()
+			recorder := &cacheRecorder{statusRecorder: newStatusRecorder(w), limit: options.MaxResponseBytes}
+			next.ServeHTTP(recorder, r)
+			if !recorder.overflow {
+				response = &CachedResponse{
+					Status: recorder.Status(),
+					Header: recorder.Header().Clone(),
+					Body:   bytes.Clone(recorder.body.Bytes()),
+				}
+			}
+		})
+	}
+}
+
+// dedupeKey hashes what makes two requests identical. Bodies are read and
+// put back; requests with larger bodies are not deduplicated.
+func dedupeKey(r *http.Request, options DedupeOptions) (string, bool) {
+	hash := sha256.New()
+	parts := []string{r.Method, r.Host, r.URL.RequestURI(), dedupePrincipal(r, options)}
+	for _, header := range append([]string{"Authorization", "Cookie"}, options.CredentialHeaders...) {
+		parts = append(parts, strings.Join(r.Header.Values(header), ","))
+	}
+	for _, part := range parts {
+		_, _ = io.WriteString(hash, part)
+		hash.Write([]byte{0})
+	}
+
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(io.LimitReader(r.Body, options.MaxBodyBytes+1))
+		r.Body = restoredBody{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+		if err != nil || int64(len(body)) > options.MaxBodyBytes {
+			return "", false
+		}
+		hash.Write(body)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
+
+func dedupePrincipal(r *http.Request, options DedupeOptions) string {
+	if options.Principal != nil {
+		return options.Principal(r)
+	}
+	principal, _ := CtxGet(r.Context(), PrincipalKey)
+	return principal
+}
+
+// restoredBody replays the hashed prefix before the unread remainder.
+type restoredBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,220 @@
+package httpsuite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDedupeCoalescesIdenticalRequests(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	handler := Dedupe(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		<-release
+		w.Header().Set("X-Result", "computed")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	}))
+
+	const clients = 5
+	recorders := make([]*httptest.ResponseRecorder, clients)
+	var wg sync.WaitGroup
+	for i := range recorders {
+		recorders[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(rec *httptest.ResponseRecorder) {
+			defer wg.Done()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader(`{"n":1}`)))
+		}(recorders[i])
+	}
+	waitForCalls(t, &calls, 1)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected handler to run once, got %d", calls.Load())
+	}
+	deduplicated := 0
+	for _, rec := range recorders {
+		if rec.Code != http.StatusCreated || rec.Body.String() != `{"n":1}` || rec.Header().Get("X-Result") != "computed" {
+			t.Fatalf("expected shared response, got %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+		}
+		if rec.Header().Get("X-Deduplicated") == "true" {
+			deduplicated++
+		}
+	}
+	if deduplicated != clients-1 {
+		t.Fatalf("expected %d deduplicated responses, got %d", clients-1, deduplicated)
+	}
+}
+
+func TestDedupeKeepsDistinctRequestsApart(t *testing.T) {
+	t.Parallel()
+
+	withHeader := func(key, value string) func() *http.Request {
+		return func() *http.Request {
+			r := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader("a"))
+			r.Header.Set(key, value)
+			return r
+		}
+	}
+
+	tests := []struct {
+		name   string
+		opts   *DedupeOptions
+		first  func() *http.Request
+		second func() *http.Request
+	}{
+		{name: "body", second: func() *http.Request {
+			return httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader("b"))
+		}},
+		{name: "path", second: func() *http.Request {
+			return httptest.NewRequest(http.MethodPost, "/other", strings.NewReader("a"))
+		}},
+		{name: "method", second: func() *http.Request {
+			return httptest.NewRequest(http.MethodPut, "/reports", strings.NewReader("a"))
+		}},
+		{name: "principal", second: func() *http.Request {
+			r := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader("a"))
+			return r.WithContext(CtxSet(r.Context(), PrincipalKey, "bob"))
+		}},
+		{name: "api key header", opts: &DedupeOptions{CredentialHeaders: []string{"X-API-Key"}}, first: withHeader("X-API-Key", "alice-key"), second: withHeader("X-API-Key", "bob-key")},
+		{name: "cookie", first: withHeader("Cookie", "sid=alice"), second: withHeader("Cookie", "sid=bob")},
+		{name: "principal extractor", opts: &DedupeOptions{Principal: func(r *http.Request) string { return r.Header.Get("X-User") }}, first: withHeader("X-User", "alice"), second: withHeader("X-User", "bob")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			release := make(chan struct{})
+			handler := Dedupe(tt.opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				<-release
+			}))
+
+			first := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader("a"))
+			first = first.WithContext(CtxSet(first.Context(), PrincipalKey, "alice"))
+			if tt.first != nil {
+				first = tt.first()
+			}
+			var wg sync.WaitGroup
+			for _, r := range []*http.Request{first, tt.second()} {
+				wg.Add(1)
+				go func(r *http.Request) {
+					defer wg.Done()
+					handler.ServeHTTP(httptest.NewRecorder(), r)
+				}(r)
+			}
+			waitForCalls(t, &calls, 2)
+			close(release)
+			wg.Wait()
+		})
+	}
+}
+
+func TestDedupeRunsHandlerWhenResponseTooLarge(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	handler := Dedupe(&DedupeOptions{MaxResponseBytes: 4})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-release
+		}
+		_, _ = w.Write([]byte("larger than four"))
+	}))
+
+	var wg sync.WaitGroup
+	recorders := []*httptest.ResponseRecorder{httptest.NewRecorder(), httptest.NewRecorder()}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(recorders[0], httptest.NewRequest(http.MethodGet, "/large", nil))
+	}()
+	waitForCalls(t, &calls, 1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(recorders[1], httptest.NewRequest(http.MethodGet, "/large", nil))
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 2 {
+		t.Fatalf("expected handler to run twice, got %d", calls.Load())
+	}
+	for _, rec := range recorders {
+		if rec.Body.String() != "larger than four" || rec.Header().Get("X-Deduplicated") != "" {
+			t.Fatalf("expected own response, got %q %v", rec.Body.String(), rec.Header())
+		}
+	}
+}
+
+func TestDedupeSkipsUnlistedMethodsAndLargeBodies(t *testing.T) {
+	t.Parallel()
+
+	handler := Dedupe(&DedupeOptions{Methods: []string{"post"}, MaxBodyBytes: 3})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("abcdef")))
+	if rec.Body.String() != "abcdef" {
+		t.Fatalf("expected full body to reach handler, got %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader("abc")))
+	if rec.Body.String() != "abc" {
+		t.Fatalf("expected body to reach handler, got %q", rec.Body.String())
+	}
+}
+
+func TestDedupeFollowerStopsWhenCanceled(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	defer close(release)
+	handler := Dedupe(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+	}))
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	waitForCalls(t, &calls, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx))
+	if calls.Load() != 1 || rec.Body.Len() != 0 {
+		t.Fatalf("expected canceled follower to return without a response, got %d calls", calls.Load())
+	}
+}
+
+func waitForCalls(t *testing.T, calls *atomic.Int32, want int32) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d handler calls, got %d", want, calls.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
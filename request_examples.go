@@ -0,0 +1,97 @@
+package httpsuite
+
+import (
+	"reflect"
+	"strings"
+)
+
+// withFieldExamples returns a copy of problem whose error details end with
+// the example of the field they report, as declared by an example tag, so
+// clients see the expected format next to the failure:
+//
+//	Day DateOnly `json:"day" example:"2023-01-02"`
+//	// {"field":"day","message":"field day is invalid: ...; expected format: 2023-01-02"}
+func withFieldExamples(problem *ProblemDetails, t reflect.Type) *ProblemDetails {
+	if problem == nil {
+		return nil
+	}
+	details, ok := problem.Extensions["errors"].([]ValidationErrorDetail)
+	if !ok || len(details) == 0 {
+		return problem
+	}
+
+	var annotated []ValidationErrorDetail
+	for i, detail := range details {
+		field, found := documentedField(t, detail.Field)
+		if !found {
+			continue
+		}
+		example, ok := field.Tag.Lookup("example")
+		if !ok || example == "" {
+			continue
+		}
+		if annotated == nil {
+			annotated = append([]ValidationErrorDetail(nil), details...)
+		}
+		annotated[i].Message = detail.Message + "; expected format: " + example
+	}
+	if annotated == nil {
+		return problem
+	}
+
+	clone := *problem
+	clone.Extensions = make(map[string]interface{}, len(problem.Extensions))
+	for key, value := range problem.Extensions {
+		clone.Extensions[key] = value
+	}
+	clone.Extensions["errors"] = annotated
+	return &clone
+}
+
+// documentedField resolves a reported field path such as "items[1].price",
+// "filter[status]", or a path parameter name to the struct field in t it
+// names. Segments match JSON names, then query, path, and header tags, then
+// Go field names case-insensitively.
+func documentedField(t reflect.Type, path string) (reflect.StructField, bool) {
+	if t == nil || path == "" {
+		return reflect.StructField{}, false
+	}
+	var field reflect.StructField
+	for _, segment := range strings.Split(path, ".") {
+		name, _, _ := strings.Cut(segment, "[")
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return reflect.StructField{}, false
+		}
+		var ok bool
+		if field, ok = namedField(t, name); !ok {
+			return reflect.StructField{}, false
+		}
+		t = field.Type
+	}
+	return field, true
+}
+
+func namedField(t reflect.Type, name string) (reflect.StructField, bool) {
+	fields := reflect.VisibleFields(t)
+	for _, field := range fields {
+		if jsonName, ok := jsonFieldName(field); ok && jsonName == name && field.IsExported() {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		for _, location := range []string{"query", "path", "header"} {
+			if tagName, _, _ := strings.Cut(field.Tag.Get(location), ","); tagName != "" && tagName == name {
+				return field, true
+			}
+		}
+	}
+	for _, field := range fields {
+		if field.IsExported() && !field.Anonymous && strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
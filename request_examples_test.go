@@ -0,0 +1,99 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type exampleLine struct {
+	SKU string `json:"sku" example:"AB-1234"`
+}
+
+type exampleRequest struct {
+	ID    int           `json:"-" path:"id" example:"42"`
+	Day   DateOnly      `json:"day" doc:"Delivery day" example:"2023-01-02"`
+	Lines []exampleLine `json:"lines"`
+	Tags  []int         `json:"-" query:"tag" example:"1,2"`
+	Note  string        `json:"note"`
+}
+
+func (r *exampleRequest) SetParam(fieldName, value string) error {
+	return nil
+}
+
+func TestDocumentedField(t *testing.T) {
+	t.Parallel()
+
+	typ := reflect.TypeFor[*exampleRequest]()
+	tests := map[string]string{
+		"day":          "Day",
+		"lines[1].sku": "SKU",
+		"tag[0]":       "Tags",
+		"id":           "ID",
+		"Note":         "Note",
+	}
+	for path, want := range tests {
+		field, ok := documentedField(typ, path)
+		if !ok || field.Name != want {
+			t.Fatalf("%s: expected field %s, got %v %v", path, want, field.Name, ok)
+		}
+	}
+	for _, path := range []string{"", "missing", "day.year", "lines[0].missing"} {
+		if field, ok := documentedField(typ, path); ok {
+			t.Fatalf("%s: expected no field, got %s", path, field.Name)
+		}
+	}
+}
+
+func TestWithFieldExamples(t *testing.T) {
+	t.Parallel()
+
+	problem := NewProblemDetails(http.StatusBadRequest, BlankURL, "Validation Error", "")
+	problem.Extensions = map[string]interface{}{"errors": []ValidationErrorDetail{
+		{Field: "lines[0].sku", Message: "sku is required"},
+		{Field: "note", Message: "note is too long"},
+	}}
+
+	annotated := withFieldExamples(problem, reflect.TypeFor[*exampleRequest]())
+	details := annotated.Extensions["errors"].([]ValidationErrorDetail)
+	if details[0].Message != "sku is required; expected format: AB-1234" || details[1].Message != "note is too long" {
+		t.Fatalf("expected example on sku only, got %#v", details)
+	}
+	if original := problem.Extensions["errors"].([]ValidationErrorDetail); original[0].Message != "sku is required" {
+		t.Fatalf("expected original problem unchanged, got %#v", original)
+	}
+	if got := withFieldExamples(problem, reflect.TypeFor[string]()); got != problem {
+		t.Fatal("expected problem to be returned as is without documented fields")
+	}
+}
+
+func TestParseRequestAddsFieldExamples(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders?tag=x", bytes.NewBufferString(`{"day":"tomorrow"}`))
+	w := httptest.NewRecorder()
+	if _, err := ParseRequest[*exampleRequest](w, req, nil, nil); err == nil {
+		t.Fatal("expected an error for invalid values")
+	}
+	var problem struct {
+		Errors []ValidationErrorDetail `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if len(problem.Errors) != 2 {
+		t.Fatalf("expected body and query errors, got %#v", problem.Errors)
+	}
+	want := map[string]string{"day": "; expected format: 2023-01-02", "tag[0]": "; expected format: 1,2"}
+	for _, detail := range problem.Errors {
+		if suffix := want[detail.Field]; suffix == "" || !strings.HasSuffix(detail.Message, suffix) {
+			t.Fatalf("expected %s message to end with %q, got %q", detail.Field, suffix, detail.Message)
+		}
+	}
+}
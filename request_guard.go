@@ -0,0 +1,190 @@
+package httpsuite
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	defaultMaxJSONDepth        = 128
+	defaultMaxJSONNumberLength = 256
+)
+
+// jsonLimits bounds the structure of request bodies beyond their size.
+type jsonLimits struct {
+	maxDepth        int
+	maxNumberLength int
+}
+
+var defaultJSONLimits = jsonLimits{maxDepth: defaultMaxJSONDepth, maxNumberLength: defaultMaxJSONNumberLength}
+
+// jsonGuard scans a JSON stream while it is decoded and fails the read on
+// invalid UTF-8, nesting deeper than maxDepth, or number literals longer
+// than maxNumberLength. encoding/json would otherwise replace invalid UTF-8
+// silently, nest up to 10000 levels, and hand huge numbers to unmarshalers.
+type jsonGuard struct {
+	r      io.Reader
+	limits jsonLimits
+	offset int64
+	depth  int
+	number int
+
+	// lines counts the newlines before offset, and lineStart is where the
+	// line at offset begins.
+	lines     int64
+	lineStart int64
+	// chunk is the data of the latest read, beginning at chunkStart on a
+	// line that starts at chunkLineStart after chunkLines newlines. It is
+	// the decoder's buffer, so it still holds the bytes a decode error
+	// points at.
+	chunk          []byte
+	chunkStart     int64
+	chunkLines     int64
+	chunkLineStart int64
+
+	inString bool
+	escaped  bool
+	// pending holds a UTF-8 sequence split across reads.
+	pending    [utf8.UTFMax]byte
+	pendingLen int
+}
+
+func newJSONGuard(r io.Reader, limits jsonLimits) *jsonGuard {
+	return &jsonGuard{r: r, limits: limits}
+}
+
+func (g *jsonGuard) Read(p []byte) (int, error) {
+	n, err := g.r.Read(p)
+	if n > 0 {
+		// Withhold the bytes: json.Decoder ignores a read error when the
+		// data returned with it completes a value.
+		if guardErr := g.scan(p[:n]); guardErr != nil {
+			return 0, guardErr
+		}
+	}
+	return n, err
+}
+
+func (g *jsonGuard) scan(data []byte) error {
+	start := g.offset
+	g.chunk, g.chunkStart, g.chunkLines, g.chunkLineStart = data, start, g.lines, g.lineStart
+	// Report whichever violation comes first so the verdict does not depend
+	// on how the stream is chunked.
+	invalid := g.invalidUTF8(data)
+	structural := data
+	if invalid >= 0 {
+		structural = data[:invalid]
+	}
+
+	for i, b := range structural {
+		if g.inString {
+			switch {
+			case g.escaped:
+				g.escaped = false
+			case b == '\\':
+				g.escaped = true
+			case b == '"':
+				g.inString = false
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			g.inString = true
+		case '{', '[':
+			g.depth++
+			if g.limits.maxDepth > 0 && g.depth > g.limits.maxDepth {
+				return &BodyDecodeError{Kind: BodyDecodeErrorTooDeep, Limit: int64(g.limits.maxDepth), Offset: start + int64(i)}
+			}
+		case '}', ']':
+			g.depth--
+		}
+
+		if (b >= '0' && b <= '9') || b == '-' || b == '+' || b == '.' || b == 'e' || b == 'E' {
+			g.number++
+			if g.limits.maxNumberLength > 0 && g.number > g.limits.maxNumberLength {
+				return &BodyDecodeError{Kind: BodyDecodeErrorNumberTooLong, Limit: int64(g.limits.maxNumberLength), Offset: start + int64(i)}
+			}
+		} else {
+			g.number = 0
+		}
+	}
+	if invalid >= 0 {
+		return &BodyDecodeError{Kind: BodyDecodeErrorInvalidUTF8, Offset: start + int64(invalid)}
+	}
+	for i, b := range data {
+		if b == '\n' {
+			g.lines++
+			g.lineStart = start + int64(i) + 1
+		}
+	}
+	g.offset += int64(len(data))
+	return nil
+}
+
+// position returns the 1-based line and byte column of the byte at index,
+// which must be the end of the stream or inside the latest read.
+func (g *jsonGuard) position(index int64) (line, column int64, ok bool) {
+	switch {
+	case index == g.offset:
+		return g.lines + 1, index - g.lineStart + 1, true
+	case index >= g.chunkStart && index < g.chunkStart+int64(len(g.chunk)):
+		line, lineStart := g.chunkLines, g.chunkLineStart
+		for i, b := range g.chunk[:index-g.chunkStart] {
+			if b == '\n' {
+				line++
+				lineStart = g.chunkStart + int64(i) + 1
+			}
+		}
+		return line + 1, index - lineStart + 1, true
+	}
+	return 0, 0, false
+}
+
+// invalidUTF8 returns the index of the first invalid UTF-8 byte in data, or
+// -1, carrying an incomplete trailing sequence over to the next read.
+func (g *jsonGuard) invalidUTF8(data []byte) int {
+	skipped := 0
+	if g.pendingLen > 0 {
+		var combined [utf8.UTFMax]byte
+		copy(combined[:], g.pending[:g.pendingLen])
+		taken := copy(combined[g.pendingLen:], data)
+		sequence := combined[:g.pendingLen+taken]
+		if !utf8.FullRune(sequence) {
+			if taken < len(data) {
+				return 0
+			}
+			g.pending, g.pendingLen = combined, len(sequence)
+			return -1
+		}
+		r, size := utf8.DecodeRune(sequence)
+		if r == utf8.RuneError && size == 1 {
+			return 0
+		}
+		skipped = size - g.pendingLen
+		data = data[skipped:]
+		g.pendingLen = 0
+	}
+
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-(utf8.UTFMax-1); i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	if !utf8.Valid(data[:cut]) {
+		for i := 0; i < cut; {
+			r, size := utf8.DecodeRune(data[i:cut])
+			if r == utf8.RuneError && size == 1 {
+				return skipped + i
+			}
+			i += size
+		}
+	}
+	g.pendingLen = copy(g.pending[:], data[cut:])
+	return -1
+}
@@ -0,0 +1,143 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+type nestedRequest struct {
+	Name  string          `json:"name"`
+	Extra json.RawMessage `json:"extra"`
+	Any   any             `json:"any"`
+}
+
+func TestParseRequestHostileBodies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		opts       *ParseOptions
+		wantStatus int
+		wantDetail string
+	}{
+		{name: "null body", body: "null", wantStatus: http.StatusOK},
+		{name: "valid multibyte", body: `{"name":"héllo 世界 🎉"}`, wantStatus: http.StatusOK},
+		{name: "invalid utf-8", body: "{\"name\":\"\xff\xfe\"}", wantStatus: http.StatusBadRequest, wantDetail: "request body contains invalid UTF-8"},
+		{name: "truncated utf-8", body: "{\"name\":\"\xe4\xb8\"}", wantStatus: http.StatusBadRequest, wantDetail: "request body contains invalid UTF-8"},
+		{name: "deep arrays", body: `{"any":` + strings.Repeat("[", 200) + strings.Repeat("]", 200) + `}`, wantStatus: http.StatusBadRequest, wantDetail: "request body exceeds the maximum nesting depth of 128"},
+		{name: "deep raw message", body: `{"extra":` + strings.Repeat(`{"a":`, 20) + "1" + strings.Repeat("}", 20) + `}`, opts: &ParseOptions{MaxJSONDepth: 10}, wantStatus: http.StatusBadRequest, wantDetail: "request body exceeds the maximum nesting depth of 10"},
+		{name: "depth within limit", body: `{"any":` + strings.Repeat("[", 100) + strings.Repeat("]", 100) + `}`, wantStatus: http.StatusOK},
+		{name: "brackets in strings", body: `{"name":"` + strings.Repeat("[{", 500) + `\"]"}`, wantStatus: http.StatusOK},
+		{name: "huge number", body: `{"any":1` + strings.Repeat("0", 1000) + `}`, wantStatus: http.StatusBadRequest, wantDetail: "request body contains a number longer than 256 characters"},
+		{name: "number within configured limit", body: `{"any":12345}`, opts: &ParseOptions{MaxJSONNumberLength: 5}, wantStatus: http.StatusOK},
+		{name: "number over configured limit", body: `{"any":123456}`, opts: &ParseOptions{MaxJSONNumberLength: 5}, wantStatus: http.StatusBadRequest, wantDetail: "request body contains a number longer than 5 characters"},
+		{name: "long digit strings", body: `{"name":"` + strings.Repeat("9", 1000) + `"}`, wantStatus: http.StatusOK},
+		{name: "out of range number", body: `{"any":1e400}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/nested", strings.NewReader(tt.body))
+			_, err := ParseRequest[*nestedRequest](w, r, nil, tt.opts)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (%v): %s", tt.wantStatus, w.Code, err, w.Body.String())
+			}
+			if tt.wantDetail != "" && !strings.Contains(w.Body.String(), `"detail":"`+tt.wantDetail+`"`) {
+				t.Fatalf("expected detail %q, got %s", tt.wantDetail, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJSONGuardSplitReads(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		body     string
+		wantKind BodyDecodeErrorKind
+	}{
+		{name: "split runes", body: `{"name":"é世🎉"}`},
+		{name: "escaped quote", body: `{"name":"\"[[[["}`},
+		{name: "invalid continuation", body: "{\"name\":\"\xe4\x41\"}", wantKind: BodyDecodeErrorInvalidUTF8},
+		{name: "overlong encoding", body: "{\"name\":\"\xc0\xaf\"}", wantKind: BodyDecodeErrorInvalidUTF8},
+		{name: "too deep", body: "[[[[", wantKind: BodyDecodeErrorTooDeep},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := newJSONGuard(iotest.OneByteReader(strings.NewReader(tt.body)), jsonLimits{maxDepth: 3, maxNumberLength: 10})
+			_, err := io.ReadAll(guard)
+			var decodeErr *BodyDecodeError
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &decodeErr) || decodeErr.Kind != tt.wantKind {
+				t.Fatalf("expected %s, got %v", tt.wantKind, err)
+			}
+		})
+	}
+}
+
+func FuzzParseRequest(f *testing.F) {
+	for _, seed := range []string{
+		`{"id":1,"name":"ada"}`,
+		"null",
+		"",
+		`{"name":"\u0000"}`,
+		"{\"name\":\"\xff\"}",
+		strings.Repeat("[", 300),
+		`{"id":1e999999}`,
+		`{"id":1}{"id":2}`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/test/1", bytes.NewReader(body))
+		request, err := ParseRequest[*testRequest](w, r, testParamExtractor, &ParseOptions{MaxBodyBytes: 4096}, "id")
+		switch {
+		case err == nil:
+			if request == nil || request.ID != 1 {
+				t.Fatalf("expected a bound request, got %#v", request)
+			}
+		case w.Code != http.StatusBadRequest && w.Code != http.StatusRequestEntityTooLarge:
+			t.Fatalf("expected a 400 or 413 problem, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+// FuzzJSONGuard checks that chunking never changes the guard's verdict.
+func FuzzJSONGuard(f *testing.F) {
+	for _, seed := range []string{`{"a":[1,2,{"b":"é"}]}`, "\"\xe4\xb8\xad\"", "[[[[[[", "\xf0\x9f", "12345678901234", "000000000\xee0"} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		limits := jsonLimits{maxDepth: 4, maxNumberLength: 8}
+		_, whole := io.ReadAll(newJSONGuard(bytes.NewReader(data), limits))
+		_, split := io.ReadAll(newJSONGuard(iotest.OneByteReader(bytes.NewReader(data)), limits))
+
+		var wholeErr, splitErr *BodyDecodeError
+		errors.As(whole, &wholeErr)
+		errors.As(split, &splitErr)
+		if (wholeErr == nil) != (splitErr == nil) || (wholeErr != nil && wholeErr.Kind != splitErr.Kind) {
+			t.Fatalf("verdict depends on chunking: whole %v, split %v", whole, split)
+		}
+	})
+}
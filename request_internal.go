@@ -1,7 +1,9 @@
 package httpsuite
 
 import (
+	"context"
 	"errors"
+	"log"
 	"net/http"
 	"reflect"
 )
@@ -14,6 +16,9 @@ var (
 	errValidationFailed   = errors.New("validation error")
 )
 
+// normalizeParseOptions applies defaults. Problems stays nil unless set, so
+// ParseRequest can resolve it from the request context without cloning the
+// package default twice.
 func normalizeParseOptions(opts *ParseOptions) ParseOptions {
 	normalized := ParseOptions{
 		MaxBodyBytes: defaultMaxBodyBytes,
@@ -32,10 +37,23 @@ func normalizeParseOptions(opts *ParseOptions) ParseOptions {
 			normalized.Validator = opts.Validator
 		}
 		normalized.SkipValidation = opts.SkipValidation
+		normalized.MaxRawBodyBytes = opts.MaxRawBodyBytes
+		normalized.MaxConcurrentChecks = opts.MaxConcurrentChecks
+		normalized.Schema = opts.Schema
+		normalized.MaxJSONDepth = opts.MaxJSONDepth
+		normalized.MaxJSONNumberLength = opts.MaxJSONNumberLength
+		if opts.ValidationStatus >= 400 && opts.ValidationStatus <= 499 {
+			normalized.ValidationStatus = opts.ValidationStatus
+		}
+	}
+	if normalized.MaxJSONDepth <= 0 {
+		normalized.MaxJSONDepth = defaultMaxJSONDepth
+	}
+	if normalized.MaxJSONNumberLength <= 0 {
+		normalized.MaxJSONNumberLength = defaultMaxJSONNumberLength
 	}
-	if normalized.Problems == nil {
-		problems := DefaultProblemConfig()
-		normalized.Problems = &problems
+	if normalized.MaxRawBodyBytes <= 0 {
+		normalized.MaxRawBodyBytes = normalized.MaxBodyBytes
 	}
 	return normalized
 }
@@ -53,6 +71,20 @@ func problemFromDecodeError(err error, problems *ProblemConfig) (*ProblemDetails
 				"Payload Too Large",
 				decodeErr.Error(),
 			), status
+		case BodyDecodeErrorInvalidType, BodyDecodeErrorInvalidValue:
+			problem := NewProblemDetails(
+				status,
+				problems.TypeURL("bad_request_error"),
+				"Invalid Request",
+				"Request body contains an invalid value",
+			)
+			problem.Extensions = map[string]interface{}{
+				"errors": []ValidationErrorDetail{{Field: decodeErr.Field, Message: decodeErrorMessage(decodeErr)}},
+			}
+			if decodeErr.Kind == BodyDecodeErrorInvalidType {
+				problem.Extensions["offset"] = decodeErr.Offset
+			}
+			return problem, status
 		case BodyDecodeErrorMultipleDocuments:
 			return NewProblemDetails(
 				status,
@@ -61,12 +93,23 @@ func problemFromDecodeError(err error, problems *ProblemConfig) (*ProblemDetails
 				"Request body must contain a single JSON document",
 			), status
 		default:
-			return NewProblemDetails(
+			problem := NewProblemDetails(
 				status,
 				problems.TypeURL("bad_request_error"),
 				"Invalid Request",
-				decodeErr.Error(),
-			), status
+				sanitizedDetail(decodeErr, "Request body is not valid JSON"),
+			)
+			if decodeErr.Offset > 0 {
+				problem.Extensions = map[string]interface{}{"offset": decodeErr.Offset}
+			}
+			if decodeErr.Line > 0 {
+				if problem.Extensions == nil {
+					problem.Extensions = map[string]interface{}{}
+				}
+				problem.Extensions["line"] = decodeErr.Line
+				problem.Extensions["column"] = decodeErr.Column
+			}
+			return problem, status
 		}
 	}
 
@@ -74,20 +117,41 @@ func problemFromDecodeError(err error, problems *ProblemConfig) (*ProblemDetails
 		status,
 		problems.TypeURL("bad_request_error"),
 		"Invalid Request",
-		err.Error(),
+		sanitizedDetail(err, "Request body could not be read"),
 	), status
 }
 
+// decodeErrorMessage describes a field-level decode error. Messages from
+// custom unmarshalers are hidden in production mode.
+func decodeErrorMessage(decodeErr *BodyDecodeError) string {
+	if decodeErr.Kind != BodyDecodeErrorInvalidValue {
+		return decodeErr.Error()
+	}
+	if decodeErr.Field == "" {
+		return sanitizedDetail(decodeErr, "request body is invalid")
+	}
+	return sanitizedDetail(decodeErr, "field "+decodeErr.Field+" is invalid")
+}
+
 func problemFromPathParamError(err error, problems *ProblemConfig) (*ProblemDetails, int) {
 	status := http.StatusBadRequest
-	var pathErr *PathParamError
-	if errors.As(err, &pathErr) {
+	pathErrs := pathParamErrors(err)
+	switch len(pathErrs) {
+	case 0:
+		return NewProblemDetails(
+			status,
+			problems.TypeURL("bad_request_error"),
+			"Invalid Parameter",
+			sanitizedDetail(err, "Invalid parameter"),
+		), status
+	case 1:
+		pathErr := pathErrs[0]
 		if pathErr.Missing {
 			return NewProblemDetails(
 				status,
 				problems.TypeURL("bad_request_error"),
 				"Missing Parameter",
-				"Parameter "+pathErr.Param+" not found in request",
+				pathParamMessage(pathErr),
 			), status
 		}
 
@@ -95,20 +159,70 @@ func problemFromPathParamError(err error, problems *ProblemConfig) (*ProblemDeta
 			status,
 			problems.TypeURL("bad_request_error"),
 			"Invalid Parameter",
-			"Failed to bind parameter "+pathErr.Param,
+			pathParamMessage(pathErr),
 		)
 		if pathErr.Err != nil {
-			problem.Extensions = map[string]interface{}{"error": pathErr.Err.Error()}
+			if ProductionMode() {
+				log.Printf("Request error: %v", pathErr)
+			} else {
+				problem.Extensions = map[string]interface{}{"error": pathErr.Err.Error()}
+			}
 		}
 		return problem, status
+	default:
+		problem := NewProblemDetails(
+			status,
+			problems.TypeURL("bad_request_error"),
+			"Invalid Parameters",
+			"One or more parameters are missing or invalid",
+		)
+		problem.Extensions = map[string]interface{}{"errors": pathParamDetails(pathErrs)}
+		return problem, status
 	}
+}
 
-	return NewProblemDetails(
+// problemFromBindingErrors combines body field errors and path and query
+// parameter errors into a single problem so clients see every failure at once.
+func problemFromBindingErrors(bodyErr, pathErr, queryErr error, problems *ProblemConfig) (*ProblemDetails, int) {
+	if pathErr == nil && queryErr == nil {
+		return problemFromDecodeError(bodyErr, problems)
+	}
+	if bodyErr == nil && queryErr == nil {
+		return problemFromPathParamError(pathErr, problems)
+	}
+
+	status := http.StatusBadRequest
+	var details []ValidationErrorDetail
+	var decodeErr *BodyDecodeError
+	if errors.As(bodyErr, &decodeErr) {
+		details = append(details, ValidationErrorDetail{Field: decodeErr.Field, Message: decodeErrorMessage(decodeErr)})
+	}
+	details = append(details, pathParamDetails(pathParamErrors(pathErr))...)
+	details = append(details, queryParamDetails(queryParamErrors(queryErr))...)
+
+	problem := NewProblemDetails(
 		status,
 		problems.TypeURL("bad_request_error"),
-		"Invalid Parameter",
-		err.Error(),
-	), status
+		"Invalid Request",
+		"Request contains invalid values",
+	)
+	problem.Extensions = map[string]interface{}{"errors": details}
+	return problem, status
+}
+
+func pathParamDetails(pathErrs []*PathParamError) []ValidationErrorDetail {
+	details := make([]ValidationErrorDetail, len(pathErrs))
+	for i, pathErr := range pathErrs {
+		details[i] = ValidationErrorDetail{Field: pathErr.Param, Message: pathParamMessage(pathErr)}
+	}
+	return details
+}
+
+func pathParamMessage(pathErr *PathParamError) string {
+	if pathErr.Missing {
+		return "Parameter " + pathErr.Param + " not found in request"
+	}
+	return "Failed to bind parameter " + pathErr.Param
 }
 
 func isRequestNil(i interface{}) bool {
@@ -141,6 +255,47 @@ func ensureRequestInitialized[T any](request T) (T, error) {
 	return request, nil
 }
 
+// withValidationStatus returns a copy of problem using status, when configured.
+// Only 4xx validation problems are changed; server errors, such as a failing
+// validator backend, keep their status.
+func withValidationStatus(problem *ProblemDetails, status int) *ProblemDetails {
+	if problem == nil || status == 0 || problem.Status == status || problem.Status < 400 || problem.Status > 499 {
+		return problem
+	}
+	clone := *problem
+	clone.Status = status
+	return &clone
+}
+
+// StatusClientClosedRequest is the non-standard status, popularized by nginx,
+// recorded for requests the client canceled before the response was ready.
+const StatusClientClosedRequest = 499
+
+// contextProblem returns the problem for a failure once ctx has ended: 499
+// when the client went away and 408 when the request deadline passed. Reads
+// and checks fail for that reason, so neither is a server fault. It returns
+// nil while ctx is live.
+func contextProblem(ctx context.Context, problems *ProblemConfig) *ProblemDetails {
+	cause := ctx.Err()
+	if cause == nil {
+		return nil
+	}
+	if errors.Is(cause, context.DeadlineExceeded) {
+		return NewProblemDetails(
+			http.StatusRequestTimeout,
+			problems.TypeURL("request_timeout_error"),
+			"Request Timeout",
+			"the request deadline passed before it was processed",
+		)
+	}
+	return NewProblemDetails(
+		StatusClientClosedRequest,
+		problems.TypeURL("client_closed_request_error"),
+		"Client Closed Request",
+		"the client canceled the request",
+	)
+}
+
 func validationProblemStatus(problem *ProblemDetails) int {
 	if problem == nil {
 		return http.StatusBadRequest
@@ -0,0 +1,127 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LocaleOptions configures UseLocale.
+type LocaleOptions struct {
+	// Supported lists the locales the service can serve, e.g. "en", "pt-BR".
+	Supported []string
+	// Default is used when nothing matches. Defaults to the first supported locale.
+	Default string
+	// QueryParam optionally names a query parameter that overrides Accept-Language.
+	QueryParam string
+}
+
+// WithLocale returns a context carrying locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return CtxSet(ctx, LocaleKey, locale)
+}
+
+// Locale returns the locale chosen for the request, or "" when none was set.
+func Locale(ctx context.Context) string {
+	locale, _ := CtxGet(ctx, LocaleKey)
+	return locale
+}
+
+// UseLocale returns middleware that picks the best supported locale for each
+// request and stores it in the context. ParseRequest localizes validation
+// messages with it, and responses carry Content-Language and
+// Vary: Accept-Language.
+func UseLocale(opts *LocaleOptions) func(http.Handler) http.Handler {
+	var options LocaleOptions
+	if opts != nil {
+		options = *opts
+	}
+	if options.Default == "" && len(options.Supported) > 0 {
+		options.Default = options.Supported[0]
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			preference := r.Header.Get("Accept-Language")
+			if options.QueryParam != "" {
+				if value := r.URL.Query().Get(options.QueryParam); value != "" {
+					preference = value
+				}
+			}
+			locale := MatchLocale(preference, options.Supported, options.Default)
+			w.Header().Add("Vary", "Accept-Language")
+			if locale != "" {
+				w.Header().Set("Content-Language", locale)
+			}
+			next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), locale)))
+		})
+	}
+}
+
+// AcceptedLanguages returns the language tags of an Accept-Language header in
+// preference order, dropping wildcards and tags with q=0.
+func AcceptedLanguages(header string) []string {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{tag: tag, quality: quality})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	tags := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		tags[i] = candidate.tag
+	}
+	return tags
+}
+
+// MatchLocale returns the supported locale that best matches an
+// Accept-Language header, or fallback. Each preferred tag is matched exactly
+// first, then by base language, so "pt-PT" can select "pt" or "pt-BR".
+// Comparison ignores case and treats "_" like "-".
+func MatchLocale(header string, supported []string, fallback string) string {
+	for _, tag := range AcceptedLanguages(header) {
+		tag = normalizeLocale(tag)
+		for _, locale := range supported {
+			if normalizeLocale(locale) == tag {
+				return locale
+			}
+		}
+		base, _, _ := strings.Cut(tag, "-")
+		for _, locale := range supported {
+			supportedBase, _, _ := strings.Cut(normalizeLocale(locale), "-")
+			if supportedBase == base {
+				return locale
+			}
+		}
+	}
+	return fallback
+}
+
+func normalizeLocale(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+}
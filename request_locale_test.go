@@ -0,0 +1,111 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAcceptedLanguages(t *testing.T) {
+	t.Parallel()
+
+	got := AcceptedLanguages("fr;q=0.5, en-GB, de;q=0, *;q=0.1, pt-BR;q=0.8, bad;q=x")
+	want := []string{"en-GB", "pt-BR", "fr"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	t.Parallel()
+
+	supported := []string{"en", "pt-BR", "fr"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "pt-br", want: "pt-BR"},
+		{header: "pt_BR", want: "pt-BR"},
+		{header: "pt-PT", want: "pt-BR"},
+		{header: "de, fr;q=0.9", want: "fr"},
+		{header: "en-US;q=0.5, fr;q=0.8", want: "fr"},
+		{header: "de", want: "en"},
+		{header: "", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := MatchLocale(tt.header, supported, "en"); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUseLocale(t *testing.T) {
+	t.Parallel()
+
+	var got string
+	handler := UseLocale(&LocaleOptions{Supported: []string{"en", "es"}, QueryParam: "lang"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = Locale(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		target string
+		header string
+		want   string
+	}{
+		{name: "header", target: "/", header: "es-MX,en;q=0.5", want: "es"},
+		{name: "query override", target: "/?lang=en", header: "es", want: "en"},
+		{name: "default", target: "/", header: "ja", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("Accept-Language", tt.header)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if got != tt.want {
+				t.Fatalf("expected locale %q, got %q", tt.want, got)
+			}
+			if w.Header().Get("Content-Language") != tt.want || w.Header().Get("Vary") != "Accept-Language" {
+				t.Fatalf("unexpected headers %#v", w.Header())
+			}
+		})
+	}
+}
+
+type localeRecordingValidator struct {
+	acceptLanguage string
+}
+
+func (v *localeRecordingValidator) Validate(any) *ProblemDetails {
+	return nil
+}
+
+func (v *localeRecordingValidator) ValidateLocalized(_ context.Context, _ any, acceptLanguage string) *ProblemDetails {
+	v.acceptLanguage = acceptLanguage
+	return nil
+}
+
+func TestParseRequestUsesResolvedLocale(t *testing.T) {
+	t.Parallel()
+
+	validator := &localeRecordingValidator{}
+	handler := UseLocale(&LocaleOptions{Supported: []string{"en", "pt-BR"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ParseRequest[*testRequest](w, r, testParamExtractor, &ParseOptions{Validator: validator}, "id")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set("Accept-Language", "pt-PT, en;q=0.1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if validator.acceptLanguage != "pt-BR" {
+		t.Fatalf("expected validator to receive pt-BR, got %q", validator.acceptLanguage)
+	}
+}
@@ -0,0 +1,93 @@
+package httpsuite
+
+import (
+	"reflect"
+	"sync"
+)
+
+// typeMetadataCache maps reflect.Type to *typeMetadata.
+var typeMetadataCache sync.Map
+
+// typeMetadata is what request parsing, value validation, and redaction need
+// to know about a type. It is computed once per type, so walking a request
+// on the hot path reads struct tags and method sets only the first time.
+type typeMetadata struct {
+	// fields lists the exported fields of a struct type in declaration order.
+	fields []fieldMetadata
+	// selfValidator, valueUnmarshaler, and redaction report whether the type
+	// or any type reachable from it implements SelfValidator, implements
+	// json.Unmarshaler or encoding.TextUnmarshaler, or declares redaction
+	// tags. Walks skip types where the flag they need is false.
+	selfValidator    bool
+	valueUnmarshaler bool
+	redaction        bool
+	// scopeTags reports whether the type, or a type reachable from it
+	// without passing through an interface, declares `scope` tags, and
+	// scopeDynamic whether an interface is reachable, whose values have to
+	// be inspected to know.
+	scopeTags    bool
+	scopeDynamic bool
+	// query reports whether the struct, or a struct embedded inline, has
+	// fields tagged query, so ParseRequest skips query binding otherwise.
+	query bool
+}
+
+// fieldMetadata describes an exported struct field.
+type fieldMetadata struct {
+	index int
+	typ   reflect.Type
+	tag   reflect.StructTag
+	// name is the JSON member name; json is false for `json:"-"` fields.
+	name string
+	json bool
+	// inline is set for embedded structs without a json tag, whose fields
+	// encoding/json promotes into the parent object.
+	inline    bool
+	omitEmpty bool
+	redacted  bool
+	// mask is the `mask` tag, naming how Mask and Redact hide the value.
+	mask string
+	// scopes lists the scopes of the `scope` tag, one of which a caller
+	// needs to see the field in scoped responses. Nil when untagged.
+	scopes []string
+}
+
+// metadataFor returns the cached metadata for t, computing it on first use.
+func metadataFor(t reflect.Type) *typeMetadata {
+	if cached, ok := typeMetadataCache.Load(t); ok {
+		return cached.(*typeMetadata)
+	}
+	metadata := &typeMetadata{
+		selfValidator:    scanValueType(t, selfValidatorType, make(map[reflect.Type]bool)),
+		valueUnmarshaler: scanValueType(t, jsonUnmarshalerType, make(map[reflect.Type]bool)) || scanValueType(t, textUnmarshalerType, make(map[reflect.Type]bool)),
+		redaction:        scanRedactionTags(t, make(map[reflect.Type]bool)),
+	}
+	metadata.scopeTags, metadata.scopeDynamic = scanScopeTags(t, make(map[reflect.Type]bool))
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, json := jsonFieldName(field)
+			inline := field.Anonymous && field.Tag.Get("json") == ""
+			metadata.fields = append(metadata.fields, fieldMetadata{
+				index:     i,
+				typ:       field.Type,
+				tag:       field.Tag,
+				name:      name,
+				json:      json,
+				inline:    inline,
+				omitEmpty: jsonOmitEmpty(field),
+				redacted:  isRedactedField(field),
+				mask:      field.Tag.Get("mask"),
+				scopes:    fieldScopes(field),
+			})
+			if field.Tag.Get("query") != "" || inline && field.Type.Kind() == reflect.Struct && metadataFor(field.Type).query {
+				metadata.query = true
+			}
+		}
+	}
+	actual, _ := typeMetadataCache.LoadOrStore(t, metadata)
+	return actual.(*typeMetadata)
+}
@@ -0,0 +1,78 @@
+package httpsuite
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type MetadataEmbedded struct {
+	Note string `json:"note"`
+}
+
+type metadataRequest struct {
+	MetadataEmbedded
+	ID       int       `json:"id"`
+	Password string    `json:"password,omitempty" redact:"true"`
+	Internal string    `json:"-"`
+	Total    testMoney `json:"total"`
+	hidden   string
+}
+
+func TestMetadataForStruct(t *testing.T) {
+	t.Parallel()
+
+	metadata := metadataFor(reflect.TypeOf(metadataRequest{}))
+	if !metadata.selfValidator || metadata.valueUnmarshaler || !metadata.redaction {
+		t.Fatalf("unexpected flags: %+v", metadata)
+	}
+
+	want := []fieldMetadata{
+		{index: 0, name: "MetadataEmbedded", json: true, inline: true},
+		{index: 1, name: "id", json: true},
+		{index: 2, name: "password", json: true, omitEmpty: true, redacted: true},
+		{index: 3, json: false},
+		{index: 4, name: "total", json: true},
+	}
+	if len(metadata.fields) != len(want) {
+		t.Fatalf("expected %d fields, got %d", len(want), len(metadata.fields))
+	}
+	for i, field := range metadata.fields {
+		field.typ, field.tag = nil, ""
+		if !reflect.DeepEqual(field, want[i]) {
+			t.Fatalf("field %d: expected %+v, got %+v", i, want[i], field)
+		}
+	}
+}
+
+func TestMetadataForCachesPerType(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		Phone testPhone `json:"phone"`
+	}
+	requestType := reflect.TypeOf(request{})
+
+	var wg sync.WaitGroup
+	results := make([]*typeMetadata, 8)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = metadataFor(requestType)
+		}()
+	}
+	wg.Wait()
+
+	for _, metadata := range results {
+		if metadata != results[0] {
+			t.Fatal("expected every caller to share one cached entry")
+		}
+	}
+	if !results[0].valueUnmarshaler || results[0].selfValidator {
+		t.Fatalf("unexpected flags: %+v", results[0])
+	}
+	if metadataFor(reflect.PointerTo(requestType)) == results[0] {
+		t.Fatal("expected pointer types to have their own entry")
+	}
+}
@@ -0,0 +1,132 @@
+package httpsuite
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PreconditionErrorKind identifies why a conditional write was rejected.
+type PreconditionErrorKind string
+
+const (
+	// PreconditionErrorFailed means If-Match or If-Unmodified-Since did not match.
+	PreconditionErrorFailed PreconditionErrorKind = "failed"
+	// PreconditionErrorRequired means the request carried no precondition.
+	PreconditionErrorRequired PreconditionErrorKind = "required"
+)
+
+// PreconditionError reports a rejected conditional write.
+type PreconditionError struct {
+	Kind PreconditionErrorKind
+	// Stale lists the items that changed when a bulk write was rejected.
+	Stale []StaleItem
+}
+
+func (e *PreconditionError) Error() string {
+	if e.Kind == PreconditionErrorRequired {
+		return "request must be conditional: send If-Match or If-Unmodified-Since"
+	}
+	switch len(e.Stale) {
+	case 0:
+		return "resource has changed since it was last retrieved"
+	case 1:
+		return "1 item has changed since it was last retrieved"
+	default:
+		return fmt.Sprintf("%d items have changed since they were last retrieved", len(e.Stale))
+	}
+}
+
+// ResourceVersion identifies the current state of a resource. A zero value
+// means the resource does not exist.
+type ResourceVersion struct {
+	ETag         string
+	LastModified time.Time
+}
+
+func (v ResourceVersion) exists() bool {
+	return v.ETag != "" || !v.LastModified.IsZero()
+}
+
+// PreconditionOptions configures CheckPreconditions.
+type PreconditionOptions struct {
+	// Required rejects unconditional requests with 428 Precondition Required.
+	Required bool
+	// Problems overrides the problem config resolved from the request context.
+	Problems *ProblemConfig
+}
+
+// EvaluatePreconditions compares If-Match and If-Unmodified-Since against the
+// current resource version. If-Unmodified-Since is only consulted when
+// If-Match is absent, as RFC 9110 requires.
+func EvaluatePreconditions(r *http.Request, current ResourceVersion, required bool) error {
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if !ifMatchSatisfied(ifMatch, current) {
+			return &PreconditionError{Kind: PreconditionErrorFailed}
+		}
+		return nil
+	}
+	if value := r.Header.Get("If-Unmodified-Since"); value != "" {
+		since, err := http.ParseTime(value)
+		if err != nil || current.LastModified.IsZero() {
+			return nil
+		}
+		if current.LastModified.Truncate(time.Second).After(since) {
+			return &PreconditionError{Kind: PreconditionErrorFailed}
+		}
+		return nil
+	}
+	if required {
+		return &PreconditionError{Kind: PreconditionErrorRequired}
+	}
+	return nil
+}
+
+// CheckPreconditions enforces optimistic concurrency for PUT, PATCH, and
+// DELETE handlers. When a precondition does not hold it writes a 412 or 428
+// problem and returns the error; the handler should stop on a non-nil result.
+func CheckPreconditions(w http.ResponseWriter, r *http.Request, current ResourceVersion, opts *PreconditionOptions) error {
+	var options PreconditionOptions
+	if opts != nil {
+		options = *opts
+	}
+	err := EvaluatePreconditions(r, current, options.Required)
+	if err == nil {
+		return nil
+	}
+
+	writePreconditionProblem(w, r, err.(*PreconditionError), options.Problems)
+	return err
+}
+
+func writePreconditionProblem(w http.ResponseWriter, r *http.Request, err *PreconditionError, config *ProblemConfig) {
+	problems := resolveProblemConfig(r.Context(), config)
+	status, key, title := http.StatusPreconditionFailed, "precondition_failed_error", "Precondition Failed"
+	if err.Kind == PreconditionErrorRequired {
+		status, key, title = http.StatusPreconditionRequired, "precondition_required_error", "Precondition Required"
+	}
+	problem := NewProblemDetails(status, problems.TypeURL(key), title, err.Error())
+	if len(err.Stale) > 0 {
+		problem.Extensions = map[string]interface{}{"stale_items": err.Stale}
+	}
+	sendRequestProblem(w, r, status, problem)
+}
+
+// ifMatchSatisfied applies the strong comparison If-Match requires, so weak
+// validators never match.
+func ifMatchSatisfied(header string, current ResourceVersion) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			if current.exists() {
+				return true
+			}
+			continue
+		}
+		if current.ETag != "" && !strings.HasPrefix(current.ETag, "W/") && candidate == current.ETag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package httpsuite
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ItemVersion identifies the current or expected version of one item in a
+// collection.
+type ItemVersion struct {
+	ID   string
+	ETag string
+}
+
+// StaleItem describes an item whose version changed before a bulk write.
+// Current is empty when the item no longer exists.
+type StaleItem struct {
+	ID       string `json:"id"`
+	Expected string `json:"expected,omitempty"`
+	Current  string `json:"current,omitempty"`
+}
+
+// CollectionETag returns a strong ETag over the versions of items. It does not
+// depend on item order and changes whenever an item is added, removed, or
+// modified.
+func CollectionETag(items []ItemVersion) string {
+	sorted := slices.Clone(items)
+	slices.SortFunc(sorted, func(a, b ItemVersion) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	hash := sha256.New()
+	for _, item := range sorted {
+		hash.Write([]byte(item.ID))
+		hash.Write([]byte{0})
+		hash.Write([]byte(item.ETag))
+		hash.Write([]byte{'\n'})
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// EvaluateBulkPreconditions checks a bulk write against the current versions
+// of the affected items. An If-Match header is compared with the
+// CollectionETag of current; expected carries the per-item versions the
+// client read, typically from the request body, and is used to list the stale
+// items. Without If-Match, any stale expected item fails the write, and
+// required rejects requests that carry neither.
+func EvaluateBulkPreconditions(r *http.Request, current, expected []ItemVersion, required bool) error {
+	stale := staleItems(current, expected)
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		var version ResourceVersion
+		if len(current) > 0 {
+			version.ETag = CollectionETag(current)
+		}
+		if !ifMatchSatisfied(ifMatch, version) {
+			return &PreconditionError{Kind: PreconditionErrorFailed, Stale: stale}
+		}
+		return nil
+	}
+	if len(stale) > 0 {
+		return &PreconditionError{Kind: PreconditionErrorFailed, Stale: stale}
+	}
+	if required && len(expected) == 0 {
+		return &PreconditionError{Kind: PreconditionErrorRequired}
+	}
+	return nil
+}
+
+// CheckBulkPreconditions enforces optimistic concurrency for bulk writes.
+// When a precondition does not hold it writes a 412 problem listing the stale
+// items under "stale_items", or a 428 problem, and returns the error; the
+// handler should stop on a non-nil result.
+func CheckBulkPreconditions(w http.ResponseWriter, r *http.Request, current, expected []ItemVersion, opts *PreconditionOptions) error {
+	var options PreconditionOptions
+	if opts != nil {
+		options = *opts
+	}
+	err := EvaluateBulkPreconditions(r, current, expected, options.Required)
+	if err == nil {
+		return nil
+	}
+	writePreconditionProblem(w, r, err.(*PreconditionError), options.Problems)
+	return err
+}
+
+// staleItems returns the expected items whose version differs from current,
+// in the order they were expected.
+func staleItems(current, expected []ItemVersion) []StaleItem {
+	versions := make(map[string]string, len(current))
+	for _, item := range current {
+		versions[item.ID] = item.ETag
+	}
+	var stale []StaleItem
+	for _, item := range expected {
+		if version, ok := versions[item.ID]; !ok || version != item.ETag {
+			stale = append(stale, StaleItem{ID: item.ID, Expected: item.ETag, Current: version})
+		}
+	}
+	return stale
+}
@@ -0,0 +1,133 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCollectionETag(t *testing.T) {
+	t.Parallel()
+
+	items := []ItemVersion{{ID: "1", ETag: `"a"`}, {ID: "2", ETag: `"b"`}}
+	etag := CollectionETag(items)
+	if len(etag) != 34 || etag[0] != '"' || etag[33] != '"' {
+		t.Fatalf("expected a quoted strong etag, got %s", etag)
+	}
+	if reordered := CollectionETag([]ItemVersion{items[1], items[0]}); reordered != etag {
+		t.Fatalf("expected order-independent etag, got %s and %s", etag, reordered)
+	}
+	if changed := CollectionETag([]ItemVersion{{ID: "1", ETag: `"a"`}, {ID: "2", ETag: `"c"`}}); changed == etag {
+		t.Fatal("expected a modified item to change the etag")
+	}
+	if removed := CollectionETag(items[:1]); removed == etag {
+		t.Fatal("expected a removed item to change the etag")
+	}
+	if items[0].ID != "1" {
+		t.Fatal("expected the input to be left unchanged")
+	}
+}
+
+func TestEvaluateBulkPreconditions(t *testing.T) {
+	t.Parallel()
+
+	current := []ItemVersion{{ID: "1", ETag: `"v2"`}, {ID: "2", ETag: `"v1"`}}
+	collection := CollectionETag(current)
+
+	tests := []struct {
+		name      string
+		ifMatch   string
+		current   []ItemVersion
+		expected  []ItemVersion
+		required  bool
+		wantKind  PreconditionErrorKind
+		wantStale []StaleItem
+	}{
+		{name: "matching collection", ifMatch: collection, current: current},
+		{
+			name:      "stale collection",
+			ifMatch:   `"old"`,
+			current:   current,
+			expected:  []ItemVersion{{ID: "1", ETag: `"v1"`}, {ID: "2", ETag: `"v1"`}, {ID: "3", ETag: `"v1"`}},
+			wantKind:  PreconditionErrorFailed,
+			wantStale: []StaleItem{{ID: "1", Expected: `"v1"`, Current: `"v2"`}, {ID: "3", Expected: `"v1"`}},
+		},
+		{name: "stale collection without items", ifMatch: `"old"`, current: current, wantKind: PreconditionErrorFailed},
+		{name: "wildcard", ifMatch: "*", current: current},
+		{name: "wildcard empty", ifMatch: "*", wantKind: PreconditionErrorFailed},
+		{name: "fresh items", current: current, expected: current, required: true},
+		{
+			name:      "stale items",
+			current:   current,
+			expected:  []ItemVersion{{ID: "2", ETag: `"v0"`}},
+			wantKind:  PreconditionErrorFailed,
+			wantStale: []StaleItem{{ID: "2", Expected: `"v0"`, Current: `"v1"`}},
+		},
+		{name: "unconditional", current: current},
+		{name: "unconditional required", current: current, required: true, wantKind: PreconditionErrorRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/items", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			err := EvaluateBulkPreconditions(req, tt.current, tt.expected, tt.required)
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("expected precondition to pass, got %v", err)
+				}
+				return
+			}
+			var precondition *PreconditionError
+			if !errors.As(err, &precondition) || precondition.Kind != tt.wantKind {
+				t.Fatalf("expected %s error, got %v", tt.wantKind, err)
+			}
+			if len(precondition.Stale) != len(tt.wantStale) {
+				t.Fatalf("expected stale items %+v, got %+v", tt.wantStale, precondition.Stale)
+			}
+			for i, item := range tt.wantStale {
+				if precondition.Stale[i] != item {
+					t.Fatalf("expected stale items %+v, got %+v", tt.wantStale, precondition.Stale)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckBulkPreconditions(t *testing.T) {
+	t.Parallel()
+
+	current := []ItemVersion{{ID: "1", ETag: `"v2"`}, {ID: "2", ETag: `"v3"`}}
+	expected := []ItemVersion{{ID: "1", ETag: `"v1"`}, {ID: "2", ETag: `"v1"`}}
+
+	req := httptest.NewRequest(http.MethodPatch, "/items", nil)
+	req.Header.Set("If-Match", `"old"`)
+	w := httptest.NewRecorder()
+	if err := CheckBulkPreconditions(w, req, current, expected, nil); err == nil {
+		t.Fatal("expected stale collection to be rejected")
+	}
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412, got %d", w.Code)
+	}
+	var problem struct {
+		Detail     string      `json:"detail"`
+		StaleItems []StaleItem `json:"stale_items"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Detail != "2 items have changed since they were last retrieved" || len(problem.StaleItems) != 2 || problem.StaleItems[1].Current != `"v3"` {
+		t.Fatalf("unexpected problem %+v", problem)
+	}
+
+	w = httptest.NewRecorder()
+	err := CheckBulkPreconditions(w, httptest.NewRequest(http.MethodPatch, "/items", nil), current, nil, &PreconditionOptions{Required: true})
+	if err == nil || w.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected 428, got %d (%v)", w.Code, err)
+	}
+}
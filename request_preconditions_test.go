@@ -0,0 +1,117 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvaluatePreconditions(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	current := ResourceVersion{ETag: `"v2"`, LastModified: modified}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		current  ResourceVersion
+		required bool
+		wantKind PreconditionErrorKind
+	}{
+		{name: "matching etag", headers: map[string]string{"If-Match": `"v1", "v2"`}, current: current},
+		{name: "stale etag", headers: map[string]string{"If-Match": `"v1"`}, current: current, wantKind: PreconditionErrorFailed},
+		{name: "weak etag never matches", headers: map[string]string{"If-Match": `W/"v2"`}, current: current, wantKind: PreconditionErrorFailed},
+		{name: "wildcard existing", headers: map[string]string{"If-Match": "*"}, current: current},
+		{name: "wildcard missing", headers: map[string]string{"If-Match": "*"}, wantKind: PreconditionErrorFailed},
+		{
+			name:    "if-match wins over date",
+			headers: map[string]string{"If-Match": `"v2"`, "If-Unmodified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)},
+			current: current,
+		},
+		{name: "unmodified", headers: map[string]string{"If-Unmodified-Since": modified.Format(http.TimeFormat)}, current: current},
+		{
+			name:     "modified since",
+			headers:  map[string]string{"If-Unmodified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)},
+			current:  current,
+			wantKind: PreconditionErrorFailed,
+		},
+		{name: "invalid date ignored", headers: map[string]string{"If-Unmodified-Since": "yesterday"}, current: current},
+		{name: "unconditional", current: current},
+		{name: "unconditional required", current: current, required: true, wantKind: PreconditionErrorRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/items/1", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			err := EvaluatePreconditions(req, tt.current, tt.required)
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("expected precondition to pass, got %v", err)
+				}
+				return
+			}
+			var preconditionErr *PreconditionError
+			if !errors.As(err, &preconditionErr) || preconditionErr.Kind != tt.wantKind {
+				t.Fatalf("expected %s error, got %v", tt.wantKind, err)
+			}
+		})
+	}
+}
+
+func TestCheckPreconditionsWritesProblems(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		ifMatch    string
+		wantStatus int
+		wantType   string
+	}{
+		{name: "failed", ifMatch: `"old"`, wantStatus: http.StatusPreconditionFailed, wantType: "/errors/precondition-failed"},
+		{name: "required", wantStatus: http.StatusPreconditionRequired, wantType: "/errors/precondition-required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/items/1", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			err := CheckPreconditions(w, req, ResourceVersion{ETag: `"new"`}, &PreconditionOptions{Required: true})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			var problem ProblemDetails
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if !strings.HasSuffix(problem.Type, tt.wantType) {
+				t.Fatalf("expected type ending in %q, got %q", tt.wantType, problem.Type)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/items/1", nil)
+	req.Header.Set("If-Match", `"new"`)
+	w := httptest.NewRecorder()
+	if err := CheckPreconditions(w, req, ResourceVersion{ETag: `"new"`}, &PreconditionOptions{Required: true}); err != nil {
+		t.Fatalf("expected write to proceed, got %v", err)
+	}
+	if w.Body.Len() != 0 {
+		t.Fatalf("expected nothing written, got %q", w.Body.String())
+	}
+}
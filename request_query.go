@@ -0,0 +1,355 @@
+package httpsuite
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// QueryParamError represents a query parameter binding error. Param names the
+// offending value, including the element index or map key, e.g. "ids[1]" or
+// "filter[status]".
+type QueryParamError struct {
+	Param string
+	Err   error
+}
+
+func (e *QueryParamError) Error() string {
+	return fmt.Sprintf("query parameter %s is invalid: %v", e.Param, e.Err)
+}
+
+func (e *QueryParamError) Unwrap() error {
+	return e.Err
+}
+
+// BindQueryParams sets the fields of a request struct tagged query:"name" from
+// the URL query without writing HTTP responses. ParseRequest calls it after
+// binding path parameters.
+//
+// Scalar fields take the first value. Slice fields collect repeated
+// parameters (?id=1&id=2), the name[] form (?id[]=1), and comma-separated
+// values (?id=1,2); tag a field query:"name,nosplit" when values may contain
+// commas. Map fields with string keys collect name[key]=value parameters,
+// e.g. ?filter[status]=paid. Elements are converted one by one, so every
+// invalid element is reported. Fields that implement encoding.TextUnmarshaler,
+// strings, booleans, numbers, and time.Duration are supported; fields of
+// other types fail with a plain error, which ParseRequest answers with a 500
+// since the request type needs fixing.
+//
+// Every field is attempted; when several fail, the returned error joins one
+// *QueryParamError per invalid value.
+func BindQueryParams[T any](request T, r *http.Request) (T, error) {
+	if r == nil {
+		var empty T
+		return empty, errNilHTTPRequest
+	}
+	t := reflect.TypeOf(request)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct || !metadataFor(t.Elem()).query {
+		return request, nil
+	}
+
+	request, err := ensureRequestInitialized(request)
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+
+	var bindErrs []error
+	bindQueryFields(reflect.ValueOf(request).Elem(), r.URL.Query(), &bindErrs)
+	// Unsupported field types are programming errors; report them alone so
+	// they are not mistaken for invalid input.
+	if setupErrs := querySetupErrors(bindErrs); len(setupErrs) > 0 {
+		bindErrs = setupErrs
+	}
+	switch len(bindErrs) {
+	case 0:
+		return request, nil
+	case 1:
+		var empty T
+		return empty, bindErrs[0]
+	default:
+		var empty T
+		return empty, errors.Join(bindErrs...)
+	}
+}
+
+func bindQueryFields(value reflect.Value, query url.Values, bindErrs *[]error) {
+	for _, field := range metadataFor(value.Type()).fields {
+		if field.inline && field.typ.Kind() == reflect.Struct {
+			bindQueryFields(value.Field(field.index), query, bindErrs)
+			continue
+		}
+		name, options, _ := strings.Cut(field.tag.Get("query"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		if !isQueryScalar(field.typ) && field.typ.Kind() == reflect.Map && field.typ.Key().Kind() != reflect.String {
+			continue
+		}
+		if !queryFieldSupported(field.typ) {
+			*bindErrs = append(*bindErrs, fmt.Errorf("query parameter %s: unsupported field type %s", name, field.typ))
+			continue
+		}
+
+		target := value.Field(field.index)
+		split := options != "nosplit"
+		switch {
+		case isQueryScalar(field.typ):
+			bindQueryScalar(target, name, query[name], bindErrs)
+		case field.typ.Kind() == reflect.Slice:
+			values := append(append([]string(nil), query[name]...), query[name+"[]"]...)
+			bindQuerySlice(target, name, values, split, bindErrs)
+		default:
+			bindQueryMap(target, name, query, split, bindErrs)
+		}
+	}
+}
+
+// querySetupErrors returns the errors in bindErrs that are not about a value.
+func querySetupErrors(bindErrs []error) []error {
+	var setupErrs []error
+	for _, err := range bindErrs {
+		var queryErr *QueryParamError
+		if !errors.As(err, &queryErr) {
+			setupErrs = append(setupErrs, err)
+		}
+	}
+	return setupErrs
+}
+
+// queryFieldSupported reports whether a field of type t can be bound:
+// scalars, slices of scalars, and maps whose values are scalars or slices of
+// scalars. Maps without string keys are skipped before this check.
+func queryFieldSupported(t reflect.Type) bool {
+	switch {
+	case isQueryScalar(t):
+		return queryValueSupported(t)
+	case t.Kind() == reflect.Slice:
+		return queryValueSupported(t.Elem())
+	case t.Elem().Kind() == reflect.Slice && !isQueryScalar(t.Elem()):
+		return queryValueSupported(t.Elem().Elem())
+	default:
+		return queryValueSupported(t.Elem())
+	}
+}
+
+// queryValueSupported reports whether parseQueryValue can convert into t.
+func queryValueSupported(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		return queryValueSupported(t.Elem())
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) || t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isQueryScalar reports whether t binds from a single value, which includes
+// slice types such as net.IP that unmarshal themselves from text.
+func isQueryScalar(t reflect.Type) bool {
+	if t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	return t.Kind() != reflect.Slice && t.Kind() != reflect.Map
+}
+
+func bindQueryScalar(target reflect.Value, name string, values []string, bindErrs *[]error) {
+	if len(values) == 0 || values[0] == "" {
+		return
+	}
+	parsed, err := parseQueryValue(target.Type(), values[0])
+	if err != nil {
+		*bindErrs = append(*bindErrs, &QueryParamError{Param: name, Err: err})
+		return
+	}
+	target.Set(parsed)
+}
+
+func bindQuerySlice(target reflect.Value, name string, values []string, split bool, bindErrs *[]error) {
+	elements := splitQueryValues(values, split)
+	if len(elements) == 0 {
+		return
+	}
+	slice := reflect.MakeSlice(target.Type(), len(elements), len(elements))
+	failed := false
+	for i, element := range elements {
+		parsed, err := parseQueryValue(target.Type().Elem(), element)
+		if err != nil {
+			*bindErrs = append(*bindErrs, &QueryParamError{Param: fmt.Sprintf("%s[%d]", name, i), Err: err})
+			failed = true
+			continue
+		}
+		slice.Index(i).Set(parsed)
+	}
+	if !failed {
+		target.Set(slice)
+	}
+}
+
+func bindQueryMap(target reflect.Value, name string, query url.Values, split bool, bindErrs *[]error) {
+	prefix := name + "["
+	var keys []string
+	for key := range query {
+		if strings.HasPrefix(key, prefix) && strings.HasSuffix(key, "]") && len(key) > len(prefix)+1 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	sort.Strings(keys)
+
+	mapType := target.Type()
+	result := reflect.MakeMapWithSize(mapType, len(keys))
+	failed := false
+	for _, key := range keys {
+		sub := key[len(prefix) : len(key)-1]
+		elem := reflect.New(mapType.Elem()).Elem()
+		before := len(*bindErrs)
+		if mapType.Elem().Kind() == reflect.Slice && !isQueryScalar(mapType.Elem()) {
+			bindQuerySlice(elem, key, query[key], split, bindErrs)
+		} else {
+			bindQueryScalar(elem, key, query[key], bindErrs)
+		}
+		if len(*bindErrs) > before {
+			failed = true
+			continue
+		}
+		result.SetMapIndex(reflect.ValueOf(sub).Convert(mapType.Key()), elem)
+	}
+	if !failed {
+		target.Set(result)
+	}
+}
+
+// splitQueryValues flattens comma-separated values, dropping empty elements.
+func splitQueryValues(values []string, split bool) []string {
+	var elements []string
+	for _, value := range values {
+		if !split {
+			if value != "" {
+				elements = append(elements, value)
+			}
+			continue
+		}
+		for _, element := range strings.Split(value, ",") {
+			if element != "" {
+				elements = append(elements, element)
+			}
+		}
+	}
+	return elements
+}
+
+// parseQueryValue converts raw into a new value of type t.
+func parseQueryValue(t reflect.Type, raw string) (reflect.Value, error) {
+	if t.Kind() == reflect.Ptr {
+		elem, err := parseQueryValue(t.Elem(), raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	}
+
+	value := reflect.New(t).Elem()
+	if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalText([]byte(raw)); err != nil {
+			return reflect.Value{}, err
+		}
+		return value, nil
+	}
+	if t == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return reflect.Value{}, errors.New("must be a duration")
+		}
+		value.SetInt(int64(duration))
+		return value, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return reflect.Value{}, errors.New("must be a boolean")
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, numberError(err, "must be an integer")
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, numberError(err, "must be a non-negative integer")
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, t.Bits())
+		if err != nil {
+			return reflect.Value{}, numberError(err, "must be a number")
+		}
+		value.SetFloat(parsed)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported query parameter type %s", t)
+	}
+	return value, nil
+}
+
+func numberError(err error, syntax string) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return errors.New("is out of range")
+	}
+	return errors.New(syntax)
+}
+
+// queryParamErrors returns every *QueryParamError contained in err.
+func queryParamErrors(err error) []*QueryParamError {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var result []*QueryParamError
+		for _, inner := range joined.Unwrap() {
+			result = append(result, queryParamErrors(inner)...)
+		}
+		return result
+	}
+
+	var queryErr *QueryParamError
+	if errors.As(err, &queryErr) {
+		return []*QueryParamError{queryErr}
+	}
+	return nil
+}
+
+func queryParamDetails(queryErrs []*QueryParamError) []ValidationErrorDetail {
+	details := make([]ValidationErrorDetail, len(queryErrs))
+	for i, queryErr := range queryErrs {
+		details[i] = ValidationErrorDetail{
+			Field:   queryErr.Param,
+			Message: sanitizedDetail(queryErr, "query parameter "+queryErr.Param+" is invalid"),
+		}
+	}
+	return details
+}
@@ -0,0 +1,173 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type QueryTestPage struct {
+	Limit int `query:"limit"`
+}
+
+type queryRequest struct {
+	QueryTestPage
+	Search  string              `json:"-" query:"q"`
+	Active  *bool               `json:"-" query:"active"`
+	Within  time.Duration       `json:"-" query:"within"`
+	IDs     []int64             `json:"-" query:"id"`
+	Names   []string            `json:"-" query:"name,nosplit"`
+	Owners  []UUID              `json:"-" query:"owner"`
+	Filter  map[string]string   `json:"-" query:"filter"`
+	Ranges  map[string][]int    `json:"-" query:"range"`
+	Ignored map[int]string      `json:"-" query:"ignored"`
+	Body    string              `json:"body"`
+	Extra   map[string]struct{} `json:"-"`
+}
+
+func TestBindQueryParams(t *testing.T) {
+	t.Parallel()
+
+	const owner = "9b2e1c4f-7a52-4d8e-9f0a-3c6d5e7f8a9b"
+	req := httptest.NewRequest(http.MethodGet, "/items?q=lamp&active=true&within=90m&limit=20"+
+		"&id=1,2&id=3&id[]=4&name=a,b&name=c&owner="+owner+
+		"&filter[status]=paid&filter[region]=eu&range[price]=10,20&ignored[1]=x", nil)
+
+	bound, err := BindQueryParams(&queryRequest{Body: "kept"}, req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if bound.Search != "lamp" || bound.Active == nil || !*bound.Active || bound.Within != 90*time.Minute || bound.Limit != 20 || bound.Body != "kept" {
+		t.Fatalf("expected scalars to bind, got %+v", bound)
+	}
+	if !reflect.DeepEqual(bound.IDs, []int64{1, 2, 3, 4}) {
+		t.Fatalf("expected repeated and comma-separated ids, got %v", bound.IDs)
+	}
+	if !reflect.DeepEqual(bound.Names, []string{"a,b", "c"}) {
+		t.Fatalf("expected nosplit names, got %v", bound.Names)
+	}
+	if len(bound.Owners) != 1 || bound.Owners[0].String() != owner {
+		t.Fatalf("expected UUID elements, got %v", bound.Owners)
+	}
+	if !reflect.DeepEqual(bound.Filter, map[string]string{"status": "paid", "region": "eu"}) {
+		t.Fatalf("expected filter map, got %v", bound.Filter)
+	}
+	if !reflect.DeepEqual(bound.Ranges, map[string][]int{"price": {10, 20}}) {
+		t.Fatalf("expected range map, got %v", bound.Ranges)
+	}
+	if bound.Ignored != nil || bound.Extra != nil {
+		t.Fatalf("expected unsupported and untagged maps to be left alone, got %+v", bound)
+	}
+}
+
+func TestBindQueryParamsAggregatesErrors(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/items?limit=ten&id=1,x,3,y&filter[status]=ok&range[price]=1,z&active=maybe", nil)
+	_, err := BindQueryParams(&queryRequest{}, req)
+
+	var got []string
+	for _, queryErr := range queryParamErrors(err) {
+		got = append(got, queryErr.Param+": "+queryErr.Err.Error())
+	}
+	want := []string{
+		"limit: must be an integer",
+		"active: must be a boolean",
+		"id[1]: must be an integer",
+		"id[3]: must be an integer",
+		"range[price][1]: must be an integer",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	var queryErr *QueryParamError
+	if !errors.As(err, &queryErr) || queryErr.Error() != "query parameter limit is invalid: must be an integer" {
+		t.Fatalf("expected QueryParamError, got %v", err)
+	}
+}
+
+type unsupportedQueryRequest struct {
+	Limit  int             `json:"-" query:"limit"`
+	Window complex128      `json:"-" query:"window"`
+	Tags   []chan int      `json:"-" query:"tag"`
+	Nested struct{ A int } `json:"-" query:"nested"`
+}
+
+func TestBindQueryParamsRejectsUnsupportedTypes(t *testing.T) {
+	t.Parallel()
+
+	// Unsupported fields fail even without values, and take precedence over
+	// invalid input.
+	req := httptest.NewRequest(http.MethodGet, "/items?limit=ten", nil)
+	_, err := BindQueryParams(&unsupportedQueryRequest{}, req)
+	if err == nil || len(queryParamErrors(err)) != 0 {
+		t.Fatalf("expected only setup errors, got %v", err)
+	}
+	for _, want := range []string{"complex128", "[]chan int", "struct { A int }"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error to name %s, got %v", want, err)
+		}
+	}
+}
+
+func TestParseRequestUnsupportedQueryTypeIsServerError(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	req := httptest.NewRequest(http.MethodGet, "/items?window=1", nil)
+	w := httptest.NewRecorder()
+	if _, err := ParseRequest[*unsupportedQueryRequest](w, req, nil, nil); err == nil {
+		t.Fatal("expected an error for an unsupported field type")
+	}
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+}
+
+func TestBindQueryParamsWithoutQueryFields(t *testing.T) {
+	t.Parallel()
+
+	request := &struct{ Name string }{Name: "kept"}
+	bound, err := BindQueryParams(request, httptest.NewRequest(http.MethodGet, "/?Name=x", nil))
+	if err != nil || bound != request || bound.Name != "kept" {
+		t.Fatalf("expected request unchanged, got %+v %v", bound, err)
+	}
+	if _, err := BindQueryParams(&queryRequest{}, nil); err == nil {
+		t.Fatal("expected error for nil request")
+	}
+}
+
+func TestParseRequestBindsQueryParams(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	req := httptest.NewRequest(http.MethodGet, "/items?id=1,2&filter[status]=paid", nil)
+	parsed, err := ParseRequest[*queryRequest](httptest.NewRecorder(), req, nil, nil)
+	if err != nil || !reflect.DeepEqual(parsed.IDs, []int64{1, 2}) || parsed.Filter["status"] != "paid" {
+		t.Fatalf("expected query values, got %+v %v", parsed, err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/items?id=1,x", nil)
+	w := httptest.NewRecorder()
+	if _, err := ParseRequest[*queryRequest](w, req, nil, nil); err == nil {
+		t.Fatal("expected an error for an invalid element")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	var problem struct {
+		Errors []ValidationErrorDetail `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "id[1]" || problem.Errors[0].Message != "query parameter id[1] is invalid: must be an integer" {
+		t.Fatalf("expected element error, got %#v", problem.Errors)
+	}
+}
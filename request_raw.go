@@ -0,0 +1,79 @@
+package httpsuite
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+type rawBodyContextKey struct{}
+
+// RawBody holds the raw request bytes captured by ParseRequestRaw or
+// CaptureRawBody.
+type RawBody struct {
+	Bytes []byte
+	// Truncated reports whether the body exceeded the capture limit.
+	Truncated bool
+}
+
+// RawBodyFromContext returns the raw body stored by CaptureRawBody.
+func RawBodyFromContext(ctx context.Context) (*RawBody, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	raw, ok := ctx.Value(rawBodyContextKey{}).(*RawBody)
+	return raw, ok
+}
+
+// CaptureRawBody returns middleware that reads up to maxBytes of the request
+// body, stores it in the request context, and restores r.Body for later parsing.
+// Bodies larger than maxBytes are rejected with a 413 problem response.
+func CaptureRawBody(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := readRequestBody(r, maxBytes)
+			if err != nil {
+				problems := RequestProblemConfig(r)
+				problem, status := problemFromDecodeError(err, &problems)
+				sendRequestProblem(w, r, status, problem)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), rawBodyContextKey{}, &RawBody{Bytes: body})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// rawBodyCapture copies bytes read from the body into a RawBody up to limit.
+type rawBodyCapture struct {
+	io.ReadCloser
+	raw   *RawBody
+	limit int64
+}
+
+func captureRawBody(r *http.Request, raw *RawBody, limit int64) {
+	if r == nil || r.Body == nil || r.Body == http.NoBody || raw == nil {
+		return
+	}
+	r.Body = &rawBodyCapture{ReadCloser: r.Body, raw: raw, limit: limit}
+}
+
+func (c *rawBodyCapture) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	if n > 0 {
+		remaining := c.limit - int64(len(c.raw.Bytes))
+		chunk := p[:n]
+		if int64(len(chunk)) > remaining {
+			chunk = chunk[:max(remaining, 0)]
+			c.raw.Truncated = true
+		}
+		c.raw.Bytes = append(c.raw.Bytes, chunk...)
+	}
+	return n, err
+}
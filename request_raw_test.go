@@ -0,0 +1,94 @@
+package httpsuite
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestParseRequestCapturesRawBody(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	body := `{"id":7,"name":"Raw"}`
+
+	t.Run("full capture", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+
+		got, raw, err := ParseRequestRaw[*testRequest](w, req, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.ID != 7 {
+			t.Fatalf("unexpected parsed request: %#v", got)
+		}
+		if string(raw.Bytes) != body || raw.Truncated {
+			t.Fatalf("expected raw body %q, got %q (truncated=%v)", body, raw.Bytes, raw.Truncated)
+		}
+	})
+
+	t.Run("capped capture", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+
+		_, raw, err := ParseRequestRaw[*testRequest](w, req, nil, &ParseOptions{MaxRawBodyBytes: 4})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(raw.Bytes) != body[:4] || !raw.Truncated {
+			t.Fatalf("expected truncated raw body, got %q (truncated=%v)", raw.Bytes, raw.Truncated)
+		}
+	})
+}
+
+func TestParseRequestRawIsPerRequest(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	// Options shared by every request of a handler.
+	opts := &ParseOptions{MaxRawBodyBytes: 1024}
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"id":%d,"name":"Raw"}`, i)
+			req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body))
+			_, raw, err := ParseRequestRaw[*testRequest](httptest.NewRecorder(), req, nil, opts)
+			if err != nil || string(raw.Bytes) != body {
+				t.Errorf("expected raw body %q, got %q (%v)", body, raw.Bytes, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCaptureRawBodyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	body := `{"id":7,"name":"Raw"}`
+	var captured *RawBody
+	handler := CaptureRawBody(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured, _ = RawBodyFromContext(r.Context())
+		if _, err := DecodeRequestBody[*testRequest](r, defaultMaxBodyBytes); err != nil {
+			t.Errorf("expected body to remain decodable, got %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body)))
+	if captured == nil || string(captured.Bytes) != body {
+		t.Fatalf("expected raw body in context, got %#v", captured)
+	}
+
+	w = httptest.NewRecorder()
+	CaptureRawBody(4)(handler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
@@ -0,0 +1,126 @@
+package httpsuite
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RequestRules is implemented by request types with business rules that
+// struct tags cannot express. ParseRequest calls Validate after tag validation
+// and merges the failures into the same validation problem.
+type RequestRules interface {
+	Validate(ctx context.Context) error
+}
+
+// ValidationErrors is an error carrying field-level validation failures.
+type ValidationErrors []ValidationErrorDetail
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, detail := range e {
+		messages[i] = detail.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Rules collects business-rule failures inside RequestRules implementations.
+type Rules struct {
+	errors ValidationErrors
+}
+
+// Add records a failure for field.
+func (r *Rules) Add(field, message string) *Rules {
+	r.errors = append(r.errors, ValidationErrorDetail{Field: field, Message: message})
+	return r
+}
+
+// RequiredIf requires value to be non-zero when condition holds.
+func (r *Rules) RequiredIf(condition bool, field string, value any, when string) *Rules {
+	if condition && isZeroValue(value) {
+		r.Add(field, field+" is required when "+when)
+	}
+	return r
+}
+
+// EitherOr requires at least one of two fields to be non-zero.
+func (r *Rules) EitherOr(fieldA string, a any, fieldB string, b any) *Rules {
+	if isZeroValue(a) && isZeroValue(b) {
+		r.Add(fieldA, "either "+fieldA+" or "+fieldB+" is required")
+	}
+	return r
+}
+
+// Before requires start to be strictly before end when both are set.
+func (r *Rules) Before(startField string, start time.Time, endField string, end time.Time) *Rules {
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		r.Add(endField, startField+" must be before "+endField)
+	}
+	return r
+}
+
+// Err returns the collected failures as ValidationErrors, or nil.
+func (r *Rules) Err() error {
+	if len(r.errors) == 0 {
+		return nil
+	}
+	return append(ValidationErrors(nil), r.errors...)
+}
+
+// applyRequestRules merges RequestRules failures into the tag validation problem.
+func applyRequestRules(ctx context.Context, request any, problem *ProblemDetails, problems *ProblemConfig) *ProblemDetails {
+	rules, ok := request.(RequestRules)
+	if !ok || isRequestNil(request) {
+		return problem
+	}
+	err := rules.Validate(ctx)
+	if err == nil {
+		return problem
+	}
+
+	var details ValidationErrors
+	if !errors.As(err, &details) {
+		details = ValidationErrors{{Message: err.Error()}}
+	}
+	return mergeValidationErrors(problem, details, problems)
+}
+
+// applyValueValidation merges SelfValidator failures into the validation problem.
+func applyValueValidation(request any, problem *ProblemDetails, problems *ProblemConfig) *ProblemDetails {
+	var details ValidationErrors
+	if !errors.As(ValidateValues(request), &details) {
+		return problem
+	}
+	return mergeValidationErrors(problem, details, problems)
+}
+
+// mergeValidationErrors appends details to the problem's errors extension,
+// creating a validation problem when there is none yet.
+func mergeValidationErrors(problem *ProblemDetails, details ValidationErrors, problems *ProblemConfig) *ProblemDetails {
+	if problem == nil {
+		problem = NewProblemDetails(
+			http.StatusBadRequest,
+			problems.TypeURL("validation_error"),
+			"Validation Error",
+			"One or more fields failed validation.",
+		)
+	}
+	merged := *problem
+	merged.Extensions = make(map[string]interface{}, len(problem.Extensions)+1)
+	for key, value := range problem.Extensions {
+		merged.Extensions[key] = value
+	}
+	existing, _ := merged.Extensions["errors"].([]ValidationErrorDetail)
+	merged.Extensions["errors"] = append(append([]ValidationErrorDetail(nil), existing...), details...)
+	return &merged
+}
+
+func isZeroValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	return reflect.ValueOf(value).IsZero()
+}
@@ -0,0 +1,96 @@
+package httpsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type bookingRequest struct {
+	Email string    `json:"email"`
+	Phone string    `json:"phone"`
+	Kind  string    `json:"kind"`
+	Card  string    `json:"card"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (b *bookingRequest) Validate(context.Context) error {
+	var rules Rules
+	return rules.
+		EitherOr("email", b.Email, "phone", b.Phone).
+		RequiredIf(b.Kind == "paid", "card", b.Card, "kind is paid").
+		Before("start", b.Start, "end", b.End).
+		Err()
+}
+
+func TestParseRequestAppliesRequestRules(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	tagProblem := NewProblemDetails(http.StatusBadRequest, GetProblemTypeURL("validation_error"), "Validation Error", "")
+	tagProblem.Extensions = map[string]interface{}{
+		"errors": []ValidationErrorDetail{{Field: "kind", Message: "kind is invalid"}},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		validator  Validator
+		wantFields []string
+	}{
+		{
+			name:       "rules only",
+			body:       `{"kind":"paid","start":"2024-01-02T00:00:00Z","end":"2024-01-01T00:00:00Z"}`,
+			wantFields: []string{"email", "card", "end"},
+		},
+		{
+			name:       "merged with tag validation",
+			body:       `{"email":"ada@example.com","kind":"paid"}`,
+			validator:  stubValidator{problem: tagProblem},
+			wantFields: []string{"kind", "card"},
+		},
+		{
+			name: "valid",
+			body: `{"phone":"+4912345","kind":"free"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			_, err := ParseRequest[*bookingRequest](w, req, nil, &ParseOptions{Validator: tt.validator})
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var problem struct {
+				Errors []ValidationErrorDetail `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if len(problem.Errors) != len(tt.wantFields) {
+				t.Fatalf("expected %d errors, got %#v", len(tt.wantFields), problem.Errors)
+			}
+			for i, field := range tt.wantFields {
+				if problem.Errors[i].Field != field {
+					t.Fatalf("expected field %q at %d, got %#v", field, i, problem.Errors)
+				}
+			}
+		})
+	}
+
+	if errorsValue := tagProblem.Extensions["errors"].([]ValidationErrorDetail); len(errorsValue) != 1 {
+		t.Fatalf("expected validator problem to stay unchanged, got %#v", errorsValue)
+	}
+}
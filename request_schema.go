@@ -0,0 +1,481 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// JSONSchema validates JSON documents against a JSON Schema. It supports the
+// commonly used subset of draft 2020-12: type, enum, const, object, array,
+// string, and numeric constraints, formats, allOf/anyOf/oneOf/not, and local
+// $ref pointers. The OpenAPI 3.0 nullable keyword is honored as well.
+type JSONSchema struct {
+	root     any
+	schema   any
+	patterns map[string]*regexp.Regexp
+}
+
+// CompileJSONSchema parses a schema document. Remote references and invalid
+// patterns are rejected up front so validation never fails on the schema.
+func CompileJSONSchema(document []byte) (*JSONSchema, error) {
+	var root any
+	if err := json.Unmarshal(document, &root); err != nil {
+		return nil, fmt.Errorf("parse JSON schema: %w", err)
+	}
+	switch root.(type) {
+	case map[string]any, bool:
+	default:
+		return nil, errors.New("JSON schema must be an object or a boolean")
+	}
+
+	schema := &JSONSchema{root: root, schema: root, patterns: make(map[string]*regexp.Regexp)}
+	if err := schema.compile(root); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Resolve returns the subschema at a JSON pointer such as
+// "#/components/schemas/User", sharing the document for $ref resolution.
+func (s *JSONSchema) Resolve(pointer string) (*JSONSchema, error) {
+	target, err := resolveJSONPointer(s.root, pointer)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONSchema{root: s.root, schema: target, patterns: s.patterns}, nil
+}
+
+// Validate checks a JSON document. Schema violations are returned as
+// ValidationErrors whose Pointer locates the offending value; malformed JSON
+// returns the decoding error.
+func (s *JSONSchema) Validate(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return s.ValidateValue(value)
+}
+
+// ValidateValue checks a value decoded by encoding/json into an any.
+func (s *JSONSchema) ValidateValue(value any) error {
+	var details ValidationErrors
+	s.validate(s.schema, value, "", "", &details, 0)
+	if len(details) == 0 {
+		return nil
+	}
+	return details
+}
+
+func (s *JSONSchema) compile(node any) error {
+	switch typed := node.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			switch key {
+			case "$ref":
+				ref, _ := value.(string)
+				if _, err := resolveJSONPointer(s.root, ref); err != nil {
+					return err
+				}
+				continue
+			case "pattern":
+				if pattern, ok := value.(string); ok {
+					compiled, err := regexp.Compile(pattern)
+					if err != nil {
+						return fmt.Errorf("compile JSON schema pattern %q: %w", pattern, err)
+					}
+					s.patterns[pattern] = compiled
+					continue
+				}
+			case "enum", "const", "default", "examples", "example":
+				continue
+			}
+			if err := s.compile(value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, value := range typed {
+			if err := s.compile(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveJSONPointer follows a local reference ("#", "#/a/b") from root.
+func resolveJSONPointer(root any, ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported JSON schema reference %q", ref)
+	}
+	pointer, err := url.PathUnescape(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON schema reference %q: %w", ref, err)
+	}
+	current := root
+	if pointer == "" {
+		return current, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch typed := current.(type) {
+		case map[string]any:
+			next, ok := typed[token]
+			if !ok {
+				return nil, fmt.Errorf("unresolved JSON schema reference %q", ref)
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, fmt.Errorf("unresolved JSON schema reference %q", ref)
+			}
+			current = typed[index]
+		default:
+			return nil, fmt.Errorf("unresolved JSON schema reference %q", ref)
+		}
+	}
+	return current, nil
+}
+
+// maxSchemaDepth stops $ref cycles that never consume any input.
+const maxSchemaDepth = 64
+
+func (s *JSONSchema) validate(node, value any, pointer, field string, details *ValidationErrors, depth int) {
+	fail := func(message string) {
+		*details = append(*details, ValidationErrorDetail{Field: field, Pointer: pointer, Message: message})
+	}
+	if depth > maxSchemaDepth {
+		fail("exceeds the maximum schema depth")
+		return
+	}
+
+	schema, ok := node.(map[string]any)
+	if !ok {
+		if allowed, isBool := node.(bool); isBool && !allowed {
+			fail("is not allowed")
+		}
+		return
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		target, _ := resolveJSONPointer(s.root, ref)
+		s.validate(target, value, pointer, field, details, depth+1)
+	}
+	if value == nil && schema["nullable"] == true {
+		return
+	}
+
+	if types, ok := schemaTypes(schema["type"]); ok && !matchesAnyType(value, types) {
+		fail("must be of type " + strings.Join(types, " or "))
+		return
+	}
+	if enum, ok := schema["enum"].([]any); ok && !containsJSONValue(enum, value) {
+		fail("must be one of " + formatJSONValues(enum))
+	}
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, value) {
+		fail("must be " + formatJSONValues([]any{constant}))
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		s.validateObject(schema, typed, pointer, field, details, depth)
+	case []any:
+		s.validateArray(schema, typed, pointer, field, details, depth)
+	case string:
+		s.validateString(schema, typed, fail)
+	case float64:
+		validateNumber(schema, typed, fail)
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, subschema := range all {
+			s.validate(subschema, value, pointer, field, details, depth+1)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok && s.countMatches(anyOf, value, depth) == 0 {
+		fail("must match at least one allowed schema")
+	}
+	if oneOf, ok := schema["oneOf"].([]any); ok && s.countMatches(oneOf, value, depth) != 1 {
+		fail("must match exactly one allowed schema")
+	}
+	if not, ok := schema["not"]; ok && s.countMatches([]any{not}, value, depth) == 1 {
+		fail("must not match the excluded schema")
+	}
+}
+
+func (s *JSONSchema) countMatches(schemas []any, value any, depth int) int {
+	matches := 0
+	for _, subschema := range schemas {
+		var nested ValidationErrors
+		s.validate(subschema, value, "", "", &nested, depth+1)
+		if len(nested) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func (s *JSONSchema) validateObject(schema, object map[string]any, pointer, field string, details *ValidationErrors, depth int) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			name, _ := name.(string)
+			if _, present := object[name]; !present {
+				*details = append(*details, ValidationErrorDetail{
+					Field:   joinFieldPath(field, name),
+					Pointer: pointer + "/" + escapeJSONPointer(name),
+					Message: "is required",
+				})
+			}
+		}
+	}
+	if limit, ok := schemaInt(schema["minProperties"]); ok && len(object) < limit {
+		*details = append(*details, ValidationErrorDetail{Field: field, Pointer: pointer, Message: "must have at least " + strconv.Itoa(limit) + " properties"})
+	}
+	if limit, ok := schemaInt(schema["maxProperties"]); ok && len(object) > limit {
+		*details = append(*details, ValidationErrorDetail{Field: field, Pointer: pointer, Message: "must have at most " + strconv.Itoa(limit) + " properties"})
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"]
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		childPointer := pointer + "/" + escapeJSONPointer(name)
+		childField := joinFieldPath(field, name)
+		if property, ok := properties[name]; ok {
+			s.validate(property, object[name], childPointer, childField, details, depth+1)
+		} else if hasAdditional {
+			s.validate(additional, object[name], childPointer, childField, details, depth+1)
+		}
+	}
+}
+
+func (s *JSONSchema) validateArray(schema map[string]any, items []any, pointer, field string, details *ValidationErrors, depth int) {
+	if limit, ok := schemaInt(schema["minItems"]); ok && len(items) < limit {
+		*details = append(*details, ValidationErrorDetail{Field: field, Pointer: pointer, Message: "must contain at least " + strconv.Itoa(limit) + " items"})
+	}
+	if limit, ok := schemaInt(schema["maxItems"]); ok && len(items) > limit {
+		*details = append(*details, ValidationErrorDetail{Field: field, Pointer: pointer, Message: "must contain at most " + strconv.Itoa(limit) + " items"})
+	}
+	if schema["uniqueItems"] == true {
+		for i := 1; i < len(items); i++ {
+			if containsJSONValue(items[:i], items[i]) {
+				*details = append(*details, ValidationErrorDetail{Field: field, Pointer: pointer, Message: "must not contain duplicate items"})
+				break
+			}
+		}
+	}
+
+	prefix, _ := schema["prefixItems"].([]any)
+	itemSchema, hasItems := schema["items"]
+	for i, item := range items {
+		childPointer := pointer + "/" + strconv.Itoa(i)
+		childField := field + "[" + strconv.Itoa(i) + "]"
+		if i < len(prefix) {
+			s.validate(prefix[i], item, childPointer, childField, details, depth+1)
+		} else if hasItems {
+			s.validate(itemSchema, item, childPointer, childField, details, depth+1)
+		}
+	}
+}
+
+func (s *JSONSchema) validateString(schema map[string]any, value string, fail func(string)) {
+	length := utf8.RuneCountInString(value)
+	if limit, ok := schemaInt(schema["minLength"]); ok && length < limit {
+		fail("must be at least " + strconv.Itoa(limit) + " characters long")
+	}
+	if limit, ok := schemaInt(schema["maxLength"]); ok && length > limit {
+		fail("must be at most " + strconv.Itoa(limit) + " characters long")
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		compiled := s.patterns[pattern]
+		if compiled == nil {
+			compiled, _ = regexp.Compile(pattern)
+		}
+		if compiled != nil && !compiled.MatchString(value) {
+			fail("must match pattern " + pattern)
+		}
+	}
+	if format, ok := schema["format"].(string); ok && !validFormat(format, value) {
+		fail("must be a valid " + format)
+	}
+}
+
+func validateNumber(schema map[string]any, value float64, fail func(string)) {
+	if limit, ok := schema["minimum"].(float64); ok && value < limit {
+		fail("must be greater than or equal to " + formatNumber(limit))
+	}
+	if limit, ok := schema["maximum"].(float64); ok && value > limit {
+		fail("must be less than or equal to " + formatNumber(limit))
+	}
+	if limit, ok := schema["exclusiveMinimum"].(float64); ok && value <= limit {
+		fail("must be greater than " + formatNumber(limit))
+	}
+	if limit, ok := schema["exclusiveMaximum"].(float64); ok && value >= limit {
+		fail("must be less than " + formatNumber(limit))
+	}
+	if factor, ok := schema["multipleOf"].(float64); ok && factor > 0 {
+		quotient := value / factor
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			fail("must be a multiple of " + formatNumber(factor))
+		}
+	}
+}
+
+func schemaTypes(value any) ([]string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}, true
+	case []any:
+		types := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types, len(types) > 0
+	}
+	return nil, false
+}
+
+func matchesAnyType(value any, types []string) bool {
+	for _, name := range types {
+		switch name {
+		case "null":
+			if value == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "object":
+			if _, ok := value.(map[string]any); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]any); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if number, ok := value.(float64); ok && number == math.Trunc(number) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validFormat(format, value string) bool {
+	switch format {
+	case "email":
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	case "uri":
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.Scheme != ""
+	case "uri-reference":
+		_, err := url.Parse(value)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, value)
+		return err == nil
+	case "ipv4":
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+	case "ipv6":
+		ip := net.ParseIP(value)
+		return ip != nil && strings.Contains(value, ":")
+	}
+	// Unknown formats are annotations only.
+	return true
+}
+
+func schemaInt(value any) (int, bool) {
+	number, ok := value.(float64)
+	return int(number), ok
+}
+
+func containsJSONValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatJSONValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		encoded, _ := json.Marshal(value)
+		parts[i] = string(encoded)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// checkRequestSchema validates the raw body against options.Schema before it
+// is decoded, writing a validation problem on failure. Malformed JSON is left
+// for the decoder to report.
+func checkRequestSchema(w http.ResponseWriter, r *http.Request, options ParseOptions) error {
+	if options.Schema == nil || r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	body, err := readRequestBody(r, options.MaxBodyBytes)
+	if err != nil {
+		problem, status := problemFromDecodeError(err, options.Problems)
+		sendRequestProblem(w, r, status, problem)
+		return err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	var details ValidationErrors
+	if !errors.As(options.Schema.Validate(body), &details) {
+		return nil
+	}
+	problem := withValidationStatus(mergeValidationErrors(nil, details, options.Problems), options.ValidationStatus)
+	sendRequestProblem(w, r, validationProblemStatus(problem), problem)
+	return errValidationFailed
+}
@@ -0,0 +1,224 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testUserSchema = `{
+	"type": "object",
+	"required": ["name", "email"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "integer"},
+		"name": {"type": "string", "minLength": 2, "maxLength": 8},
+		"email": {"type": "string", "format": "email"},
+		"role": {"enum": ["admin", "member"]},
+		"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+		"code": {"type": "string", "pattern": "^[A-Z]{3}$"},
+		"score": {"type": "number", "multipleOf": 0.5},
+		"nickname": {"type": ["string", "null"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2, "uniqueItems": true},
+		"address": {"$ref": "#/$defs/address"},
+		"contact": {"oneOf": [{"required": ["phone"]}, {"required": ["fax"]}]},
+		"deleted": {"not": {"const": true}},
+		"a/b": {"type": "boolean"}
+	},
+	"$defs": {
+		"address": {
+			"type": "object",
+			"required": ["city"],
+			"properties": {"city": {"type": "string"}, "zip": {"type": "string", "nullable": true}}
+		}
+	}
+}`
+
+func TestJSONSchemaValidate(t *testing.T) {
+	t.Parallel()
+
+	schema, err := CompileJSONSchema([]byte(testUserSchema))
+	if err != nil {
+		t.Fatalf("compile schema: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want ValidationErrors
+	}{
+		{
+			name: "valid",
+			body: `{"id":1,"name":"ada","email":"ada@example.com","role":"admin","age":36,"code":"ADA","score":1.5,"nickname":null,"tags":["x","y"],"address":{"city":"London","zip":null},"contact":{"phone":"1"},"deleted":false,"a/b":true}`,
+		},
+		{
+			name: "missing required",
+			body: `{"name":"ada"}`,
+			want: ValidationErrors{{Field: "email", Pointer: "/email", Message: "is required"}},
+		},
+		{
+			name: "scalar constraints",
+			body: `{"id":1.5,"name":"a","email":"nope","role":"guest","age":150,"code":"ab","score":0.3}`,
+			want: ValidationErrors{
+				{Field: "age", Pointer: "/age", Message: "must be less than 150"},
+				{Field: "code", Pointer: "/code", Message: "must match pattern ^[A-Z]{3}$"},
+				{Field: "email", Pointer: "/email", Message: "must be a valid email"},
+				{Field: "id", Pointer: "/id", Message: "must be of type integer"},
+				{Field: "name", Pointer: "/name", Message: "must be at least 2 characters long"},
+				{Field: "role", Pointer: "/role", Message: `must be one of "admin", "member"`},
+				{Field: "score", Pointer: "/score", Message: "must be a multiple of 0.5"},
+			},
+		},
+		{
+			name: "nested values",
+			body: `{"name":"ada","email":"ada@example.com","tags":["x",1,"x"],"address":{"zip":5},"a/b":"yes","extra":1}`,
+			want: ValidationErrors{
+				{Field: "a/b", Pointer: "/a~1b", Message: "must be of type boolean"},
+				{Field: "address.city", Pointer: "/address/city", Message: "is required"},
+				{Field: "address.zip", Pointer: "/address/zip", Message: "must be of type string"},
+				{Field: "extra", Pointer: "/extra", Message: "is not allowed"},
+				{Field: "tags", Pointer: "/tags", Message: "must contain at most 2 items"},
+				{Field: "tags", Pointer: "/tags", Message: "must not contain duplicate items"},
+				{Field: "tags[1]", Pointer: "/tags/1", Message: "must be of type string"},
+			},
+		},
+		{
+			name: "combinators",
+			body: `{"name":"ada","email":"ada@example.com","contact":{"phone":"1","fax":"2"},"deleted":true}`,
+			want: ValidationErrors{
+				{Field: "contact", Pointer: "/contact", Message: "must match exactly one allowed schema"},
+				{Field: "deleted", Pointer: "/deleted", Message: "must not match the excluded schema"},
+			},
+		},
+		{
+			name: "wrong root type",
+			body: `[]`,
+			want: ValidationErrors{{Message: "must be of type object"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.body))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			var got ValidationErrors
+			if !errors.As(err, &got) {
+				t.Fatalf("expected ValidationErrors, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompileJSONSchemaErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"malformed":        `{`,
+		"not a schema":     `"string"`,
+		"remote reference": `{"$ref": "https://example.com/schema.json"}`,
+		"missing target":   `{"properties": {"a": {"$ref": "#/$defs/missing"}}}`,
+		"invalid pattern":  `{"pattern": "("}`,
+	}
+	for name, document := range tests {
+		if _, err := CompileJSONSchema([]byte(document)); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestJSONSchemaResolve(t *testing.T) {
+	t.Parallel()
+
+	schema, err := CompileJSONSchema([]byte(testUserSchema))
+	if err != nil {
+		t.Fatalf("compile schema: %v", err)
+	}
+	address, err := schema.Resolve("#/$defs/address")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if err := address.ValidateValue(map[string]any{"city": "Paris"}); err != nil {
+		t.Fatalf("expected valid address, got %v", err)
+	}
+	if err := address.ValidateValue(map[string]any{}); err == nil {
+		t.Fatal("expected missing city to fail")
+	}
+	if _, err := schema.Resolve("#/$defs/missing"); err == nil {
+		t.Fatal("expected unresolved pointer to fail")
+	}
+}
+
+func TestParseRequestWithSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := CompileJSONSchema([]byte(`{"type":"object","required":["name"],"properties":{"name":{"type":"string","minLength":2}}}`))
+	if err != nil {
+		t.Fatalf("compile schema: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		opts       *ParseOptions
+		wantStatus int
+		wantErrors []ValidationErrorDetail
+	}{
+		{name: "valid", body: `{"name":"ada"}`, opts: &ParseOptions{Schema: schema}},
+		{
+			name: "invalid", body: `{"name":"a"}`, opts: &ParseOptions{Schema: schema},
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationErrorDetail{{Field: "name", Pointer: "/name", Message: "must be at least 2 characters long"}},
+		},
+		{
+			name: "instead of tags", body: `{}`, opts: &ParseOptions{Schema: schema, SkipValidation: true, ValidationStatus: http.StatusUnprocessableEntity},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []ValidationErrorDetail{{Field: "name", Pointer: "/name", Message: "is required"}},
+		},
+		{name: "malformed", body: `{"name":`, opts: &ParseOptions{Schema: schema}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users/7", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			got, err := ParseRequest[*testRequest](w, req, testParamExtractor, tt.opts, "id")
+
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if got.ID != 7 || got.Name != "ada" {
+					t.Fatalf("unexpected request %#v", got)
+				}
+				return
+			}
+			if err == nil || w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d and an error, got %d and %v", tt.wantStatus, w.Code, err)
+			}
+			if tt.wantErrors == nil {
+				return
+			}
+			var body struct {
+				Errors []ValidationErrorDetail `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if !reflect.DeepEqual(body.Errors, tt.wantErrors) {
+				t.Fatalf("expected errors %#v, got %#v", tt.wantErrors, body.Errors)
+			}
+		})
+	}
+}
@@ -48,7 +48,17 @@ func TestParseRequest(t *testing.T) {
 			wantErr:            true,
 			wantStatus:         http.StatusBadRequest,
 			wantTitle:          "Invalid Request",
-			wantDetailContains: "invalid character",
+			wantDetailContains: "request body is not valid JSON at line 1, column 2",
+		},
+		{
+			name:               "invalid field type",
+			body:               `{"name":42}`,
+			path:               "/test/123",
+			pathParams:         []string{"id"},
+			wantErr:            true,
+			wantStatus:         http.StatusBadRequest,
+			wantTitle:          "Invalid Request",
+			wantDetailContains: "invalid value",
 		},
 		{
 			name:               "multiple json documents",
@@ -187,6 +197,8 @@ func TestParseRequestInvalidInputs(t *testing.T) {
 		extractor  ParamExtractor
 		pathParams []string
 		wantErr    error
+		// wantStatus is 0 when nothing must be written.
+		wantStatus int
 	}{
 		{
 			name:      "nil request",
@@ -211,6 +223,7 @@ func TestParseRequestInvalidInputs(t *testing.T) {
 			},
 			pathParams: []string{"id"},
 			wantErr:    errNilParamExtractor,
+			wantStatus: http.StatusInternalServerError,
 		},
 	}
 
@@ -224,9 +237,12 @@ func TestParseRequestInvalidInputs(t *testing.T) {
 			if got != nil {
 				t.Fatalf("expected nil request, got %#v", got)
 			}
-			if w.Body.Len() != 0 {
+			if tt.wantStatus == 0 && w.Body.Len() != 0 {
 				t.Fatalf("expected no response body to be written, got %q", w.Body.String())
 			}
+			if tt.wantStatus != 0 && (w.Code != tt.wantStatus || w.Header().Get("Content-Type") != "application/problem+json; charset=utf-8") {
+				t.Fatalf("expected a %d problem, got %d %q", tt.wantStatus, w.Code, w.Body.String())
+			}
 		})
 	}
 }
@@ -370,3 +386,29 @@ func TestParseRequestSkipValidation(t *testing.T) {
 		t.Fatalf("expected parsed request, got %#v", got)
 	}
 }
+
+func TestParseRequestAggregatesBindingErrors(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	req := httptest.NewRequest(http.MethodPost, "/test/nope", strings.NewReader(`{"name":42}`))
+	w := httptest.NewRecorder()
+
+	if _, err := ParseRequest[*testRequest](w, req, testParamExtractor, nil, "id"); err == nil {
+		t.Fatal("expected binding error, got nil")
+	}
+
+	var problem struct {
+		Title  string                  `json:"title"`
+		Errors []ValidationErrorDetail `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Title != "Invalid Request" || len(problem.Errors) != 2 {
+		t.Fatalf("expected aggregated body and path errors, got %#v", problem)
+	}
+	if problem.Errors[0].Field != "name" || problem.Errors[1].Field != "id" {
+		t.Fatalf("unexpected error fields: %#v", problem.Errors)
+	}
+}
@@ -1,6 +1,9 @@
 package httpsuite
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 // RequestParamSetter defines custom path parameter binding for request structs.
 type RequestParamSetter interface {
@@ -15,12 +18,43 @@ type Validator interface {
 	Validate(any) *ProblemDetails
 }
 
+// ContextValidator is implemented by validators that perform context-bound
+// checks such as tenant configuration or deadline-aware lookups.
+type ContextValidator interface {
+	Validator
+	ValidateContext(ctx context.Context, request any) *ProblemDetails
+}
+
+// LocalizedValidator is implemented by validators that can localize messages
+// for the caller's Accept-Language header. ParseRequest prefers it when available
+// and passes the UseLocale choice instead of the header when one was made.
+type LocalizedValidator interface {
+	Validator
+	ValidateLocalized(ctx context.Context, request any, acceptLanguage string) *ProblemDetails
+}
+
 // ParseOptions configures request parsing behavior.
 type ParseOptions struct {
 	MaxBodyBytes   int64
 	Problems       *ProblemConfig
 	Validator      Validator
 	SkipValidation bool
+	// MaxRawBodyBytes caps the raw body returned by ParseRequestRaw and
+	// defaults to MaxBodyBytes.
+	MaxRawBodyBytes int64
+	// ValidationStatus overrides the status of validation problems, e.g. 422
+	// for APIs that separate malformed syntax (400) from invalid content.
+	ValidationStatus int
+	// MaxConcurrentChecks bounds parallel FieldChecker checks and defaults to 4.
+	MaxConcurrentChecks int
+	// Schema validates the raw body before decoding. It runs even with
+	// SkipValidation, so spec-first APIs can use it instead of struct tags.
+	Schema *JSONSchema
+	// MaxJSONDepth bounds object and array nesting and defaults to 128.
+	MaxJSONDepth int
+	// MaxJSONNumberLength bounds number literals, in characters, and
+	// defaults to 256.
+	MaxJSONNumberLength int
 }
 
 const defaultMaxBodyBytes int64 = 1 << 20
@@ -0,0 +1,224 @@
+package httpsuite
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultMaxUploadBytes  = 32 << 20
+	defaultMaxUploadMemory = 8 << 20
+	sniffLength            = 512
+)
+
+// UploadRule validates the files sent in one multipart part.
+type UploadRule struct {
+	// Field is the multipart part name, reported in validation errors.
+	Field    string
+	Required bool
+	// MaxFiles caps the files in the part. Defaults to 1.
+	MaxFiles int
+	// MaxBytes caps each file's size. Zero means no limit beyond the body.
+	MaxBytes int64
+	// AllowedTypes lists media types detected from the content, such as
+	// "image/png" or "image/*". The client's declared type is ignored.
+	AllowedTypes []string
+	// MaxWidth and MaxHeight cap image dimensions in pixels. Images are
+	// measured with the decoders registered with the image package, e.g. by
+	// importing image/png for its side effect; other files fail when a limit
+	// is set.
+	MaxWidth  int
+	MaxHeight int
+}
+
+// UploadOptions configures ParseUploads.
+type UploadOptions struct {
+	// MaxBodyBytes limits the whole request body. Defaults to 32 MiB.
+	MaxBodyBytes int64
+	// MaxMemory is the part of the body kept in memory; the rest is spooled
+	// to temporary files. Defaults to 8 MiB.
+	MaxMemory int64
+	Files     []UploadRule
+	// Scanner inspects every file that passed its rule before ParseUploads
+	// returns, e.g. with an antivirus engine.
+	Scanner Scanner
+	// Problems resolves problem type URLs. Defaults to the scoped or default
+	// ProblemConfig.
+	Problems *ProblemConfig
+}
+
+// UploadedFile is a validated file part.
+type UploadedFile struct {
+	Field    string
+	Filename string
+	Size     int64
+	// ContentType is detected from the file's leading bytes.
+	ContentType string
+	// Width and Height are set for images that could be measured.
+	Width  int
+	Height int
+	header *multipart.FileHeader
+}
+
+// Open opens the file content.
+func (f *UploadedFile) Open() (multipart.File, error) {
+	return f.header.Open()
+}
+
+// Uploads holds the validated files of a request by part name.
+type Uploads map[string][]*UploadedFile
+
+// File returns the first file of field, or nil when none was sent.
+func (u Uploads) File(field string) *UploadedFile {
+	if files := u[field]; len(files) > 0 {
+		return files[0]
+	}
+	return nil
+}
+
+// ParseUploads parses a multipart/form-data request and validates its files
+// against opts.Files, detecting each file's real type from its magic bytes.
+// Failures are answered with a validation problem whose errors reference the
+// part names, and returned as ValidationErrors. Form values stay available
+// through r.FormValue.
+func ParseUploads(w http.ResponseWriter, r *http.Request, opts *UploadOptions) (Uploads, error) {
+	options := UploadOptions{MaxBodyBytes: defaultMaxUploadBytes, MaxMemory: defaultMaxUploadMemory}
+	if opts != nil {
+		options.Files = opts.Files
+		options.Scanner = opts.Scanner
+		options.Problems = opts.Problems
+		if opts.MaxBodyBytes > 0 {
+			options.MaxBodyBytes = opts.MaxBodyBytes
+		}
+		if opts.MaxMemory > 0 {
+			options.MaxMemory = opts.MaxMemory
+		}
+	}
+	problems := options.Problems
+	if problems == nil {
+		problems = sharedProblemConfig(r.Context())
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "multipart/form-data" {
+		err := errors.New("request must be multipart/form-data")
+		sendRequestProblem(w, r, http.StatusUnsupportedMediaType, NewProblemDetails(http.StatusUnsupportedMediaType, problems.TypeURL("bad_request_error"), "Unsupported Media Type", "Request body must be multipart/form-data"))
+		return nil, err
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, options.MaxBodyBytes)
+	if err := r.ParseMultipartForm(options.MaxMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			sendRequestProblem(w, r, http.StatusRequestEntityTooLarge, NewProblemDetails(http.StatusRequestEntityTooLarge, problems.TypeURL("bad_request_error"), "Payload Too Large", fmt.Sprintf("request body exceeds %d bytes", options.MaxBodyBytes)))
+			return nil, err
+		}
+		sendRequestProblem(w, r, http.StatusBadRequest, NewProblemDetails(http.StatusBadRequest, problems.TypeURL("bad_request_error"), "Invalid Request", "Request body is not valid multipart/form-data"))
+		return nil, err
+	}
+
+	uploads := make(Uploads, len(options.Files))
+	var failures ValidationErrors
+	for _, rule := range options.Files {
+		headers := r.MultipartForm.File[rule.Field]
+		maxFiles := rule.MaxFiles
+		if maxFiles <= 0 {
+			maxFiles = 1
+		}
+		switch {
+		case len(headers) == 0 && rule.Required:
+			failures = append(failures, ValidationErrorDetail{Field: rule.Field, Message: "file is required"})
+			continue
+		case len(headers) > maxFiles:
+			failures = append(failures, ValidationErrorDetail{Field: rule.Field, Message: fmt.Sprintf("at most %d files are allowed", maxFiles)})
+			continue
+		}
+		for _, header := range headers {
+			file, message, err := inspectUpload(rule, header)
+			if err != nil {
+				sendRequestProblem(w, r, http.StatusInternalServerError, internalErrorProblem(r.Context()))
+				return nil, err
+			}
+			if message != "" {
+				failures = append(failures, ValidationErrorDetail{Field: rule.Field, Message: message})
+				continue
+			}
+			uploads[rule.Field] = append(uploads[rule.Field], file)
+		}
+	}
+
+	if len(failures) > 0 {
+		problem := mergeValidationErrors(nil, failures, problems)
+		sendRequestProblem(w, r, problem.Status, problem)
+		return nil, failures
+	}
+	if options.Scanner != nil {
+		if err := scanUploads(w, r, options.Scanner, uploads, options.Files, problems); err != nil {
+			return nil, err
+		}
+	}
+	return uploads, nil
+}
+
+// inspectUpload sniffs and measures one file. It returns a validation
+// message for files breaking the rule, or an error when the file cannot be
+// read.
+func inspectUpload(rule UploadRule, header *multipart.FileHeader) (*UploadedFile, string, error) {
+	file := &UploadedFile{Field: rule.Field, Filename: header.Filename, Size: header.Size, header: header}
+	if rule.MaxBytes > 0 && header.Size > rule.MaxBytes {
+		return nil, fmt.Sprintf("file exceeds %d bytes", rule.MaxBytes), nil
+	}
+
+	content, err := header.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = content.Close() }()
+	buffer := make([]byte, sniffLength)
+	n, err := io.ReadFull(content, buffer)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, "", err
+	}
+	file.ContentType, _, _ = mime.ParseMediaType(http.DetectContentType(buffer[:n]))
+	if len(rule.AllowedTypes) > 0 && !mediaTypeAllowed(file.ContentType, rule.AllowedTypes) {
+		return nil, fmt.Sprintf("file type %s is not allowed", file.ContentType), nil
+	}
+
+	if strings.HasPrefix(file.ContentType, "image/") || rule.MaxWidth > 0 || rule.MaxHeight > 0 {
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return nil, "", err
+		}
+		config, _, err := image.DecodeConfig(content)
+		switch {
+		case err == nil:
+			file.Width, file.Height = config.Width, config.Height
+		case rule.MaxWidth > 0 || rule.MaxHeight > 0:
+			return nil, "image dimensions could not be read", nil
+		}
+		if rule.MaxWidth > 0 && file.Width > rule.MaxWidth {
+			return nil, fmt.Sprintf("image must be at most %d pixels wide", rule.MaxWidth), nil
+		}
+		if rule.MaxHeight > 0 && file.Height > rule.MaxHeight {
+			return nil, fmt.Sprintf("image must be at most %d pixels high", rule.MaxHeight), nil
+		}
+	}
+	return file, "", nil
+}
+
+// mediaTypeAllowed matches exact types and "type/*" wildcards.
+func mediaTypeAllowed(mediaType string, allowed []string) bool {
+	for _, candidate := range allowed {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if candidate == mediaType || candidate == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(candidate, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
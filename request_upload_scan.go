@@ -0,0 +1,81 @@
+package httpsuite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ErrUploadRejected marks scanner verdicts against a file. Scanners wrap it,
+// usually through RejectUpload; any other error is a scanner failure.
+var ErrUploadRejected = errors.New("upload rejected")
+
+// Scanner inspects uploaded file content, for antivirus engines such as
+// ClamAV or cloud scanning services. Return an error wrapping
+// ErrUploadRejected to reject the file; other errors fail the request with a
+// 503 so unscanned files never reach the handler.
+type Scanner interface {
+	Scan(ctx context.Context, file *UploadedFile, content io.Reader) error
+}
+
+// ScannerFunc adapts a function to the Scanner interface.
+type ScannerFunc func(ctx context.Context, file *UploadedFile, content io.Reader) error
+
+// Scan calls f(ctx, file, content).
+func (f ScannerFunc) Scan(ctx context.Context, file *UploadedFile, content io.Reader) error {
+	return f(ctx, file, content)
+}
+
+// RejectUpload returns a scanner verdict with a reason shown to the client,
+// e.g. RejectUpload("malware detected: Eicar-Test-Signature").
+func RejectUpload(reason string) error {
+	return fmt.Errorf("%w: %s", ErrUploadRejected, reason)
+}
+
+// scanUploads runs scanner on every file in rule order and answers
+// rejections with a 422 problem listing each part.
+func scanUploads(w http.ResponseWriter, r *http.Request, scanner Scanner, uploads Uploads, rules []UploadRule, problems *ProblemConfig) error {
+	var rejections ValidationErrors
+	for _, rule := range rules {
+		for _, file := range uploads[rule.Field] {
+			err := scanUpload(r.Context(), scanner, file)
+			switch {
+			case err == nil:
+			case errors.Is(err, ErrUploadRejected):
+				rejections = append(rejections, ValidationErrorDetail{Field: file.Field, Message: rejectionReason(err)})
+			default:
+				log.Printf("Upload scan failed: %v", err)
+				sendRequestProblem(w, r, http.StatusServiceUnavailable, NewProblemDetails(http.StatusServiceUnavailable, problems.TypeURL("service_unavailable_error"), "Service Unavailable", "Uploaded files could not be scanned"))
+				return err
+			}
+		}
+	}
+	if len(rejections) == 0 {
+		return nil
+	}
+	problem := NewProblemDetails(http.StatusUnprocessableEntity, problems.TypeURL("upload_rejected_error"), "Upload Rejected", "One or more uploaded files were rejected.")
+	problem.Extensions = map[string]interface{}{"errors": []ValidationErrorDetail(rejections)}
+	sendRequestProblem(w, r, problem.Status, problem)
+	return rejections
+}
+
+func scanUpload(ctx context.Context, scanner Scanner, file *UploadedFile) error {
+	content, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = content.Close() }()
+	return scanner.Scan(ctx, file, content)
+}
+
+// rejectionReason strips the ErrUploadRejected prefix RejectUpload adds.
+func rejectionReason(err error) string {
+	if reason, ok := strings.CutPrefix(err.Error(), ErrUploadRejected.Error()+": "); ok && reason != "" {
+		return reason
+	}
+	return err.Error()
+}
@@ -0,0 +1,91 @@
+package httpsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseUploadsScanner(t *testing.T) {
+	t.Parallel()
+
+	eicar := []byte(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`)
+	scanner := ScannerFunc(func(ctx context.Context, file *UploadedFile, content io.Reader) error {
+		data, err := io.ReadAll(content)
+		if err != nil {
+			return err
+		}
+		switch {
+		case bytes.Contains(data, []byte("EICAR")):
+			return RejectUpload("malware detected: Eicar-Test-Signature")
+		case bytes.Contains(data, []byte("offline")):
+			return errors.New("scanner offline")
+		case bytes.Contains(data, []byte("bare")):
+			return ErrUploadRejected
+		}
+		return nil
+	})
+	options := &UploadOptions{Scanner: scanner, Files: []UploadRule{{Field: "document", MaxFiles: 3}}}
+
+	tests := []struct {
+		name       string
+		contents   [][]byte
+		wantStatus int
+		wantErrors []ValidationErrorDetail
+	}{
+		{name: "clean", contents: [][]byte{[]byte("quarterly report")}},
+		{
+			name:       "rejected",
+			contents:   [][]byte{[]byte("clean"), eicar, []byte("bare")},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []ValidationErrorDetail{
+				{Field: "document", Message: "malware detected: Eicar-Test-Signature"},
+				{Field: "document", Message: "upload rejected"},
+			},
+		},
+		{name: "scanner failure", contents: [][]byte{[]byte("offline")}, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var parts []uploadPart
+			for _, content := range tt.contents {
+				parts = append(parts, uploadPart{field: "document", filename: "doc.txt", contentType: "text/plain", content: content})
+			}
+			rec := httptest.NewRecorder()
+			uploads, err := ParseUploads(rec, newUploadRequest(t, parts...), options)
+
+			if tt.wantStatus == 0 {
+				if err != nil || uploads.File("document") == nil {
+					t.Fatalf("expected a clean upload, got %v (%s)", err, rec.Body.String())
+				}
+				return
+			}
+			if err == nil || rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d and an error, got %d, %v", tt.wantStatus, rec.Code, err)
+			}
+			if tt.wantErrors == nil {
+				return
+			}
+			var problem ProblemDetails
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Title != "Upload Rejected" {
+				t.Fatalf("expected Upload Rejected problem, got %+v", problem)
+			}
+			encoded, _ := json.Marshal(problem.Extensions["errors"])
+			want, _ := json.Marshal(tt.wantErrors)
+			if string(encoded) != string(want) {
+				t.Fatalf("expected errors %s, got %s", want, encoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type uploadPart struct {
+	field       string
+	filename    string
+	contentType string
+	content     []byte
+}
+
+func newUploadRequest(t *testing.T, parts ...uploadPart) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	_ = writer.WriteField("title", "holiday")
+	for _, part := range parts {
+		header := make(map[string][]string)
+		header["Content-Disposition"] = []string{`form-data; name="` + part.field + `"; filename="` + part.filename + `"`}
+		header["Content-Type"] = []string{part.contentType}
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			t.Fatalf("create part: %v", err)
+		}
+		_, _ = w.Write(part.content)
+	}
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/photos", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func TestParseUploads(t *testing.T) {
+	t.Parallel()
+
+	rules := &UploadOptions{Files: []UploadRule{
+		{Field: "photo", Required: true, MaxBytes: 4096, AllowedTypes: []string{"image/*"}, MaxWidth: 64, MaxHeight: 32},
+		{Field: "attachments", MaxFiles: 2, AllowedTypes: []string{"application/pdf", "text/plain"}},
+	}}
+	smallPNG := testPNG(t, 40, 20)
+	pdf := []byte("%PDF-1.7\n...")
+
+	tests := []struct {
+		name       string
+		parts      []uploadPart
+		wantStatus int
+		wantErrors []ValidationErrorDetail
+	}{
+		{
+			name: "valid",
+			parts: []uploadPart{
+				{field: "photo", filename: "a.png", contentType: "image/png", content: smallPNG},
+				{field: "attachments", filename: "a.pdf", contentType: "application/pdf", content: pdf},
+			},
+		},
+		{
+			name:       "missing required",
+			parts:      []uploadPart{{field: "attachments", filename: "a.pdf", contentType: "application/pdf", content: pdf}},
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationErrorDetail{{Field: "photo", Message: "file is required"}},
+		},
+		{
+			name:       "disguised type",
+			parts:      []uploadPart{{field: "photo", filename: "a.png", contentType: "image/png", content: pdf}},
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationErrorDetail{{Field: "photo", Message: "file type application/pdf is not allowed"}},
+		},
+		{
+			name:       "too wide",
+			parts:      []uploadPart{{field: "photo", filename: "a.png", contentType: "image/png", content: testPNG(t, 80, 10)}},
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationErrorDetail{{Field: "photo", Message: "image must be at most 64 pixels wide"}},
+		},
+		{
+			// The package links no GIF decoder, so the image cannot be measured.
+			name:       "unregistered format",
+			parts:      []uploadPart{{field: "photo", filename: "a.gif", contentType: "image/gif", content: []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;")}},
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationErrorDetail{{Field: "photo", Message: "image dimensions could not be read"}},
+		},
+		{
+			name:       "too large",
+			parts:      []uploadPart{{field: "photo", filename: "a.png", contentType: "image/png", content: append(append([]byte(nil), smallPNG...), make([]byte, 5000)...)}},
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationErrorDetail{{Field: "photo", Message: "file exceeds 4096 bytes"}},
+		},
+		{
+			name: "too many files",
+			parts: []uploadPart{
+				{field: "photo", filename: "a.png", contentType: "image/png", content: smallPNG},
+				{field: "attachments", filename: "1.txt", contentType: "text/plain", content: []byte("one")},
+				{field: "attachments", filename: "2.txt", contentType: "text/plain", content: []byte("two")},
+				{field: "attachments", filename: "3.txt", contentType: "text/plain", content: []byte("three")},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationErrorDetail{{Field: "attachments", Message: "at most 2 files are allowed"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := newUploadRequest(t, tt.parts...)
+			rec := httptest.NewRecorder()
+			uploads, err := ParseUploads(rec, req, rules)
+
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v (%s)", err, rec.Body.String())
+				}
+				photo := uploads.File("photo")
+				if photo == nil || photo.ContentType != "image/png" || photo.Width != 40 || photo.Height != 20 || photo.Filename != "a.png" {
+					t.Fatalf("unexpected photo: %+v", photo)
+				}
+				if uploads.File("attachments").ContentType != "application/pdf" {
+					t.Fatalf("unexpected attachment: %+v", uploads.File("attachments"))
+				}
+				if req.FormValue("title") != "holiday" {
+					t.Fatalf("expected form values to stay available, got %q", req.FormValue("title"))
+				}
+				return
+			}
+
+			if err == nil || rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d and an error, got %d, %v", tt.wantStatus, rec.Code, err)
+			}
+			var problem ProblemDetails
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			encoded, _ := json.Marshal(problem.Extensions["errors"])
+			want, _ := json.Marshal(tt.wantErrors)
+			if string(encoded) != string(want) {
+				t.Fatalf("expected errors %s, got %s", want, encoded)
+			}
+		})
+	}
+}
+
+func TestParseUploadsRejectsRequests(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/photos", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := ParseUploads(rec, req, nil); err == nil || rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415, got %d, %v", rec.Code, err)
+	}
+
+	rec = httptest.NewRecorder()
+	req = newUploadRequest(t, uploadPart{field: "photo", filename: "a.bin", contentType: "application/octet-stream", content: make([]byte, 2048)})
+	if _, err := ParseUploads(rec, req, &UploadOptions{MaxBodyBytes: 1024}); err == nil || rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d, %v", rec.Code, err)
+	}
+}
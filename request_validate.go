@@ -1,12 +1,18 @@
 package httpsuite
 
-import "sync"
+import (
+	"context"
+	"net/http"
+	"sync"
+)
 
 var (
 	defaultValidatorMu sync.RWMutex
 	defaultValidator   Validator
 )
 
+type validatorContextKey struct{}
+
 // ValidateRequest applies a validator without writing HTTP responses.
 func ValidateRequest(request any, validator Validator) *ProblemDetails {
 	if validator == nil {
@@ -15,6 +21,26 @@ func ValidateRequest(request any, validator Validator) *ProblemDetails {
 	return validator.Validate(request)
 }
 
+// ValidateRequestContext applies a validator with the request context when the
+// validator supports it, without writing HTTP responses.
+func ValidateRequestContext(ctx context.Context, request any, validator Validator) *ProblemDetails {
+	if contextual, ok := validator.(ContextValidator); ok {
+		return contextual.ValidateContext(ctx, request)
+	}
+	return ValidateRequest(request, validator)
+}
+
+func validateParsedRequest(r *http.Request, request any, validator Validator) *ProblemDetails {
+	if localized, ok := validator.(LocalizedValidator); ok {
+		acceptLanguage := r.Header.Get("Accept-Language")
+		if locale := Locale(r.Context()); locale != "" {
+			acceptLanguage = locale
+		}
+		return localized.ValidateLocalized(r.Context(), request, acceptLanguage)
+	}
+	return ValidateRequestContext(r.Context(), request, validator)
+}
+
 // SetValidator configures the package-level default validator used by ParseRequest.
 func SetValidator(v Validator) {
 	defaultValidatorMu.Lock()
@@ -33,3 +59,24 @@ func DefaultValidator() Validator {
 	defer defaultValidatorMu.RUnlock()
 	return defaultValidator
 }
+
+// WithValidator returns a context whose ParseRequest calls use v instead of the
+// package-level default validator. ParseOptions.Validator still takes precedence.
+func WithValidator(ctx context.Context, v Validator) context.Context {
+	return context.WithValue(ctx, validatorContextKey{}, v)
+}
+
+// UseValidator returns middleware that scopes v to every request it wraps, so
+// API groups can use isolated validators with their own rules.
+func UseValidator(v Validator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithValidator(r.Context(), v)))
+		})
+	}
+}
+
+func validatorFromContext(ctx context.Context) (Validator, bool) {
+	v, ok := ctx.Value(validatorContextKey{}).(Validator)
+	return v, ok
+}
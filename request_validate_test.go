@@ -1,7 +1,10 @@
 package httpsuite
 
 import (
+	"context"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -100,3 +103,139 @@ func TestDefaultValidatorConcurrentAccess(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+type localizedStubValidator struct {
+	acceptLanguage *string
+}
+
+func (s localizedStubValidator) Validate(any) *ProblemDetails {
+	return NewProblemDetails(http.StatusBadRequest, "", "Validation Error", "unlocalized")
+}
+
+func (s localizedStubValidator) ValidateLocalized(_ context.Context, _ any, acceptLanguage string) *ProblemDetails {
+	*s.acceptLanguage = acceptLanguage
+	return NewProblemDetails(http.StatusBadRequest, "", "Validation Error", "localized")
+}
+
+func TestParseRequestUsesLocalizedValidator(t *testing.T) {
+	t.Parallel()
+
+	var acceptLanguage string
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"Ada"}`))
+	req.Header.Set("Accept-Language", "pt-BR")
+	w := httptest.NewRecorder()
+
+	_, err := ParseRequest[*testRequest](w, req, nil, &ParseOptions{
+		Validator: localizedStubValidator{acceptLanguage: &acceptLanguage},
+	})
+	if err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+	if acceptLanguage != "pt-BR" {
+		t.Fatalf("expected Accept-Language to reach the validator, got %q", acceptLanguage)
+	}
+	if !strings.Contains(w.Body.String(), "localized") {
+		t.Fatalf("expected localized problem, got %s", w.Body.String())
+	}
+}
+
+func TestUseValidatorScopesValidator(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	SetValidator(stubValidator{})
+	scoped := stubValidator{problem: NewProblemDetails(http.StatusUnprocessableEntity, "", "Scoped", "")}
+
+	handler := UseValidator(scoped)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ParseRequest[*testRequest](w, r, nil, nil)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"Ada"}`)))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected scoped validator status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"Ada"}`))
+	req = req.WithContext(WithValidator(req.Context(), scoped))
+	if _, err := ParseRequest[*testRequest](w, req, nil, &ParseOptions{Validator: stubValidator{}}); err != nil {
+		t.Fatalf("expected ParseOptions.Validator to take precedence, got %v", err)
+	}
+}
+
+type tenantKey struct{}
+
+type contextStubValidator struct{}
+
+func (contextStubValidator) Validate(any) *ProblemDetails {
+	return nil
+}
+
+func (contextStubValidator) ValidateContext(ctx context.Context, _ any) *ProblemDetails {
+	if ctx.Value(tenantKey{}) == nil {
+		return NewProblemDetails(http.StatusBadRequest, "", "Missing Tenant", "")
+	}
+	return nil
+}
+
+func TestParseRequestUsesContextValidator(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"Ada"}`))
+	w := httptest.NewRecorder()
+	if _, err := ParseRequest[*testRequest](w, req, nil, &ParseOptions{Validator: contextStubValidator{}}); err == nil {
+		t.Fatal("expected context validator to reject request without tenant")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"Ada"}`))
+	req = req.WithContext(context.WithValue(req.Context(), tenantKey{}, "acme"))
+	w = httptest.NewRecorder()
+	if _, err := ParseRequest[*testRequest](w, req, nil, &ParseOptions{Validator: contextStubValidator{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseRequestConfiguredValidationStatus(t *testing.T) {
+	t.Parallel()
+
+	problem := NewProblemDetails(http.StatusBadRequest, "", "Validation Error", "")
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   int
+	}{
+		{name: "validator status by default", body: `{"name":"Ada"}`, want: http.StatusBadRequest},
+		{name: "configured status", status: http.StatusUnprocessableEntity, body: `{"name":"Ada"}`, want: http.StatusUnprocessableEntity},
+		{name: "malformed body stays 400", status: http.StatusUnprocessableEntity, body: `{`, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			_, _ = ParseRequest[*testRequest](w, req, nil, &ParseOptions{
+				Validator:        stubValidator{problem: problem},
+				ValidationStatus: tt.status,
+			})
+			if w.Code != tt.want {
+				t.Fatalf("expected status %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+	if problem.Status != http.StatusBadRequest {
+		t.Fatalf("expected validator problem to stay unchanged, got %d", problem.Status)
+	}
+
+	unavailable := NewProblemDetails(http.StatusServiceUnavailable, "", "Validator Unavailable", "")
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"Ada"}`))
+	w := httptest.NewRecorder()
+	_, _ = ParseRequest[*testRequest](w, req, nil, &ParseOptions{
+		Validator:        stubValidator{problem: unavailable},
+		ValidationStatus: http.StatusUnprocessableEntity,
+	})
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected validator server errors to keep status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
@@ -0,0 +1,236 @@
+package httpsuite
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SelfValidator is implemented by value objects such as UUIDs, money amounts,
+// or phone numbers that check their own invariants. ParseRequest calls
+// Validate on every request field whose type implements it and reports
+// failures under the field's JSON path, e.g. "items[1].price".
+type SelfValidator interface {
+	Validate() error
+}
+
+var (
+	selfValidatorType   = reflect.TypeOf((*SelfValidator)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// ValidateValues calls Validate on every SelfValidator field reachable from
+// request and returns the failures as ValidationErrors, or nil. The request
+// itself is not validated, only the values it contains.
+func ValidateValues(request any) error {
+	if isRequestNil(request) {
+		return nil
+	}
+	value := reflect.ValueOf(request)
+	if !metadataFor(value.Type()).selfValidator {
+		return nil
+	}
+
+	var details ValidationErrors
+	walkValues(value, "", true, make(map[uintptr]bool), &details)
+	if len(details) == 0 {
+		return nil
+	}
+	return details
+}
+
+func walkValues(value reflect.Value, path string, root bool, visited map[uintptr]bool, details *ValidationErrors) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		if value.Kind() == reflect.Pointer {
+			if visited[value.Pointer()] {
+				return
+			}
+			visited[value.Pointer()] = true
+		}
+		if !root && validateValue(value, path, details) {
+			return
+		}
+		value = value.Elem()
+	}
+	if !root {
+		if value.CanAddr() && validateValue(value.Addr(), path, details) {
+			return
+		}
+		if validateValue(value, path, details) {
+			return
+		}
+	}
+	metadata := metadataFor(value.Type())
+	if !metadata.selfValidator {
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		for _, field := range metadata.fields {
+			if !field.json {
+				continue
+			}
+			fieldPath := joinFieldPath(path, field.name)
+			if field.inline {
+				fieldPath = path
+			}
+			walkValues(value.Field(field.index), fieldPath, false, visited, details)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			walkValues(value.Index(i), path+"["+strconv.Itoa(i)+"]", false, visited, details)
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			walkValues(iter.Value(), path+"["+mapKeyString(iter.Key())+"]", false, visited, details)
+		}
+	}
+}
+
+// validateValue runs Validate when value implements SelfValidator and reports
+// whether it did, so value objects are not walked any further.
+func validateValue(value reflect.Value, path string, details *ValidationErrors) bool {
+	if !value.Type().Implements(selfValidatorType) || !value.CanInterface() {
+		return false
+	}
+	err := value.Interface().(SelfValidator).Validate()
+	if err == nil {
+		return true
+	}
+
+	var nested ValidationErrors
+	if errors.As(err, &nested) {
+		for _, detail := range nested {
+			*details = append(*details, ValidationErrorDetail{Field: joinFieldPath(path, detail.Field), Message: detail.Message})
+		}
+		return true
+	}
+	*details = append(*details, ValidationErrorDetail{Field: path, Message: err.Error()})
+	return true
+}
+
+// locateValueDecodeError finds the JSON path of the value whose UnmarshalJSON
+// or UnmarshalText rejected data. encoding/json returns those errors without
+// any field context, so the payload is decoded again one field at a time.
+func locateValueDecodeError(t reflect.Type, data []byte, path string) (string, error, bool) {
+	if t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType) ||
+		reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		if err := json.Unmarshal(data, reflect.New(t).Interface()); err != nil {
+			return path, err, true
+		}
+		return "", nil, false
+	}
+	metadata := metadataFor(t)
+	if !metadata.valueUnmarshaler {
+		return "", nil, false
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return locateValueDecodeError(t.Elem(), data, path)
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			return "", nil, false
+		}
+		for _, field := range metadata.fields {
+			if !field.json {
+				continue
+			}
+			if field.inline {
+				if found, err, ok := locateValueDecodeError(field.typ, data, path); ok {
+					return found, err, true
+				}
+				continue
+			}
+			raw, ok := lookupJSONField(fields, field.name)
+			if !ok {
+				continue
+			}
+			if found, err, ok := locateValueDecodeError(field.typ, raw, joinFieldPath(path, field.name)); ok {
+				return found, err, true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return "", nil, false
+		}
+		for i, raw := range items {
+			if found, err, ok := locateValueDecodeError(t.Elem(), raw, path+"["+strconv.Itoa(i)+"]"); ok {
+				return found, err, true
+			}
+		}
+	case reflect.Map:
+		var items map[string]json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return "", nil, false
+		}
+		for key, raw := range items {
+			if found, err, ok := locateValueDecodeError(t.Elem(), raw, path+"["+key+"]"); ok {
+				return found, err, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// lookupJSONField matches keys the way encoding/json does: exact match first,
+// then case-insensitively.
+func lookupJSONField(fields map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if raw, ok := fields[name]; ok {
+		return raw, true
+	}
+	for key, raw := range fields {
+		if strings.EqualFold(key, name) {
+			return raw, true
+		}
+	}
+	return nil, false
+}
+
+// scanValueType reports whether t, or any type reachable from it, implements
+// iface directly or through a pointer receiver.
+func scanValueType(t reflect.Type, iface reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+
+	if t.Implements(iface) || (t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(iface)) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return scanValueType(t.Elem(), iface, visiting)
+	case reflect.Interface:
+		return true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.IsExported() && scanValueType(field.Type, iface, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func joinFieldPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	if name == "" {
+		return parent
+	}
+	return parent + "." + name
+}
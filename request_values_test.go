@@ -0,0 +1,144 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testMoney struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m testMoney) Validate() error {
+	if m.Amount < 0 {
+		return errors.New("amount must not be negative")
+	}
+	return nil
+}
+
+type testPhone string
+
+func (p *testPhone) UnmarshalText(text []byte) error {
+	if !strings.HasPrefix(string(text), "+") {
+		return errors.New("phone number must start with +")
+	}
+	*p = testPhone(text)
+	return nil
+}
+
+type testLineItem struct {
+	Price testMoney `json:"price"`
+}
+
+type valueObjectRequest struct {
+	Total testMoney      `json:"total"`
+	Items []testLineItem `json:"items"`
+	Phone testPhone      `json:"phone"`
+}
+
+func TestValidateValues(t *testing.T) {
+	t.Parallel()
+
+	request := &valueObjectRequest{
+		Total: testMoney{Amount: -1},
+		Items: []testLineItem{{Price: testMoney{Amount: 5}}, {Price: testMoney{Amount: -2}}},
+	}
+
+	var details ValidationErrors
+	if !errors.As(ValidateValues(request), &details) {
+		t.Fatal("expected validation errors")
+	}
+	if len(details) != 2 {
+		t.Fatalf("expected 2 errors, got %#v", details)
+	}
+	if details[0].Field != "total" || details[1].Field != "items[1].price" {
+		t.Fatalf("unexpected field paths: %#v", details)
+	}
+	if details[0].Message != "amount must not be negative" {
+		t.Fatalf("unexpected message %q", details[0].Message)
+	}
+
+	if err := ValidateValues(&valueObjectRequest{}); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+	if err := ValidateValues((*valueObjectRequest)(nil)); err != nil {
+		t.Fatalf("expected nil request to be ignored, got %v", err)
+	}
+}
+
+func TestDecodeRequestBodyLocatesValueErrors(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(`{"total":{"amount":1},"phone":"555-0100"}`))
+	_, err := DecodeRequestBody[*valueObjectRequest](req, defaultMaxBodyBytes)
+
+	var decodeErr *BodyDecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Kind != BodyDecodeErrorInvalidValue {
+		t.Fatalf("expected invalid value error, got %v", err)
+	}
+	if decodeErr.Field != "phone" {
+		t.Fatalf("expected field phone, got %q", decodeErr.Field)
+	}
+	if decodeErr.Error() != "field phone is invalid: phone number must start with +" {
+		t.Fatalf("unexpected error message %q", decodeErr.Error())
+	}
+}
+
+func TestParseRequestReportsValueObjectErrors(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	tests := []struct {
+		name      string
+		body      string
+		wantTitle string
+		wantField string
+	}{
+		{
+			name:      "validate error",
+			body:      `{"items":[{"price":{"amount":-3}}],"phone":"+15550100"}`,
+			wantTitle: "Validation Error",
+			wantField: "items[0].price",
+		},
+		{
+			name:      "unmarshal text error",
+			body:      `{"phone":"555-0100"}`,
+			wantTitle: "Invalid Request",
+			wantField: "phone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			if _, err := ParseRequest[*valueObjectRequest](w, req, nil, nil); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+
+			var problem struct {
+				Title  string                  `json:"title"`
+				Errors []ValidationErrorDetail `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Title != tt.wantTitle {
+				t.Fatalf("expected title %q, got %q", tt.wantTitle, problem.Title)
+			}
+			if len(problem.Errors) != 1 || problem.Errors[0].Field != tt.wantField {
+				t.Fatalf("expected one error for %q, got %#v", tt.wantField, problem.Errors)
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ func Reply() *ReplyBuilder {
 }
 
 // SendResponse sends a JSON response to the client, supporting both success and error scenarios.
+// Fields tagged `scope` are removed; use Respond(data).Scoped(ctx) to show
+// them to callers with the scopes.
 func SendResponse[T any](w http.ResponseWriter, code int, data T, problem *ProblemDetails, meta any) {
-	writeResponse(w, code, data, problem, meta, nil)
+	(&ResponseBuilder[T]{code: code, data: data, problem: problem, meta: meta}).Write(w)
 }
@@ -1,6 +1,7 @@
 package httpsuite
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -15,3 +16,36 @@ func BenchmarkSendResponse(b *testing.B) {
 		SendResponse(w, http.StatusOK, payload, nil, nil)
 	}
 }
+
+func BenchmarkSendResponseLarge(b *testing.B) {
+	var order benchmarkOrder
+	if err := json.Unmarshal(benchmarkPayload(b, 500), &order); err != nil {
+		b.Fatalf("unmarshal payload: %v", err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		SendResponse(w, http.StatusOK, order, nil, NewPageMeta(1, 10, 500))
+	}
+}
+
+func BenchmarkProblemResponse(b *testing.B) {
+	problem := NewNotFoundProblem("order 7 not found")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		ProblemResponse(w, problem)
+	}
+}
+
+func BenchmarkStaticProblem(b *testing.B) {
+	problem := NewStaticProblem(NewNotFoundProblem("order 7 not found"), nil)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		problem.Write(w)
+	}
+}
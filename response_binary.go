@@ -0,0 +1,248 @@
+package httpsuite
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultMaxRanges = 16
+
+var (
+	// ErrRangeNotSatisfiable reports a Range header none of whose ranges
+	// overlap the content.
+	ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+
+	errInvalidRange = errors.New("invalid range")
+)
+
+// ByteRange is a resolved byte range of a representation.
+type ByteRange struct {
+	Start  int64
+	Length int64
+}
+
+// ContentRange returns the Content-Range value of the range for content of
+// the given size, e.g. "bytes 0-499/1234".
+func (r ByteRange) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.Start+r.Length-1, size)
+}
+
+// BinaryOptions configures SendBinary.
+type BinaryOptions struct {
+	// ContentType defaults to application/octet-stream.
+	ContentType string
+	// Filename, when set, makes the response a download with that name.
+	Filename string
+	// ETag and LastModified validate If-Range. Only a strong ETag can match.
+	ETag         string
+	LastModified time.Time
+	// MaxRanges caps the ranges of one request; requests asking for more get
+	// the whole content. Defaults to 16.
+	MaxRanges int
+}
+
+// ParseRange parses an RFC 7233 Range header such as "bytes=0-499,-500"
+// against content of the given size. Unsatisfiable ranges are dropped, and
+// ErrRangeNotSatisfiable is returned when none remain. Other errors mean the
+// header is malformed and should be ignored.
+func ParseRange(header string, size int64) ([]ByteRange, error) {
+	specs, ok := strings.CutPrefix(header, "bytes=")
+	if !ok {
+		return nil, errInvalidRange
+	}
+	var ranges []ByteRange
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		first, last, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, errInvalidRange
+		}
+		first, last = strings.TrimSpace(first), strings.TrimSpace(last)
+
+		var byteRange ByteRange
+		if first == "" {
+			// A suffix range: the last N bytes.
+			suffix, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || suffix < 0 {
+				return nil, errInvalidRange
+			}
+			if suffix == 0 || size == 0 {
+				continue
+			}
+			suffix = min(suffix, size)
+			byteRange = ByteRange{Start: size - suffix, Length: suffix}
+		} else {
+			start, err := strconv.ParseInt(first, 10, 64)
+			if err != nil || start < 0 {
+				return nil, errInvalidRange
+			}
+			end := size - 1
+			if last != "" {
+				if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+					return nil, errInvalidRange
+				}
+			}
+			if start >= size {
+				continue
+			}
+			end = min(end, size-1)
+			byteRange = ByteRange{Start: start, Length: end - start + 1}
+		}
+		ranges = append(ranges, byteRange)
+	}
+	if len(ranges) == 0 {
+		return nil, ErrRangeNotSatisfiable
+	}
+	return ranges, nil
+}
+
+// SendBinary writes content with Accept-Ranges support: GET requests with a
+// Range header receive 206 Partial Content, as multipart/byteranges when
+// several ranges are asked for, and unsatisfiable ranges get a 416 problem.
+// If-Range is honoured against opts.ETag and opts.LastModified, so resumed
+// downloads restart from scratch when the content changed. Malformed Range
+// headers are ignored.
+func SendBinary(w http.ResponseWriter, r *http.Request, content io.ReadSeeker, opts *BinaryOptions) {
+	options := BinaryOptions{ContentType: "application/octet-stream", MaxRanges: defaultMaxRanges}
+	if opts != nil {
+		options.Filename = opts.Filename
+		options.ETag = opts.ETag
+		options.LastModified = opts.LastModified
+		if opts.ContentType != "" {
+			options.ContentType = opts.ContentType
+		}
+		if opts.MaxRanges > 0 {
+			options.MaxRanges = opts.MaxRanges
+		}
+	}
+
+	size, err := content.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = content.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		log.Printf("Failed to determine content size: %v", err)
+		sendRequestProblem(w, r, http.StatusInternalServerError, internalErrorProblem(r.Context()))
+		return
+	}
+
+	header := w.Header()
+	header.Set("Accept-Ranges", "bytes")
+	if options.ETag != "" {
+		header.Set("ETag", options.ETag)
+	}
+	if !options.LastModified.IsZero() {
+		header.Set("Last-Modified", options.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if options.Filename != "" {
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": options.Filename}))
+	}
+
+	var ranges []ByteRange
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" && r.Method == http.MethodGet && ifRangeMatches(r, options) {
+		parsed, err := ParseRange(rangeHeader, size)
+		switch {
+		case errors.Is(err, ErrRangeNotSatisfiable):
+			header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+			problems := resolveProblemConfig(r.Context(), nil)
+			sendRequestProblem(w, r, http.StatusRequestedRangeNotSatisfiable, NewProblemDetails(
+				http.StatusRequestedRangeNotSatisfiable,
+				problems.TypeURL("range_not_satisfiable_error"),
+				"Range Not Satisfiable",
+				fmt.Sprintf("no requested range overlaps the %d bytes of content", size),
+			))
+			return
+		case err == nil && len(parsed) <= options.MaxRanges && rangesLength(parsed) <= size:
+			ranges = parsed
+		}
+	}
+
+	switch len(ranges) {
+	case 0:
+		header.Set("Content-Type", options.ContentType)
+		header.Set("Content-Length", strconv.FormatInt(size, 10))
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodHead {
+			copyBinary(w, content, size)
+		}
+	case 1:
+		header.Set("Content-Type", options.ContentType)
+		header.Set("Content-Range", ranges[0].ContentRange(size))
+		header.Set("Content-Length", strconv.FormatInt(ranges[0].Length, 10))
+		w.WriteHeader(http.StatusPartialContent)
+		if _, err := content.Seek(ranges[0].Start, io.SeekStart); err != nil {
+			log.Printf("Failed to seek content: %v", err)
+			return
+		}
+		copyBinary(w, content, ranges[0].Length)
+	default:
+		parts := multipart.NewWriter(w)
+		header.Set("Content-Type", "multipart/byteranges; boundary="+parts.Boundary())
+		w.WriteHeader(http.StatusPartialContent)
+		for _, byteRange := range ranges {
+			part, err := parts.CreatePart(textproto.MIMEHeader{
+				"Content-Type":  {options.ContentType},
+				"Content-Range": {byteRange.ContentRange(size)},
+			})
+			if err != nil {
+				log.Printf("Failed to write response body (status=%d): %v", http.StatusPartialContent, err)
+				return
+			}
+			if _, err := content.Seek(byteRange.Start, io.SeekStart); err != nil {
+				log.Printf("Failed to seek content: %v", err)
+				return
+			}
+			if !copyBinary(part, content, byteRange.Length) {
+				return
+			}
+		}
+		if err := parts.Close(); err != nil {
+			log.Printf("Failed to write response body (status=%d): %v", http.StatusPartialContent, err)
+		}
+	}
+}
+
+// ifRangeMatches reports whether a Range header applies: there is no
+// If-Range, or it names the current strong ETag or exact Last-Modified time.
+func ifRangeMatches(r *http.Request, options BinaryOptions) bool {
+	ifRange := strings.TrimSpace(r.Header.Get("If-Range"))
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) {
+		return options.ETag != "" && !strings.HasPrefix(options.ETag, "W/") && ifRange == options.ETag
+	}
+	if strings.HasPrefix(ifRange, "W/") {
+		return false
+	}
+	when, err := http.ParseTime(ifRange)
+	return err == nil && !options.LastModified.IsZero() && options.LastModified.Truncate(time.Second).Equal(when)
+}
+
+func rangesLength(ranges []ByteRange) int64 {
+	var total int64
+	for _, byteRange := range ranges {
+		total += byteRange.Length
+	}
+	return total
+}
+
+func copyBinary(w io.Writer, content io.Reader, length int64) bool {
+	if _, err := io.CopyN(w, content, length); err != nil {
+		log.Printf("Failed to write binary response body: %v", err)
+		return false
+	}
+	return true
+}
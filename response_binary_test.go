@@ -0,0 +1,169 @@
+package httpsuite
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		header  string
+		want    []ByteRange
+		wantErr error
+	}{
+		{header: "bytes=0-4", want: []ByteRange{{Start: 0, Length: 5}}},
+		{header: "bytes=5-", want: []ByteRange{{Start: 5, Length: 5}}},
+		{header: "bytes=-3", want: []ByteRange{{Start: 7, Length: 3}}},
+		{header: "bytes=-30", want: []ByteRange{{Start: 0, Length: 10}}},
+		{header: "bytes=8-20", want: []ByteRange{{Start: 8, Length: 2}}},
+		{header: "bytes=0-1, 4-5", want: []ByteRange{{Start: 0, Length: 2}, {Start: 4, Length: 2}}},
+		{header: "bytes=0-1,20-30", want: []ByteRange{{Start: 0, Length: 2}}},
+		{header: "bytes=20-30", wantErr: ErrRangeNotSatisfiable},
+		{header: "bytes=-0", wantErr: ErrRangeNotSatisfiable},
+		{header: "bytes=5-1", wantErr: errInvalidRange},
+		{header: "bytes=a-b", wantErr: errInvalidRange},
+		{header: "bytes=1", wantErr: errInvalidRange},
+		{header: "items=0-1", wantErr: errInvalidRange},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRange(tt.header, 10)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("ParseRange(%q): expected error %v, got %v", tt.header, tt.wantErr, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("ParseRange(%q): expected %v, got %v", tt.header, tt.want, got)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("ParseRange(%q): expected %v, got %v", tt.header, tt.want, got)
+			}
+		}
+	}
+}
+
+func TestSendBinary(t *testing.T) {
+	t.Parallel()
+
+	const content = "0123456789"
+	modified := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	options := &BinaryOptions{ContentType: "video/mp4", ETag: `"v1"`, LastModified: modified, MaxRanges: 3}
+
+	tests := []struct {
+		name         string
+		method       string
+		headers      map[string]string
+		wantStatus   int
+		wantBody     string
+		wantRange    string
+		wantType     string
+		wantMultiple []string
+	}{
+		{name: "full", wantStatus: http.StatusOK, wantBody: content, wantType: "video/mp4"},
+		{name: "head", method: http.MethodHead, headers: map[string]string{"Range": "bytes=0-1"}, wantStatus: http.StatusOK, wantType: "video/mp4"},
+		{name: "single", headers: map[string]string{"Range": "bytes=2-5"}, wantStatus: http.StatusPartialContent, wantBody: "2345", wantRange: "bytes 2-5/10", wantType: "video/mp4"},
+		{name: "resume", headers: map[string]string{"Range": "bytes=7-"}, wantStatus: http.StatusPartialContent, wantBody: "789", wantRange: "bytes 7-9/10"},
+		{name: "multiple", headers: map[string]string{"Range": "bytes=0-1,-2"}, wantStatus: http.StatusPartialContent, wantType: "multipart/byteranges", wantMultiple: []string{"01", "89"}},
+		{name: "unsatisfiable", headers: map[string]string{"Range": "bytes=10-"}, wantStatus: http.StatusRequestedRangeNotSatisfiable, wantRange: "bytes */10", wantType: problemContentType, wantBody: "Range Not Satisfiable"},
+		{name: "malformed", headers: map[string]string{"Range": "bytes=x"}, wantStatus: http.StatusOK, wantBody: content},
+		{name: "too many ranges", headers: map[string]string{"Range": "bytes=0-0,2-2,4-4,6-6"}, wantStatus: http.StatusOK, wantBody: content},
+		{name: "overlapping ranges exceed size", headers: map[string]string{"Range": "bytes=0-9,0-9"}, wantStatus: http.StatusOK, wantBody: content},
+		{name: "if-range etag match", headers: map[string]string{"Range": "bytes=0-1", "If-Range": `"v1"`}, wantStatus: http.StatusPartialContent, wantBody: "01", wantRange: "bytes 0-1/10"},
+		{name: "if-range etag mismatch", headers: map[string]string{"Range": "bytes=0-1", "If-Range": `"v0"`}, wantStatus: http.StatusOK, wantBody: content},
+		{name: "if-range weak etag", headers: map[string]string{"Range": "bytes=0-1", "If-Range": `W/"v1"`}, wantStatus: http.StatusOK, wantBody: content},
+		{name: "if-range date match", headers: map[string]string{"Range": "bytes=0-1", "If-Range": modified.Format(http.TimeFormat)}, wantStatus: http.StatusPartialContent, wantBody: "01", wantRange: "bytes 0-1/10"},
+		{name: "if-range date mismatch", headers: map[string]string{"Range": "bytes=0-1", "If-Range": modified.Add(-time.Hour).Format(http.TimeFormat)}, wantStatus: http.StatusOK, wantBody: content},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/media/7", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			SendBinary(rec, req, strings.NewReader(content), options)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if rec.Header().Get("Accept-Ranges") != "bytes" {
+				t.Fatalf("expected Accept-Ranges bytes, got %q", rec.Header().Get("Accept-Ranges"))
+			}
+			if rec.Header().Get("Content-Range") != tt.wantRange {
+				t.Fatalf("expected Content-Range %q, got %q", tt.wantRange, rec.Header().Get("Content-Range"))
+			}
+			if !strings.HasPrefix(rec.Header().Get("Content-Type"), tt.wantType) {
+				t.Fatalf("expected Content-Type %q, got %q", tt.wantType, rec.Header().Get("Content-Type"))
+			}
+			if tt.wantMultiple != nil {
+				assertByteRanges(t, rec, tt.wantMultiple)
+				return
+			}
+			if tt.wantStatus == http.StatusRequestedRangeNotSatisfiable {
+				if !strings.Contains(rec.Body.String(), tt.wantBody) {
+					t.Fatalf("expected body to contain %q, got %q", tt.wantBody, rec.Body.String())
+				}
+				return
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Fatalf("expected body %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func assertByteRanges(t *testing.T, rec *httptest.ResponseRecorder, want []string) {
+	t.Helper()
+
+	_, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("parse content type: %v", err)
+	}
+	reader := multipart.NewReader(rec.Body, params["boundary"])
+	for i, body := range want {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+		data, _ := io.ReadAll(part)
+		if string(data) != body || part.Header.Get("Content-Type") != "video/mp4" {
+			t.Fatalf("part %d: expected %q, got %q (%v)", i, body, data, part.Header)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Fatalf("expected %d parts, got more (%v)", len(want), err)
+	}
+}
+
+func TestSendBinaryDownload(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	SendBinary(rec, httptest.NewRequest(http.MethodGet, "/export", nil), strings.NewReader("a,b\n"), &BinaryOptions{Filename: "report 2024.csv"})
+
+	if rec.Header().Get("Content-Type") != "application/octet-stream" {
+		t.Fatalf("expected default content type, got %q", rec.Header().Get("Content-Type"))
+	}
+	if rec.Header().Get("Content-Disposition") != `attachment; filename="report 2024.csv"` {
+		t.Fatalf("unexpected Content-Disposition %q", rec.Header().Get("Content-Disposition"))
+	}
+	if rec.Header().Get("Content-Length") != "4" {
+		t.Fatalf("expected Content-Length 4, got %q", rec.Header().Get("Content-Length"))
+	}
+}
@@ -1,20 +1,30 @@
 package httpsuite
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"reflect"
+)
 
 // ReplyBuilder configures metadata and headers before writing a response.
 type ReplyBuilder struct {
-	meta    any
-	headers http.Header
+	meta     any
+	headers  http.Header
+	preloads []Preload
+	masked   bool
+	scopes   []string
 }
 
 // ResponseBuilder builds and writes HTTP responses declaratively.
 type ResponseBuilder[T any] struct {
-	code    int
-	data    T
-	meta    any
-	problem *ProblemDetails
-	headers http.Header
+	code     int
+	data     T
+	meta     any
+	problem  *ProblemDetails
+	headers  http.Header
+	preloads []Preload
+	masked   bool
+	scopes   []string
 }
 
 // Respond starts a success response builder.
@@ -65,14 +75,35 @@ func (b *ReplyBuilder) Headers(headers http.Header) *ReplyBuilder {
 	return b
 }
 
+// Preload adds preload Link headers for a fluent helper chain.
+func (b *ReplyBuilder) Preload(links ...Preload) *ReplyBuilder {
+	b.preloads = append(b.preloads, links...)
+	return b
+}
+
+// Masked encodes data with fields tagged `mask` partially masked for a
+// fluent helper chain. See Mask.
+func (b *ReplyBuilder) Masked() *ReplyBuilder {
+	b.masked = true
+	return b
+}
+
+// Scoped shows fields tagged `scope` that the caller's scopes in ctx grant,
+// for a fluent helper chain. Without it every tagged field is removed. See
+// FilterScopes.
+func (b *ReplyBuilder) Scoped(ctx context.Context) *ReplyBuilder {
+	b.scopes = ScopesFromContext(ctx)
+	return b
+}
+
 // OK writes a 200 JSON response using the fluent helper configuration.
 func (b *ReplyBuilder) OK(w http.ResponseWriter, data any) {
-	Respond(data).Meta(b.meta).Headers(b.headers).Write(w)
+	Respond(data).Meta(b.meta).Headers(b.headers).Preload(b.preloads...).mask(b.masked).scope(b.scopes).Write(w)
 }
 
 // Created writes a 201 JSON response using the fluent helper configuration.
 func (b *ReplyBuilder) Created(w http.ResponseWriter, data any, location string) {
-	builder := Respond(data).Status(http.StatusCreated).Meta(b.meta).Headers(b.headers)
+	builder := Respond(data).Status(http.StatusCreated).Meta(b.meta).Headers(b.headers).Preload(b.preloads...).mask(b.masked).scope(b.scopes)
 	if location != "" {
 		builder.Header("Location", location)
 	}
@@ -118,7 +149,48 @@ func (b *ResponseBuilder[T]) Headers(headers http.Header) *ResponseBuilder[T] {
 	return b
 }
 
-// Write writes the configured response.
+// Preload adds preload Link headers, pushing links marked Push over HTTP/2.
+// See AddPreloads.
+func (b *ResponseBuilder[T]) Preload(links ...Preload) *ResponseBuilder[T] {
+	b.preloads = append(b.preloads, links...)
+	return b
+}
+
+// Masked encodes the data with fields tagged `mask` partially masked, e.g.
+// `mask:"email"` or `mask:"last4"`. See Mask.
+func (b *ResponseBuilder[T]) Masked() *ResponseBuilder[T] {
+	return b.mask(true)
+}
+
+func (b *ResponseBuilder[T]) mask(masked bool) *ResponseBuilder[T] {
+	b.masked = masked
+	return b
+}
+
+// Scoped shows fields tagged `scope`, e.g. `scope:"admin"`, that the
+// caller's scopes in ctx grant. Without it, or for callers without scopes,
+// every tagged field is removed. See FilterScopes.
+func (b *ResponseBuilder[T]) Scoped(ctx context.Context) *ResponseBuilder[T] {
+	return b.scope(ScopesFromContext(ctx))
+}
+
+func (b *ResponseBuilder[T]) scope(scopes []string) *ResponseBuilder[T] {
+	b.scopes = scopes
+	return b
+}
+
+// Write writes the configured response. Fields tagged `scope` are removed
+// unless Scoped supplied scopes that grant them.
 func (b *ResponseBuilder[T]) Write(w http.ResponseWriter) {
-	writeResponse(w, b.code, b.data, b.problem, b.meta, b.headers)
+	AddPreloads(w, b.preloads...)
+	if !b.masked && (b.problem != nil && b.code >= 400 || !hasScopedFields(reflect.ValueOf(b.data))) {
+		writeResponse(w, b.code, b.data, b.problem, b.meta, b.headers)
+		return
+	}
+	data := redactValue(b.data, redactMode{mask: b.masked, scoped: true, scopes: b.scopes})
+	writeResponse(w, b.code, data, b.problem, b.meta, b.headers)
+	// Response hooks see the typed data rather than its filtered encoding.
+	if hook := responseHookOf(w); hook != nil && hook.payload != nil {
+		hook.payload = b.data
+	}
 }
@@ -1,6 +1,7 @@
 package httpsuite
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -90,3 +91,102 @@ func TestReplyBuilderHelpers(t *testing.T) {
 		}
 	})
 }
+
+type maskedContact struct {
+	Name  string `json:"name"`
+	Email string `json:"email" mask:"email"`
+}
+
+func TestResponseBuilderMasked(t *testing.T) {
+	t.Parallel()
+
+	contact := maskedContact{Name: "Jo", Email: "jo@example.com"}
+	w := httptest.NewRecorder()
+	Respond(contact).Masked().Write(w)
+	if want := `{"data":{"email":"j***@example.com","name":"Jo"}}` + "\n"; w.Body.String() != want {
+		t.Fatalf("expected %s, got %s", want, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	Reply().Masked().Created(w, []maskedContact{contact}, "/contacts/1")
+	if want := `{"data":[{"email":"j***@example.com","name":"Jo"}]}` + "\n"; w.Code != http.StatusCreated || w.Body.String() != want {
+		t.Fatalf("expected masked 201 %s, got %d %s", want, w.Code, w.Body.String())
+	}
+
+	var hooked any
+	handler := OnResponse(func(ctx context.Context, event ResponseEvent) { hooked = event.Response })(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Reply().Masked().OK(w, contact)
+		}),
+	)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if hooked != any(contact) {
+		t.Fatalf("expected response hooks to see the unmasked data, got %#v", hooked)
+	}
+}
+
+type scopedContact struct {
+	Name  string `json:"name"`
+	Email string `json:"email" scope:"admin" mask:"email"`
+}
+
+func TestResponseBuilderScoped(t *testing.T) {
+	t.Parallel()
+
+	contact := scopedContact{Name: "Jo", Email: "jo@example.com"}
+	admin := WithScopes(context.Background(), "admin")
+
+	tests := []struct {
+		name  string
+		write func(http.ResponseWriter)
+		want  string
+	}{
+		{
+			name:  "without scopes",
+			write: func(w http.ResponseWriter) { Respond(contact).Scoped(context.Background()).Write(w) },
+			want:  `{"data":{"name":"Jo"}}`,
+		},
+		{
+			name:  "admin",
+			write: func(w http.ResponseWriter) { Reply().Scoped(admin).OK(w, contact) },
+			want:  `{"data":{"email":"jo@example.com","name":"Jo"}}`,
+		},
+		{
+			name:  "admin masked",
+			write: func(w http.ResponseWriter) { Reply().Scoped(admin).Masked().Created(w, []scopedContact{contact}, "") },
+			want:  `{"data":[{"email":"j***@example.com","name":"Jo"}]}`,
+		},
+		{
+			name:  "without Scoped",
+			write: func(w http.ResponseWriter) { Respond(contact).Write(w) },
+			want:  `{"data":{"name":"Jo"}}`,
+		},
+		{
+			name:  "OK",
+			write: func(w http.ResponseWriter) { OK(w, contact) },
+			want:  `{"data":{"name":"Jo"}}`,
+		},
+		{
+			name:  "SendResponse",
+			write: func(w http.ResponseWriter) { SendResponse(w, http.StatusOK, []scopedContact{contact}, nil, nil) },
+			want:  `{"data":[{"name":"Jo"}]}`,
+		},
+		{
+			name:  "behind an interface",
+			write: func(w http.ResponseWriter) { OK(w, map[string]any{"contact": &contact}) },
+			want:  `{"data":{"contact":{"name":"Jo"}}}`,
+		},
+		{
+			name:  "untagged data keeps its field order",
+			write: func(w http.ResponseWriter) { OK(w, map[string]any{"item": benchmarkItem{SKU: "lamp", Quantity: 1}}) },
+			want:  `{"data":{"item":{"sku":"lamp","quantity":1,"price":0,"tags":null}}}`,
+		},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.write(w)
+		if want := tt.want + "\n"; w.Body.String() != want {
+			t.Fatalf("%s: expected %s, got %s", tt.name, want, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package httpsuite
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheTTL          = time.Minute
+	defaultCacheCapacity     = 1024
+	defaultCacheMaxBodyBytes = 1 << 20
+)
+
+// CachedResponse is a stored GET response. Its fields are exported so external
+// stores such as Redis can serialize it.
+type CachedResponse struct {
+	Status    int         `json:"status"`
+	Header    http.Header `json:"header"`
+	Body      []byte      `json:"body"`
+	ETag      string      `json:"etag"`
+	StoredAt  time.Time   `json:"stored_at"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+// CacheStore persists cached responses. Stores with native expiry, such as
+// Redis, can use ExpiresAt; Cache deletes expired entries it encounters.
+type CacheStore interface {
+	Get(ctx context.Context, key string) (*CachedResponse, bool, error)
+	Set(ctx context.Context, key string, response *CachedResponse) error
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryCacheStore is an in-memory LRU CacheStore.
+type MemoryCacheStore struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type memoryCacheEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+// NewMemoryCacheStore returns an LRU store holding up to capacity responses.
+func NewMemoryCacheStore(capacity int) *MemoryCacheStore {
+	if capacity <= 0 {
+		capacity = defaultCacheCapacity
+	}
+	return &MemoryCacheStore{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns a cached response and marks it as recently used.
+func (s *MemoryCacheStore) Get(_ context.Context, key string) (*CachedResponse, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	s.order.MoveToFront(element)
+	return element.Value.(*memoryCacheEntry).response, true, nil
+}
+
+// Set stores a response, evicting the least recently used one when full.
+func (s *MemoryCacheStore) Set(_ context.Context, key string, response *CachedResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[key]; ok {
+		element.Value.(*memoryCacheEntry).response = response
+		s.order.MoveToFront(element)
+		return nil
+	}
+	s.entries[key] = s.order.PushFront(&memoryCacheEntry{key: key, response: response})
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+	return nil
+}
+
+// Delete removes a cached response.
+func (s *MemoryCacheStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[key]; ok {
+		s.order.Remove(element)
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// CacheOptions configures the response cache middleware.
+type CacheOptions struct {
+	Store CacheStore
+	// TTL applies to responses marked public that set neither s-maxage nor
+	// max-age.
+	TTL time.Duration
+	// Vary lists request headers that select different cached variants.
+	// Responses whose own Vary header names any other request header are
+	// not stored.
+	Vary []string
+	// CredentialHeaders lists request headers besides Authorization and
+	// Cookie that identify the caller, such as X-API-Key. Requests carrying
+	// any of them bypass the cache and are never coalesced.
+	CredentialHeaders []string
+	// MaxBodyBytes bounds the size of cacheable responses.
+	MaxBodyBytes int64
+	// Singleflight runs the handler once for concurrent misses of the same
+	// GET without credentials, keyed by the normalized URL and Vary headers. The other requests
+	// receive the same response bytes marked X-Cache: SHARED, unless it is
+	// larger than MaxBodyBytes, sets cookies, or is private or no-store.
+	Singleflight bool
+	Now          func() time.Time
+}
+
+// Cache returns middleware that caches successful GET and HEAD responses keyed
+// by URL and the configured Vary headers. Only responses marked public or
+// carrying s-maxage or max-age are stored; those marked no-store, no-cache, or
+// private, with max-age=0, setting cookies, or varying on headers outside the
+// key are not. Requests with Authorization, Cookie, a configured credential
+// header, or Cache-Control: no-store bypass the cache. Hits carry an ETag and
+// conditional requests that match it receive 304 Not Modified.
+func Cache(opts *CacheOptions) func(http.Handler) http.Handler {
+	options := normalizeCacheOptions(opts)
+	return func(next http.Handler) http.Handler {
+		var flights flightGroup
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestDirectives := parseCacheControl(r.Header.Get("Cache-Control"))
+			_, noStore := requestDirectives["no-store"]
+			if (r.Method != http.MethodGet && r.Method != http.MethodHead) || noStore || credentialed(r, options.CredentialHeaders) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := cacheKey(r.URL.RequestURI(), r, options.Vary)
+			if _, noCache := requestDirectives["no-cache"]; !noCache {
+				cached, ok, err := options.Store.Get(r.Context(), key)
+				if err != nil {
+					log.Printf("Cache lookup failed: %v", err)
+				}
+				if ok && cached.ExpiresAt.After(options.Now()) {
+					serveCachedResponse(w, r, cached, options.Now())
+					return
+				}
+				if ok {
+					_ = options.Store.Delete(r.Context(), key)
+				}
+			}
+
+			var flight *flightCall
+			var shared *CachedResponse
+			if options.Singleflight && r.Method == http.MethodGet {
+				flightKey := cacheKey(normalizedRequestURI(r.URL), r, options.Vary)
+				call, leader := flights.join(flightKey)
+				if leader {
+					flight = call
+					defer func() { flights.finish(flightKey, call, shared) }()
+				} else {
+					response, ok := call.wait(r.Context())
+					if !ok {
+						return
+					}
+					if response != nil {
+						replayResponse(w, response, "X-Cache", "SHARED")
+						return
+					}
+				}
+			}
+
+			w.Header().Set("X-Cache", "MISS")
+			recorder := &cacheRecorder{statusRecorder: newStatusRecorder(w), limit: options.MaxBodyBytes}
+			next.ServeHTTP(recorder, r)
+			if r.Method != http.MethodGet {
+				return
+			}
+			if flight != nil {
+				shared = shareableResponse(recorder, options)
+			}
+			storeCachedResponse(r, key, recorder, options)
+		})
+	}
+}
+
+// storeCachedResponse stores the recorded response when it is cacheable.
+func storeCachedResponse(r *http.Request, key string, recorder *cacheRecorder, options CacheOptions) {
+
+	response, ttl := cacheableResponse(recorder, options)
+	if ttl <= 0 {
+		return
+	}
+	now := options.Now()
+	response.StoredAt = now
+	response.ExpiresAt = now.Add(ttl)
+	if err := options.Store.Set(r.Context(), key, response); err != nil {
+		log.Printf("Cache store failed: %v", err)
+	}
+}
+
+func normalizeCacheOptions(opts *CacheOptions) CacheOptions {
+	options := CacheOptions{
+		TTL:          defaultCacheTTL,
+		MaxBodyBytes: defaultCacheMaxBodyBytes,
+		Now:          time.Now,
+	}
+	if opts != nil {
+		options.Store = opts.Store
+		if opts.TTL > 0 {
+			options.TTL = opts.TTL
+		}
+		for _, header := range opts.Vary {
+			options.Vary = append(options.Vary, http.CanonicalHeaderKey(header))
+		}
+		options.CredentialHeaders = append([]string(nil), opts.CredentialHeaders...)
+		if opts.MaxBodyBytes > 0 {
+			options.MaxBodyBytes = opts.MaxBodyBytes
+		}
+		options.Singleflight = opts.Singleflight
+		if opts.Now != nil {
+			options.Now = opts.Now
+		}
+	}
+	if options.Store == nil {
+		options.Store = NewMemoryCacheStore(defaultCacheCapacity)
+	}
+	return options
+}
+
+// credentialed reports whether r carries Authorization, Cookie, or one of
+// headers. Responses to such requests may be personal, so they are neither
+// cached nor shared with concurrent callers.
+func credentialed(r *http.Request, headers []string) bool {
+	if r.Header.Get("Authorization") != "" || r.Header.Get("Cookie") != "" {
+		return true
+	}
+	for _, header := range headers {
+		if r.Header.Get(header) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func cacheKey(uri string, r *http.Request, vary []string) string {
+	var key strings.Builder
+	key.WriteString(uri)
+	for _, header := range vary {
+		key.WriteString("\n")
+		key.WriteString(header)
+		key.WriteString(":")
+		key.WriteString(strings.Join(r.Header.Values(header), ","))
+	}
+	return key.String()
+}
+
+func serveCachedResponse(w http.ResponseWriter, r *http.Request, cached *CachedResponse, now time.Time) {
+	header := w.Header()
+	for key, values := range cached.Header {
+		header[key] = append([]string(nil), values...)
+	}
+	header.Set("ETag", cached.ETag)
+	header.Set("Age", strconv.FormatInt(int64(now.Sub(cached.StoredAt)/time.Second), 10))
+	header.Set("X-Cache", "HIT")
+
+	if etagMatches(r.Header.Get("If-None-Match"), cached.ETag) {
+		header.Del("Content-Length")
+		header.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Length", strconv.Itoa(len(cached.Body)))
+	w.WriteHeader(cached.Status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write(cached.Body); err != nil {
+		log.Printf("Failed to write cached response body: %v", err)
+	}
+}
+
+// normalizedRequestURI returns the escaped path with the query parameters
+// sorted, so equivalent URLs share a key.
+func normalizedRequestURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery == "" {
+		return path
+	}
+	return path + "?" + u.Query().Encode()
+}
+
+// shareableResponse returns the recorded response for requests coalesced
+// with it, or nil when it is too large or must not reach other callers.
+func shareableResponse(recorder *cacheRecorder, options CacheOptions) *CachedResponse {
+	header := recorder.Header().Clone()
+	if recorder.overflow || header.Get("Set-Cookie") != "" || variesBeyond(header, options.Vary) {
+		return nil
+	}
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	for _, directive := range []string{"no-store", "private"} {
+		if _, ok := directives[directive]; ok {
+			return nil
+		}
+	}
+	header.Del("X-Cache")
+	return &CachedResponse{Status: recorder.Status(), Header: header, Body: bytes.Clone(recorder.body.Bytes())}
+}
+
+// cacheableResponse returns the recorded response and how long it may be
+// cached, or a zero TTL when it must not be stored.
+func cacheableResponse(recorder *cacheRecorder, options CacheOptions) (*CachedResponse, time.Duration) {
+	if recorder.Status() != http.StatusOK || recorder.overflow {
+		return nil, 0
+	}
+	header := recorder.Header().Clone()
+	if header.Get("Set-Cookie") != "" || variesBeyond(header, options.Vary) {
+		return nil, 0
+	}
+
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	for _, directive := range []string{"no-store", "no-cache", "private"} {
+		if _, ok := directives[directive]; ok {
+			return nil, 0
+		}
+	}
+	// Without explicit freshness the response may be personalized by means
+	// the cache cannot see, so it is only stored when declared public.
+	ttl := time.Duration(0)
+	if _, public := directives["public"]; public {
+		ttl = options.TTL
+	}
+	for _, directive := range []string{"s-maxage", "max-age"} {
+		if value, ok := directives[directive]; ok {
+			seconds, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, 0
+			}
+			ttl = time.Duration(seconds) * time.Second
+			break
+		}
+	}
+
+	body := append([]byte(nil), recorder.body.Bytes()...)
+	etag := header.Get("ETag")
+	if etag == "" {
+		sum := sha256.Sum256(body)
+		etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	}
+	header.Del("X-Cache")
+	header.Del("Date")
+	return &CachedResponse{Status: recorder.Status(), Header: header, Body: body, ETag: etag}, ttl
+}
+
+// variesBeyond reports whether the response's Vary header names a request
+// header outside vary, which the cache key does not distinguish.
+func variesBeyond(header http.Header, vary []string) bool {
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" && !slices.Contains(vary, http.CanonicalHeaderKey(name)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseCacheControl splits a Cache-Control header into lowercased directives
+// and their unquoted values.
+func parseCacheControl(value string) map[string]string {
+	directives := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name == "" {
+			continue
+		}
+		directives[strings.ToLower(name)] = strings.Trim(arg, `"`)
+	}
+	return directives
+}
+
+// etagMatches reports whether an If-None-Match header matches etag using the
+// weak comparison defined for conditional GET requests.
+func etagMatches(header, etag string) bool {
+	if header == "" || etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheRecorder tees the response body so it can be stored after the handler returns.
+type cacheRecorder struct {
+	*statusRecorder
+	body     bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (r *cacheRecorder) Write(p []byte) (int, error) {
+	n, err := r.statusRecorder.Write(p)
+	if !r.overflow {
+		if int64(r.body.Len()+n) > r.limit {
+			r.overflow = true
+			r.body.Reset()
+		} else {
+			r.body.Write(p[:n])
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,158 @@
+package httpsuite
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy describes the Cache-Control header for a route. Build one with
+// CachePublic, CachePrivate, or CacheNoStore.
+type CachePolicy struct {
+	visibility           string
+	maxAge               time.Duration
+	sharedMaxAge         time.Duration
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+	mustRevalidate       bool
+	immutable            bool
+}
+
+// CachePublic allows browsers and shared caches to store the response for maxAge.
+func CachePublic(maxAge time.Duration) CachePolicy {
+	return CachePolicy{visibility: "public", maxAge: maxAge}
+}
+
+// CachePrivate allows only the client's own cache to store the response for maxAge.
+func CachePrivate(maxAge time.Duration) CachePolicy {
+	return CachePolicy{visibility: "private", maxAge: maxAge}
+}
+
+// CacheNoStore forbids every cache from storing the response.
+func CacheNoStore() CachePolicy {
+	return CachePolicy{visibility: "no-store"}
+}
+
+// SharedMaxAge sets s-maxage, overriding max-age for shared caches.
+func (p CachePolicy) SharedMaxAge(d time.Duration) CachePolicy {
+	p.sharedMaxAge = d
+	return p
+}
+
+// StaleWhileRevalidate lets caches serve a stale response for d while they refresh it.
+func (p CachePolicy) StaleWhileRevalidate(d time.Duration) CachePolicy {
+	p.staleWhileRevalidate = d
+	return p
+}
+
+// StaleIfError lets caches serve a stale response for d when the origin fails.
+func (p CachePolicy) StaleIfError(d time.Duration) CachePolicy {
+	p.staleIfError = d
+	return p
+}
+
+// MustRevalidate forbids serving the response once it is stale.
+func (p CachePolicy) MustRevalidate() CachePolicy {
+	p.mustRevalidate = true
+	return p
+}
+
+// Immutable marks the response as never changing while fresh.
+func (p CachePolicy) Immutable() CachePolicy {
+	p.immutable = true
+	return p
+}
+
+// String renders the policy as a Cache-Control header value.
+func (p CachePolicy) String() string {
+	if p.visibility == "" || p.visibility == "no-store" {
+		return "no-store"
+	}
+	directives := []string{p.visibility, "max-age=" + cacheSeconds(p.maxAge)}
+	if p.sharedMaxAge > 0 && p.visibility == "public" {
+		directives = append(directives, "s-maxage="+cacheSeconds(p.sharedMaxAge))
+	}
+	if p.staleWhileRevalidate > 0 {
+		directives = append(directives, "stale-while-revalidate="+cacheSeconds(p.staleWhileRevalidate))
+	}
+	if p.staleIfError > 0 {
+		directives = append(directives, "stale-if-error="+cacheSeconds(p.staleIfError))
+	}
+	if p.mustRevalidate {
+		directives = append(directives, "must-revalidate")
+	}
+	if p.immutable {
+		directives = append(directives, "immutable")
+	}
+	return strings.Join(directives, ", ")
+}
+
+// SetCacheControl writes the policy to the Cache-Control header.
+func SetCacheControl(w http.ResponseWriter, policy CachePolicy) {
+	w.Header().Set("Cache-Control", policy.String())
+}
+
+// CacheControl returns middleware that applies policy to successful responses.
+// Handlers that set Cache-Control themselves keep their value, and error
+// responses are left alone so failures are not cached under the route policy.
+func CacheControl(policy CachePolicy) func(http.Handler) http.Handler {
+	value := policy.String()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&cacheControlWriter{ResponseWriter: w, value: value}, r)
+		})
+	}
+}
+
+func cacheSeconds(d time.Duration) string {
+	if d <= 0 {
+		return "0"
+	}
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
+
+// cacheControlWriter sets Cache-Control just before the status line is sent.
+type cacheControlWriter struct {
+	http.ResponseWriter
+	value   string
+	applied bool
+}
+
+func (w *cacheControlWriter) apply(status int) {
+	if w.applied {
+		return
+	}
+	w.applied = true
+	header := w.Header()
+	if status < http.StatusBadRequest && header.Get("Cache-Control") == "" {
+		header.Set("Cache-Control", w.value)
+	}
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	if !isInformational(code) {
+		w.apply(code)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) Write(p []byte) (int, error) {
+	w.apply(http.StatusOK)
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *cacheControlWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError is used by http.ResponseController.
+func (w *cacheControlWriter) FlushError() error {
+	w.apply(http.StatusOK)
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
@@ -0,0 +1,85 @@
+package httpsuite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCachePolicyString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy CachePolicy
+		want   string
+	}{
+		{name: "public", policy: CachePublic(time.Minute), want: "public, max-age=60"},
+		{name: "private", policy: CachePrivate(1500 * time.Millisecond), want: "private, max-age=2"},
+		{name: "no-store", policy: CacheNoStore(), want: "no-store"},
+		{name: "zero value", policy: CachePolicy{}, want: "no-store"},
+		{
+			name:   "stale while revalidate",
+			policy: CachePublic(time.Minute).SharedMaxAge(5 * time.Minute).StaleWhileRevalidate(30 * time.Second),
+			want:   "public, max-age=60, s-maxage=300, stale-while-revalidate=30",
+		},
+		{
+			name:   "private ignores s-maxage",
+			policy: CachePrivate(0).SharedMaxAge(time.Minute).MustRevalidate(),
+			want:   "private, max-age=0, must-revalidate",
+		},
+		{
+			name:   "immutable",
+			policy: CachePublic(24 * time.Hour).StaleIfError(time.Hour).Immutable(),
+			want:   "public, max-age=86400, stale-if-error=3600, immutable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.String(); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCacheControlMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{
+			name:    "implicit ok",
+			handler: func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("ok")) },
+			want:    "public, max-age=60",
+		},
+		{
+			name: "handler override",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				SetCacheControl(w, CacheNoStore())
+				w.WriteHeader(http.StatusOK)
+			},
+			want: "no-store",
+		},
+		{
+			name:    "error response",
+			handler: func(w http.ResponseWriter, r *http.Request) { ProblemResponse(w, ProblemNotFound("missing").Build()) },
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			CacheControl(CachePublic(time.Minute))(tt.handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if got := w.Header().Get("Cache-Control"); got != tt.want {
+				t.Fatalf("expected Cache-Control %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
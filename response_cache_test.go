@@ -0,0 +1,287 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheServesStoredResponses(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	calls := 0
+	handler := Cache(&CacheOptions{
+		Vary: []string{"accept-language"},
+		Now:  func() time.Time { return now },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public")
+		w.Header().Set("Vary", "Accept-Language")
+		_, _ = w.Write([]byte(`{"call":` + strconv.Itoa(calls) + `,"lang":"` + r.Header.Get("Accept-Language") + `"}`))
+	}))
+
+	get := func(lang, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/users?page=1", nil)
+		req.Header.Set("Accept-Language", lang)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("en", "")
+	if first.Header().Get("X-Cache") != "MISS" || first.Body.String() != `{"call":1,"lang":"en"}` {
+		t.Fatalf("unexpected first response %q %q", first.Header().Get("X-Cache"), first.Body.String())
+	}
+
+	now = now.Add(10 * time.Second)
+	second := get("en", "")
+	if second.Header().Get("X-Cache") != "HIT" || second.Body.String() != first.Body.String() {
+		t.Fatalf("expected cached body, got %q", second.Body.String())
+	}
+	if second.Header().Get("Age") != "10" || second.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected cached headers %#v", second.Header())
+	}
+	etag := second.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected cached response to carry an ETag")
+	}
+
+	notModified := get("en", etag)
+	if notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 {
+		t.Fatalf("expected 304 without body, got %d %q", notModified.Code, notModified.Body.String())
+	}
+
+	variant := get("fr", "")
+	if variant.Body.String() != `{"call":2,"lang":"fr"}` {
+		t.Fatalf("expected Vary header to select a new variant, got %q", variant.Body.String())
+	}
+
+	now = now.Add(time.Minute)
+	if expired := get("en", ""); expired.Body.String() != `{"call":3,"lang":"en"}` {
+		t.Fatalf("expected expired entry to be refreshed, got %q", expired.Body.String())
+	}
+}
+
+func TestCacheHonorsCacheControl(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		method          string
+		requestHeaders  map[string]string
+		responseControl string
+		responseVary    string
+		status          int
+		wantCalls       int
+	}{
+		{name: "public", method: http.MethodGet, responseControl: "public", status: http.StatusOK, wantCalls: 1},
+		{name: "max-age", method: http.MethodGet, responseControl: "max-age=60", status: http.StatusOK, wantCalls: 1},
+		{name: "s-maxage", method: http.MethodGet, responseControl: "s-maxage=60", status: http.StatusOK, wantCalls: 1},
+		{name: "no cache headers", method: http.MethodGet, status: http.StatusOK, wantCalls: 2},
+		{name: "vary outside key", method: http.MethodGet, responseControl: "public", responseVary: "Accept-Language", status: http.StatusOK, wantCalls: 2},
+		{name: "vary star", method: http.MethodGet, responseControl: "public", responseVary: "*", status: http.StatusOK, wantCalls: 2},
+		{name: "response no-store", method: http.MethodGet, responseControl: "no-store", status: http.StatusOK, wantCalls: 2},
+		{name: "response private", method: http.MethodGet, responseControl: "private, max-age=60", status: http.StatusOK, wantCalls: 2},
+		{name: "max-age zero", method: http.MethodGet, responseControl: "max-age=0", status: http.StatusOK, wantCalls: 2},
+		{name: "error status", method: http.MethodGet, responseControl: "public", status: http.StatusNotFound, wantCalls: 2},
+		{name: "post", method: http.MethodPost, responseControl: "public", status: http.StatusOK, wantCalls: 2},
+		{name: "request no-store", method: http.MethodGet, requestHeaders: map[string]string{"Cache-Control": "no-store"}, responseControl: "public", status: http.StatusOK, wantCalls: 2},
+		{name: "authorized", method: http.MethodGet, requestHeaders: map[string]string{"Authorization": "Bearer token"}, responseControl: "public", status: http.StatusOK, wantCalls: 2},
+		{name: "cookie", method: http.MethodGet, requestHeaders: map[string]string{"Cookie": "sid=alice"}, responseControl: "public", status: http.StatusOK, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := Cache(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if tt.responseControl != "" {
+					w.Header().Set("Cache-Control", tt.responseControl)
+				}
+				if tt.responseVary != "" {
+					w.Header().Set("Vary", tt.responseVary)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("body"))
+			}))
+
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest(tt.method, "/resource", nil)
+				for key, value := range tt.requestHeaders {
+					req.Header.Set(key, value)
+				}
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("expected %d handler calls, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestCacheDoesNotShareCookieSessions(t *testing.T) {
+	t.Parallel()
+
+	handler := Cache(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := r.Cookie("sid")
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("profile of " + session.Value))
+	}))
+
+	for _, user := range []string{"alice", "bob"} {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.AddCookie(&http.Cookie{Name: "sid", Value: user})
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Header().Get("X-Cache") == "HIT" || w.Body.String() != "profile of "+user {
+			t.Fatalf("expected the profile of %s, got %q %q", user, w.Header().Get("X-Cache"), w.Body.String())
+		}
+	}
+}
+
+func TestMemoryCacheStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryCacheStore(2)
+	ctx := context.Background()
+	fresh := func() *CachedResponse { return &CachedResponse{ExpiresAt: time.Now().Add(time.Hour)} }
+
+	_ = store.Set(ctx, "a", fresh())
+	_ = store.Set(ctx, "b", fresh())
+	if _, ok, _ := store.Get(ctx, "a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	_ = store.Set(ctx, "c", fresh())
+
+	if _, ok, _ := store.Get(ctx, "b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if _, ok, _ := store.Get(ctx, "a"); !ok {
+		t.Fatal("expected recently used a to survive")
+	}
+}
+
+func TestCacheSingleflightSharesConcurrentMisses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		control    string
+		wantCalls  int32
+		wantShared int
+	}{
+		{name: "cacheable", control: "max-age=60", wantCalls: 1, wantShared: 2},
+		{name: "no-cache is still shared", control: "no-cache", wantCalls: 1, wantShared: 2},
+		{name: "private runs separately", control: "private", wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			release := make(chan struct{})
+			handler := Cache(&CacheOptions{Singleflight: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) == 1 {
+					<-release
+				}
+				w.Header().Set("Cache-Control", tt.control)
+				_, _ = w.Write([]byte(`{"page":` + r.URL.Query().Get("page") + `}`))
+			}))
+
+			targets := []string{"/users?page=1&sort=name", "/users?sort=name&page=1", "/users?page=1&sort=name"}
+			recorders := make([]*httptest.ResponseRecorder, len(targets))
+			var wg sync.WaitGroup
+			for i, target := range targets {
+				recorders[i] = httptest.NewRecorder()
+				wg.Add(1)
+				go func(rec *httptest.ResponseRecorder, target string) {
+					defer wg.Done()
+					handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+				}(recorders[i], target)
+				if i == 0 {
+					waitForCalls(t, &calls, 1)
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			if calls.Load() != tt.wantCalls {
+				t.Fatalf("expected %d handler calls, got %d", tt.wantCalls, calls.Load())
+			}
+			shared := 0
+			for _, rec := range recorders {
+				if rec.Body.String() != `{"page":1}` {
+					t.Fatalf("expected response body, got %q", rec.Body.String())
+				}
+				if rec.Header().Get("X-Cache") == "SHARED" {
+					shared++
+				}
+			}
+			if shared != tt.wantShared {
+				t.Fatalf("expected %d shared responses, got %d", tt.wantShared, shared)
+			}
+		})
+	}
+}
+
+func TestCacheSingleflightSkipsCredentialedRequests(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	handler := Cache(&CacheOptions{Singleflight: true, CredentialHeaders: []string{"X-API-Key"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-release
+		}
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte("account of " + r.Header.Get("X-API-Key") + r.Header.Get("Cookie")))
+	}))
+
+	requests := []map[string]string{
+		{"X-API-Key": "alice"},
+		{"X-API-Key": "bob"},
+		{"Cookie": "sid=carol"},
+	}
+	recorders := make([]*httptest.ResponseRecorder, len(requests))
+	var wg sync.WaitGroup
+	for i, headers := range requests {
+		recorders[i] = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/account", nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		wg.Add(1)
+		go func(rec *httptest.ResponseRecorder, req *http.Request) {
+			defer wg.Done()
+			handler.ServeHTTP(rec, req)
+		}(recorders[i], req)
+		if i == 0 {
+			waitForCalls(t, &calls, 1)
+		}
+	}
+	waitForCalls(t, &calls, int32(len(requests)))
+	close(release)
+	wg.Wait()
+
+	for i, want := range []string{"account of alice", "account of bob", "account of sid=carol"} {
+		if recorders[i].Body.String() != want || recorders[i].Header().Get("X-Cache") == "SHARED" {
+			t.Fatalf("expected %q, got %q %q", want, recorders[i].Header().Get("X-Cache"), recorders[i].Body.String())
+		}
+	}
+}
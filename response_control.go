@@ -0,0 +1,42 @@
+package httpsuite
+
+import (
+	"errors"
+	"log"
+	"net/http"
+)
+
+// SendEarlyHints adds links as Link headers and sends a 103 Early Hints
+// response, so browsers can preload assets while the handler is still
+// working. Links use the header syntax, e.g. "</app.css>; rel=preload; as=style".
+// The headers stay set for the final response. HTTP/1.0 clients receive no
+// informational responses.
+func SendEarlyHints(w http.ResponseWriter, links ...string) {
+	for _, link := range links {
+		w.Header().Add("Link", link)
+	}
+	w.WriteHeader(http.StatusEarlyHints)
+}
+
+// Flush sends buffered response data to the client through any of the
+// suite's writer wrappers. It returns an error wrapping http.ErrNotSupported
+// when the underlying writer cannot flush.
+func Flush(w http.ResponseWriter) error {
+	return http.NewResponseController(w).Flush()
+}
+
+// SendHeaderOnly writes the status line and headers without a body and
+// flushes them, so clients see the status before a slow stream or work that
+// follows. Write the body, if any, with w afterwards.
+func SendHeaderOnly(w http.ResponseWriter, code int) {
+	w.WriteHeader(code)
+	if err := Flush(w); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Failed to flush response headers (status=%d): %v", code, err)
+	}
+}
+
+// isInformational reports whether code is a 1xx status that precedes the
+// final response. 101 Switching Protocols is final.
+func isInformational(code int) bool {
+	return code >= 100 && code < 200 && code != http.StatusSwitchingProtocols
+}
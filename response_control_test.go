@@ -0,0 +1,112 @@
+package httpsuite
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+	"time"
+)
+
+func TestSendEarlyHints(t *testing.T) {
+	t.Parallel()
+
+	statuses := make(chan int, 1)
+	server := httptest.NewServer(Audit(AuditorFunc(func(_ context.Context, entry AuditEntry) {
+		statuses <- entry.Status
+	}), nil)(CacheControl(CachePublic(time.Minute))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SendEarlyHints(w, "</app.css>; rel=preload; as=style")
+		OK(w, "done")
+	}))))
+	defer server.Close()
+
+	var hints []textproto.MIMEHeader
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				hints = append(hints, header)
+			}
+			return nil
+		},
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if len(hints) != 1 || hints[0].Get("Link") != "</app.css>; rel=preload; as=style" {
+		t.Fatalf("expected one early hint with the link, got %v", hints)
+	}
+	if hints[0].Get("Cache-Control") != "" {
+		t.Fatalf("expected no Cache-Control on early hints, got %q", hints[0].Get("Cache-Control"))
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Cache-Control") != "public, max-age=60" {
+		t.Fatalf("unexpected final response: %d %v", resp.StatusCode, resp.Header)
+	}
+	if status := <-statuses; status != http.StatusOK {
+		t.Fatalf("expected audited status %d, got %d", http.StatusOK, status)
+	}
+}
+
+func TestFlushThroughWrappers(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	handler := Chain(HTMLProblems(nil), CacheControl(CacheNoStore()), Audit(AuditorFunc(func(context.Context, AuditEntry) {}), nil)).ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := Flush(w); err != nil {
+			t.Errorf("flush: %v", err)
+		}
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/html")
+	handler.ServeHTTP(rec, req)
+
+	if !rec.Flushed {
+		t.Fatal("expected the response to be flushed")
+	}
+	if rec.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("expected Cache-Control before flushing, got %q", rec.Header().Get("Cache-Control"))
+	}
+}
+
+type plainWriter struct {
+	header http.Header
+}
+
+func (w *plainWriter) Header() http.Header         { return w.header }
+func (w *plainWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w *plainWriter) WriteHeader(int)             {}
+
+func TestFlushNotSupported(t *testing.T) {
+	t.Parallel()
+
+	err := Flush(newStatusRecorder(&plainWriter{header: http.Header{}}))
+	if !errors.Is(err, http.ErrNotSupported) {
+		t.Fatalf("expected http.ErrNotSupported, got %v", err)
+	}
+}
+
+func TestSendHeaderOnly(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	recorder := newStatusRecorder(rec)
+	SendHeaderOnly(recorder, http.StatusAccepted)
+
+	if rec.Code != http.StatusAccepted || !rec.Flushed || rec.Body.Len() != 0 {
+		t.Fatalf("expected flushed 202 without body, got %d flushed=%v body=%q", rec.Code, rec.Flushed, rec.Body.String())
+	}
+	if recorder.Status() != http.StatusAccepted {
+		t.Fatalf("expected recorded status %d, got %d", http.StatusAccepted, recorder.Status())
+	}
+}
@@ -1,6 +1,9 @@
 package httpsuite
 
-import "net/http"
+import (
+	"log"
+	"net/http"
+)
 
 // OK writes a 200 JSON response without metadata.
 func OK[T any](w http.ResponseWriter, data T) {
@@ -17,6 +20,17 @@ func Created[T any](w http.ResponseWriter, data T, location string) {
 	Reply().Created(w, data, location)
 }
 
+// ErrorResponse writes the problem carried by err, or a generic 500 problem
+// when err does not wrap a *ProblemDetails. Unrecognized errors are logged.
+func ErrorResponse(w http.ResponseWriter, err error) {
+	problem, ok := AsProblem(err)
+	if !ok {
+		log.Printf("Unhandled error: %v", err)
+		problem = NewProblemDetails(http.StatusInternalServerError, GetProblemTypeURL("server_error"), "Internal Server Error", "")
+	}
+	ProblemResponse(w, problem)
+}
+
 // ProblemResponse writes a problem response using the problem's status.
 func ProblemResponse(w http.ResponseWriter, problem *ProblemDetails) {
 	if problem == nil {
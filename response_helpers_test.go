@@ -1,8 +1,11 @@
 package httpsuite
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -44,3 +47,29 @@ func TestResponseHelpers(t *testing.T) {
 		}
 	})
 }
+
+func TestErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "wrapped problem", err: fmt.Errorf("handler: %w", NewBadRequestProblem("bad input")), wantStatus: http.StatusBadRequest},
+		{name: "plain error", err: errors.New("boom"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ErrorResponse(w, tt.err)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if strings.Contains(w.Body.String(), "boom") {
+				t.Fatalf("expected unrecognized error to stay server-side, got %s", w.Body.String())
+			}
+		})
+	}
+}
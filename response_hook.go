@@ -0,0 +1,122 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// ResponseEvent describes a response written successfully by a handler
+// wrapped in OnResponse.
+type ResponseEvent struct {
+	Method string
+	Route  string
+	Path   string
+	Status int
+	// Request is the request parsed with ParseRequest, or nil.
+	Request any
+	// Response is the data passed to the response helpers, such as OK,
+	// Created, or Handle's typed handlers, or nil for other writes.
+	Response any
+}
+
+// ResponseHook receives events from the OnResponse middleware.
+type ResponseHook func(ctx context.Context, event ResponseEvent)
+
+type responseHookContextKey struct{}
+
+type responseHookRecord struct {
+	mu      sync.Mutex
+	request any
+}
+
+// OnResponse returns middleware that calls hooks, in order, after the wrapped
+// handler wrote a 2xx response without a write error. It suits domain-event
+// publication, cache invalidation, and outbox writers that need the parsed
+// request and the response payload without wrapping every handler:
+//
+//	r.Use(httpsuite.OnResponse(func(ctx context.Context, event httpsuite.ResponseEvent) {
+//		if order, ok := event.Response.(Order); ok && event.Method == http.MethodPost {
+//			outbox.Publish(ctx, OrderCreated{ID: order.ID})
+//		}
+//	}))
+//
+// Hooks run on the request goroutine once the handler returns, with a
+// context that is not canceled when the client disconnects.
+func OnResponse(hooks ...ResponseHook) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(hooks) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			record := &responseHookRecord{}
+			writer := &responseHookWriter{statusRecorder: statusRecorder{ResponseWriter: w}}
+			r = withRoutePatternRecord(r.WithContext(context.WithValue(r.Context(), responseHookContextKey{}, record)))
+			next.ServeHTTP(writer, r)
+
+			status := writer.Status()
+			if writer.failed || status < 200 || status > 299 {
+				return
+			}
+			record.mu.Lock()
+			event := ResponseEvent{
+				Method:   r.Method,
+				Route:    auditRoute(r),
+				Path:     r.URL.Path,
+				Status:   status,
+				Request:  record.request,
+				Response: writer.payload,
+			}
+			record.mu.Unlock()
+
+			ctx := context.WithoutCancel(r.Context())
+			for _, hook := range hooks {
+				hook(ctx, event)
+			}
+		})
+	}
+}
+
+func recordResponseHookRequest(ctx context.Context, request any) {
+	record, ok := ctx.Value(responseHookContextKey{}).(*responseHookRecord)
+	if !ok {
+		return
+	}
+	record.mu.Lock()
+	defer record.mu.Unlock()
+	record.request = request
+}
+
+// responseHookOf returns the OnResponse writer wrapping w, if any, so the
+// response helpers can record their payload.
+func responseHookOf(w http.ResponseWriter) *responseHookWriter {
+	for w != nil {
+		if writer, ok := w.(*responseHookWriter); ok {
+			return writer
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = unwrapper.Unwrap()
+	}
+	return nil
+}
+
+// responseHookWriter records the status, payload, and write errors of a
+// response for OnResponse.
+type responseHookWriter struct {
+	statusRecorder
+	payload any
+	failed  bool
+}
+
+func (w *responseHookWriter) Write(p []byte) (int, error) {
+	n, err := w.statusRecorder.Write(p)
+	if err != nil {
+		w.failed = true
+	}
+	return n, err
+}
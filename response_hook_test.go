@@ -0,0 +1,114 @@
+package httpsuite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type hookOrderRequest struct {
+	Item string `json:"item"`
+}
+
+type hookOrder struct {
+	ID   int64  `json:"id"`
+	Item string `json:"item"`
+}
+
+func TestOnResponse(t *testing.T) {
+	t.Parallel()
+
+	var events []ResponseEvent
+	api := NewAPI(APIInfo{Title: "Orders", Version: "1.0.0"})
+	mux := http.NewServeMux()
+	mux.Handle("POST /orders", Handle(api, http.MethodPost, "/orders", func(ctx context.Context, req *hookOrderRequest) (hookOrder, error) {
+		if req.Item == "" {
+			return hookOrder{}, errors.New("missing item")
+		}
+		return hookOrder{ID: 7, Item: req.Item}, nil
+	}, &RouteOptions{Status: http.StatusCreated}))
+	handler := OnResponse(func(ctx context.Context, event ResponseEvent) {
+		if ctx.Err() != nil {
+			t.Errorf("expected a live context, got %v", ctx.Err())
+		}
+		events = append(events, event)
+	})(mux)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(`{"item":"lamp"}`)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events))
+	}
+	event := events[0]
+	if event.Method != http.MethodPost || event.Route != "POST /orders" || event.Path != "/orders" || event.Status != http.StatusCreated {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if request, ok := event.Request.(*hookOrderRequest); !ok || request.Item != "lamp" {
+		t.Fatalf("expected parsed request, got %#v", event.Request)
+	}
+	if response, ok := event.Response.(hookOrder); !ok || response.ID != 7 {
+		t.Fatalf("expected response payload, got %#v", event.Response)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(`{}`)))
+	if w.Code < 400 || len(events) != 1 {
+		t.Fatalf("expected no event for a failed request, got status %d and %d events", w.Code, len(events))
+	}
+}
+
+func TestOnResponsePlainHandlers(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	handler := OnResponse(
+		func(ctx context.Context, event ResponseEvent) { calls = append(calls, "first") },
+		func(ctx context.Context, event ResponseEvent) {
+			calls = append(calls, "second")
+			if event.Request != nil || event.Response != nil || event.Status != http.StatusAccepted {
+				t.Errorf("expected a bare accepted event, got %+v", event)
+			}
+		},
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/cache", nil))
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Fatalf("expected hooks in order, got %v", calls)
+	}
+
+	OnResponse()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		OK(w, "ok")
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+type failingWriter struct {
+	http.ResponseWriter
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestOnResponseSkipsFailedWrites(t *testing.T) {
+	t.Parallel()
+
+	fired := false
+	handler := OnResponse(func(ctx context.Context, event ResponseEvent) {
+		fired = true
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		OK(w, "ok")
+	}))
+
+	handler.ServeHTTP(failingWriter{httptest.NewRecorder()}, httptest.NewRequest(http.MethodGet, "/", nil))
+	if fired {
+		t.Fatal("expected no event when the body could not be written")
+	}
+}
@@ -0,0 +1,227 @@
+package httpsuite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const htmlContentType = "text/html; charset=utf-8"
+
+// defaultProblemPage renders ProblemDetails for humans. html/template
+// escapes every field, so details echoing user input stay inert.
+var defaultProblemPage = template.Must(template.New("problem").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Status}} {{.Title}}</title>
+<style>
+body{margin:0;font-family:system-ui,sans-serif;color:#1f2328;background:#f6f8fa}
+main{max-width:40rem;margin:15vh auto;padding:2rem;background:#fff;border:1px solid #d0d7de;border-radius:6px}
+.status{color:#57606a;font-size:.875rem;letter-spacing:.05em}
+h1{margin:.25rem 0 1rem;font-size:1.5rem}
+code{font-size:.875rem}
+</style>
+</head>
+<body>
+<main>
+<p class="status">{{.Status}}</p>
+<h1>{{.Title}}</h1>
+{{with .Detail}}<p>{{.}}</p>
+{{end}}{{with .Instance}}<p>Reference: <code>{{.}}</code></p>
+{{end}}{{if and .Type (ne .Type "about:blank")}}<p><a href="{{.Type}}">About this error</a></p>
+{{end}}</main>
+</body>
+</html>
+`))
+
+// TemplateOptions configures LoadTemplates.
+type TemplateOptions struct {
+	// Pages are glob patterns of page files, e.g. "pages/*.html". Each page
+	// is registered under its file name without extension.
+	Pages []string
+	// Layouts are glob patterns of files parsed into every page, e.g.
+	// "layouts/*.html", for shared layouts and partials.
+	Layouts []string
+	// Layout names the template pages execute, e.g. "base" for a layout
+	// defining {{define "base"}} around {{block "content" .}}. When empty,
+	// the page file itself is executed.
+	Layout string
+	// Funcs are available to every template.
+	Funcs template.FuncMap
+	// Preload lists assets shared by every page, such as the stylesheet
+	// and scripts of the layout, sent as preload Link headers by Render.
+	Preload []Preload
+}
+
+// Templates is a registry of html/template pages parsed from an fs.FS, for
+// services that serve a few human-facing pages next to the JSON API.
+type Templates struct {
+	pages   map[string]*template.Template
+	layout  string
+	preload []Preload
+}
+
+// LoadTemplates parses every page together with the layouts. A page named
+// "error" replaces the built-in problem page used by RenderProblem; it
+// receives the *ProblemDetails.
+func LoadTemplates(fsys fs.FS, opts *TemplateOptions) (*Templates, error) {
+	var options TemplateOptions
+	if opts != nil {
+		options = *opts
+	}
+	if len(options.Pages) == 0 {
+		return nil, errors.New("at least one page pattern is required")
+	}
+
+	var layouts []string
+	for _, pattern := range options.Layouts {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("match layouts %q: %w", pattern, err)
+		}
+		layouts = append(layouts, matches...)
+	}
+
+	templates := &Templates{pages: make(map[string]*template.Template), layout: options.Layout, preload: options.Preload}
+	for _, pattern := range options.Pages {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("match pages %q: %w", pattern, err)
+		}
+		for _, file := range matches {
+			name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+			if _, ok := templates.pages[name]; ok {
+				return nil, fmt.Errorf("duplicate page %q", name)
+			}
+			page := template.New(path.Base(file)).Funcs(options.Funcs)
+			// Layouts come first so pages can redefine their blocks.
+			if len(layouts) > 0 {
+				if page, err = page.ParseFS(fsys, layouts...); err != nil {
+					return nil, fmt.Errorf("parse page %q: %w", name, err)
+				}
+			}
+			if page, err = page.ParseFS(fsys, file); err != nil {
+				return nil, fmt.Errorf("parse page %q: %w", name, err)
+			}
+			templates.pages[name] = page
+		}
+	}
+	return templates, nil
+}
+
+// Page returns the named page, resolved to the Layout template when one is
+// configured, for use with SendHTML, or nil when there is no such page.
+func (t *Templates) Page(name string) *template.Template {
+	page, ok := t.pages[name]
+	if !ok || t.layout == "" {
+		return page
+	}
+	return page.Lookup(t.layout)
+}
+
+// Render writes the named page with data. Unknown pages and rendering
+// failures are logged and answered with a 500 problem page.
+func (t *Templates) Render(w http.ResponseWriter, code int, name string, data any) {
+	page := t.Page(name)
+	if page == nil {
+		log.Printf("Failed to render template: unknown page %q", name)
+		t.RenderProblem(w, internalErrorProblem(context.Background()))
+		return
+	}
+	AddPreloads(w, t.preload...)
+	SendHTML(w, code, page, data)
+}
+
+// RenderProblem writes problem as an HTML error page using the "error" page,
+// or the built-in page when there is none.
+func (t *Templates) RenderProblem(w http.ResponseWriter, problem *ProblemDetails) {
+	SendProblemHTML(w, problem, t.Page("error"))
+}
+
+// SendHTML renders tmpl with data and writes it with status code. The page
+// is rendered completely first, so a failing template produces a 500
+// problem page instead of a truncated response.
+func SendHTML(w http.ResponseWriter, code int, tmpl *template.Template, data any) {
+	sendHTML(context.Background(), w, code, tmpl, data)
+}
+
+// sendHTML is SendHTML with the context whose problem config types the
+// fallback problem.
+func sendHTML(ctx context.Context, w http.ResponseWriter, code int, tmpl *template.Template, data any) {
+	if tmpl == nil {
+		log.Printf("Failed to render template: template is nil")
+		sendProblemHTML(ctx, w, internalErrorProblem(ctx), nil)
+		return
+	}
+	buffer := getEncodeBuffer()
+	defer putEncodeBuffer(buffer)
+	if err := tmpl.Execute(buffer, data); err != nil {
+		log.Printf("Failed to render template: %v", err)
+		sendProblemHTML(ctx, w, internalErrorProblem(ctx), nil)
+		return
+	}
+	writeHTML(w, code, buffer.Bytes())
+}
+
+// SendProblemHTML writes problem as an HTML error page rendered with tmpl,
+// or the built-in page when tmpl is nil. The template receives the
+// *ProblemDetails; status and Retry-After follow ProblemResponse.
+func SendProblemHTML(w http.ResponseWriter, problem *ProblemDetails, tmpl *template.Template) {
+	sendProblemHTML(context.Background(), w, problem, tmpl)
+}
+
+// sendProblemHTML is SendProblemHTML with the context whose problem config
+// types the fallback problem.
+func sendProblemHTML(ctx context.Context, w http.ResponseWriter, problem *ProblemDetails, tmpl *template.Template) {
+	if problem == nil {
+		problem = internalErrorProblem(ctx)
+	}
+	if tmpl == nil {
+		tmpl = defaultProblemPage
+	}
+	normalized := *problem
+	if normalized.Status < 400 || normalized.Status > 599 {
+		normalized.Status = http.StatusInternalServerError
+	}
+	if delay, ok := normalized.RetryAfter(); ok && w.Header().Get("Retry-After") == "" {
+		w.Header().Set("Retry-After", strconv.FormatInt(retryAfterSeconds(delay), 10))
+	}
+
+	buffer := getEncodeBuffer()
+	defer putEncodeBuffer(buffer)
+	if err := tmpl.Execute(buffer, &normalized); err != nil {
+		log.Printf("Failed to render problem page: %v", err)
+		buffer.Reset()
+		fallback := internalErrorProblem(ctx)
+		if err := defaultProblemPage.Execute(buffer, fallback); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		normalized.Status = fallback.Status
+	}
+	writeHTML(w, normalized.Status, buffer.Bytes())
+}
+
+func writeHTML(w http.ResponseWriter, code int, body []byte) {
+	w.Header().Set("Content-Type", htmlContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("Failed to write HTML response body (status=%d): %v", code, err)
+	}
+}
+
+// internalErrorProblem returns the generic 500 problem typed with the config
+// scoped to ctx.
+func internalErrorProblem(ctx context.Context) *ProblemDetails {
+	return NewProblemDetails(http.StatusInternalServerError, sharedProblemConfig(ctx).TypeURL("server_error"), "Internal Server Error", "An internal server error occurred.")
+}
@@ -0,0 +1,137 @@
+package httpsuite
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testTemplateFS() fstest.MapFS {
+	return fstest.MapFS{
+		"layouts/base.html": {Data: []byte(`{{define "base"}}<html><title>{{block "title" .}}App{{end}}</title><body>{{block "content" .}}{{end}}</body></html>{{end}}`)},
+		"pages/home.html":   {Data: []byte(`{{define "title"}}Home{{end}}{{define "content"}}<p>Hello {{.Name}}</p>{{end}}`)},
+		"pages/about.html":  {Data: []byte(`{{define "content"}}<p>{{upper "about"}}</p>{{end}}`)},
+		"pages/error.html":  {Data: []byte(`{{define "title"}}Oops{{end}}{{define "content"}}<h1>{{.Status}} {{.Title}}</h1>{{end}}`)},
+	}
+}
+
+func TestTemplatesRender(t *testing.T) {
+	t.Parallel()
+
+	templates, err := LoadTemplates(testTemplateFS(), &TemplateOptions{
+		Pages:   []string{"pages/*.html"},
+		Layouts: []string{"layouts/*.html"},
+		Layout:  "base",
+		Funcs:   template.FuncMap{"upper": strings.ToUpper},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		page       string
+		data       any
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "layout and escaping", page: "home", data: map[string]string{"Name": "<b>Ann</b>"}, wantStatus: http.StatusOK, wantBody: "<html><title>Home</title><body><p>Hello &lt;b&gt;Ann&lt;/b&gt;</p></body></html>"},
+		{name: "default block and funcs", page: "about", wantStatus: http.StatusOK, wantBody: "<html><title>App</title><body><p>ABOUT</p></body></html>"},
+		{name: "unknown page", page: "missing", wantStatus: http.StatusInternalServerError, wantBody: "<h1>500 Internal Server Error</h1>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			templates.Render(rec, http.StatusOK, tt.page, tt.data)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != htmlContentType {
+				t.Fatalf("expected HTML content type, got %q", got)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	templates.RenderProblem(rec, NewNotFoundProblem("no such page"))
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "<title>Oops</title>") {
+		t.Fatalf("expected the custom error page, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestLoadTemplatesErrors(t *testing.T) {
+	t.Parallel()
+
+	fsys := testTemplateFS()
+	fsys["other/home.html"] = &fstest.MapFile{Data: []byte("dup")}
+	fsys["broken/page.html"] = &fstest.MapFile{Data: []byte("{{if}}")}
+
+	tests := []struct {
+		name string
+		opts *TemplateOptions
+	}{
+		{name: "no pages", opts: nil},
+		{name: "duplicate page", opts: &TemplateOptions{Pages: []string{"pages/*.html", "other/*.html"}}},
+		{name: "parse error", opts: &TemplateOptions{Pages: []string{"broken/*.html"}}},
+		{name: "bad pattern", opts: &TemplateOptions{Pages: []string{"pages/[.html"}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := LoadTemplates(fsys, tt.opts); err == nil {
+			t.Fatalf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestSendHTML(t *testing.T) {
+	t.Parallel()
+
+	page := template.Must(template.New("page").Parse(`<p>{{.}}</p>`))
+	rec := httptest.NewRecorder()
+	SendHTML(rec, http.StatusCreated, page, "<script>")
+	if rec.Code != http.StatusCreated || rec.Body.String() != "<p>&lt;script&gt;</p>" {
+		t.Fatalf("unexpected response: %d %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Fatal("expected nosniff header")
+	}
+
+	failing := template.Must(template.New("page").Parse(`<p>partial{{.Missing.Field}}</p>`))
+	rec = httptest.NewRecorder()
+	SendHTML(rec, http.StatusOK, failing, struct{ Missing *struct{ Field string } }{})
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "partial") {
+		t.Fatalf("expected a clean 500 page, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSendProblemHTML(t *testing.T) {
+	t.Parallel()
+
+	problem := NewTooManyRequestsProblem(`slow down <img src=x onerror=alert(1)>`, 30*time.Second)
+	problem.Instance = "/items#abc"
+	rec := httptest.NewRecorder()
+	SendProblemHTML(rec, problem, nil)
+
+	body := rec.Body.String()
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "30" {
+		t.Fatalf("unexpected status or headers: %d %v", rec.Code, rec.Header())
+	}
+	if strings.Contains(body, "<img") || !strings.Contains(body, "&lt;img") {
+		t.Fatalf("expected the detail to be escaped, got %q", body)
+	}
+	for _, want := range []string{"<title>429 Too Many Requests</title>", "/items#abc", `href="` + problem.Type + `"`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected body to contain %q, got %q", want, body)
+		}
+	}
+}
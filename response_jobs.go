@@ -0,0 +1,153 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// JobState is the lifecycle state of an asynchronous job.
+type JobState string
+
+const (
+	JobPending   JobState = "pending"
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+)
+
+// JobStatus is the resource clients poll after receiving 202 Accepted.
+type JobStatus struct {
+	ID        string          `json:"id"`
+	State     JobState        `json:"state"`
+	Result    any             `json:"result,omitempty"`
+	ResultURL string          `json:"result_url,omitempty"`
+	Problem   *ProblemDetails `json:"problem,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// NewJobStatus returns a pending job with a random ID.
+func NewJobStatus() *JobStatus {
+	now := time.Now().UTC()
+	return &JobStatus{ID: newRandomID(), State: JobPending, CreatedAt: now, UpdatedAt: now}
+}
+
+// Done reports whether the job has finished.
+func (j *JobStatus) Done() bool {
+	return j.State == JobSucceeded || j.State == JobFailed
+}
+
+// Start marks the job as running.
+func (j *JobStatus) Start() {
+	j.transition(JobRunning)
+}
+
+// Succeed marks the job as finished with an inline result or a result URL.
+func (j *JobStatus) Succeed(result any, resultURL string) {
+	j.Result = result
+	j.ResultURL = resultURL
+	j.transition(JobSucceeded)
+}
+
+// Fail marks the job as failed with the problem that clients should see.
+func (j *JobStatus) Fail(problem *ProblemDetails) {
+	j.Problem = problem
+	j.transition(JobFailed)
+}
+
+func (j *JobStatus) transition(state JobState) {
+	j.State = state
+	j.UpdatedAt = time.Now().UTC()
+}
+
+// JobStore persists job statuses so any instance can answer status requests.
+type JobStore interface {
+	Get(ctx context.Context, id string) (*JobStatus, bool, error)
+	Save(ctx context.Context, job *JobStatus) error
+}
+
+// MemoryJobStore is an in-process JobStore for tests and single-instance services.
+type MemoryJobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]JobStatus
+}
+
+// NewMemoryJobStore returns an empty in-memory job store.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[string]JobStatus)}
+}
+
+// Get returns a copy of the stored job.
+func (s *MemoryJobStore) Get(_ context.Context, id string) (*JobStatus, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, false, nil
+	}
+	return &job, true, nil
+}
+
+// Save stores a copy of job, replacing any previous version.
+func (s *MemoryJobStore) Save(_ context.Context, job *JobStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = *job
+	return nil
+}
+
+// SendAccepted writes 202 Accepted with the job and points Location at the
+// URL clients should poll for its status.
+func SendAccepted(w http.ResponseWriter, job *JobStatus, statusURL string) {
+	builder := Respond(job).Status(http.StatusAccepted)
+	if statusURL != "" {
+		builder.Header("Location", statusURL)
+	}
+	builder.Write(w)
+}
+
+// JobStatusOptions configures JobStatusHandler.
+type JobStatusOptions struct {
+	// Param names the path parameter holding the job ID. Defaults to "id".
+	Param string
+	// PollInterval is suggested through Retry-After while the job runs.
+	PollInterval time.Duration
+}
+
+// JobStatusHandler serves job statuses from store. Unknown jobs reply 404;
+// unfinished jobs carry Retry-After, and finished jobs with a ResultURL carry
+// it in the Location header.
+func JobStatusHandler(store JobStore, paramExtractor ParamExtractor, opts *JobStatusOptions) http.Handler {
+	options := JobStatusOptions{Param: "id"}
+	if opts != nil {
+		if opts.Param != "" {
+			options.Param = opts.Param
+		}
+		options.PollInterval = opts.PollInterval
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := paramExtractor(r, options.Param)
+		job, ok, err := store.Get(r.Context(), id)
+		if err != nil {
+			ErrorResponse(w, err)
+			return
+		}
+		if !ok {
+			sendRequestProblem(w, r, http.StatusNotFound, ProblemNotFound("job "+id+" not found").Build())
+			return
+		}
+
+		builder := Respond(job)
+		if !job.Done() && options.PollInterval > 0 {
+			builder.Header("Retry-After", strconv.FormatInt(retryAfterSeconds(options.PollInterval), 10))
+		}
+		if job.State == JobSucceeded && job.ResultURL != "" {
+			builder.Header("Location", job.ResultURL)
+		}
+		builder.Write(w)
+	})
+}
@@ -0,0 +1,95 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSendAccepted(t *testing.T) {
+	t.Parallel()
+
+	job := NewJobStatus()
+	w := httptest.NewRecorder()
+	SendAccepted(w, job, "/jobs/"+job.ID)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+	if w.Header().Get("Location") != "/jobs/"+job.ID {
+		t.Fatalf("unexpected Location %q", w.Header().Get("Location"))
+	}
+	var response Response[JobStatus]
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Data.ID != job.ID || response.Data.State != JobPending {
+		t.Fatalf("unexpected job %#v", response.Data)
+	}
+}
+
+func TestJobStatusHandler(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryJobStore()
+	ctx := context.Background()
+
+	running := NewJobStatus()
+	running.Start()
+	succeeded := NewJobStatus()
+	succeeded.Succeed(nil, "/reports/42")
+	failed := NewJobStatus()
+	failed.Fail(NewBadRequestProblem("input file is empty"))
+	for _, job := range []*JobStatus{running, succeeded, failed} {
+		if err := store.Save(ctx, job); err != nil {
+			t.Fatalf("save job: %v", err)
+		}
+	}
+
+	handler := JobStatusHandler(store, testParamExtractor, &JobStatusOptions{PollInterval: 2 * time.Second})
+
+	tests := []struct {
+		name           string
+		id             string
+		wantStatus     int
+		wantState      JobState
+		wantRetryAfter string
+		wantLocation   string
+	}{
+		{name: "running", id: running.ID, wantStatus: http.StatusOK, wantState: JobRunning, wantRetryAfter: "2"},
+		{name: "succeeded", id: succeeded.ID, wantStatus: http.StatusOK, wantState: JobSucceeded, wantLocation: "/reports/42"},
+		{name: "failed", id: failed.ID, wantStatus: http.StatusOK, wantState: JobFailed},
+		{name: "unknown", id: "missing", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if w.Header().Get("Retry-After") != tt.wantRetryAfter || w.Header().Get("Location") != tt.wantLocation {
+				t.Fatalf("unexpected headers %#v", w.Header())
+			}
+			if tt.wantState == "" {
+				return
+			}
+			var response Response[JobStatus]
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if response.Data.State != tt.wantState {
+				t.Fatalf("expected state %q, got %q", tt.wantState, response.Data.State)
+			}
+			if tt.wantState == JobFailed && (response.Data.Problem == nil || response.Data.Problem.Detail != "input file is empty") {
+				t.Fatalf("expected failure problem, got %#v", response.Data.Problem)
+			}
+		})
+	}
+}
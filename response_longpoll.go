@@ -0,0 +1,70 @@
+package httpsuite
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultLongPollTimeout = 30 * time.Second
+
+// LongPollOptions configures LongPoll.
+type LongPollOptions struct {
+	// Timeout bounds how long the request waits for data. Defaults to 30s.
+	// Clients may ask for less with "Prefer: wait=<seconds>".
+	Timeout time.Duration
+}
+
+// LongPoll blocks until wait returns data or the timeout expires. Data is
+// written as 200 and a timeout as 204 No Content. wait must return promptly
+// with the context error once ctx is done. When the client disconnects
+// nothing is written and the context error is returned; other errors from
+// wait are written with ErrorResponse and returned.
+func LongPoll[T any](w http.ResponseWriter, r *http.Request, opts *LongPollOptions, wait func(ctx context.Context) (T, error)) error {
+	timeout := defaultLongPollTimeout
+	if opts != nil && opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+	if preferred, ok := preferWait(r); ok && preferred < timeout {
+		timeout = preferred
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	data, err := wait(ctx)
+	switch {
+	case err == nil:
+		OK(w, data)
+		return nil
+	case r.Context().Err() != nil:
+		return r.Context().Err()
+	case errors.Is(err, context.DeadlineExceeded):
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	default:
+		ErrorResponse(w, err)
+		return err
+	}
+}
+
+// preferWait reads the RFC 7240 "wait" preference.
+func preferWait(r *http.Request) (time.Duration, bool) {
+	for _, value := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(value, ",") {
+			name, arg, ok := strings.Cut(strings.TrimSpace(preference), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "wait") {
+				continue
+			}
+			seconds, err := strconv.Atoi(strings.Trim(strings.TrimSpace(arg), `"`))
+			if err != nil || seconds < 0 {
+				return 0, false
+			}
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+	return 0, false
+}
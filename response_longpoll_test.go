@@ -0,0 +1,80 @@
+package httpsuite
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLongPoll(t *testing.T) {
+	t.Parallel()
+
+	ready := make(chan string, 1)
+	ready <- "event-1"
+	waitForEvent := func(ctx context.Context) (string, error) {
+		select {
+		case event := <-ready:
+			return event, nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	t.Run("data available", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := LongPoll(w, httptest.NewRequest(http.MethodGet, "/events", nil), &LongPollOptions{Timeout: time.Second}, waitForEvent)
+		if err != nil || w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d (%v)", w.Code, err)
+		}
+		if w.Body.String() != "{\"data\":\"event-1\"}\n" {
+			t.Fatalf("unexpected body %q", w.Body.String())
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := LongPoll(w, httptest.NewRequest(http.MethodGet, "/events", nil), &LongPollOptions{Timeout: 10 * time.Millisecond}, waitForEvent)
+		if err != nil || w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+			t.Fatalf("expected empty 204, got %d %q (%v)", w.Code, w.Body.String(), err)
+		}
+	})
+
+	t.Run("prefer wait", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		req.Header.Set("Prefer", "respond-async, wait=0")
+		started := time.Now()
+		w := httptest.NewRecorder()
+		if err := LongPoll(w, req, &LongPollOptions{Timeout: time.Minute}, waitForEvent); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if w.Code != http.StatusNoContent || time.Since(started) > 5*time.Second {
+			t.Fatalf("expected Prefer: wait to shorten the timeout, got %d", w.Code)
+		}
+	})
+
+	t.Run("client gone", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		w := httptest.NewRecorder()
+		err := LongPoll(w, httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(ctx), nil, waitForEvent)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if w.Body.Len() != 0 {
+			t.Fatalf("expected nothing written, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("wait error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := LongPoll(w, httptest.NewRequest(http.MethodGet, "/events", nil), nil, func(context.Context) (string, error) {
+			return "", NewNotFoundProblem("stream closed")
+		})
+		if err == nil || w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404 problem, got %d (%v)", w.Code, err)
+		}
+	})
+}
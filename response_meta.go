@@ -1,5 +1,11 @@
 package httpsuite
 
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
 // Response represents the structure of an HTTP response, including an optional body and metadata.
 type Response[T any] struct {
 	Data T   `json:"data"`
@@ -12,9 +18,13 @@ type PageMeta struct {
 	PageSize   int `json:"page_size,omitempty"`
 	TotalPages int `json:"total_pages,omitempty"`
 	TotalItems int `json:"total_items,omitempty"`
+	// Extra holds members beyond pagination, such as those set by
+	// WithRateLimit, WithProcessingTime, and WithAPIVersion.
+	Extra map[string]any `json:"-"`
 }
 
 // Meta is kept as a compatibility alias for page-based pagination metadata.
+// An empty &Meta{} with extra members carries metadata without pagination.
 type Meta = PageMeta
 
 // CursorMeta provides cursor-based pagination details.
@@ -23,6 +33,9 @@ type CursorMeta struct {
 	PrevCursor string `json:"prev_cursor,omitempty"`
 	HasNext    bool   `json:"has_next"`
 	HasPrev    bool   `json:"has_prev"`
+	// Extra holds members beyond pagination, such as those set by
+	// WithRateLimit, WithProcessingTime, and WithAPIVersion.
+	Extra map[string]any `json:"-"`
 }
 
 // NewPageMeta builds page-based metadata and derives total pages when possible.
@@ -47,3 +60,184 @@ func NewCursorMeta(nextCursor, prevCursor string, hasNext, hasPrev bool) *Cursor
 		HasPrev:    hasPrev,
 	}
 }
+
+// Keys of the extra meta members set by the typed helpers.
+const (
+	MetaKeyRateLimit      = "rate_limit"
+	MetaKeyProcessingTime = "processing_time_ms"
+	MetaKeyAPIVersion     = "api_version"
+)
+
+// RateLimitMeta describes the caller's rate limit in response metadata.
+type RateLimitMeta struct {
+	Limit     int `json:"limit"`
+	Remaining int `json:"remaining"`
+	// Reset is when the limit resets, in Unix seconds like X-Quota-Reset.
+	Reset int64 `json:"reset,omitempty"`
+}
+
+// NewRateLimitMeta builds rate-limit metadata. A zero reset is omitted.
+func NewRateLimitMeta(limit, remaining int, reset time.Time) RateLimitMeta {
+	meta := RateLimitMeta{Limit: limit, Remaining: remaining}
+	if !reset.IsZero() {
+		meta.Reset = reset.Unix()
+	}
+	return meta
+}
+
+// With sets an extra meta member. Extra members are encoded next to the
+// pagination fields, which they never replace. Use an empty &Meta{} to send
+// only extra members.
+func (m *PageMeta) With(key string, value any) *PageMeta {
+	m.Extra = withMetaExtra(m.Extra, key, value)
+	return m
+}
+
+// WithRateLimit sets the rate_limit member.
+func (m *PageMeta) WithRateLimit(info RateLimitMeta) *PageMeta {
+	return m.With(MetaKeyRateLimit, info)
+}
+
+// WithProcessingTime sets the processing_time_ms member.
+func (m *PageMeta) WithProcessingTime(d time.Duration) *PageMeta {
+	return m.With(MetaKeyProcessingTime, processingMillis(d))
+}
+
+// WithAPIVersion sets the api_version member.
+func (m *PageMeta) WithAPIVersion(version string) *PageMeta {
+	return m.With(MetaKeyAPIVersion, version)
+}
+
+// MarshalJSON encodes the pagination fields followed by Extra.
+func (m PageMeta) MarshalJSON() ([]byte, error) {
+	type plain PageMeta
+	return marshalMeta(plain(m), reflect.TypeFor[PageMeta](), m.Extra)
+}
+
+// UnmarshalJSON decodes the pagination fields and collects other members
+// into Extra.
+func (m *PageMeta) UnmarshalJSON(data []byte) error {
+	type plain PageMeta
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	extra, err := unmarshalMetaExtra(data, reflect.TypeFor[PageMeta]())
+	if err != nil {
+		return err
+	}
+	*m = PageMeta(decoded)
+	m.Extra = extra
+	return nil
+}
+
+// With sets an extra meta member. Extra members are encoded next to the
+// cursor fields, which they never replace.
+func (m *CursorMeta) With(key string, value any) *CursorMeta {
+	m.Extra = withMetaExtra(m.Extra, key, value)
+	return m
+}
+
+// WithRateLimit sets the rate_limit member.
+func (m *CursorMeta) WithRateLimit(info RateLimitMeta) *CursorMeta {
+	return m.With(MetaKeyRateLimit, info)
+}
+
+// WithProcessingTime sets the processing_time_ms member.
+func (m *CursorMeta) WithProcessingTime(d time.Duration) *CursorMeta {
+	return m.With(MetaKeyProcessingTime, processingMillis(d))
+}
+
+// WithAPIVersion sets the api_version member.
+func (m *CursorMeta) WithAPIVersion(version string) *CursorMeta {
+	return m.With(MetaKeyAPIVersion, version)
+}
+
+// MarshalJSON encodes the cursor fields followed by Extra.
+func (m CursorMeta) MarshalJSON() ([]byte, error) {
+	type plain CursorMeta
+	return marshalMeta(plain(m), reflect.TypeFor[CursorMeta](), m.Extra)
+}
+
+// UnmarshalJSON decodes the cursor fields and collects other members into
+// Extra.
+func (m *CursorMeta) UnmarshalJSON(data []byte) error {
+	type plain CursorMeta
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	extra, err := unmarshalMetaExtra(data, reflect.TypeFor[CursorMeta]())
+	if err != nil {
+		return err
+	}
+	*m = CursorMeta(decoded)
+	m.Extra = extra
+	return nil
+}
+
+func withMetaExtra(extra map[string]any, key string, value any) map[string]any {
+	if extra == nil {
+		extra = make(map[string]any)
+	}
+	extra[key] = value
+	return extra
+}
+
+// processingMillis reports d in milliseconds with microsecond precision.
+func processingMillis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// marshalMeta appends the extra members that do not collide with a field of
+// t to the JSON object encoding base.
+func marshalMeta(base any, t reflect.Type, extra map[string]any) ([]byte, error) {
+	data, err := json.Marshal(base)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	members := make(map[string]any, len(extra))
+	for key, value := range extra {
+		if !isMetaField(t, key) {
+			members[key] = value
+		}
+	}
+	if len(members) == 0 {
+		return data, nil
+	}
+	encoded, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 2 {
+		return encoded, nil
+	}
+	return append(append(data[:len(data)-1], ','), encoded[1:]...), nil
+}
+
+// unmarshalMetaExtra returns the members of data that are not fields of t,
+// or nil when there are none.
+func unmarshalMetaExtra(data []byte, t reflect.Type) (map[string]any, error) {
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for key := range members {
+		if isMetaField(t, key) {
+			delete(members, key)
+		}
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return members, nil
+}
+
+func isMetaField(t reflect.Type, key string) bool {
+	for _, field := range metadataFor(t).fields {
+		if field.json && field.name == key {
+			return true
+		}
+	}
+	return false
+}
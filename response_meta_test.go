@@ -2,8 +2,10 @@ package httpsuite
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestMetaSerialization(t *testing.T) {
@@ -74,3 +76,62 @@ func TestResponseSerializationPreservesMeaningfulZeroValues(t *testing.T) {
 		})
 	}
 }
+
+func TestMetaExtraMembers(t *testing.T) {
+	t.Parallel()
+
+	reset := time.Unix(1700000000, 0)
+	tests := []struct {
+		name string
+		meta any
+		want string
+	}{
+		{
+			name: "page",
+			meta: NewPageMeta(2, 10, 15).WithAPIVersion("2024-06-01").With("page", 99),
+			want: `{"page":2,"page_size":10,"total_pages":2,"total_items":15,"api_version":"2024-06-01"}`,
+		},
+		{
+			name: "cursor",
+			meta: NewCursorMeta("next", "", true, false).WithRateLimit(NewRateLimitMeta(100, 42, reset)),
+			want: `{"next_cursor":"next","has_next":true,"has_prev":false,"rate_limit":{"limit":100,"remaining":42,"reset":1700000000}}`,
+		},
+		{
+			name: "extra only",
+			meta: (&Meta{}).WithProcessingTime(12345 * time.Microsecond),
+			want: `{"processing_time_ms":12.345}`,
+		},
+		{
+			name: "no extra",
+			meta: &Meta{},
+			want: `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.meta)
+		if err != nil || string(data) != tt.want {
+			t.Fatalf("%s: expected %s, got %s %v", tt.name, tt.want, data, err)
+		}
+	}
+}
+
+func TestMetaUnmarshalCollectsExtra(t *testing.T) {
+	t.Parallel()
+
+	var page PageMeta
+	if err := json.Unmarshal([]byte(`{"page":3,"api_version":"v2"}`), &page); err != nil {
+		t.Fatalf("unmarshal page meta: %v", err)
+	}
+	if page.Page != 3 || !reflect.DeepEqual(page.Extra, map[string]any{"api_version": "v2"}) {
+		t.Fatalf("expected page and extra members, got %+v", page)
+	}
+
+	var cursor CursorMeta
+	if err := json.Unmarshal([]byte(`{"next_cursor":"n","has_next":true}`), &cursor); err != nil {
+		t.Fatalf("unmarshal cursor meta: %v", err)
+	}
+	if cursor.NextCursor != "n" || !cursor.HasNext || cursor.Extra != nil {
+		t.Fatalf("expected cursor fields without extra members, got %+v", cursor)
+	}
+}
@@ -0,0 +1,98 @@
+package httpsuite
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Preload describes an asset the client should fetch early, sent as a
+// "Link: <url>; rel=preload" header.
+type Preload struct {
+	URL string
+	// As is the request destination, e.g. "style", "script", "font", "image",
+	// or "fetch". Browsers ignore preloads without it.
+	As string
+	// Type is the optional MIME type, letting browsers skip unsupported formats.
+	Type string
+	// CrossOrigin is "anonymous" or "use-credentials". Fonts and fetches
+	// need it to reuse the preloaded response.
+	CrossOrigin string
+	// Push also pushes same-origin assets over HTTP/2 when the server
+	// supports http.Pusher. Most browsers ignore pushes, so prefer plain
+	// preloads or SendEarlyHints unless the clients are known to use them.
+	Push bool
+}
+
+// String returns the Link header value for p, usable with SendEarlyHints.
+func (p Preload) String() string {
+	var b strings.Builder
+	b.WriteString("<")
+	b.WriteString(p.URL)
+	b.WriteString(">; rel=preload")
+	if p.As != "" {
+		b.WriteString("; as=")
+		b.WriteString(p.As)
+	}
+	if p.Type != "" {
+		b.WriteString(`; type="`)
+		b.WriteString(p.Type)
+		b.WriteString(`"`)
+	}
+	if p.CrossOrigin != "" {
+		b.WriteString("; crossorigin=")
+		b.WriteString(p.CrossOrigin)
+	}
+	return b.String()
+}
+
+// AddPreloads adds links as Link headers and pushes those marked Push. Call
+// it before the response is written:
+//
+//	httpsuite.AddPreloads(w,
+//		httpsuite.Preload{URL: "/app.css", As: "style"},
+//		httpsuite.Preload{URL: "/font.woff2", As: "font", Type: "font/woff2", CrossOrigin: "anonymous"},
+//	)
+//
+// Pushes are skipped without error over HTTP/1.x, for absolute URLs, and
+// when the client disabled them.
+func AddPreloads(w http.ResponseWriter, links ...Preload) {
+	for _, link := range links {
+		if link.URL == "" {
+			continue
+		}
+		w.Header().Add("Link", link.String())
+		if link.Push {
+			pushPreload(w, link.URL)
+		}
+	}
+}
+
+func pushPreload(w http.ResponseWriter, target string) {
+	if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") {
+		return
+	}
+	pusher := pusherOf(w)
+	if pusher == nil {
+		return
+	}
+	if err := pusher.Push(target, nil); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Failed to push %s: %v", target, err)
+	}
+}
+
+// pusherOf returns the http.Pusher behind the suite's writer wrappers, if any.
+func pusherOf(w http.ResponseWriter) http.Pusher {
+	for w != nil {
+		if pusher, ok := w.(http.Pusher); ok {
+			return pusher
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = unwrapper.Unwrap()
+	}
+	return nil
+}
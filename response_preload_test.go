@@ -0,0 +1,112 @@
+package httpsuite
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestPreloadString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		preload Preload
+		want    string
+	}{
+		{preload: Preload{URL: "/app.css", As: "style"}, want: "</app.css>; rel=preload; as=style"},
+		{
+			preload: Preload{URL: "/font.woff2", As: "font", Type: "font/woff2", CrossOrigin: "anonymous"},
+			want:    `</font.woff2>; rel=preload; as=font; type="font/woff2"; crossorigin=anonymous`,
+		},
+		{preload: Preload{URL: "https://cdn.example.com/app.js"}, want: "<https://cdn.example.com/app.js>; rel=preload"},
+	}
+	for _, tt := range tests {
+		if got := tt.preload.String(); got != tt.want {
+			t.Fatalf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+	err    error
+}
+
+func (p *pushRecorder) Push(target string, opts *http.PushOptions) error {
+	p.pushed = append(p.pushed, target)
+	return p.err
+}
+
+func TestAddPreloads(t *testing.T) {
+	t.Parallel()
+
+	pusher := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	w := newStatusRecorder(pusher)
+	AddPreloads(w,
+		Preload{URL: "/app.css", As: "style", Push: true},
+		Preload{URL: "/app.js", As: "script"},
+		Preload{URL: "https://cdn.example.com/lib.js", As: "script", Push: true},
+		Preload{URL: "//cdn.example.com/other.js", As: "script", Push: true},
+		Preload{},
+	)
+
+	links := pusher.Header().Values("Link")
+	if len(links) != 4 || links[0] != "</app.css>; rel=preload; as=style" {
+		t.Fatalf("expected four Link headers, got %q", links)
+	}
+	if !slices.Equal(pusher.pushed, []string{"/app.css"}) {
+		t.Fatalf("expected only the same-origin asset to be pushed, got %v", pusher.pushed)
+	}
+
+	pusher = &pushRecorder{ResponseRecorder: httptest.NewRecorder(), err: http.ErrNotSupported}
+	AddPreloads(pusher, Preload{URL: "/app.css", Push: true})
+	pusher.err = errors.New("stream closed")
+	AddPreloads(pusher, Preload{URL: "/app.css", Push: true})
+	if len(pusher.Header().Values("Link")) != 2 {
+		t.Fatalf("expected push failures to keep the Link headers, got %q", pusher.Header().Values("Link"))
+	}
+
+	plain := httptest.NewRecorder()
+	AddPreloads(plain, Preload{URL: "/app.css", Push: true})
+	if plain.Header().Get("Link") == "" {
+		t.Fatal("expected a Link header without a pusher")
+	}
+}
+
+func TestResponseBuilderPreload(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	Reply().Preload(Preload{URL: "/app.css", As: "style"}).OK(w, "ok")
+	if got := w.Header().Get("Link"); got != "</app.css>; rel=preload; as=style" {
+		t.Fatalf("expected preload link on OK, got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	Respond("ok").Preload(Preload{URL: "/a.js", As: "script"}, Preload{URL: "/b.js", As: "script"}).Write(w)
+	if links := w.Header().Values("Link"); len(links) != 2 || w.Code != http.StatusOK {
+		t.Fatalf("expected two preload links, got %d %q", w.Code, links)
+	}
+}
+
+func TestTemplatesPreload(t *testing.T) {
+	t.Parallel()
+
+	templates, err := LoadTemplates(testTemplateFS(), &TemplateOptions{
+		Pages:   []string{"pages/home.html"},
+		Layouts: []string{"layouts/*.html"},
+		Layout:  "base",
+		Preload: []Preload{{URL: "/app.css", As: "style"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w := httptest.NewRecorder()
+	templates.Render(w, http.StatusOK, "home", map[string]string{"Name": "Ann"})
+	if got := w.Header().Get("Link"); got != "</app.css>; rel=preload; as=style" {
+		t.Fatalf("expected layout preload on rendered pages, got %q", got)
+	}
+}
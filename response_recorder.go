@@ -0,0 +1,62 @@
+package httpsuite
+
+import "net/http"
+
+// statusRecorder captures the status code written through a ResponseWriter.
+type statusRecorder struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+func newStatusRecorder(w http.ResponseWriter) *statusRecorder {
+	if recorder, ok := w.(*statusRecorder); ok {
+		return recorder
+	}
+	return &statusRecorder{ResponseWriter: w}
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 && !isInformational(code) {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.written += int64(n)
+	return n, err
+}
+
+// Status returns the written status code, or 200 when the handler wrote nothing.
+func (r *statusRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Flush flushes through any writers wrapped below this one.
+func (r *statusRecorder) Flush() {
+	_ = r.FlushError()
+}
+
+// FlushError is used by http.ResponseController.
+func (r *statusRecorder) FlushError() error {
+	if err := http.NewResponseController(r.ResponseWriter).Flush(); err != nil {
+		return err
+	}
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return nil
+}
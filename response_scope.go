@@ -0,0 +1,147 @@
+package httpsuite
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// WithScopes returns a context carrying the scopes or roles granted to the
+// caller. Authentication middleware sets them next to PrincipalKey so scoped
+// responses can tell which fields the caller may see.
+func WithScopes(ctx context.Context, scopes ...string) context.Context {
+	return CtxSet(ctx, ScopesKey, scopes)
+}
+
+// ScopesFromContext returns the scopes stored by WithScopes.
+func ScopesFromContext(ctx context.Context) []string {
+	scopes, _ := CtxGet(ctx, ScopesKey)
+	return scopes
+}
+
+// FilterScopes converts value into JSON-shaped data without the fields the
+// given scopes do not grant, so one struct can serve several permission
+// levels. Fields tagged `scope` list the scopes that may see them; any one
+// of them is enough:
+//
+//	type User struct {
+//		Name  string `json:"name"`
+//		Email string `json:"email" scope:"admin,support"`
+//		Notes string `json:"notes" scope:"admin"`
+//	}
+//
+// Untagged fields are always kept. Without scopes every tagged field is
+// removed.
+func FilterScopes(value any, scopes ...string) any {
+	return redactValue(value, redactMode{scoped: true, scopes: scopes})
+}
+
+// hasScopedFields reports whether value holds a struct with `scope` tags,
+// looking into interface values only when the static types cannot tell.
+func hasScopedFields(value reflect.Value) bool {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return false
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return false
+	}
+	metadata := metadataFor(value.Type())
+	if metadata.scopeTags {
+		return true
+	}
+	if !metadata.scopeDynamic {
+		return false
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		for _, field := range metadata.fields {
+			if hasScopedFields(value.Field(field.index)) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if hasScopedFields(value.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			if hasScopedFields(iter.Value()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scanScopeTags reports whether t, or a type reachable from it, declares
+// `scope` tags, and whether an interface is reachable. Types that marshal
+// themselves are opaque to both.
+func scanScopeTags(t reflect.Type, visiting map[reflect.Type]bool) (tagged, dynamic bool) {
+	if visiting[t] {
+		return false, false
+	}
+	visiting[t] = true
+
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return false, false
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return scanScopeTags(t.Elem(), visiting)
+	case reflect.Interface:
+		return false, true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get("scope") != "" {
+				return true, dynamic
+			}
+			fieldTagged, fieldDynamic := scanScopeTags(field.Type, visiting)
+			if fieldTagged {
+				return true, dynamic
+			}
+			dynamic = dynamic || fieldDynamic
+		}
+	}
+	return false, dynamic
+}
+
+// fieldScopes parses the `scope` tag of field.
+func fieldScopes(field reflect.StructField) []string {
+	tag := field.Tag.Get("scope")
+	if tag == "" {
+		return nil
+	}
+	var scopes []string
+	for _, scope := range strings.Split(tag, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	if scopes == nil {
+		// A tag without scope names grants nothing rather than everything.
+		scopes = []string{}
+	}
+	return scopes
+}
+
+// grantsScope reports whether granted holds one of required.
+func grantsScope(granted, required []string) bool {
+	for _, scope := range required {
+		for _, have := range granted {
+			if have == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+type ScopeTestAudit struct {
+	UpdatedBy string `json:"updated_by" scope:"admin"`
+}
+
+type scopedNote struct {
+	Text     string `json:"text"`
+	Internal bool   `json:"internal" scope:"staff"`
+}
+
+type scopedUser struct {
+	ScopeTestAudit
+	Name    string       `json:"name"`
+	Email   string       `json:"email" scope:"admin, support"`
+	Phone   string       `json:"phone" scope:"support" mask:"last4"`
+	Notes   []scopedNote `json:"notes"`
+	Blocked string       `json:"blocked" scope:","`
+}
+
+func TestFilterScopes(t *testing.T) {
+	t.Parallel()
+
+	user := scopedUser{
+		ScopeTestAudit: ScopeTestAudit{UpdatedBy: "ops"},
+		Name:           "Jo",
+		Email:          "jo@example.com",
+		Phone:          "+1 555 0100",
+		Notes:          []scopedNote{{Text: "vip", Internal: true}},
+		Blocked:        "never",
+	}
+
+	tests := []struct {
+		name   string
+		scopes []string
+		want   string
+	}{
+		{name: "anonymous", want: `{"name":"Jo","notes":[{"text":"vip"}]}`},
+		{name: "support", scopes: []string{"support"}, want: `{"email":"jo@example.com","name":"Jo","notes":[{"text":"vip"}],"phone":"+1 555 0100"}`},
+		{name: "admin and staff", scopes: []string{"staff", "admin"}, want: `{"email":"jo@example.com","name":"Jo","notes":[{"internal":true,"text":"vip"}],"updated_by":"ops"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := json.Marshal(FilterScopes(&user, tt.scopes...))
+			if err != nil {
+				t.Fatalf("marshal filtered value: %v", err)
+			}
+			if string(encoded) != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, encoded)
+			}
+		})
+	}
+
+	if FilterScopes(nil, "admin") != nil {
+		t.Fatal("expected nil to stay nil")
+	}
+}
+
+func TestScopesFromContext(t *testing.T) {
+	t.Parallel()
+
+	if scopes := ScopesFromContext(context.Background()); scopes != nil {
+		t.Fatalf("expected no scopes, got %v", scopes)
+	}
+	ctx := WithScopes(context.Background(), "admin", "billing")
+	if scopes := ScopesFromContext(ctx); len(scopes) != 2 || scopes[0] != "admin" || scopes[1] != "billing" {
+		t.Fatalf("expected [admin billing], got %v", scopes)
+	}
+}
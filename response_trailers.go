@@ -0,0 +1,69 @@
+package httpsuite
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// forbiddenTrailers are fields that must not be sent as trailers because
+// they frame, route, or authenticate the message; net/http drops them.
+var forbiddenTrailers = map[string]bool{
+	"Authorization":       true,
+	"Cache-Control":       true,
+	"Connection":          true,
+	"Content-Encoding":    true,
+	"Content-Length":      true,
+	"Content-Range":       true,
+	"Content-Type":        true,
+	"Expect":              true,
+	"Host":                true,
+	"Keep-Alive":          true,
+	"Max-Forwards":        true,
+	"Pragma":              true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+	"Proxy-Connection":    true,
+	"Range":               true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Www-Authenticate":    true,
+}
+
+// Trailers sets HTTP trailer fields, values computed while a body streams
+// such as a checksum or a row count, after the body has been written.
+type Trailers struct {
+	w        http.ResponseWriter
+	declared map[string]bool
+}
+
+// DeclareTrailers announces the trailer fields in the Trailer header. Call it
+// before the status or any body is written; clients only accept declared
+// trailers. Fields that may not be trailers, such as Content-Length, are an
+// error.
+func DeclareTrailers(w http.ResponseWriter, names ...string) (*Trailers, error) {
+	trailers := &Trailers{w: w, declared: make(map[string]bool, len(names))}
+	for _, name := range names {
+		canonical := http.CanonicalHeaderKey(name)
+		if canonical == "" || forbiddenTrailers[canonical] {
+			return nil, fmt.Errorf("invalid trailer %q", name)
+		}
+		if trailers.declared[canonical] {
+			continue
+		}
+		trailers.declared[canonical] = true
+		w.Header().Add("Trailer", canonical)
+	}
+	return trailers, nil
+}
+
+// Set sets the value of a declared trailer. Call it once the body has been
+// written, before the handler returns.
+func (t *Trailers) Set(name, value string) error {
+	canonical := http.CanonicalHeaderKey(name)
+	if !t.declared[canonical] {
+		return fmt.Errorf("trailer %q was not declared", name)
+	}
+	t.w.Header().Set(canonical, value)
+	return nil
+}
@@ -0,0 +1,72 @@
+package httpsuite
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestTrailers(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(Audit(AuditorFunc(func(context.Context, AuditEntry) {}), nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trailers, err := DeclareTrailers(w, "x-row-count", "X-Checksum", "X-Row-Count")
+		if err != nil {
+			t.Errorf("declare: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		hash := sha256.New()
+		rows := 0
+		for _, row := range []string{`{"id":1}`, `{"id":2}`} {
+			line := row + "\n"
+			_, _ = io.WriteString(io.MultiWriter(w, hash), line)
+			_ = Flush(w)
+			rows++
+		}
+		if err := trailers.Set("X-Row-Count", strconv.Itoa(rows)); err != nil {
+			t.Errorf("set: %v", err)
+		}
+		if err := trailers.Set("X-Checksum", hex.EncodeToString(hash.Sum(nil))); err != nil {
+			t.Errorf("set: %v", err)
+		}
+		if err := trailers.Set("X-Other", "1"); err == nil {
+			t.Error("expected an error for an undeclared trailer")
+		}
+	})))
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+
+	sum := sha256.Sum256(body)
+	if resp.Trailer.Get("X-Row-Count") != "2" {
+		t.Fatalf("expected row count trailer 2, got %v", resp.Trailer)
+	}
+	if resp.Trailer.Get("X-Checksum") != hex.EncodeToString(sum[:]) {
+		t.Fatalf("expected checksum trailer %x, got %q", sum, resp.Trailer.Get("X-Checksum"))
+	}
+	if len(resp.Trailer) != 2 {
+		t.Fatalf("expected two declared trailers, got %v", resp.Trailer)
+	}
+}
+
+func TestDeclareTrailersRejectsFramingFields(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"Content-Length", "transfer-encoding", "Trailer", ""} {
+		rec := httptest.NewRecorder()
+		if _, err := DeclareTrailers(rec, "X-Checksum", name); err == nil {
+			t.Fatalf("expected an error for trailer %q", name)
+		}
+	}
+}
@@ -5,21 +5,51 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
+	"sync"
 )
 
+// maxPooledBufferBytes keeps unusually large responses from pinning memory
+// in the encode buffer pool.
+const maxPooledBufferBytes = 256 << 10
+
+const problemContentType = "application/problem+json; charset=utf-8"
+
+var encodeBufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+func getEncodeBuffer() *bytes.Buffer {
+	buffer := encodeBufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	return buffer
+}
+
+func putEncodeBuffer(buffer *bytes.Buffer) {
+	if buffer.Cap() <= maxPooledBufferBytes {
+		encodeBufferPool.Put(buffer)
+	}
+}
+
 func writeResponse[T any](w http.ResponseWriter, code int, data T, problem *ProblemDetails, meta any, headers http.Header) {
 	if code >= 400 && problem != nil {
 		writeProblemDetail(w, code, problem, headers)
 		return
 	}
 
+	timing := serverTimingOf(w)
+	if timing != nil && timing.meta {
+		meta = withTimingMeta(meta, timing.metaMillis())
+	}
 	response := &Response[T]{
 		Data: data,
 		Meta: meta,
 	}
 
-	var buffer bytes.Buffer
-	if err := json.NewEncoder(&buffer).Encode(response); err != nil {
+	buffer := getEncodeBuffer()
+	defer putEncodeBuffer(buffer)
+	encodeStarted := timing.clock()
+	err := json.NewEncoder(buffer).Encode(response)
+	timing.since(TimingEncode, encodeStarted)
+	if err != nil {
 		log.Printf("Error writing response: %v", err)
 
 		internalError := NewProblemDetails(
@@ -32,6 +62,9 @@ func writeResponse[T any](w http.ResponseWriter, code int, data T, problem *Prob
 		return
 	}
 
+	if hook := responseHookOf(w); hook != nil {
+		hook.payload = data
+	}
 	applyHeaders(w, headers)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(code)
@@ -41,6 +74,34 @@ func writeResponse[T any](w http.ResponseWriter, code int, data T, problem *Prob
 }
 
 func writeProblemDetail(w http.ResponseWriter, code int, problem *ProblemDetails, headers http.Header) {
+	timing := serverTimingOf(w)
+	buffer := getEncodeBuffer()
+	defer putEncodeBuffer(buffer)
+	// A problem sent while parsing ends the parse or validate phase.
+	timing.end()
+	encodeStarted := timing.clock()
+	status, retryAfter, ok := encodeProblemDetail(buffer, code, problem)
+	timing.since(TimingEncode, encodeStarted)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if retryAfter != "" && headers.Get("Retry-After") == "" && w.Header().Get("Retry-After") == "" {
+		w.Header().Set("Retry-After", retryAfter)
+	}
+
+	applyHeaders(w, headers)
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(status)
+	if _, err := w.Write(buffer.Bytes()); err != nil {
+		log.Printf("Failed to write problem details response body: %v", err)
+	}
+}
+
+// encodeProblemDetail encodes problem into buffer and returns the status to
+// send and the Retry-After value it asks for. Problems that fail to encode
+// are replaced with a generic 500; ok is false when even that fails.
+func encodeProblemDetail(buffer *bytes.Buffer, code int, problem *ProblemDetails) (status int, retryAfter string, ok bool) {
 	if problem == nil {
 		problem = NewProblemDetails(
 			http.StatusInternalServerError,
@@ -50,19 +111,21 @@ func writeProblemDetail(w http.ResponseWriter, code int, problem *ProblemDetails
 		)
 	}
 
-	effectiveStatus := code
-	if effectiveStatus < 400 || effectiveStatus > 599 {
-		effectiveStatus = problem.Status
+	status = code
+	if status < 400 || status > 599 {
+		status = problem.Status
 	}
-	if effectiveStatus < 400 || effectiveStatus > 599 {
-		effectiveStatus = http.StatusInternalServerError
+	if status < 400 || status > 599 {
+		status = http.StatusInternalServerError
 	}
 
 	normalized := *problem
-	normalized.Status = effectiveStatus
+	normalized.Status = status
+	if delay, ok := normalized.RetryAfter(); ok {
+		retryAfter = strconv.FormatInt(retryAfterSeconds(delay), 10)
+	}
 
-	var buffer bytes.Buffer
-	if err := json.NewEncoder(&buffer).Encode(normalized); err != nil {
+	if err := json.NewEncoder(buffer).Encode(normalized); err != nil {
 		log.Printf("Failed to encode problem details: %v", err)
 
 		fallback := NewProblemDetails(
@@ -72,20 +135,13 @@ func writeProblemDetail(w http.ResponseWriter, code int, problem *ProblemDetails
 			"An internal server error occurred.",
 		)
 		buffer.Reset()
-		if fallbackErr := json.NewEncoder(&buffer).Encode(fallback); fallbackErr != nil {
+		if fallbackErr := json.NewEncoder(buffer).Encode(fallback); fallbackErr != nil {
 			log.Printf("Failed to encode fallback problem details: %v", fallbackErr)
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-			return
+			return 0, "", false
 		}
-		effectiveStatus = http.StatusInternalServerError
-	}
-
-	applyHeaders(w, headers)
-	w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
-	w.WriteHeader(effectiveStatus)
-	if _, err := w.Write(buffer.Bytes()); err != nil {
-		log.Printf("Failed to write problem details response body: %v", err)
+		status = http.StatusInternalServerError
 	}
+	return status, retryAfter, true
 }
 
 func applyHeaders(w http.ResponseWriter, headers http.Header) {
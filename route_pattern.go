@@ -0,0 +1,75 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type routePatternContextKey struct{}
+
+// routePatternRecord carries the pattern matched deep inside the handler
+// back out to the middleware that installed it.
+type routePatternRecord struct {
+	mu      sync.Mutex
+	pattern string
+}
+
+// TrackRoutePattern returns middleware that makes the matched route pattern
+// visible to RoutePattern on its request context, even though routing
+// happens further in. Install it outside routers, metrics, and loggers, and
+// read the pattern after the wrapped handler returns. Audit installs it on
+// its own.
+func TrackRoutePattern() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, withRoutePatternRecord(r))
+		})
+	}
+}
+
+// RoutePattern returns the route template that matched the request, such as
+// "/users/{id}", for labelling metrics and logs without the cardinality of
+// raw paths. Handle and ParseRequest record it; routers without ServeMux
+// patterns can use SetRoutePattern. It is empty when nothing matched.
+func RoutePattern(ctx context.Context) string {
+	if record, ok := ctx.Value(routePatternContextKey{}).(*routePatternRecord); ok {
+		record.mu.Lock()
+		pattern := record.pattern
+		record.mu.Unlock()
+		if pattern != "" {
+			return pattern
+		}
+	}
+	pattern, _ := CtxGet(ctx, RoutePatternKey)
+	return pattern
+}
+
+// SetRoutePattern records pattern for RoutePattern and returns r with the
+// RoutePatternKey value set, for router integrations such as chi's
+// RouteContext().RoutePattern().
+func SetRoutePattern(r *http.Request, pattern string) *http.Request {
+	recordRoutePattern(r.Context(), pattern, true)
+	return r.WithContext(CtxSet(r.Context(), RoutePatternKey, pattern))
+}
+
+func withRoutePatternRecord(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(routePatternContextKey{}).(*routePatternRecord); ok {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), routePatternContextKey{}, &routePatternRecord{}))
+}
+
+// recordRoutePattern stores pattern in the tracking record, keeping an
+// earlier, more specific pattern unless overwrite is set.
+func recordRoutePattern(ctx context.Context, pattern string, overwrite bool) {
+	record, ok := ctx.Value(routePatternContextKey{}).(*routePatternRecord)
+	if !ok || pattern == "" {
+		return
+	}
+	record.mu.Lock()
+	defer record.mu.Unlock()
+	if overwrite || record.pattern == "" {
+		record.pattern = pattern
+	}
+}
@@ -0,0 +1,72 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRoutePattern(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(APIInfo{Title: "Items", ParamExtractor: testParamExtractor})
+	mux := http.NewServeMux()
+	mux.Handle("GET /typed/{id}", Handle(api, http.MethodGet, "/typed/{id}", func(ctx context.Context, req *testRequest) (*testRequest, error) {
+		if got := RoutePattern(ctx); got != "/typed/{id}" {
+			t.Errorf("expected pattern inside handler, got %q", got)
+		}
+		return req, nil
+	}, nil))
+	mux.HandleFunc("POST /plain/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ParseRequest[*testRequest](w, r, testParamExtractor, nil, "id"); err != nil {
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /custom/", func(w http.ResponseWriter, r *http.Request) {
+		r = SetRoutePattern(r, "/custom/{slug}")
+		w.Header().Set("X-Inner", RoutePattern(r.Context()))
+	})
+
+	var seen string
+	handler := TrackRoutePattern()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), struct{}{}, "clone")))
+		seen = RoutePattern(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   string
+	}{
+		{name: "typed handler", method: http.MethodGet, target: "/typed/7", want: "/typed/{id}"},
+		{name: "parse request", method: http.MethodPost, target: "/plain/7", body: `{"name":"lamp"}`, want: "POST /plain/{id}"},
+		{name: "router integration", method: http.MethodGet, target: "/custom/a/b", want: "/custom/{slug}"},
+		{name: "no match", method: http.MethodGet, target: "/missing", want: ""},
+	}
+
+	for _, tt := range tests {
+		seen = "unset"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+		if seen != tt.want {
+			t.Fatalf("%s: expected pattern %q, got %q", tt.name, tt.want, seen)
+		}
+	}
+}
+
+func TestRoutePatternWithoutTracking(t *testing.T) {
+	t.Parallel()
+
+	if got := RoutePattern(context.Background()); got != "" {
+		t.Fatalf("expected empty pattern, got %q", got)
+	}
+	r := SetRoutePattern(httptest.NewRequest(http.MethodGet, "/users/7", nil), "/users/{id}")
+	if got := RoutePattern(r.Context()); got != "/users/{id}" {
+		t.Fatalf("expected context pattern, got %q", got)
+	}
+}
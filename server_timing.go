@@ -0,0 +1,314 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Phases measured by ServerTiming. Handler time excludes the parse,
+// validate, and encode phases.
+const (
+	TimingParse    = "parse"
+	TimingValidate = "validate"
+	TimingHandler  = "handler"
+	TimingEncode   = "encode"
+	TimingTotal    = "total"
+)
+
+// MetaKeyTiming is the meta member that carries phase durations when
+// ServerTimingOptions.Meta is set.
+const MetaKeyTiming = "timing"
+
+// ServerTimingOptions configures the ServerTiming middleware.
+type ServerTimingOptions struct {
+	// Allow decides which requests get timings, e.g. only internal callers,
+	// since durations can reveal implementation details. Nil allows all.
+	Allow func(*http.Request) bool
+	// Meta adds the phases measured before encoding to the meta member
+	// "timing" of JSON responses, in milliseconds.
+	Meta bool
+	Now  func() time.Time
+}
+
+type serverTimingContextKey struct{}
+
+type timingPhase struct {
+	name     string
+	duration time.Duration
+}
+
+// serverTiming accumulates phase durations for one request. The parse and
+// validate phases are sequential, so one phase is open at a time; custom
+// phases from StartTiming may overlap them and are only reported.
+type serverTiming struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	started time.Time
+	meta    bool
+	phases  []timingPhase
+	open    string
+	opened  time.Time
+}
+
+// ServerTiming returns middleware that measures where each request spends
+// its time and reports it in a Server-Timing header, for example:
+//
+//	Server-Timing: parse;dur=0.41, validate;dur=0.05, handler;dur=12.3, encode;dur=0.2, total;dur=13.0
+//
+// ParseRequest records the parse and validate phases, the response helpers
+// record encode, and StartTiming adds custom phases such as database calls.
+// The header is set when the response header is written.
+func ServerTiming(opts *ServerTimingOptions) func(http.Handler) http.Handler {
+	options := ServerTimingOptions{Now: time.Now}
+	if opts != nil {
+		options.Allow = opts.Allow
+		options.Meta = opts.Meta
+		if opts.Now != nil {
+			options.Now = opts.Now
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if options.Allow != nil && !options.Allow(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			timing := &serverTiming{now: options.Now, started: options.Now(), meta: options.Meta}
+			writer := &serverTimingWriter{ResponseWriter: w, timing: timing}
+			next.ServeHTTP(writer, r.WithContext(context.WithValue(r.Context(), serverTimingContextKey{}, timing)))
+		})
+	}
+}
+
+// StartTiming starts a custom Server-Timing phase and returns the function
+// that ends it. Durations of phases sharing a name add up. It is a no-op
+// outside ServerTiming:
+//
+//	defer httpsuite.StartTiming(ctx, "db")()
+func StartTiming(ctx context.Context, name string) func() {
+	timing := serverTimingFrom(ctx)
+	if timing == nil {
+		return func() {}
+	}
+	started := timing.now()
+	return func() {
+		timing.since(name, started)
+	}
+}
+
+func serverTimingFrom(ctx context.Context) *serverTiming {
+	timing, _ := ctx.Value(serverTimingContextKey{}).(*serverTiming)
+	return timing
+}
+
+// serverTimingOf finds the timing of the ServerTiming writer wrapping w.
+func serverTimingOf(w http.ResponseWriter) *serverTiming {
+	for w != nil {
+		if writer, ok := w.(*serverTimingWriter); ok {
+			return writer.timing
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = unwrapper.Unwrap()
+	}
+	return nil
+}
+
+// clock returns the current time, or the zero time without a timing.
+func (t *serverTiming) clock() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.now()
+}
+
+// since adds the time elapsed since started to the phase name.
+func (t *serverTiming) since(name string, started time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.addLocked(name, t.now().Sub(started))
+}
+
+// begin closes the open sequential phase, if any, and opens name.
+func (t *serverTiming) begin(name string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	t.closeLocked(now)
+	t.open, t.opened = name, now
+}
+
+// end closes the open sequential phase.
+func (t *serverTiming) end() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closeLocked(t.now())
+}
+
+func (t *serverTiming) closeLocked(now time.Time) {
+	if t.open != "" {
+		t.addLocked(t.open, now.Sub(t.opened))
+		t.open = ""
+	}
+}
+
+func (t *serverTiming) addLocked(name string, duration time.Duration) {
+	for i := range t.phases {
+		if t.phases[i].name == name {
+			t.phases[i].duration += duration
+			return
+		}
+	}
+	t.phases = append(t.phases, timingPhase{name: name, duration: duration})
+}
+
+// report closes the open phase and returns every phase in order, followed
+// by the handler and total durations.
+func (t *serverTiming) report() []timingPhase {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	t.closeLocked(now)
+	total := now.Sub(t.started)
+
+	handler := total
+	report := make([]timingPhase, 0, len(t.phases)+2)
+	for _, phase := range t.phases {
+		switch phase.name {
+		case TimingParse, TimingValidate, TimingEncode:
+			handler -= phase.duration
+		}
+		report = append(report, phase)
+	}
+	if handler < 0 {
+		handler = 0
+	}
+	return append(report, timingPhase{name: TimingHandler, duration: handler}, timingPhase{name: TimingTotal, duration: total})
+}
+
+// metaMillis returns the phases measured so far in milliseconds.
+func (t *serverTiming) metaMillis() map[string]float64 {
+	phases := t.report()
+	millis := make(map[string]float64, len(phases))
+	for _, phase := range phases {
+		millis[phase.name] = processingMillis(phase.duration)
+	}
+	return millis
+}
+
+func formatServerTiming(phases []timingPhase) string {
+	var header strings.Builder
+	for i, phase := range phases {
+		if i > 0 {
+			header.WriteString(", ")
+		}
+		header.WriteString(phase.name)
+		header.WriteString(";dur=")
+		header.WriteString(strconv.FormatFloat(processingMillis(phase.duration), 'f', -1, 64))
+	}
+	return header.String()
+}
+
+// withTimingMeta returns meta with the timing member added, copying the
+// metadata types that carry extra members. Other meta values are returned
+// unchanged.
+func withTimingMeta(meta any, timing map[string]float64) any {
+	switch value := meta.(type) {
+	case nil:
+		return (&Meta{}).With(MetaKeyTiming, timing)
+	case *PageMeta:
+		if value == nil {
+			return (&Meta{}).With(MetaKeyTiming, timing)
+		}
+		clone := *value
+		clone.Extra = cloneMetaExtra(value.Extra)
+		return clone.With(MetaKeyTiming, timing)
+	case PageMeta:
+		value.Extra = cloneMetaExtra(value.Extra)
+		return value.With(MetaKeyTiming, timing)
+	case *CursorMeta:
+		if value == nil {
+			return (&Meta{}).With(MetaKeyTiming, timing)
+		}
+		clone := *value
+		clone.Extra = cloneMetaExtra(value.Extra)
+		return clone.With(MetaKeyTiming, timing)
+	case CursorMeta:
+		value.Extra = cloneMetaExtra(value.Extra)
+		return value.With(MetaKeyTiming, timing)
+	case map[string]any:
+		clone := cloneMetaExtra(value)
+		clone[MetaKeyTiming] = timing
+		return clone
+	default:
+		return meta
+	}
+}
+
+func cloneMetaExtra(extra map[string]any) map[string]any {
+	clone := make(map[string]any, len(extra)+1)
+	for key, value := range extra {
+		clone[key] = value
+	}
+	return clone
+}
+
+// serverTimingWriter sets the Server-Timing header before the response
+// header is written.
+type serverTimingWriter struct {
+	http.ResponseWriter
+	timing      *serverTiming
+	wroteHeader bool
+}
+
+func (w *serverTimingWriter) writeTiming() {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.Header().Set("Server-Timing", formatServerTiming(w.timing.report()))
+}
+
+func (w *serverTimingWriter) WriteHeader(code int) {
+	if !isInformational(code) {
+		w.writeTiming()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *serverTimingWriter) Write(p []byte) (int, error) {
+	w.writeTiming()
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *serverTimingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush flushes through any writers wrapped below this one.
+func (w *serverTimingWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError is used by http.ResponseController.
+func (w *serverTimingWriter) FlushError() error {
+	w.writeTiming()
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
@@ -0,0 +1,148 @@
+package httpsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestServerTimingPhases(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	advance := func(d time.Duration) { now = now.Add(d) }
+	handler := ServerTiming(&ServerTimingOptions{
+		Meta: true,
+		Now:  func() time.Time { return now },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timing := serverTimingFrom(r.Context())
+		timing.begin(TimingParse)
+		advance(3 * time.Millisecond)
+		timing.begin(TimingValidate)
+		advance(time.Millisecond)
+		timing.end()
+		advance(5 * time.Millisecond)
+		stop := StartTiming(r.Context(), "db")
+		advance(4 * time.Millisecond)
+		stop()
+		OKWithMeta(w, "ok", NewPageMeta(1, 10, 1))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := "parse;dur=3, validate;dur=1, db;dur=4, encode;dur=0, handler;dur=9, total;dur=13"
+	if got := w.Header().Get("Server-Timing"); got != want {
+		t.Fatalf("expected Server-Timing %q, got %q", want, got)
+	}
+	var body struct {
+		Meta struct {
+			Page   int                `json:"page"`
+			Timing map[string]float64 `json:"timing"`
+		} `json:"meta"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	wantMeta := map[string]float64{"parse": 3, "validate": 1, "db": 4, "handler": 9, "total": 13}
+	if body.Meta.Page != 1 || len(body.Meta.Timing) != len(wantMeta) {
+		t.Fatalf("expected page meta with timing %v, got %+v", wantMeta, body.Meta)
+	}
+	for name, value := range wantMeta {
+		if body.Meta.Timing[name] != value {
+			t.Fatalf("expected timing %s=%v, got %v", name, value, body.Meta.Timing)
+		}
+	}
+}
+
+type timingRequest struct {
+	Name string `json:"name"`
+}
+
+func (r *timingRequest) Validate() error {
+	if r.Name == "" {
+		return ValidationErrors{{Field: "name", Message: "name is required"}}
+	}
+	return nil
+}
+
+func TestServerTimingWithParseRequest(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	handler := ServerTiming(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request, err := ParseRequest[*timingRequest](w, r, nil, nil)
+		if err != nil {
+			return
+		}
+		OK(w, request)
+	}))
+
+	header := regexp.MustCompile(`^parse;dur=[0-9.]+, validate;dur=[0-9.]+, encode;dur=[0-9.]+, handler;dur=[0-9.]+, total;dur=[0-9.]+$`)
+	for _, body := range []string{`{"name":"lamp"}`, `{}`} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body)))
+		if got := w.Header().Get("Server-Timing"); !header.MatchString(got) {
+			t.Fatalf("%s: expected every phase in Server-Timing, got %q (status %d)", body, got, w.Code)
+		}
+	}
+}
+
+func TestServerTimingAllow(t *testing.T) {
+	t.Parallel()
+
+	handler := ServerTiming(&ServerTimingOptions{
+		Allow: func(r *http.Request) bool { return r.Header.Get("X-Internal") == "true" },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer StartTiming(r.Context(), "db")()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := w.Header().Get("Server-Timing"); got != "" {
+		t.Fatalf("expected no Server-Timing for external callers, got %q", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Internal", "true")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Server-Timing"); !regexp.MustCompile(`^handler;dur=[0-9.]+, total;dur=[0-9.]+$`).MatchString(got) {
+		t.Fatalf("expected handler and total timings, got %q", got)
+	}
+
+	StartTiming(context.Background(), "noop")()
+}
+
+func TestWithTimingMeta(t *testing.T) {
+	t.Parallel()
+
+	timing := map[string]float64{"total": 1}
+	page := NewPageMeta(1, 10, 1).WithAPIVersion("v1")
+	tests := []struct {
+		name string
+		meta any
+		want string
+	}{
+		{name: "nil", meta: nil, want: `{"timing":{"total":1}}`},
+		{name: "page", meta: page, want: `{"page":1,"page_size":10,"total_pages":1,"total_items":1,"api_version":"v1","timing":{"total":1}}`},
+		{name: "cursor", meta: CursorMeta{HasNext: true}, want: `{"has_next":true,"has_prev":false,"timing":{"total":1}}`},
+		{name: "map", meta: map[string]any{"a": 1}, want: `{"a":1,"timing":{"total":1}}`},
+		{name: "other", meta: "custom", want: `"custom"`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(withTimingMeta(tt.meta, timing))
+		if err != nil || string(data) != tt.want {
+			t.Fatalf("%s: expected %s, got %s %v", tt.name, tt.want, data, err)
+		}
+	}
+	if _, ok := page.Extra[MetaKeyTiming]; ok {
+		t.Fatal("expected the original meta to be left unchanged")
+	}
+}
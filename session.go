@@ -0,0 +1,334 @@
+package httpsuite
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSessionCookie = "session"
+	defaultSessionMaxAge = 24 * time.Hour
+	maxSessionCookieSize = 4096
+)
+
+// ErrInvalidSession reports a session cookie that could not be decrypted
+// with any key, was tampered with, or has expired.
+var ErrInvalidSession = errors.New("invalid session cookie")
+
+// SessionOptions configures Sessions.
+type SessionOptions struct {
+	// Keys are AES-128, AES-192, or AES-256 keys. The first encrypts new
+	// cookies; all of them decrypt, so a new key can be put first while old
+	// cookies stay readable. Cookies read with an older key are re-encrypted.
+	Keys [][]byte
+	// CookieName defaults to "session".
+	CookieName string
+	// Path defaults to "/".
+	Path   string
+	Domain string
+	// MaxAge bounds the session lifetime, enforced inside the encrypted
+	// payload as well as by the cookie. Defaults to 24 hours.
+	MaxAge time.Duration
+	// SameSite defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+	// Insecure drops the Secure attribute, for local development over HTTP.
+	Insecure bool
+	Now      func() time.Time
+}
+
+// Sessions stores small string maps in encrypted, authenticated cookies
+// (AES-GCM), for lightweight stateful flows without a session backend.
+type Sessions struct {
+	ciphers []cipher.AEAD
+	options SessionOptions
+}
+
+// Session is the decoded state of one request. It is safe for concurrent use.
+type Session struct {
+	mu       sync.Mutex
+	values   map[string]string
+	modified bool
+	cleared  bool
+}
+
+type sessionPayload struct {
+	Values    map[string]string `json:"v"`
+	ExpiresAt int64             `json:"e"`
+}
+
+type sessionContextKey struct{}
+
+// NewSessions validates the keys and returns a session codec. At least one
+// key is required.
+func NewSessions(opts *SessionOptions) (*Sessions, error) {
+	options := SessionOptions{CookieName: defaultSessionCookie, Path: "/", MaxAge: defaultSessionMaxAge, SameSite: http.SameSiteLaxMode, Now: time.Now}
+	if opts != nil {
+		options.Keys = opts.Keys
+		options.Domain = opts.Domain
+		options.Insecure = opts.Insecure
+		if opts.CookieName != "" {
+			options.CookieName = opts.CookieName
+		}
+		if opts.Path != "" {
+			options.Path = opts.Path
+		}
+		if opts.MaxAge > 0 {
+			options.MaxAge = opts.MaxAge
+		}
+		if opts.SameSite != 0 {
+			options.SameSite = opts.SameSite
+		}
+		if opts.Now != nil {
+			options.Now = opts.Now
+		}
+	}
+	if len(options.Keys) == 0 {
+		return nil, errors.New("at least one session key is required")
+	}
+
+	sessions := &Sessions{options: options}
+	for i, key := range options.Keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("session key %d: %w", i, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("session key %d: %w", i, err)
+		}
+		sessions.ciphers = append(sessions.ciphers, aead)
+	}
+	return sessions, nil
+}
+
+// Load decodes the session cookie of r. A missing cookie yields an empty
+// session; an invalid or expired one yields an empty session and
+// ErrInvalidSession.
+func (s *Sessions) Load(r *http.Request) (*Session, error) {
+	session := &Session{values: make(map[string]string)}
+	cookie, err := r.Cookie(s.options.CookieName)
+	if err != nil {
+		return session, nil
+	}
+	payload, keyIndex, err := s.decode(cookie.Value)
+	if err != nil {
+		return session, err
+	}
+	if payload.Values != nil {
+		session.values = payload.Values
+	}
+	// Re-encrypt cookies written with a retired key.
+	session.modified = keyIndex > 0
+	return session, nil
+}
+
+// Save writes session as a cookie, or expires the cookie when the session
+// was cleared. It must be called before the response status is written.
+func (s *Sessions) Save(w http.ResponseWriter, session *Session) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	cookie := &http.Cookie{
+		Name:     s.options.CookieName,
+		Path:     s.options.Path,
+		Domain:   s.options.Domain,
+		Secure:   !s.options.Insecure,
+		HttpOnly: true,
+		SameSite: s.options.SameSite,
+	}
+	if session.cleared {
+		cookie.MaxAge = -1
+		http.SetCookie(w, cookie)
+		session.modified = false
+		return nil
+	}
+
+	value, err := s.encode(session.values)
+	if err != nil {
+		return err
+	}
+	cookie.Value = value
+	cookie.MaxAge = int(s.options.MaxAge / time.Second)
+	if encoded := cookie.String(); len(encoded) > maxSessionCookieSize {
+		return fmt.Errorf("session cookie is %d bytes, over the %d byte limit", len(encoded), maxSessionCookieSize)
+	}
+	http.SetCookie(w, cookie)
+	session.modified = false
+	return nil
+}
+
+// Middleware loads the session into the request context, where
+// SessionFromContext finds it, and saves it just before the response status
+// is written when the handler changed it. Changes made after the response
+// started are not saved.
+func (s *Sessions) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, err := s.Load(r)
+			if err != nil {
+				// Start over instead of failing the request; the stale cookie
+				// is replaced once the session is written.
+				session.modified = true
+			}
+			writer := &sessionWriter{ResponseWriter: w, sessions: s, session: session}
+			next.ServeHTTP(writer, r.WithContext(WithSession(r.Context(), session)))
+			writer.save()
+		})
+	}
+}
+
+// WithSession returns a context carrying session.
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
+
+// SessionFromContext returns the session loaded by Sessions.Middleware.
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(sessionContextKey{}).(*Session)
+	return session, ok && session != nil
+}
+
+// Get returns the value stored under key.
+func (s *Session) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores value under key.
+func (s *Session) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	s.modified = true
+	s.cleared = false
+}
+
+// Delete removes key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.modified = true
+	}
+}
+
+// Clear removes every value and expires the cookie, e.g. on logout.
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]string)
+	s.modified = true
+	s.cleared = true
+}
+
+// Values returns a copy of the stored values.
+func (s *Session) Values() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.values)
+}
+
+func (s *Session) isModified() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.modified
+}
+
+func (s *Sessions) encode(values map[string]string) (string, error) {
+	plaintext, err := json.Marshal(sessionPayload{Values: values, ExpiresAt: s.options.Now().Add(s.options.MaxAge).Unix()})
+	if err != nil {
+		return "", err
+	}
+	aead := s.ciphers[0]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	// The cookie name is authenticated so a value cannot be replayed under
+	// another cookie sharing the keys.
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(s.options.CookieName))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (s *Sessions) decode(value string) (sessionPayload, int, error) {
+	var payload sessionPayload
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return payload, 0, ErrInvalidSession
+	}
+	for i, aead := range s.ciphers {
+		if len(sealed) < aead.NonceSize() {
+			return payload, 0, ErrInvalidSession
+		}
+		plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(s.options.CookieName))
+		if err != nil {
+			continue
+		}
+		if err := json.Unmarshal(plaintext, &payload); err != nil {
+			return payload, 0, ErrInvalidSession
+		}
+		if !s.options.Now().Before(time.Unix(payload.ExpiresAt, 0)) {
+			return sessionPayload{}, 0, ErrInvalidSession
+		}
+		return payload, i, nil
+	}
+	return payload, 0, ErrInvalidSession
+}
+
+// sessionWriter saves a modified session before the status line is sent.
+type sessionWriter struct {
+	http.ResponseWriter
+	sessions *Sessions
+	session  *Session
+	saved    bool
+}
+
+func (w *sessionWriter) save() {
+	if w.saved {
+		return
+	}
+	w.saved = true
+	if !w.session.isModified() {
+		return
+	}
+	if err := w.sessions.Save(w.ResponseWriter, w.session); err != nil {
+		log.Printf("Failed to save session: %v", err)
+	}
+}
+
+func (w *sessionWriter) WriteHeader(code int) {
+	if !isInformational(code) {
+		w.save()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sessionWriter) Write(p []byte) (int, error) {
+	w.save()
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// FlushError is used by http.ResponseController.
+func (w *sessionWriter) FlushError() error {
+	w.save()
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
@@ -0,0 +1,167 @@
+package httpsuite
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testSessionKey    = bytes.Repeat([]byte{1}, 32)
+	testSessionKeyOld = bytes.Repeat([]byte{2}, 16)
+)
+
+func newTestSessions(t *testing.T, now *time.Time, keys ...[]byte) *Sessions {
+	t.Helper()
+
+	sessions, err := NewSessions(&SessionOptions{Keys: keys, Now: func() time.Time { return *now }, MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("new sessions: %v", err)
+	}
+	return sessions
+}
+
+func sessionCookie(t *testing.T, rec *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == defaultSessionCookie {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestSessionsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sessions := newTestSessions(t, &now, testSessionKey)
+	handler := sessions.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, ok := SessionFromContext(r.Context())
+		if !ok {
+			t.Error("expected a session in context")
+			return
+		}
+		switch r.URL.Path {
+		case "/login":
+			session.Set("user", "ada")
+			OK(w, "logged in")
+		case "/logout":
+			session.Clear()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			user, _ := session.Get("user")
+			OK(w, user)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/login", nil))
+	cookie := sessionCookie(t, rec)
+	if cookie == nil {
+		t.Fatal("expected a session cookie")
+	}
+	if !cookie.Secure || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode || cookie.MaxAge != 3600 || cookie.Path != "/" {
+		t.Fatalf("unexpected cookie attributes: %+v", cookie)
+	}
+	if strings.Contains(cookie.Value, "ada") {
+		t.Fatalf("expected an encrypted cookie, got %q", cookie.Value)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"ada"`) {
+		t.Fatalf("expected the session user, got %s", rec.Body.String())
+	}
+	if sessionCookie(t, rec) != nil {
+		t.Fatal("expected unchanged sessions not to be rewritten")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if cleared := sessionCookie(t, rec); cleared == nil || cleared.MaxAge != -1 {
+		t.Fatalf("expected an expired cookie, got %+v", cleared)
+	}
+}
+
+func TestSessionsLoad(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	old := newTestSessions(t, &now, testSessionKeyOld)
+	rotated := newTestSessions(t, &now, testSessionKey, testSessionKeyOld)
+	other := newTestSessions(t, &now, bytes.Repeat([]byte{3}, 32))
+
+	session := &Session{values: map[string]string{}}
+	session.Set("cart", "7")
+	rec := httptest.NewRecorder()
+	if err := old.Save(rec, session); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	cookie := sessionCookie(t, rec)
+
+	load := func(sessions *Sessions, cookie *http.Cookie) (*Session, error) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		return sessions.Load(req)
+	}
+
+	loaded, err := load(rotated, cookie)
+	if err != nil {
+		t.Fatalf("load with rotated keys: %v", err)
+	}
+	if value, _ := loaded.Get("cart"); value != "7" || !loaded.isModified() {
+		t.Fatalf("expected value from old key and re-encryption, got %q modified=%v", value, loaded.isModified())
+	}
+
+	if _, err := load(other, cookie); !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("expected ErrInvalidSession for unknown key, got %v", err)
+	}
+	tampered := *cookie
+	tampered.Value = cookie.Value[:len(cookie.Value)-2] + "AA"
+	if _, err := load(old, &tampered); !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("expected ErrInvalidSession for tampered cookie, got %v", err)
+	}
+	if empty, err := load(old, nil); err != nil || len(empty.Values()) != 0 {
+		t.Fatalf("expected empty session without cookie, got %v, %v", empty.Values(), err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := load(old, cookie); !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("expected ErrInvalidSession for expired cookie, got %v", err)
+	}
+}
+
+func TestSessionsSaveTooLarge(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	sessions := newTestSessions(t, &now, testSessionKey)
+	session := &Session{values: map[string]string{}}
+	session.Set("blob", strings.Repeat("x", 4096))
+	if err := sessions.Save(httptest.NewRecorder(), session); err == nil {
+		t.Fatal("expected an error for an oversized cookie")
+	}
+}
+
+func TestNewSessionsRejectsKeys(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewSessions(nil); err == nil {
+		t.Fatal("expected an error without keys")
+	}
+	if _, err := NewSessions(&SessionOptions{Keys: [][]byte{[]byte("short")}}); err == nil {
+		t.Fatal("expected an error for an invalid key size")
+	}
+}
@@ -0,0 +1,68 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// flightGroup coalesces concurrent work by key so one leader runs it and the
+// callers that join meanwhile share its recorded response.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done     chan struct{}
+	response *CachedResponse
+}
+
+// join returns the in-flight call for key and whether the caller leads it.
+// Leaders must call finish once their response is recorded.
+func (g *flightGroup) join(key string) (*flightCall, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if call, ok := g.calls[key]; ok {
+		return call, false
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	return call, true
+}
+
+// finish publishes response to the callers waiting on call. A nil response
+// tells them to handle the request themselves.
+func (g *flightGroup) finish(key string, call *flightCall, response *CachedResponse) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	call.response = response
+	close(call.done)
+}
+
+// wait blocks until the leader finishes and returns its response, or returns
+// false when ctx ends first.
+func (c *flightCall) wait(ctx context.Context) (*CachedResponse, bool) {
+	select {
+	case <-c.done:
+		return c.response, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// replayResponse writes a response recorded for another request, marking it
+// with the given header.
+func replayResponse(w http.ResponseWriter, response *CachedResponse, markerHeader, markerValue string) {
+	header := w.Header()
+	for key, values := range response.Header {
+		header[key] = append([]string(nil), values...)
+	}
+	header.Set(markerHeader, markerValue)
+	w.WriteHeader(response.Status)
+	_, _ = w.Write(response.Body)
+}
@@ -0,0 +1,61 @@
+package httpsuite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFlightGroupSharesLeaderResponse(t *testing.T) {
+	t.Parallel()
+
+	var group flightGroup
+	call, leader := group.join("key")
+	if !leader {
+		t.Fatal("expected first caller to lead")
+	}
+	follower, leader := group.join("key")
+	if leader || follower != call {
+		t.Fatal("expected second caller to join the in-flight call")
+	}
+
+	response := &CachedResponse{Status: http.StatusAccepted}
+	group.finish("key", call, response)
+	if got, ok := follower.wait(context.Background()); !ok || got != response {
+		t.Fatalf("expected leader response, got %v %v", got, ok)
+	}
+	if _, leader := group.join("key"); !leader {
+		t.Fatal("expected finished key to start a new call")
+	}
+}
+
+func TestFlightCallWaitStopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	var group flightGroup
+	call, _ := group.join("key")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := call.wait(ctx); ok {
+		t.Fatal("expected canceled wait to return false")
+	}
+}
+
+func TestReplayResponse(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	replayResponse(rec, &CachedResponse{
+		Status: http.StatusCreated,
+		Header: http.Header{"Content-Type": {"text/plain"}},
+		Body:   []byte("done"),
+	}, "X-Cache", "SHARED")
+
+	if rec.Code != http.StatusCreated || rec.Body.String() != "done" {
+		t.Fatalf("expected replayed response, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Type") != "text/plain" || rec.Header().Get("X-Cache") != "SHARED" {
+		t.Fatalf("expected replayed headers, got %v", rec.Header())
+	}
+}
@@ -0,0 +1,228 @@
+package httpsuite
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultStaticIndex = "index.html"
+
+var defaultStaticCache = CachePublic(time.Hour)
+
+// Pre-compressed variants, in order of preference.
+var staticEncodings = []struct {
+	coding string
+	suffix string
+}{
+	{coding: "br", suffix: ".br"},
+	{coding: "gzip", suffix: ".gz"},
+}
+
+// StaticOptions configures StaticHandler.
+type StaticOptions struct {
+	// Index is served for directories and SPA routes. Defaults to "index.html".
+	Index string
+	// SPA serves the root Index for paths that match no file and have no
+	// file extension, so client-side routes load the app. Missing assets
+	// such as "/app.js" still get a 404 problem.
+	SPA bool
+	// Cache applies to every file except Index, which is always served with
+	// "no-cache" so deploys take effect. Use
+	// CachePublic(365*24*time.Hour).Immutable() for fingerprinted assets.
+	// Defaults to CachePublic(time.Hour).
+	Cache *CachePolicy
+}
+
+type staticHandler struct {
+	fsys       fs.FS
+	options    StaticOptions
+	notFound   http.Handler
+	notAllowed *StaticProblem
+	etags      sync.Map
+}
+
+type staticETagKey struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+// StaticHandler serves files from fsys, such as an embed.FS holding a built
+// frontend, next to the API. It answers conditional and range requests with
+// strong content-hash ETags, serves "name.br" or "name.gz" variants to
+// clients that accept them, and replies with 404 problems for missing files,
+// hidden files such as ".env", and paths escaping the root. Directories are
+// never listed. Mount it with http.StripPrefix when serving below "/".
+func StaticHandler(fsys fs.FS, opts *StaticOptions) http.Handler {
+	options := StaticOptions{Index: defaultStaticIndex, Cache: &defaultStaticCache}
+	if opts != nil {
+		options.SPA = opts.SPA
+		if opts.Index != "" {
+			options.Index = opts.Index
+		}
+		if opts.Cache != nil {
+			cache := *opts.Cache
+			options.Cache = &cache
+		}
+	}
+	return &staticHandler{
+		fsys:       fsys,
+		options:    options,
+		notFound:   NotFoundHandler(),
+		notAllowed: readOnlyMethodNotAllowed(),
+	}
+}
+
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		h.notAllowed.Write(w)
+		return
+	}
+	name, ok := staticName(r.URL.Path)
+	if !ok {
+		h.notFound.ServeHTTP(w, r)
+		return
+	}
+
+	info, err := fs.Stat(h.fsys, name)
+	if err == nil && info.IsDir() {
+		name = path.Join(name, h.options.Index)
+		info, err = fs.Stat(h.fsys, name)
+	}
+	if err != nil || info.IsDir() {
+		if !h.options.SPA || path.Ext(r.URL.Path) != "" {
+			h.notFound.ServeHTTP(w, r)
+			return
+		}
+		name = h.options.Index
+		if info, err = fs.Stat(h.fsys, name); err != nil || info.IsDir() {
+			h.notFound.ServeHTTP(w, r)
+			return
+		}
+	}
+	h.serveFile(w, r, name, info)
+}
+
+func (h *staticHandler) serveFile(w http.ResponseWriter, r *http.Request, name string, info fs.FileInfo) {
+	header := w.Header()
+	header.Add("Vary", "Accept-Encoding")
+	contentType := mime.TypeByExtension(path.Ext(name))
+
+	variant := name
+	for _, encoding := range staticEncodings {
+		if !acceptsEncoding(r.Header.Get("Accept-Encoding"), encoding.coding) {
+			continue
+		}
+		if compressed, err := fs.Stat(h.fsys, name+encoding.suffix); err == nil && !compressed.IsDir() {
+			variant, info = name+encoding.suffix, compressed
+			header.Set("Content-Encoding", encoding.coding)
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+			break
+		}
+	}
+
+	file, err := h.fsys.Open(variant)
+	if err != nil {
+		h.notFound.ServeHTTP(w, r)
+		return
+	}
+	defer func() { _ = file.Close() }()
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			sendStaticServerError(w, r)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+	etag, err := h.etag(variant, info, content)
+	if err != nil {
+		sendStaticServerError(w, r)
+		return
+	}
+
+	header.Set("ETag", etag)
+	header.Set("X-Content-Type-Options", "nosniff")
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	if path.Base(name) == h.options.Index {
+		header.Set("Cache-Control", "no-cache")
+	} else {
+		SetCacheControl(w, *h.options.Cache)
+	}
+	http.ServeContent(w, r, name, info.ModTime(), content)
+}
+
+// etag hashes a file once per name, size, and modification time and rewinds
+// content afterwards.
+func (h *staticHandler) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	key := staticETagKey{name: name, size: info.Size(), modTime: info.ModTime()}
+	if cached, ok := h.etags.Load(key); ok {
+		return cached.(string), nil
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	h.etags.Store(key, etag)
+	return etag, nil
+}
+
+func sendStaticServerError(w http.ResponseWriter, r *http.Request) {
+	problems := resolveProblemConfig(r.Context(), nil)
+	sendRequestProblem(w, r, http.StatusInternalServerError, NewProblemDetails(http.StatusInternalServerError, problems.TypeURL("server_error"), "Internal Server Error", ""))
+}
+
+// staticName maps a URL path to an fs.FS name, rejecting hidden segments
+// and anything fs.ValidPath refuses. Cleaning resolves ".." within the root.
+func staticName(urlPath string) (string, bool) {
+	if strings.ContainsAny(urlPath, "\\\x00") {
+		return "", false
+	}
+	name := strings.TrimPrefix(path.Clean("/"+urlPath), "/")
+	if name == "" {
+		return ".", true
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return "", false
+		}
+	}
+	return name, fs.ValidPath(name)
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows coding.
+func acceptsEncoding(header, coding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		token, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		token = strings.TrimSpace(token)
+		if !strings.EqualFold(token, coding) && token != "*" {
+			continue
+		}
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if quality, err := strconv.ParseFloat(value, 64); err != nil || quality <= 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
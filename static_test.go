@@ -0,0 +1,182 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testStaticFS() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":         {Data: []byte("<!doctype html><title>app</title>")},
+		"assets/app.js":      {Data: []byte("console.log('app')")},
+		"assets/app.js.br":   {Data: []byte("brotli bytes")},
+		"assets/app.js.gz":   {Data: []byte("gzip bytes")},
+		"assets/style.css":   {Data: []byte("body{}")},
+		"docs/index.html":    {Data: []byte("<h1>docs</h1>")},
+		"empty/readme.txt":   {Data: []byte("no index here")},
+		".env":               {Data: []byte("SECRET=1")},
+		"assets/.git/config": {Data: []byte("[core]")},
+	}
+}
+
+func TestStaticHandler(t *testing.T) {
+	t.Parallel()
+
+	cache := CachePublic(365 * 24 * time.Hour).Immutable()
+	plain := StaticHandler(testStaticFS(), nil)
+	spa := StaticHandler(testStaticFS(), &StaticOptions{SPA: true, Cache: &cache})
+
+	tests := []struct {
+		name           string
+		handler        http.Handler
+		method         string
+		path           string
+		acceptEncoding string
+		wantStatus     int
+		wantBody       string
+		wantEncoding   string
+		wantCache      string
+		wantType       string
+	}{
+		{name: "root index", handler: plain, path: "/", wantStatus: http.StatusOK, wantBody: "<!doctype html>", wantCache: "no-cache", wantType: "text/html; charset=utf-8"},
+		{name: "asset", handler: plain, path: "/assets/style.css", wantStatus: http.StatusOK, wantBody: "body{}", wantCache: "public, max-age=3600", wantType: "text/css; charset=utf-8"},
+		{name: "directory index", handler: plain, path: "/docs/", wantStatus: http.StatusOK, wantBody: "<h1>docs</h1>", wantCache: "no-cache"},
+		{name: "directory without index", handler: plain, path: "/empty", wantStatus: http.StatusNotFound},
+		{name: "brotli variant", handler: plain, path: "/assets/app.js", acceptEncoding: "gzip, br", wantStatus: http.StatusOK, wantBody: "brotli bytes", wantEncoding: "br", wantType: "text/javascript; charset=utf-8"},
+		{name: "gzip variant", handler: plain, path: "/assets/app.js", acceptEncoding: "gzip, br;q=0", wantStatus: http.StatusOK, wantBody: "gzip bytes", wantEncoding: "gzip"},
+		{name: "identity", handler: plain, path: "/assets/app.js", wantStatus: http.StatusOK, wantBody: "console.log"},
+		{name: "missing file", handler: plain, path: "/nope", wantStatus: http.StatusNotFound},
+		{name: "dotfile", handler: plain, path: "/.env", wantStatus: http.StatusNotFound},
+		{name: "hidden directory", handler: plain, path: "/assets/.git/config", wantStatus: http.StatusNotFound},
+		{name: "traversal", handler: plain, path: "/assets/../../.env", wantStatus: http.StatusNotFound},
+		{name: "backslash", handler: plain, path: `/assets\..\.env`, wantStatus: http.StatusNotFound},
+		{name: "method not allowed", handler: plain, method: http.MethodPost, path: "/", wantStatus: http.StatusMethodNotAllowed},
+		{name: "head", handler: plain, method: http.MethodHead, path: "/assets/style.css", wantStatus: http.StatusOK},
+		{name: "spa route", handler: spa, path: "/settings/profile", wantStatus: http.StatusOK, wantBody: "<!doctype html>", wantCache: "no-cache"},
+		{name: "spa missing asset", handler: spa, path: "/assets/missing.js", wantStatus: http.StatusNotFound},
+		{name: "spa asset cache", handler: spa, path: "/assets/style.css", wantStatus: http.StatusOK, wantCache: "public, max-age=31536000, immutable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			req.URL.Path = tt.path
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus >= 400 {
+				if got := rec.Header().Get("Content-Type"); got != problemContentType {
+					t.Fatalf("expected a problem response, got %q", got)
+				}
+				return
+			}
+			if !strings.HasPrefix(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body starting with %q, got %q", tt.wantBody, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("expected Content-Encoding %q, got %q", tt.wantEncoding, got)
+			}
+			if tt.wantCache != "" && rec.Header().Get("Cache-Control") != tt.wantCache {
+				t.Fatalf("expected Cache-Control %q, got %q", tt.wantCache, rec.Header().Get("Cache-Control"))
+			}
+			if tt.wantType != "" && rec.Header().Get("Content-Type") != tt.wantType {
+				t.Fatalf("expected Content-Type %q, got %q", tt.wantType, rec.Header().Get("Content-Type"))
+			}
+			if rec.Header().Get("ETag") == "" || rec.Header().Get("Vary") != "Accept-Encoding" {
+				t.Fatalf("expected ETag and Vary headers, got %v", rec.Header())
+			}
+		})
+	}
+}
+
+func TestStaticHandlerConditionalRequests(t *testing.T) {
+	t.Parallel()
+
+	handler := StaticHandler(testStaticFS(), nil)
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/assets/style.css", nil))
+	etag := first.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, "/assets/style.css", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for a matching ETag, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/assets/app.js", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get("ETag") == etag {
+		t.Fatal("expected variants to have their own ETags")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/assets/style.css", nil)
+	req.Header.Set("Range", "bytes=0-3")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "body" {
+		t.Fatalf("expected a partial response, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+// unreadableFS opens files that fail every read and cannot seek.
+type unreadableFS struct {
+	fstest.MapFS
+}
+
+type unreadableFile struct {
+	fs.File
+}
+
+func (f unreadableFile) Read([]byte) (int, error) {
+	return 0, errors.New("disk failure")
+}
+
+func (u unreadableFS) Open(name string) (fs.File, error) {
+	file, err := u.MapFS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return unreadableFile{File: file}, nil
+}
+
+func TestStaticHandlerReadFailureUsesScopedProblemConfig(t *testing.T) {
+	t.Parallel()
+
+	handler := UseProblemConfig(ProblemConfig{BaseURL: "https://assets.example.com"})(StaticHandler(unreadableFS{testStaticFS()}, nil))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/assets/style.css", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	var problem ProblemDetails
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Type != "https://assets.example.com/errors/server-error" {
+		t.Fatalf("expected scoped type, got %q", problem.Type)
+	}
+}
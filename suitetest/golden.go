@@ -0,0 +1,132 @@
+package suitetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+const scrubbed = "<scrubbed>"
+
+// Volatile values replaced in every JSON string before comparison.
+var defaultScrubbers = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`), "<timestamp>"},
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	// Problem instances end in the request ID, e.g. "/items/7#9f86d081...".
+	{regexp.MustCompile(`#[0-9a-f]{32}$`), "#<request-id>"},
+}
+
+// GoldenOptions configures AssertGolden.
+type GoldenOptions struct {
+	// Scrub lists JSON member names, at any depth, whose values are replaced
+	// with "<scrubbed>", e.g. "id" or "etag".
+	Scrub []string
+	// Dir holds the golden files. Defaults to "testdata".
+	Dir string
+}
+
+// AssertGolden compares body with the golden file <Dir>/<name>.golden and
+// rewrites the file instead when tests run with -update. JSON bodies are
+// indented with sorted keys, and RFC 3339 timestamps, UUIDs, request IDs in
+// problem instances, and Scrub members are replaced with placeholders, so
+// snapshots only change when the payload does.
+func AssertGolden(t TB, name string, body []byte, opts *GoldenOptions) {
+	t.Helper()
+	options := GoldenOptions{Dir: "testdata"}
+	if opts != nil {
+		options.Scrub = opts.Scrub
+		if opts.Dir != "" {
+			options.Dir = opts.Dir
+		}
+	}
+
+	got := normalizeGolden(body, options.Scrub)
+	path := filepath.Join(options.Dir, name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file %s (run with -update to create it): %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s differs from the response (run with -update to accept it):\n%s", path, firstDifference(want, got))
+	}
+}
+
+// normalizeGolden renders JSON deterministically; other bodies are kept.
+func normalizeGolden(body []byte, scrub []string) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return body
+	}
+	var normalized bytes.Buffer
+	encoder := json.NewEncoder(&normalized)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(scrubValue(value, scrub)); err != nil {
+		return body
+	}
+	return normalized.Bytes()
+}
+
+func scrubValue(value any, scrub []string) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, member := range typed {
+			if slices.Contains(scrub, key) {
+				typed[key] = scrubbed
+				continue
+			}
+			typed[key] = scrubValue(member, scrub)
+		}
+	case []any:
+		for i, item := range typed {
+			typed[i] = scrubValue(item, scrub)
+		}
+	case string:
+		for _, scrubber := range defaultScrubbers {
+			typed = scrubber.pattern.ReplaceAllString(typed, scrubber.replacement)
+		}
+		return typed
+	}
+	return value
+}
+
+func firstDifference(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var wantLine, gotLine string
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if wantLine != gotLine {
+			return "line " + strconv.Itoa(i+1) + ":\n- " + wantLine + "\n+ " + gotLine
+		}
+	}
+	return ""
+}
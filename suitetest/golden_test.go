@@ -0,0 +1,76 @@
+package suitetest
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAssertGoldenResponses(t *testing.T) {
+	t.Parallel()
+
+	problem := serve(NewJSONRequest(t, http.MethodPut, "/items/7", "{", "id", "7"))
+	AssertGolden(t, "bad_request_problem", problem.Body.Bytes(), nil)
+
+	listed := serve(NewJSONRequest(t, http.MethodPut, "/items/7", item{Name: "desk"}, "id", "7"))
+	AssertGolden(t, "item", listed.Body.Bytes(), &GoldenOptions{Scrub: []string{"id"}})
+}
+
+func TestNormalizeGolden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		body  string
+		scrub []string
+		want  string
+	}{
+		{
+			name: "sorts keys and keeps numbers",
+			body: `{"b":1,"a":{"d":12345678901234567890,"c":[true,null]}}`,
+			want: "{\n  \"a\": {\n    \"c\": [\n      true,\n      null\n    ],\n    \"d\": 12345678901234567890\n  },\n  \"b\": 1\n}\n",
+		},
+		{
+			name:  "scrubs volatile values",
+			body:  `{"id":"f47ac10b-58cc-4372-a567-0e02b2c3d479","at":"2024-05-01T10:00:00.123Z","instance":"/items/7#0123456789abcdef0123456789abcdef","etag":"v1","nested":[{"etag":"v2"}]}`,
+			scrub: []string{"etag"},
+			want:  "{\n  \"at\": \"<timestamp>\",\n  \"etag\": \"<scrubbed>\",\n  \"id\": \"<uuid>\",\n  \"instance\": \"/items/7#<request-id>\",\n  \"nested\": [\n    {\n      \"etag\": \"<scrubbed>\"\n    }\n  ]\n}\n",
+		},
+		{name: "keeps other bodies", body: "plain text", want: "plain text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(normalizeGolden([]byte(tt.body), tt.scrub)); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAssertGoldenMismatchAndUpdate(t *testing.T) {
+	dir := t.TempDir()
+	opts := &GoldenOptions{Dir: dir}
+
+	tb := &fatalTB{}
+	tb.run(func() { AssertGolden(tb, "missing", []byte(`{}`), opts) })
+	if !strings.Contains(tb.failure, "run with -update to create it") {
+		t.Fatalf("expected a missing golden failure, got %q", tb.failure)
+	}
+
+	*update = true
+	AssertGolden(t, "nested/item", []byte(`{"name":"desk"}`), opts)
+	*update = false
+	if data, err := os.ReadFile(filepath.Join(dir, "nested", "item.golden")); err != nil || string(data) != "{\n  \"name\": \"desk\"\n}\n" {
+		t.Fatalf("expected the golden file to be written, got %q (%v)", data, err)
+	}
+
+	AssertGolden(t, "nested/item", []byte(`{ "name": "desk" }`), opts)
+	tb = &fatalTB{}
+	tb.run(func() { AssertGolden(tb, "nested/item", []byte(`{"name":"lamp"}`), opts) })
+	if !strings.Contains(tb.failure, "line 2:\n-   \"name\": \"desk\"\n+   \"name\": \"lamp\"") {
+		t.Fatalf("expected a line diff, got %q", tb.failure)
+	}
+}
@@ -0,0 +1,104 @@
+package suitetest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+// PathParams returns a ParamExtractor that reads path parameters from values,
+// for calling ParseRequest in tests without a router.
+func PathParams(values map[string]string) httpsuite.ParamExtractor {
+	return func(_ *http.Request, key string) string {
+		return values[key]
+	}
+}
+
+// Call describes a direct invocation of a typed handler.
+type Call struct {
+	// Method defaults to POST when Body is set and GET otherwise.
+	Method string
+	// Path is the request path. Defaults to "/".
+	Path string
+	// Pattern is the route pattern naming the path parameters to bind, such
+	// as "/users/{id}". Defaults to one built from the Params names.
+	Pattern string
+	Params  map[string]string
+	Query   url.Values
+	Header  http.Header
+	// Body is encoded as JSON, unless it is a string or []byte.
+	Body any
+	// Parse configures request parsing as RouteOptions.Parse does.
+	Parse *httpsuite.ParseOptions
+	// Context, when set, is the request context, e.g. carrying a principal.
+	Context context.Context
+}
+
+// Invoke runs handler the way httpsuite.Handle serves it, without a router or
+// server: the request is parsed and validated, path parameters come from
+// call.Params, and the reply is decoded. It returns the response data, or the
+// problem written instead of it.
+func Invoke[Req, Resp any](t TB, handler httpsuite.TypedHandler[Req, Resp], call Call) (Resp, *httpsuite.ProblemDetails) {
+	t.Helper()
+	var zero Resp
+
+	method := call.Method
+	if method == "" {
+		method = http.MethodGet
+		if call.Body != nil {
+			method = http.MethodPost
+		}
+	}
+	path := call.Path
+	if path == "" {
+		path = "/"
+	}
+	pattern := call.Pattern
+	if pattern == "" {
+		names := make([]string, 0, len(call.Params))
+		for name := range call.Params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		pattern = "/"
+		for _, name := range names {
+			pattern += "{" + name + "}/"
+		}
+	}
+
+	req := NewJSONRequest(t, method, path, call.Body)
+	if len(call.Query) > 0 {
+		query := req.URL.Query()
+		for key, values := range call.Query {
+			query[key] = append(query[key], values...)
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+	for key, values := range call.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	for name, value := range call.Params {
+		req.SetPathValue(name, value)
+	}
+	if call.Context != nil {
+		req = req.WithContext(call.Context)
+	}
+
+	api := httpsuite.NewAPI(httpsuite.APIInfo{ParamExtractor: PathParams(call.Params)})
+	rec := httptest.NewRecorder()
+	httpsuite.Handle(api, method, pattern, handler, &httpsuite.RouteOptions{Parse: call.Parse}).ServeHTTP(rec, req)
+
+	if rec.Code >= 400 {
+		problem := AssertProblem(t, rec, rec.Code, "")
+		return zero, problem
+	}
+	if rec.Code == http.StatusNoContent || len(bytes.TrimSpace(rec.Body.Bytes())) == 0 {
+		return zero, nil
+	}
+	return DecodeData[Resp](t, rec), nil
+}
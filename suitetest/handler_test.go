@@ -0,0 +1,102 @@
+package suitetest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+type renameItem struct {
+	ID   int    `json:"-"`
+	Name string `json:"name"`
+	Org  string `json:"-"`
+}
+
+func (r *renameItem) SetParam(fieldName, value string) error {
+	switch fieldName {
+	case "id":
+		if _, err := fmt.Sscan(value, &r.ID); err != nil {
+			return httpsuite.NewBadRequestProblem("id must be a number")
+		}
+	case "org":
+		r.Org = value
+	}
+	return nil
+}
+
+func TestInvoke(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, req *renameItem) (item, error) {
+		if req.ID == 404 {
+			return item{}, httpsuite.NewNotFoundProblem("item not found")
+		}
+		if principal, _ := httpsuite.CtxGet(ctx, httpsuite.PrincipalKey); principal != "ada" {
+			return item{}, fmt.Errorf("missing principal")
+		}
+		return item{ID: req.ID, Name: req.Org + "/" + req.Name}, nil
+	}
+	ctx := httpsuite.CtxSet(context.Background(), httpsuite.PrincipalKey, "ada")
+
+	got, problem := Invoke(t, handler, Call{
+		Method:  http.MethodPut,
+		Path:    "/orgs/acme/items/7",
+		Params:  map[string]string{"org": "acme", "id": "7"},
+		Query:   url.Values{"dry_run": {"true"}},
+		Body:    renameItem{Name: "desk"},
+		Context: ctx,
+	})
+	if problem != nil || got != (item{ID: 7, Name: "acme/desk"}) {
+		t.Fatalf("unexpected result %#v, %v", got, problem)
+	}
+
+	_, problem = Invoke(t, handler, Call{
+		Path:    "/items/404",
+		Params:  map[string]string{"id": "404"},
+		Header:  http.Header{"X-Request-Id": {"req-1"}},
+		Body:    renameItem{},
+		Context: httpsuite.WithProblemConfig(context.Background(), httpsuite.ProblemConfig{Instance: httpsuite.DefaultInstance}),
+	})
+	if problem == nil || problem.Instance != "/items/404#req-1" {
+		t.Fatalf("expected the path and request ID in the instance, got %#v", problem)
+	}
+
+	tests := []struct {
+		name       string
+		call       Call
+		wantStatus int
+	}{
+		{name: "handler problem", call: Call{Params: map[string]string{"id": "404"}, Body: renameItem{}, Context: ctx}, wantStatus: http.StatusNotFound},
+		{name: "invalid param", call: Call{Params: map[string]string{"id": "x"}, Body: renameItem{}, Context: ctx}, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", call: Call{Params: map[string]string{"id": "1"}, Body: "{", Context: ctx}, wantStatus: http.StatusBadRequest},
+		{name: "unhandled error", call: Call{Params: map[string]string{"id": "1"}, Body: renameItem{}}, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, problem := Invoke(t, handler, tt.call)
+			if problem == nil || problem.Status != tt.wantStatus {
+				t.Fatalf("expected a %d problem, got %#v", tt.wantStatus, problem)
+			}
+		})
+	}
+}
+
+func TestInvokeRequestDetails(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, _ httpsuite.NoBody) (httpsuite.NoBody, error) {
+		return httpsuite.NoBody{}, nil
+	}
+	if _, problem := Invoke(t, handler, Call{Method: http.MethodDelete}); problem != nil {
+		t.Fatalf("unexpected problem %#v", problem)
+	}
+
+	extractor := PathParams(map[string]string{"id": "7"})
+	if got := extractor(nil, "id"); got != "7" {
+		t.Fatalf("expected 7, got %q", got)
+	}
+}
@@ -0,0 +1,141 @@
+// Package suitetest provides assertions and request builders for testing
+// handlers that use httpsuite with net/http/httptest.
+package suitetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+// TB is the part of testing.TB used by the helpers.
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+// NewJSONRequest builds a request for a handler under test. body is encoded
+// as JSON unless it is nil, a string, or a []byte, which are sent as is.
+// params are path parameter name and value pairs, readable with
+// r.PathValue as if the request had been routed by http.ServeMux.
+func NewJSONRequest(t TB, method, path string, body any, params ...string) *http.Request {
+	t.Helper()
+	if len(params)%2 != 0 {
+		t.Fatalf("suitetest: path params must be name and value pairs, got %q", params)
+	}
+
+	var reader io.Reader
+	switch typed := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(typed)
+	case []byte:
+		reader = bytes.NewReader(typed)
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("suitetest: encode request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i < len(params); i += 2 {
+		req.SetPathValue(params[i], params[i+1])
+	}
+	return req
+}
+
+// AssertProblem checks that rec holds an application/problem+json reply with
+// status and returns the decoded problem. typeKey is a problem type key such
+// as "validation_error", resolved with httpsuite.GetProblemTypeURL, or the
+// literal type URL; an empty typeKey skips the type check.
+func AssertProblem(t TB, rec *httptest.ResponseRecorder, status int, typeKey string) *httpsuite.ProblemDetails {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("expected status %d, got %d: %s", status, rec.Code, rec.Body.String())
+	}
+	if mediaType := contentType(rec); mediaType != "application/problem+json" {
+		t.Fatalf("expected application/problem+json, got %q", rec.Header().Get("Content-Type"))
+	}
+
+	var problem httpsuite.ProblemDetails
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v: %s", err, rec.Body.String())
+	}
+	if problem.Status != status {
+		t.Fatalf("expected problem status %d, got %d", status, problem.Status)
+	}
+	if typeKey != "" && problem.Type != typeKey && problem.Type != httpsuite.GetProblemTypeURL(typeKey) {
+		t.Fatalf("expected problem type %q, got %q", typeKey, problem.Type)
+	}
+	return &problem
+}
+
+// DecodeData checks that rec holds a successful JSON reply and returns the
+// "data" member of its envelope.
+func DecodeData[T any](t TB, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var envelope struct {
+		Data T `json:"data"`
+	}
+	decodeEnvelope(t, rec, &envelope)
+	return envelope.Data
+}
+
+// DecodeMeta returns the "meta" member of the envelope in rec.
+func DecodeMeta[T any](t TB, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var envelope struct {
+		Meta T `json:"meta"`
+	}
+	decodeEnvelope(t, rec, &envelope)
+	return envelope.Meta
+}
+
+// AssertMeta checks that the envelope meta in rec equals want, e.g. a
+// httpsuite.PageMeta, comparing their JSON forms.
+func AssertMeta(t TB, rec *httptest.ResponseRecorder, want any) {
+	t.Helper()
+	got := DecodeMeta[any](t, rec)
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("encode expected meta: %v", err)
+	}
+	var expected any
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatalf("decode expected meta: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		actual, _ := json.Marshal(got)
+		t.Fatalf("expected meta %s, got %s", data, actual)
+	}
+}
+
+func decodeEnvelope(t TB, rec *httptest.ResponseRecorder, envelope any) {
+	t.Helper()
+	if rec.Code < 200 || rec.Code > 299 {
+		t.Fatalf("expected a successful status, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if mediaType := contentType(rec); mediaType != "application/json" {
+		t.Fatalf("expected application/json, got %q", rec.Header().Get("Content-Type"))
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), envelope); err != nil {
+		t.Fatalf("decode response: %v: %s", err, rec.Body.String())
+	}
+}
+
+func contentType(rec *httptest.ResponseRecorder) string {
+	mediaType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	return mediaType
+}
@@ -0,0 +1,125 @@
+package suitetest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+type item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (i *item) SetParam(fieldName, value string) error {
+	if fieldName == "id" {
+		_, err := fmt.Sscan(value, &i.ID)
+		return err
+	}
+	return nil
+}
+
+// fatalTB records the first failure and stops the helper like t.Fatalf.
+type fatalTB struct {
+	failure string
+}
+
+type fatalStop struct{}
+
+func (f *fatalTB) Helper() {}
+
+func (f *fatalTB) Fatalf(format string, args ...any) {
+	f.failure = fmt.Sprintf(format, args...)
+	panic(fatalStop{})
+}
+
+func (f *fatalTB) run(fn func()) string {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if _, ok := recovered.(fatalStop); !ok {
+				panic(recovered)
+			}
+		}
+	}()
+	fn()
+	return ""
+}
+
+func serve(r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, err := httpsuite.ParseRequest[*item](w, r, func(r *http.Request, key string) string {
+		return r.PathValue(key)
+	}, nil, "id")
+	if err != nil {
+		return w
+	}
+	httpsuite.Reply().Meta(httpsuite.NewPageMeta(1, 10, 1)).OK(w, req)
+	return w
+}
+
+func TestNewJSONRequestAndDecodeData(t *testing.T) {
+	t.Parallel()
+
+	rec := serve(NewJSONRequest(t, http.MethodPut, "/items/7", item{Name: "desk"}, "id", "7"))
+	if got := DecodeData[item](t, rec); got != (item{ID: 7, Name: "desk"}) {
+		t.Fatalf("unexpected data %#v", got)
+	}
+	AssertMeta(t, rec, httpsuite.NewPageMeta(1, 10, 1))
+	if meta := DecodeMeta[httpsuite.PageMeta](t, rec); meta.TotalItems != 1 {
+		t.Fatalf("unexpected meta %#v", meta)
+	}
+
+	raw := NewJSONRequest(t, http.MethodPost, "/items", `{"name":"lamp"}`)
+	if raw.Header.Get("Content-Type") != "application/json" {
+		t.Fatal("expected a JSON content type for raw bodies")
+	}
+	if empty := NewJSONRequest(t, http.MethodGet, "/items", nil); empty.Body != http.NoBody || empty.Header.Get("Content-Type") != "" {
+		t.Fatal("expected no body or content type for nil bodies")
+	}
+}
+
+func TestAssertProblem(t *testing.T) {
+	t.Parallel()
+
+	rec := serve(NewJSONRequest(t, http.MethodPut, "/items/7", "{", "id", "7"))
+	problem := AssertProblem(t, rec, http.StatusBadRequest, "bad_request_error")
+	if problem.Detail != "request body contains incomplete JSON at line 1, column 2" || problem.Extensions["line"] != float64(1) {
+		t.Fatalf("unexpected problem %#v", problem)
+	}
+	AssertProblem(t, rec, http.StatusBadRequest, httpsuite.GetProblemTypeURL("bad_request_error"))
+	AssertProblem(t, rec, http.StatusBadRequest, "")
+}
+
+func TestHelperFailures(t *testing.T) {
+	t.Parallel()
+
+	invalid := serve(NewJSONRequest(t, http.MethodPut, "/items/7", "{", "id", "7"))
+	valid := serve(NewJSONRequest(t, http.MethodPut, "/items/7", item{Name: "desk"}, "id", "7"))
+
+	tests := []struct {
+		name string
+		fn   func(TB)
+		want string
+	}{
+		{name: "odd params", fn: func(tb TB) { NewJSONRequest(tb, http.MethodGet, "/", nil, "id") }, want: "name and value pairs"},
+		{name: "wrong status", fn: func(tb TB) { AssertProblem(tb, invalid, http.StatusNotFound, "") }, want: "expected status 404, got 400"},
+		{name: "wrong type", fn: func(tb TB) { AssertProblem(tb, invalid, http.StatusBadRequest, "not_found_error") }, want: "expected problem type"},
+		{name: "not a problem", fn: func(tb TB) { AssertProblem(tb, valid, http.StatusOK, "") }, want: "expected application/problem+json"},
+		{name: "data from a problem", fn: func(tb TB) { DecodeData[item](tb, invalid) }, want: "expected a successful status"},
+		{name: "wrong meta", fn: func(tb TB) { AssertMeta(tb, valid, httpsuite.NewPageMeta(2, 10, 1)) }, want: "expected meta"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := &fatalTB{}
+			tb.run(func() { tt.fn(tb) })
+			if !strings.Contains(tb.failure, tt.want) {
+				t.Fatalf("expected failure containing %q, got %q", tt.want, tb.failure)
+			}
+		})
+	}
+}
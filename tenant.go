@@ -0,0 +1,182 @@
+package httpsuite
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TenantSource records where a tenant identifier was found.
+type TenantSource string
+
+const (
+	TenantSourceHeader    TenantSource = "header"
+	TenantSourceSubdomain TenantSource = "subdomain"
+	TenantSourcePath      TenantSource = "path"
+)
+
+// ErrTenantNotFound is returned by resolvers for unknown tenant identifiers.
+var ErrTenantNotFound = errors.New("tenant not found")
+
+var errMissingTenant = errors.New("tenant identifier is missing")
+
+// Tenant is the tenant resolved for a request.
+type Tenant struct {
+	ID     string
+	Source TenantSource
+	// Data holds whatever the resolver returned, such as an account record.
+	Data any
+}
+
+// TenantResolver validates a tenant identifier and returns data to attach to
+// the request. Return ErrTenantNotFound for unknown tenants, or a
+// *ProblemDetails to control the response.
+type TenantResolver func(ctx context.Context, id string) (any, error)
+
+// TenancyOptions configures the Tenancy middleware. Sources are tried in
+// order: header, subdomain, path.
+type TenancyOptions struct {
+	// Header names the request header carrying the tenant ID, e.g. "X-Tenant-ID".
+	Header string
+	// BaseDomain enables subdomain resolution: "acme.example.com" resolves to
+	// "acme" when BaseDomain is "example.com".
+	BaseDomain string
+	// ParamExtractor and PathParam enable path resolution. The router must
+	// have matched the route before the middleware runs.
+	ParamExtractor ParamExtractor
+	PathParam      string
+	// Resolver validates identifiers. When nil every identifier is accepted.
+	Resolver TenantResolver
+	// Optional lets requests without a tenant through instead of replying 400.
+	Optional bool
+	// Problems overrides the problem config resolved from the request context.
+	Problems *ProblemConfig
+}
+
+// WithTenant returns a context carrying tenant.
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return CtxSet(ctx, TenantKey, tenant)
+}
+
+// TenantFromContext returns the tenant stored by Tenancy or WithTenant.
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	return CtxGet(ctx, TenantKey)
+}
+
+// TenantID returns the current tenant ID, or "" when there is none. It is
+// suitable as a log field or metrics label.
+func TenantID(ctx context.Context) string {
+	tenant, _ := TenantFromContext(ctx)
+	return tenant.ID
+}
+
+// TenantData returns the resolver data of the current tenant as T.
+func TenantData[T any](ctx context.Context) (T, bool) {
+	tenant, _ := TenantFromContext(ctx)
+	data, ok := tenant.Data.(T)
+	return data, ok
+}
+
+// Tenancy returns middleware that resolves the tenant of each request and
+// stores it in the context. Missing tenants reply 400 unless Optional is set,
+// unknown tenants reply 404, and other resolver errors reply 500. The tenant
+// is added to audit entries and to the default problem instance URI.
+func Tenancy(opts *TenancyOptions) func(http.Handler) http.Handler {
+	var options TenancyOptions
+	if opts != nil {
+		options = *opts
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, source := tenantIdentifier(r, options)
+			if id == "" {
+				if options.Optional {
+					next.ServeHTTP(w, r)
+					return
+				}
+				problems := resolveProblemConfig(r.Context(), options.Problems)
+				sendRequestProblem(w, r, http.StatusBadRequest, Problem(http.StatusBadRequest).
+					Type(problems.TypeURL("bad_request_error")).
+					Title("Missing Tenant").
+					Detail(errMissingTenant.Error()).
+					Build())
+				return
+			}
+
+			tenant := Tenant{ID: id, Source: source}
+			if options.Resolver != nil {
+				data, err := options.Resolver(r.Context(), id)
+				if err != nil {
+					sendTenantError(w, r, id, err, options.Problems)
+					return
+				}
+				tenant.Data = data
+			}
+
+			recordAuditTenant(r.Context(), id)
+			next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), tenant)))
+		})
+	}
+}
+
+func tenantIdentifier(r *http.Request, options TenancyOptions) (string, TenantSource) {
+	if options.Header != "" {
+		if id := strings.TrimSpace(r.Header.Get(options.Header)); id != "" {
+			return id, TenantSourceHeader
+		}
+	}
+	if options.BaseDomain != "" {
+		if id := tenantSubdomain(r.Host, options.BaseDomain); id != "" {
+			return id, TenantSourceSubdomain
+		}
+	}
+	if options.ParamExtractor != nil && options.PathParam != "" {
+		if id := options.ParamExtractor(r, options.PathParam); id != "" {
+			return id, TenantSourcePath
+		}
+	}
+	return "", ""
+}
+
+// tenantSubdomain returns the single label in front of baseDomain.
+func tenantSubdomain(host, baseDomain string) string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(strings.Trim(baseDomain, ".")))
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
+
+func sendTenantError(w http.ResponseWriter, r *http.Request, id string, err error, explicit *ProblemConfig) {
+	if problem, ok := AsProblem(err); ok {
+		sendRequestProblem(w, r, problem.Status, problem)
+		return
+	}
+	problems := resolveProblemConfig(r.Context(), explicit)
+	if errors.Is(err, ErrTenantNotFound) {
+		sendRequestProblem(w, r, http.StatusNotFound, Problem(http.StatusNotFound).
+			Type(problems.TypeURL("not_found_error")).
+			Title("Unknown Tenant").
+			Detail("tenant "+id+" does not exist").
+			Build())
+		return
+	}
+	status := http.StatusInternalServerError
+	sendRequestProblem(w, r, status, NewProblemDetails(status, problems.TypeURL("server_error"), "Internal Server Error", sanitizedDetail(err, "tenant could not be resolved")))
+}
+
+// tenantInstanceQuery returns "?tenant=<id>" for tenants that are not already
+// part of the request path.
+func tenantInstanceQuery(r *http.Request) string {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok || tenant.Source == TenantSourcePath {
+		return ""
+	}
+	return "?" + url.Values{"tenant": {tenant.ID}}.Encode()
+}
@@ -0,0 +1,180 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testAccount struct {
+	Plan string
+}
+
+func TestTenancyResolvesTenant(t *testing.T) {
+	t.Parallel()
+
+	resolver := func(_ context.Context, id string) (any, error) {
+		switch id {
+		case "acme", "globex":
+			return &testAccount{Plan: "pro"}, nil
+		case "suspended":
+			return nil, Problem(http.StatusForbidden).Detail("tenant is suspended").Build()
+		case "broken":
+			return nil, errors.New("database unavailable")
+		}
+		return nil, ErrTenantNotFound
+	}
+	handler := Tenancy(&TenancyOptions{
+		Header:         "X-Tenant-ID",
+		BaseDomain:     "example.com",
+		ParamExtractor: testParamExtractor,
+		PathParam:      "id",
+		Resolver:       resolver,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, _ := TenantFromContext(r.Context())
+		account, ok := TenantData[*testAccount](r.Context())
+		if !ok {
+			t.Error("expected typed tenant data")
+		}
+		OK(w, map[string]string{"tenant": TenantID(r.Context()), "source": string(tenant.Source), "plan": account.Plan})
+	}))
+
+	tests := []struct {
+		name       string
+		host       string
+		target     string
+		header     string
+		wantStatus int
+		wantTenant string
+		wantSource TenantSource
+	}{
+		{name: "header", host: "api.example.com", target: "/orders", header: "acme", wantStatus: http.StatusOK, wantTenant: "acme", wantSource: TenantSourceHeader},
+		{name: "subdomain", host: "globex.example.com:8080", target: "/orders", wantStatus: http.StatusOK, wantTenant: "globex", wantSource: TenantSourceSubdomain},
+		{name: "path", host: "example.com", target: "/tenants/acme", wantStatus: http.StatusOK, wantTenant: "acme", wantSource: TenantSourcePath},
+		{name: "nested subdomain ignored", host: "a.b.example.com", target: "/", wantStatus: http.StatusBadRequest},
+		{name: "missing", host: "example.com", target: "/", wantStatus: http.StatusBadRequest},
+		{name: "unknown", host: "initech.example.com", target: "/", wantStatus: http.StatusNotFound},
+		{name: "resolver problem", host: "suspended.example.com", target: "/", wantStatus: http.StatusForbidden},
+		{name: "resolver failure", host: "broken.example.com", target: "/", wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Host = tt.host
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantTenant == "" {
+				return
+			}
+			var response Response[map[string]string]
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if response.Data["tenant"] != tt.wantTenant || response.Data["source"] != string(tt.wantSource) || response.Data["plan"] != "pro" {
+				t.Fatalf("unexpected tenant %#v", response.Data)
+			}
+		})
+	}
+}
+
+func TestTenancyUsesProblemConfig(t *testing.T) {
+	t.Parallel()
+
+	resolver := func(_ context.Context, id string) (any, error) {
+		if id == "broken" {
+			return nil, errors.New("database unavailable")
+		}
+		return nil, ErrTenantNotFound
+	}
+	explicit := &ProblemConfig{BaseURL: "https://explicit.example.com"}
+
+	tests := []struct {
+		name     string
+		problems *ProblemConfig
+		header   string
+		wantType string
+	}{
+		{name: "missing from context", wantType: "https://ctx.example.com/errors/bad-request"},
+		{name: "unknown from context", header: "initech", wantType: "https://ctx.example.com/errors/not-found"},
+		{name: "failure from context", header: "broken", wantType: "https://ctx.example.com/errors/server-error"},
+		{name: "failure explicit", problems: explicit, header: "broken", wantType: "https://explicit.example.com/errors/server-error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Tenancy(&TenancyOptions{Header: "X-Tenant-ID", Resolver: resolver, Problems: tt.problems})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				t.Error("expected the request to be rejected")
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(WithProblemConfig(req.Context(), ProblemConfig{BaseURL: "https://ctx.example.com"}))
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			var problem ProblemDetails
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Type != tt.wantType {
+				t.Fatalf("expected type %q, got %q", tt.wantType, problem.Type)
+			}
+		})
+	}
+}
+
+func TestTenancyOptional(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	handler := Tenancy(&TenancyOptions{Header: "X-Tenant-ID", Optional: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if _, ok := TenantFromContext(r.Context()); ok {
+			t.Error("expected no tenant")
+		}
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if !called {
+		t.Fatal("expected handler to run without a tenant")
+	}
+}
+
+func TestTenantInProblemInstanceAndAudit(t *testing.T) {
+	t.Parallel()
+
+	var entry AuditEntry
+	auditor := AuditorFunc(func(_ context.Context, e AuditEntry) { entry = e })
+	handler := Audit(auditor, nil)(Tenancy(&TenancyOptions{Header: "X-Tenant-ID"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ParseRequest[*testRequest](w, r, testParamExtractor, nil, "id")
+	})))
+	handler = UseProblemConfig(ProblemConfig{Instance: DefaultInstance})(handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/items/abc", nil)
+	req.Header.Set("X-Tenant-ID", "acme corp")
+	req.Header.Set("X-Request-ID", "req-1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var problem ProblemDetails
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Instance != "/items/abc?tenant=acme+corp#req-1" {
+		t.Fatalf("unexpected instance %q", problem.Instance)
+	}
+	if entry.Tenant != "acme corp" {
+		t.Fatalf("expected audit entry tenant, got %q", entry.Tenant)
+	}
+}
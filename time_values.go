@@ -0,0 +1,407 @@
+package httpsuite
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const timeOnlyLayout = "15:04:05.999999999"
+
+// ErrInvalidDuration is returned when a value is not a duration.
+var ErrInvalidDuration = errors.New("invalid duration")
+
+var responseTimezone atomic.Pointer[time.Location]
+
+// SetResponseTimezone sets the zone that Timestamp values are converted to
+// when encoded and that Today uses. nil restores the default, UTC.
+func SetResponseTimezone(loc *time.Location) {
+	responseTimezone.Store(loc)
+}
+
+// ResponseTimezone returns the zone set with SetResponseTimezone, or UTC.
+func ResponseTimezone() *time.Location {
+	if loc := responseTimezone.Load(); loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// unquoteJSON strips the quotes of a JSON string and reports whether data
+// was one.
+func unquoteJSON(data []byte) ([]byte, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		return data[1 : len(data)-1], true
+	}
+	return data, false
+}
+
+// Timestamp is a time.Time that always encodes as RFC 3339 in the response
+// timezone, e.g. "2024-03-01T09:30:00Z", whatever zone it was created in.
+type Timestamp struct {
+	time.Time
+}
+
+// String formats t like MarshalText.
+func (t Timestamp) String() string {
+	return t.In(ResponseTimezone()).Format(time.RFC3339Nano)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText parses an RFC 3339 timestamp.
+func (t *Timestamp) UnmarshalText(text []byte) error {
+	parsed, err := time.Parse(time.RFC3339Nano, string(text))
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalJSON encodes t as an RFC 3339 string in the response timezone.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+func (Timestamp) openAPISchema() map[string]any {
+	return map[string]any{"type": "string", "format": "date-time"}
+}
+
+// UnmarshalJSON parses an RFC 3339 string. null leaves t unchanged.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	text, ok := unquoteJSON(data)
+	if !ok {
+		return errors.New("timestamp must be an RFC 3339 string")
+	}
+	return t.UnmarshalText(text)
+}
+
+// DateOnly is a calendar date without a time of day, encoded as
+// "2006-01-02". It holds midnight UTC of that date.
+type DateOnly struct {
+	time.Time
+}
+
+// NewDateOnly returns the given date.
+func NewDateOnly(year int, month time.Month, day int) DateOnly {
+	return DateOnly{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf returns the calendar date of t in t's location.
+func DateOf(t time.Time) DateOnly {
+	year, month, day := t.Date()
+	return NewDateOnly(year, month, day)
+}
+
+// Today returns the current date in the response timezone.
+func Today() DateOnly {
+	return DateOf(time.Now().In(ResponseTimezone()))
+}
+
+// ParseDateOnly parses a "2006-01-02" date.
+func ParseDateOnly(s string) (DateOnly, error) {
+	parsed, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return DateOnly{}, err
+	}
+	return DateOnly{Time: parsed}, nil
+}
+
+// AddDays returns the date n days later, or earlier for negative n.
+func (d DateOnly) AddDays(n int) DateOnly {
+	return DateOnly{Time: d.AddDate(0, 0, n)}
+}
+
+// String formats d as "2006-01-02".
+func (d DateOnly) String() string {
+	return d.Format(time.DateOnly)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d DateOnly) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses a "2006-01-02" date, so dates bind from path and
+// query parameters.
+func (d *DateOnly) UnmarshalText(text []byte) error {
+	parsed, err := ParseDateOnly(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes d as a "2006-01-02" string.
+func (d DateOnly) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+func (DateOnly) openAPISchema() map[string]any {
+	return map[string]any{"type": "string", "format": "date"}
+}
+
+// UnmarshalJSON parses a "2006-01-02" string. null leaves d unchanged.
+func (d *DateOnly) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	text, ok := unquoteJSON(data)
+	if !ok {
+		return errors.New("date must be a string formatted as 2006-01-02")
+	}
+	return d.UnmarshalText(text)
+}
+
+// TimeOnly is a time of day without a date, encoded as "15:04:05" with
+// fractional seconds when present. It holds that time on January 1 of year 0
+// in UTC; parsing also accepts "15:04".
+type TimeOnly struct {
+	time.Time
+}
+
+// NewTimeOnly returns the given time of day.
+func NewTimeOnly(hour, minute, second int) TimeOnly {
+	return TimeOnly{Time: time.Date(0, time.January, 1, hour, minute, second, 0, time.UTC)}
+}
+
+// TimeOf returns the time of day of t in t's location.
+func TimeOf(t time.Time) TimeOnly {
+	return TimeOnly{Time: time.Date(0, time.January, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)}
+}
+
+// ParseTimeOnly parses "15:04:05", with optional fractional seconds, or
+// "15:04".
+func ParseTimeOnly(s string) (TimeOnly, error) {
+	parsed, err := time.Parse(timeOnlyLayout, s)
+	if err != nil {
+		var shortErr error
+		if parsed, shortErr = time.Parse("15:04", s); shortErr != nil {
+			return TimeOnly{}, err
+		}
+	}
+	return TimeOf(parsed), nil
+}
+
+// String formats t as "15:04:05".
+func (t TimeOnly) String() string {
+	return t.Format(timeOnlyLayout)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t TimeOnly) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText parses a time of day.
+func (t *TimeOnly) UnmarshalText(text []byte) error {
+	parsed, err := ParseTimeOnly(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalJSON encodes t as a "15:04:05" string.
+func (t TimeOnly) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+func (TimeOnly) openAPISchema() map[string]any {
+	return map[string]any{"type": "string", "format": "time"}
+}
+
+// UnmarshalJSON parses a time-of-day string. null leaves t unchanged.
+func (t *TimeOnly) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	text, ok := unquoteJSON(data)
+	if !ok {
+		return errors.New("time must be a string formatted as 15:04:05")
+	}
+	return t.UnmarshalText(text)
+}
+
+// UnixMillis is an instant encoded as milliseconds since the Unix epoch, for
+// clients that exchange numeric timestamps. It decodes from a JSON number or
+// a numeric string.
+type UnixMillis struct {
+	time.Time
+}
+
+// String formats u as its millisecond count.
+func (u UnixMillis) String() string {
+	return strconv.FormatInt(u.UnixMilli(), 10)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u UnixMillis) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText parses a millisecond count.
+func (u *UnixMillis) UnmarshalText(text []byte) error {
+	millis, err := strconv.ParseInt(string(text), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Unix milliseconds %q", text)
+	}
+	u.Time = time.UnixMilli(millis).UTC()
+	return nil
+}
+
+// MarshalJSON encodes u as a JSON number.
+func (u UnixMillis) MarshalJSON() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+func (UnixMillis) openAPISchema() map[string]any {
+	return map[string]any{"type": "integer", "format": "int64"}
+}
+
+// UnmarshalJSON accepts a JSON number or numeric string. null leaves u
+// unchanged.
+func (u *UnixMillis) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	text, _ := unquoteJSON(data)
+	return u.UnmarshalText(text)
+}
+
+// Duration is a time.Duration encoded as a Go duration string such as
+// "1h30m0s". It decodes from Go duration strings or ISO 8601 durations such
+// as "PT1H30M" and "P1DT12H"; years and months are rejected because their
+// length varies.
+type Duration time.Duration
+
+// ParseDuration parses a Go or ISO 8601 duration.
+func ParseDuration(s string) (Duration, error) {
+	if strings.HasPrefix(s, "P") || strings.HasPrefix(s, "-P") {
+		return parseISODuration(s)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+	return Duration(parsed), nil
+}
+
+// isoDurationUnits lists the designators parseISODuration accepts, in the
+// order they must appear.
+var isoDurationUnits = []struct {
+	designator byte
+	inTime     bool
+	unit       time.Duration
+}{
+	{'W', false, 7 * 24 * time.Hour},
+	{'D', false, 24 * time.Hour},
+	{'H', true, time.Hour},
+	{'M', true, time.Minute},
+	{'S', true, time.Second},
+}
+
+// parseISODuration parses the week, day, hour, minute, and second
+// components of an ISO 8601 duration. Each component may appear once, in
+// that order, and the total must fit in a time.Duration.
+func parseISODuration(s string) (Duration, error) {
+	invalid := fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	text, negative := strings.CutPrefix(s, "-")
+	text = strings.TrimPrefix(text, "P")
+	if text == "" || strings.HasSuffix(text, "T") {
+		return 0, invalid
+	}
+
+	var total time.Duration
+	inTime := false
+	next := 0
+	for text != "" {
+		if text[0] == 'T' {
+			if inTime {
+				return 0, invalid
+			}
+			inTime = true
+			text = text[1:]
+			continue
+		}
+		end := strings.IndexFunc(text, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if end <= 0 {
+			return 0, invalid
+		}
+		value, err := strconv.ParseFloat(text[:end], 64)
+		if err != nil {
+			return 0, invalid
+		}
+		index := next
+		for index < len(isoDurationUnits) && (isoDurationUnits[index].designator != text[end] || isoDurationUnits[index].inTime != inTime) {
+			index++
+		}
+		if index == len(isoDurationUnits) {
+			// Unknown, repeated, or out-of-order component.
+			return 0, invalid
+		}
+		next = index + 1
+		part := value * float64(isoDurationUnits[index].unit)
+		if part >= float64(math.MaxInt64) || total+time.Duration(part) < total {
+			return 0, fmt.Errorf("%w: %q overflows", ErrInvalidDuration, s)
+		}
+		total += time.Duration(part)
+		text = text[end+1:]
+	}
+	if negative {
+		total = -total
+	}
+	return Duration(total), nil
+}
+
+// String formats d like time.Duration.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses a Go or ISO 8601 duration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes d as a Go duration string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON parses a duration string. null leaves d unchanged.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	text, ok := unquoteJSON(data)
+	if !ok {
+		return fmt.Errorf("%w: must be a string such as \"1h30m\"", ErrInvalidDuration)
+	}
+	return d.UnmarshalText(text)
+}
@@ -0,0 +1,176 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeValuesJSON(t *testing.T) {
+	t.Parallel()
+
+	type schedule struct {
+		Day      DateOnly   `json:"day"`
+		Opens    TimeOnly   `json:"opens"`
+		Updated  UnixMillis `json:"updated"`
+		Interval Duration   `json:"interval"`
+	}
+
+	var decoded schedule
+	body := `{"day":"2024-02-29","opens":"09:30","updated":1709199000123,"interval":"PT1H30M"}`
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		t.Fatalf("expected schedule to decode, got %v", err)
+	}
+	if !decoded.Day.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected 2024-02-29, got %v", decoded.Day.Time)
+	}
+	if decoded.Opens.Hour() != 9 || decoded.Opens.Minute() != 30 {
+		t.Fatalf("expected 09:30, got %v", decoded.Opens)
+	}
+	if decoded.Updated.UnixMilli() != 1709199000123 {
+		t.Fatalf("expected millis to round-trip, got %d", decoded.Updated.UnixMilli())
+	}
+	if time.Duration(decoded.Interval) != 90*time.Minute {
+		t.Fatalf("expected 90m, got %v", decoded.Interval)
+	}
+
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("expected schedule to encode, got %v", err)
+	}
+	want := `{"day":"2024-02-29","opens":"09:30:00","updated":1709199000123,"interval":"1h30m0s"}`
+	if string(encoded) != want {
+		t.Fatalf("expected %s, got %s", want, encoded)
+	}
+}
+
+func TestTimeValuesRejectInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		target any
+		body   string
+	}{
+		{name: "date format", target: new(DateOnly), body: `"29/02/2024"`},
+		{name: "date number", target: new(DateOnly), body: `20240229`},
+		{name: "impossible date", target: new(DateOnly), body: `"2023-02-29"`},
+		{name: "time", target: new(TimeOnly), body: `"25:00"`},
+		{name: "millis", target: new(UnixMillis), body: `"yesterday"`},
+		{name: "duration number", target: new(Duration), body: `90`},
+		{name: "duration months", target: new(Duration), body: `"P1M"`},
+		{name: "duration empty time", target: new(Duration), body: `"P1DT"`},
+		{name: "timestamp", target: new(Timestamp), body: `"2024-02-29 10:00"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := json.Unmarshal([]byte(tt.body), tt.target); err == nil {
+				t.Fatalf("expected %s to be rejected", tt.body)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{input: "1h30m", want: 90 * time.Minute},
+		{input: "PT45S", want: 45 * time.Second},
+		{input: "PT0.5S", want: 500 * time.Millisecond},
+		{input: "P1DT12H", want: 36 * time.Hour},
+		{input: "P2W", want: 14 * 24 * time.Hour},
+		{input: "-PT10M", want: -10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if err != nil || time.Duration(got) != tt.want {
+			t.Fatalf("ParseDuration(%q): expected %v, got %v %v", tt.input, tt.want, got, err)
+		}
+	}
+	for _, input := range []string{
+		"soon",
+		"P1000000000W",
+		"PT99999999999999H",
+		"P15250W2D",
+		"P1D1D",
+		"PT1M1H",
+		"P1DT1H1H",
+		"P1D2W",
+		"PT1S1M",
+	} {
+		if _, err := ParseDuration(input); !errors.Is(err, ErrInvalidDuration) {
+			t.Fatalf("ParseDuration(%q): expected ErrInvalidDuration, got %v", input, err)
+		}
+	}
+	if got, err := ParseDuration("PT2562047H47M16S"); err != nil || time.Duration(got) != 2562047*time.Hour+47*time.Minute+16*time.Second {
+		t.Fatalf("expected the largest whole-second duration to parse, got %v %v", got, err)
+	}
+}
+
+func TestTimeValuesBindFromText(t *testing.T) {
+	t.Parallel()
+
+	var date DateOnly
+	var clock TimeOnly
+	var millis UnixMillis
+	var interval Duration
+	for _, bind := range []struct {
+		value  string
+		target interface{ UnmarshalText([]byte) error }
+	}{
+		{value: "2024-12-31", target: &date},
+		{value: "18:45:10.5", target: &clock},
+		{value: "0", target: &millis},
+		{value: "15m", target: &interval},
+	} {
+		if err := bind.target.UnmarshalText([]byte(bind.value)); err != nil {
+			t.Fatalf("expected %q to bind, got %v", bind.value, err)
+		}
+	}
+	if date.AddDays(1).String() != "2025-01-01" || clock.String() != "18:45:10.5" || !millis.Equal(time.Unix(0, 0)) || interval.String() != "15m0s" {
+		t.Fatalf("unexpected bound values %s %s %s %s", date, clock, millis, interval)
+	}
+}
+
+func TestTimestampUsesResponseTimezone(t *testing.T) {
+	t.Cleanup(func() { SetResponseTimezone(nil) })
+
+	instant := Timestamp{Time: time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))}
+	if encoded, _ := json.Marshal(instant); string(encoded) != `"2024-03-01T08:30:00Z"` {
+		t.Fatalf("expected UTC by default, got %s", encoded)
+	}
+
+	SetResponseTimezone(time.FixedZone("BRT", -3*3600))
+	if encoded, _ := json.Marshal(instant); string(encoded) != `"2024-03-01T05:30:00-03:00"` {
+		t.Fatalf("expected configured zone, got %s", encoded)
+	}
+
+	var decoded Timestamp
+	if err := json.Unmarshal([]byte(`"2024-03-01T05:30:00-03:00"`), &decoded); err != nil || !decoded.Equal(instant.Time) {
+		t.Fatalf("expected timestamp to round-trip, got %v %v", decoded, err)
+	}
+	if ResponseTimezone().String() != "BRT" || Today().IsZero() {
+		t.Fatal("expected Today to use the configured zone")
+	}
+}
+
+func TestDateAndTimeOf(t *testing.T) {
+	t.Parallel()
+
+	moment := time.Date(2024, 6, 30, 23, 15, 0, 0, time.FixedZone("X", 5*3600))
+	if DateOf(moment).String() != "2024-06-30" || TimeOf(moment).String() != "23:15:00" {
+		t.Fatalf("expected local date and time, got %s %s", DateOf(moment), TimeOf(moment))
+	}
+	if NewDateOnly(2024, time.June, 30).String() != "2024-06-30" || NewTimeOnly(7, 5, 0).String() != "07:05:00" {
+		t.Fatal("expected constructors to build the given values")
+	}
+}
@@ -0,0 +1,14 @@
+module github.com/rluders/httpsuite/transcoding/grpc
+
+go 1.25.0
+
+require (
+	github.com/rluders/httpsuite/v3 v3.0.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260819154853-08b0e4226688
+	google.golang.org/grpc v1.82.1
+	google.golang.org/protobuf v1.36.12
+)
+
+require golang.org/x/sys v0.43.0 // indirect
+
+replace github.com/rluders/httpsuite/v3 => ../..
@@ -0,0 +1,248 @@
+// Package grpc maps httpsuite problems to and from gRPC statuses, so services
+// exposing the same operations over gRPC and REST keep one error model.
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rluders/httpsuite/v3"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorInfo metadata keys that carry the problem members gRPC has no field for.
+const (
+	metadataType     = "type"
+	metadataTitle    = "title"
+	metadataStatus   = "status"
+	metadataInstance = "instance"
+)
+
+// Code returns the gRPC code for an HTTP status, following the mapping in
+// google/rpc/code.proto.
+func Code(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return codes.NotFound
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusPreconditionFailed, http.StatusPreconditionRequired:
+		return codes.FailedPrecondition
+	case http.StatusRequestedRangeNotSatisfiable:
+		return codes.OutOfRange
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	}
+	switch {
+	case httpStatus < 400:
+		return codes.OK
+	case httpStatus < 500:
+		return codes.InvalidArgument
+	default:
+		return codes.Internal
+	}
+}
+
+// HTTPStatus returns the HTTP status for a gRPC code, as grpc-gateway does.
+func HTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ToStatus converts problem into a gRPC status. The detail becomes the
+// message, and the details carry the rest: an ErrorInfo with the type,
+// title, status, instance, and scalar extensions, a BadRequest for
+// validation errors, and a RetryInfo for retry_after.
+func ToStatus(problem *httpsuite.ProblemDetails) *status.Status {
+	if problem == nil {
+		return status.New(codes.Internal, http.StatusText(http.StatusInternalServerError))
+	}
+	message := problem.Detail
+	if message == "" {
+		message = problem.Title
+	}
+	st := status.New(Code(problem.Status), message)
+
+	info := &errdetails.ErrorInfo{
+		Reason: reason(problem.Type),
+		Metadata: map[string]string{
+			metadataType:   problem.Type,
+			metadataTitle:  problem.Title,
+			metadataStatus: strconv.Itoa(problem.Status),
+		},
+	}
+	if parsed, err := url.Parse(problem.Type); err == nil {
+		info.Domain = parsed.Host
+	}
+	if problem.Instance != "" {
+		info.Metadata[metadataInstance] = problem.Instance
+	}
+	details := []protoadapt.MessageV1{info}
+
+	for key, value := range problem.Extensions {
+		if _, reserved := info.Metadata[key]; reserved {
+			continue
+		}
+		switch value := value.(type) {
+		case string, bool, int, int64, float64:
+			info.Metadata[key] = fmt.Sprint(value)
+		case []httpsuite.ValidationErrorDetail:
+			badRequest := &errdetails.BadRequest{}
+			for _, detail := range value {
+				badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+					Field:       detail.Field,
+					Description: detail.Message,
+				})
+			}
+			details = append(details, badRequest)
+		}
+	}
+	if delay, ok := problem.RetryAfter(); ok {
+		delete(info.Metadata, "retry_after")
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// FromStatus converts a gRPC status into a problem, restoring the members
+// ToStatus stored in its details. Statuses from other services get a
+// blank type and the title of the mapped HTTP status.
+func FromStatus(st *status.Status) *httpsuite.ProblemDetails {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	httpStatus := HTTPStatus(st.Code())
+	problemType, title, instance := httpsuite.BlankURL, "", ""
+	extensions := make(map[string]interface{})
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			for key, value := range detail.GetMetadata() {
+				switch key {
+				case metadataType:
+					problemType = value
+				case metadataTitle:
+					title = value
+				case metadataInstance:
+					instance = value
+				case metadataStatus:
+					if parsed, err := strconv.Atoi(value); err == nil && Code(parsed) == st.Code() {
+						httpStatus = parsed
+					}
+				default:
+					extensions[key] = value
+				}
+			}
+		case *errdetails.BadRequest:
+			var errs []httpsuite.ValidationErrorDetail
+			for _, violation := range detail.GetFieldViolations() {
+				errs = append(errs, httpsuite.ValidationErrorDetail{Field: violation.GetField(), Message: violation.GetDescription()})
+			}
+			extensions["errors"] = errs
+		case *errdetails.RetryInfo:
+			delay := detail.GetRetryDelay().AsDuration()
+			extensions["retry_after"] = int64((delay + time.Second - 1) / time.Second)
+		}
+	}
+
+	problem := httpsuite.NewProblemDetails(httpStatus, problemType, title, st.Message())
+	if problem.Detail == problem.Title {
+		problem.Detail = ""
+	}
+	problem.Instance = instance
+	if len(extensions) > 0 {
+		problem.Extensions = extensions
+	}
+	return problem
+}
+
+// FromError converts err into a problem. Problems in err's chain are
+// returned as they are, errors carrying a gRPC status are converted with
+// FromStatus, and anything else becomes a 500 problem. It returns nil for a
+// nil error.
+func FromError(err error) *httpsuite.ProblemDetails {
+	return FromErrorContext(context.Background(), err)
+}
+
+// FromErrorContext is FromError with the 500 problem typed by the problem
+// config scoped to ctx, such as a request context under UseProblemConfig.
+func FromErrorContext(ctx context.Context, err error) *httpsuite.ProblemDetails {
+	if err == nil {
+		return nil
+	}
+	if problem, ok := httpsuite.AsProblem(err); ok {
+		return problem
+	}
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		problems := httpsuite.ProblemConfigFromContext(ctx)
+		return httpsuite.NewProblemDetails(http.StatusInternalServerError, problems.TypeURL("server_error"), "", "").WithCause(err)
+	}
+	return FromStatus(st).WithCause(err)
+}
+
+// reason derives an UPPER_SNAKE_CASE ErrorInfo reason from the last segment
+// of a problem type URL, e.g. "VALIDATION_ERROR".
+func reason(problemType string) string {
+	if problemType == "" || problemType == httpsuite.BlankURL {
+		return "UNSPECIFIED"
+	}
+	segment := path.Base(strings.TrimSuffix(problemType, "/"))
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(segment))
+}
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rluders/httpsuite/v3"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCodeMapping(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status int
+		code   codes.Code
+		back   int
+	}{
+		{status: http.StatusBadRequest, code: codes.InvalidArgument, back: http.StatusBadRequest},
+		{status: http.StatusUnprocessableEntity, code: codes.InvalidArgument, back: http.StatusBadRequest},
+		{status: http.StatusUnauthorized, code: codes.Unauthenticated, back: http.StatusUnauthorized},
+		{status: http.StatusForbidden, code: codes.PermissionDenied, back: http.StatusForbidden},
+		{status: http.StatusNotFound, code: codes.NotFound, back: http.StatusNotFound},
+		{status: http.StatusConflict, code: codes.Aborted, back: http.StatusConflict},
+		{status: http.StatusTooManyRequests, code: codes.ResourceExhausted, back: http.StatusTooManyRequests},
+		{status: http.StatusNotImplemented, code: codes.Unimplemented, back: http.StatusNotImplemented},
+		{status: http.StatusServiceUnavailable, code: codes.Unavailable, back: http.StatusServiceUnavailable},
+		{status: http.StatusGatewayTimeout, code: codes.DeadlineExceeded, back: http.StatusGatewayTimeout},
+		{status: http.StatusTeapot, code: codes.InvalidArgument, back: http.StatusBadRequest},
+		{status: http.StatusInternalServerError, code: codes.Internal, back: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if got := Code(tt.status); got != tt.code {
+			t.Fatalf("Code(%d): expected %s, got %s", tt.status, tt.code, got)
+		}
+		if got := HTTPStatus(tt.code); got != tt.back {
+			t.Fatalf("HTTPStatus(%s): expected %d, got %d", tt.code, tt.back, got)
+		}
+	}
+}
+
+func TestToStatusRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	problem := httpsuite.Problem(http.StatusUnprocessableEntity).
+		Title("Validation Failed").
+		Type("https://example.com/problems/validation-error").
+		Detail("name is required").
+		Instance("/items/7").
+		Extension("errors", []httpsuite.ValidationErrorDetail{{Field: "name", Message: "name is required"}}).
+		Extension("trace_id", "abc").
+		RetryAfter(1500 * time.Millisecond).
+		Build()
+
+	st := ToStatus(problem)
+	if st.Code() != codes.InvalidArgument || st.Message() != "name is required" {
+		t.Fatalf("unexpected status: %v", st)
+	}
+	var info *errdetails.ErrorInfo
+	var retry *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			info = detail
+		case *errdetails.RetryInfo:
+			retry = detail
+		}
+	}
+	if info == nil || info.GetReason() != "VALIDATION_ERROR" || info.GetDomain() != "example.com" {
+		t.Fatalf("unexpected error info: %v", info)
+	}
+	if _, ok := info.GetMetadata()["retry_after"]; ok {
+		t.Fatalf("expected retry_after in RetryInfo only, got metadata %v", info.GetMetadata())
+	}
+	if retry == nil || retry.GetRetryDelay().AsDuration() != 2*time.Second {
+		t.Fatalf("unexpected retry info: %v", retry)
+	}
+
+	got := FromStatus(st)
+	if got.Status != http.StatusUnprocessableEntity || got.Type != problem.Type || got.Title != "Validation Failed" {
+		t.Fatalf("unexpected problem: %+v", got)
+	}
+	if got.Detail != "name is required" || got.Instance != "/items/7" || got.Extensions["trace_id"] != "abc" {
+		t.Fatalf("unexpected problem members: %+v", got)
+	}
+	if delay, ok := got.RetryAfter(); !ok || delay != 2*time.Second {
+		t.Fatalf("expected retry after 2s, got %v", delay)
+	}
+	errs, _ := got.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+	if len(errs) != 1 || errs[0].Field != "name" {
+		t.Fatalf("unexpected validation errors: %v", got.Extensions["errors"])
+	}
+}
+
+func TestFromStatusForeign(t *testing.T) {
+	t.Parallel()
+
+	got := FromStatus(status.New(codes.NotFound, "item 7 not found"))
+	if got.Status != http.StatusNotFound || got.Type != httpsuite.BlankURL || got.Title != "Not Found" || got.Detail != "item 7 not found" {
+		t.Fatalf("unexpected problem: %+v", got)
+	}
+	if FromStatus(status.New(codes.OK, "")) != nil {
+		t.Fatal("expected nil problem for OK status")
+	}
+}
+
+func TestFromError(t *testing.T) {
+	t.Parallel()
+
+	if FromError(nil) != nil {
+		t.Fatal("expected nil problem for nil error")
+	}
+	problem := httpsuite.NewNotFoundProblem("missing")
+	if FromError(problem) != problem {
+		t.Fatal("expected problems to pass through")
+	}
+
+	grpcErr := status.Error(codes.Unavailable, "backend down")
+	got := FromError(grpcErr)
+	if got.Status != http.StatusServiceUnavailable || !errors.Is(got, grpcErr) {
+		t.Fatalf("unexpected problem: %+v", got)
+	}
+
+	plain := FromError(errors.New("boom"))
+	if plain.Status != http.StatusInternalServerError || plain.Detail != "" {
+		t.Fatalf("unexpected problem: %+v", plain)
+	}
+
+	ctx := httpsuite.WithProblemConfig(context.Background(), httpsuite.ProblemConfig{BaseURL: "https://tenant.example.com"})
+	scoped := FromErrorContext(ctx, errors.New("boom"))
+	if scoped.Type != "https://tenant.example.com/errors/server-error" {
+		t.Fatalf("expected scoped type, got %q", scoped.Type)
+	}
+}
@@ -0,0 +1,127 @@
+package httpsuite
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// ErrInvalidULID is returned for malformed ULIDs. Its message is safe to show
+// to clients as a field error.
+var ErrInvalidULID = errors.New("must be a valid ULID")
+
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidDecoding maps Crockford base32 characters, in either case, to their
+// values; 0xff marks invalid characters.
+var ulidDecoding = func() [256]byte {
+	var table [256]byte
+	for i := range table {
+		table[i] = 0xff
+	}
+	for i := 0; i < len(ulidAlphabet); i++ {
+		table[ulidAlphabet[i]] = byte(i)
+		table[ulidAlphabet[i]|0x20] = byte(i)
+	}
+	return table
+}()
+
+// ULID is a lexicographically sortable identifier: a 48-bit millisecond
+// timestamp followed by 80 random bits, written as 26 Crockford base32
+// characters such as "01ARZ3NDEKTSV4RRFFQ69G5FAV". It binds from path
+// parameters, query parameters, and JSON bodies; parsing is case-insensitive.
+type ULID [16]byte
+
+// NewULID returns a ULID for the current time with random entropy.
+func NewULID() ULID {
+	var id ULID
+	millis := uint64(time.Now().UnixMilli())
+	var stamp [8]byte
+	binary.BigEndian.PutUint64(stamp[:], millis)
+	copy(id[:6], stamp[2:])
+	_, _ = rand.Read(id[6:])
+	return id
+}
+
+// ParseULID parses a 26-character ULID.
+func ParseULID(s string) (ULID, error) {
+	var id ULID
+	if len(s) != 26 || ulidDecoding[s[0]] > 7 {
+		return id, ErrInvalidULID
+	}
+	var values [26]byte
+	for i := 0; i < len(s); i++ {
+		values[i] = ulidDecoding[s[i]]
+		if values[i] == 0xff {
+			return id, ErrInvalidULID
+		}
+	}
+
+	// 26 characters carry 130 bits; the first character holds only 3.
+	var acc uint64
+	bits := 0
+	n := 0
+	for i, value := range values {
+		width := 5
+		if i == 0 {
+			width = 3
+		}
+		acc = acc<<width | uint64(value)
+		bits += width
+		for bits >= 8 {
+			bits -= 8
+			id[n] = byte(acc >> bits)
+			n++
+		}
+	}
+	return id, nil
+}
+
+// Time returns the timestamp encoded in the ULID.
+func (id ULID) Time() time.Time {
+	var stamp [8]byte
+	copy(stamp[2:], id[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(stamp[:]))).UTC()
+}
+
+// IsZero reports whether id is all zeros.
+func (id ULID) IsZero() bool {
+	return id == ULID{}
+}
+
+// String returns the canonical uppercase form.
+func (id ULID) String() string {
+	var buf [26]byte
+	var acc uint64
+	bits := 0
+	n := 25
+	for i := len(id) - 1; i >= 0; i-- {
+		acc |= uint64(id[i]) << bits
+		bits += 8
+		for bits >= 5 {
+			buf[n] = ulidAlphabet[acc&0x1f]
+			acc >>= 5
+			bits -= 5
+			n--
+		}
+	}
+	buf[0] = ulidAlphabet[acc&0x1f]
+	return string(buf[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ULID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so SetParam can bind a
+// path parameter with id.UnmarshalText([]byte(value)).
+func (id *ULID) UnmarshalText(text []byte) error {
+	parsed, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
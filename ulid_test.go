@@ -0,0 +1,79 @@
+package httpsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseULID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "01ARZ3NDEKTSV4RRFFQ69G5FAV", want: "01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{input: "01arz3ndektsv4rrffq69g5fav", want: "01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{input: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", want: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{input: "80000000000000000000000000", wantErr: true},
+		{input: "01ARZ3NDEKTSV4RRFFQ69G5FA", wantErr: true},
+		{input: "01ARZ3NDEKTSV4RRFFQ69G5FAU", wantErr: true},
+		{input: "01ARZ3NDEKTSV4RRFFQ69G5FA!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseULID(tt.input)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidULID) || err.Error() != "must be a valid ULID" {
+				t.Fatalf("ParseULID(%q): expected ErrInvalidULID, got %v", tt.input, err)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Fatalf("ParseULID(%q): expected %s, got %s %v", tt.input, tt.want, got, err)
+		}
+	}
+
+	id, _ := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if got := id.Time(); !got.Equal(time.UnixMilli(1469922850259)) {
+		t.Fatalf("expected the spec example timestamp, got %v", got)
+	}
+}
+
+func TestNewULIDSortsByTime(t *testing.T) {
+	t.Parallel()
+
+	first := NewULID()
+	time.Sleep(2 * time.Millisecond)
+	second := NewULID()
+	if first.String() >= second.String() {
+		t.Fatalf("expected %s to sort before %s", first, second)
+	}
+	if since := time.Since(first.Time()); since < 0 || since > time.Minute {
+		t.Fatalf("expected ULID time close to now, got %v", first.Time())
+	}
+	if parsed, err := ParseULID(first.String()); err != nil || parsed != first {
+		t.Fatalf("expected ULID to round-trip, got %s %v", parsed, err)
+	}
+}
+
+func TestULIDJSON(t *testing.T) {
+	t.Parallel()
+
+	var payload struct {
+		ID ULID `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(`{"id":"01ARZ3NDEKTSV4RRFFQ69G5FAV"}`), &payload); err != nil {
+		t.Fatalf("expected ULID to decode, got %v", err)
+	}
+	encoded, _ := json.Marshal(payload)
+	if string(encoded) != `{"id":"01ARZ3NDEKTSV4RRFFQ69G5FAV"}` {
+		t.Fatalf("expected string-encoded ULID, got %s", encoded)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"nope"}`), &payload); !errors.Is(err, ErrInvalidULID) {
+		t.Fatalf("expected ErrInvalidULID, got %v", err)
+	}
+}
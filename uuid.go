@@ -0,0 +1,83 @@
+package httpsuite
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrInvalidUUID is returned for malformed UUIDs. Its message is safe to show
+// to clients as a field error.
+var ErrInvalidUUID = errors.New("must be a valid UUID")
+
+// UUID is an RFC 9562 UUID that binds from path parameters, query parameters,
+// and JSON bodies in the canonical form
+// "9b2e1c4f-7a52-4d8e-9f0a-3c6d5e7f8a9b". Parsing is case-insensitive.
+type UUID [16]byte
+
+// NewUUID returns a random version 4 UUID.
+func NewUUID() UUID {
+	var id UUID
+	_, _ = rand.Read(id[:])
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return id
+}
+
+// ParseUUID parses a canonical UUID.
+func ParseUUID(s string) (UUID, error) {
+	var id UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return id, ErrInvalidUUID
+	}
+	compact := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(id[:], []byte(compact)); err != nil {
+		return UUID{}, ErrInvalidUUID
+	}
+	return id, nil
+}
+
+// Version returns the version number stored in the UUID.
+func (id UUID) Version() int {
+	return int(id[6] >> 4)
+}
+
+// IsZero reports whether id is the nil UUID.
+func (id UUID) IsZero() bool {
+	return id == UUID{}
+}
+
+// String returns the canonical lowercase form.
+func (id UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], id[10:])
+	return string(buf[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id UUID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so SetParam can bind a
+// path parameter with id.UnmarshalText([]byte(value)).
+func (id *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+func (UUID) openAPISchema() map[string]any {
+	return map[string]any{"type": "string", "format": "uuid"}
+}
@@ -0,0 +1,119 @@
+package httpsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseUUID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "9b2e1c4f-7a52-4d8e-9f0a-3c6d5e7f8a9b", want: "9b2e1c4f-7a52-4d8e-9f0a-3c6d5e7f8a9b"},
+		{input: "9B2E1C4F-7A52-4D8E-9F0A-3C6D5E7F8A9B", want: "9b2e1c4f-7a52-4d8e-9f0a-3c6d5e7f8a9b"},
+		{input: "00000000-0000-0000-0000-000000000000", want: "00000000-0000-0000-0000-000000000000"},
+		{input: "9b2e1c4f7a524d8e9f0a3c6d5e7f8a9b", wantErr: true},
+		{input: "9b2e1c4f-7a52-4d8e-9f0a-3c6d5e7f8a9", wantErr: true},
+		{input: "9b2e1c4f-7a52-4d8e-9f0a-3c6d5e7f8a9g", wantErr: true},
+		{input: "9b2e1c4f_7a52-4d8e-9f0a-3c6d5e7f8a9b", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseUUID(tt.input)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidUUID) || err.Error() != "must be a valid UUID" {
+				t.Fatalf("ParseUUID(%q): expected ErrInvalidUUID, got %v", tt.input, err)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Fatalf("ParseUUID(%q): expected %s, got %s %v", tt.input, tt.want, got, err)
+		}
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	t.Parallel()
+
+	a, b := NewUUID(), NewUUID()
+	if a == b || a.IsZero() {
+		t.Fatal("expected distinct random UUIDs")
+	}
+	if a.Version() != 4 || a[8]&0xc0 != 0x80 {
+		t.Fatalf("expected version 4 RFC 9562 variant, got %s", a)
+	}
+	if parsed, err := ParseUUID(a.String()); err != nil || parsed != a {
+		t.Fatalf("expected UUID to round-trip, got %s %v", parsed, err)
+	}
+}
+
+type uuidRequest struct {
+	ID      UUID  `json:"-"`
+	OwnerID UUID  `json:"owner_id"`
+	Parent  *UUID `json:"parent"`
+}
+
+func (r *uuidRequest) SetParam(fieldName, value string) error {
+	if fieldName != "id" {
+		return nil
+	}
+	return r.ID.UnmarshalText([]byte(value))
+}
+
+func TestParseRequestBindsUUIDs(t *testing.T) {
+	ClearValidator()
+	t.Cleanup(ClearValidator)
+
+	const id = "9b2e1c4f-7a52-4d8e-9f0a-3c6d5e7f8a9b"
+	tests := []struct {
+		name      string
+		path      string
+		body      string
+		wantCode  int
+		wantField string
+		wantError string
+	}{
+		{name: "valid", path: "/items/" + id, body: `{"owner_id":"` + id + `","parent":null}`, wantCode: http.StatusOK},
+		{name: "bad path", path: "/items/42", body: `{"owner_id":"` + id + `"}`, wantCode: http.StatusBadRequest, wantError: "must be a valid UUID"},
+		{name: "bad body", path: "/items/" + id, body: `{"owner_id":"abc"}`, wantCode: http.StatusBadRequest, wantField: "owner_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			parsed, err := ParseRequest[*uuidRequest](w, req, testParamExtractor, nil, "id")
+			if tt.wantCode == http.StatusOK {
+				if err != nil || parsed.ID.String() != id || parsed.OwnerID.String() != id || parsed.Parent != nil {
+					t.Fatalf("expected bound UUIDs, got %+v %v", parsed, err)
+				}
+				return
+			}
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			var problem struct {
+				Error  string                  `json:"error"`
+				Errors []ValidationErrorDetail `json:"errors"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if tt.wantError != "" && problem.Error != tt.wantError {
+				t.Fatalf("expected error %q, got %q", tt.wantError, problem.Error)
+			}
+			if tt.wantField != "" && (len(problem.Errors) != 1 || problem.Errors[0].Field != tt.wantField || problem.Errors[0].Message != "field owner_id is invalid: must be a valid UUID") {
+				t.Fatalf("expected UUID error on %q, got %#v", tt.wantField, problem.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package playground
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	ut "github.com/go-playground/universal-translator"
+	playgroundvalidator "github.com/go-playground/validator/v10"
+	"github.com/rluders/httpsuite/v3"
+)
+
+// Built-in tags registered on every Validator in addition to the go-playground
+// baked-in ones. API-oriented tags that go-playground already ships (uuid4,
+// ulid, semver, timezone, iso4217, iso3166_1_alpha2) only get messages.
+const (
+	TagSlug         = "slug"
+	TagRFC3339      = "rfc3339"
+	TagRFC3339Range = "rfc3339_range"
+	TagSafeFilename = "safe_filename"
+	TagMaxScale     = "max_scale"
+	TagBefore       = "before"
+	TagAfter        = "after"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+var builtinValidations = map[string]playgroundvalidator.Func{
+	TagSlug:         isSlug,
+	TagRFC3339:      isRFC3339,
+	TagRFC3339Range: isRFC3339Range,
+	TagSafeFilename: isSafeFilename,
+	TagMaxScale:     hasMaxScale,
+	TagBefore:       isBefore,
+	TagAfter:        isAfter,
+}
+
+// builtinTranslations holds messages for tags the go-playground translation
+// packages do not cover. {0} is the field name and {1} the tag parameter.
+var builtinTranslations = map[string]map[string]string{
+	"en": {
+		TagSlug:            "{0} must be a valid slug",
+		TagRFC3339:         "{0} must be an RFC 3339 timestamp",
+		TagRFC3339Range:    "{0} must be an RFC 3339 range whose start is not after its end",
+		TagSafeFilename:    "{0} must be a safe file name",
+		TagMaxScale:        "{0} must have at most {1} decimal places",
+		TagBefore:          "{0} must be before {1}",
+		TagAfter:           "{0} must be after {1}",
+		"semver":           "{0} must be a valid semantic version",
+		"timezone":         "{0} must be a valid time zone",
+		"iso4217":          "{0} must be a valid ISO 4217 currency code",
+		"iso3166_1_alpha2": "{0} must be a valid ISO 3166-1 alpha-2 country code",
+	},
+	"es": {
+		TagSlug:            "{0} debe ser un slug válido",
+		TagRFC3339:         "{0} debe ser una fecha RFC 3339",
+		TagRFC3339Range:    "{0} debe ser un rango RFC 3339 cuyo inicio no sea posterior a su fin",
+		TagSafeFilename:    "{0} debe ser un nombre de archivo seguro",
+		TagMaxScale:        "{0} debe tener como máximo {1} decimales",
+		TagBefore:          "{0} debe ser anterior a {1}",
+		TagAfter:           "{0} debe ser posterior a {1}",
+		"semver":           "{0} debe ser una versión semántica válida",
+		"timezone":         "{0} debe ser una zona horaria válida",
+		"iso4217":          "{0} debe ser un código de moneda ISO 4217 válido",
+		"iso3166_1_alpha2": "{0} debe ser un código de país ISO 3166-1 alfa-2 válido",
+	},
+	"fr": {
+		TagSlug:            "{0} doit être un slug valide",
+		TagRFC3339:         "{0} doit être un horodatage RFC 3339",
+		TagRFC3339Range:    "{0} doit être un intervalle RFC 3339 dont le début ne suit pas la fin",
+		TagSafeFilename:    "{0} doit être un nom de fichier sûr",
+		TagMaxScale:        "{0} doit avoir au plus {1} décimales",
+		TagBefore:          "{0} doit être antérieur à {1}",
+		TagAfter:           "{0} doit être postérieur à {1}",
+		"semver":           "{0} doit être une version sémantique valide",
+		"timezone":         "{0} doit être un fuseau horaire valide",
+		"iso4217":          "{0} doit être un code de devise ISO 4217 valide",
+		"iso3166_1_alpha2": "{0} doit être un code pays ISO 3166-1 alpha-2 valide",
+	},
+	"pt_BR": {
+		TagSlug:            "{0} deve ser um slug válido",
+		TagRFC3339:         "{0} deve ser uma data RFC 3339",
+		TagRFC3339Range:    "{0} deve ser um intervalo RFC 3339 cujo início não seja posterior ao fim",
+		TagSafeFilename:    "{0} deve ser um nome de arquivo seguro",
+		TagMaxScale:        "{0} deve ter no máximo {1} casas decimais",
+		TagBefore:          "{0} deve ser anterior a {1}",
+		TagAfter:           "{0} deve ser posterior a {1}",
+		"semver":           "{0} deve ser uma versão semântica válida",
+		"timezone":         "{0} deve ser um fuso horário válido",
+		"iso4217":          "{0} deve ser um código de moeda ISO 4217 válido",
+		"iso3166_1_alpha2": "{0} deve ser um código de país ISO 3166-1 alfa-2 válido",
+	},
+}
+
+func (v *Validator) registerBuiltins() {
+	for tag, fn := range builtinValidations {
+		_ = v.RegisterValidation(tag, fn)
+	}
+	_ = v.register(func(validate *playgroundvalidator.Validate) error {
+		validate.RegisterCustomTypeFunc(timeValue,
+			httpsuite.Timestamp{}, httpsuite.DateOnly{}, httpsuite.TimeOnly{}, httpsuite.UnixMillis{}, httpsuite.Duration(0))
+		return nil
+	})
+}
+
+// timeValue exposes the httpsuite time types to go-playground as time.Time
+// and time.Duration, so required, gt, lt, and the before and after tags
+// apply to them.
+func timeValue(field reflect.Value) any {
+	switch value := field.Interface().(type) {
+	case httpsuite.Timestamp:
+		return value.Time
+	case httpsuite.DateOnly:
+		return value.Time
+	case httpsuite.TimeOnly:
+		return value.Time
+	case httpsuite.UnixMillis:
+		return value.Time
+	case httpsuite.Duration:
+		return time.Duration(value)
+	}
+	return nil
+}
+
+// withBuiltinTranslations chains the built-in tag translations for locale after register.
+func withBuiltinTranslations(locale string, register TranslationRegistrar) TranslationRegistrar {
+	return func(validate *playgroundvalidator.Validate, translator ut.Translator) error {
+		if err := register(validate, translator); err != nil {
+			return err
+		}
+		for tag, text := range builtinTranslations[locale] {
+			err := validate.RegisterTranslation(tag, translator,
+				func(translator ut.Translator) error {
+					return translator.Add(tag, text, true)
+				},
+				func(translator ut.Translator, fieldErr playgroundvalidator.FieldError) string {
+					message, err := translator.T(fieldErr.Tag(), fieldErr.Field(), fieldErr.Param())
+					if err != nil {
+						return fieldErr.Error()
+					}
+					return message
+				},
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func isSlug(fl playgroundvalidator.FieldLevel) bool {
+	value, ok := stringField(fl)
+	return ok && slugPattern.MatchString(value)
+}
+
+func isRFC3339(fl playgroundvalidator.FieldLevel) bool {
+	value, ok := stringField(fl)
+	if !ok {
+		return false
+	}
+	_, err := time.Parse(time.RFC3339, value)
+	return err == nil
+}
+
+// isRFC3339Range accepts "start/end" intervals where start is not after end.
+func isRFC3339Range(fl playgroundvalidator.FieldLevel) bool {
+	value, ok := stringField(fl)
+	if !ok {
+		return false
+	}
+	startText, endText, ok := strings.Cut(value, "/")
+	if !ok {
+		return false
+	}
+	start, err := time.Parse(time.RFC3339, startText)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(time.RFC3339, endText)
+	if err != nil {
+		return false
+	}
+	return !end.Before(start)
+}
+
+var reservedFilenames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// isSafeFilename rejects names that could escape a directory or that
+// Windows cannot store: path separators, "." and "..", control and reserved
+// characters, trailing dots or spaces, and device names such as CON.
+func isSafeFilename(fl playgroundvalidator.FieldLevel) bool {
+	value, ok := stringField(fl)
+	if !ok || value == "" || value == "." || value == ".." || len(value) > 255 {
+		return false
+	}
+	if strings.ContainsAny(value, `/\:*?"<>|`) || strings.HasSuffix(value, ".") || strings.HasSuffix(value, " ") {
+		return false
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	base, _, _ := strings.Cut(value, ".")
+	return !reservedFilenames[strings.ToUpper(base)]
+}
+
+// hasMaxScale checks httpsuite.Decimal and httpsuite.Money amounts, or
+// decimal strings, for at most max_scale significant decimal places.
+func hasMaxScale(fl playgroundvalidator.FieldLevel) bool {
+	limit, err := strconv.Atoi(fl.Param())
+	if err != nil || limit < 0 {
+		return false
+	}
+	var amount httpsuite.Decimal
+	switch value := fl.Field().Interface().(type) {
+	case httpsuite.Decimal:
+		amount = value
+	case httpsuite.Money:
+		amount = value.Amount
+	case string:
+		parsed, err := httpsuite.ParseDecimal(value)
+		if err != nil {
+			return false
+		}
+		amount = parsed
+	default:
+		return false
+	}
+	return amount.Equal(amount.Round(limit))
+}
+
+// isBefore and isAfter compare time fields with their parameter: "now",
+// optionally offset as in "now+24h" or "now-7d", an RFC 3339 timestamp, a
+// "2006-01-02" date, or a "15:04" time of day. httpsuite.TimeOnly fields
+// compare with the time of day, using the clock in the response timezone for
+// "now".
+func isBefore(fl playgroundvalidator.FieldLevel) bool {
+	value, reference, ok := timeComparison(fl)
+	return ok && value.Before(reference)
+}
+
+func isAfter(fl playgroundvalidator.FieldLevel) bool {
+	value, reference, ok := timeComparison(fl)
+	return ok && value.After(reference)
+}
+
+func timeComparison(fl playgroundvalidator.FieldLevel) (time.Time, time.Time, bool) {
+	value, ok := fl.Field().Interface().(time.Time)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	clockOnly := value.Year() == 0 && value.YearDay() == 1
+	param := fl.Param()
+
+	if offset, ok := strings.CutPrefix(param, "now"); ok {
+		reference := time.Now().In(httpsuite.ResponseTimezone())
+		if clockOnly {
+			reference = httpsuite.TimeOf(reference).Time
+		}
+		if offset == "" {
+			return value, reference, true
+		}
+		shift, ok := parseTimeOffset(offset)
+		if !ok {
+			return time.Time{}, time.Time{}, false
+		}
+		return value, reference.Add(shift), true
+	}
+	if clockOnly {
+		reference, err := httpsuite.ParseTimeOnly(param)
+		return value, reference.Time, err == nil
+	}
+	if reference, err := time.Parse(time.RFC3339Nano, param); err == nil {
+		return value, reference, true
+	}
+	reference, err := httpsuite.ParseDateOnly(param)
+	return value, reference.Time, err == nil
+}
+
+// parseTimeOffset parses "+24h", "-90m", or "+7d".
+func parseTimeOffset(offset string) (time.Duration, bool) {
+	if offset == "" || (offset[0] != '+' && offset[0] != '-') {
+		return 0, false
+	}
+	if days, ok := strings.CutSuffix(offset, "d"); ok {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err == nil
+	}
+	shift, err := time.ParseDuration(offset)
+	return shift, err == nil
+}
+
+func stringField(fl playgroundvalidator.FieldLevel) (string, bool) {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return "", false
+	}
+	return field.String(), true
+}
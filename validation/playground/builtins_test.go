@@ -0,0 +1,212 @@
+package playground
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+func TestBuiltinValidations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag   string
+		valid []string
+		bad   []string
+	}{
+		{tag: "slug", valid: []string{"hello-world", "v2"}, bad: []string{"Hello", "a--b", "-a", "a_b"}},
+		{tag: "rfc3339", valid: []string{"2024-01-02T15:04:05Z", "2024-01-02T15:04:05+02:00"}, bad: []string{"2024-01-02", "yesterday"}},
+		{
+			tag:   "rfc3339_range",
+			valid: []string{"2024-01-01T00:00:00Z/2024-02-01T00:00:00Z"},
+			bad:   []string{"2024-02-01T00:00:00Z/2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z"},
+		},
+		{tag: "safe_filename", valid: []string{"report.pdf", "photo 1.jpg"}, bad: []string{"../etc/passwd", "a/b", "CON.txt", "name.", "..", "a\x00b"}},
+		{tag: "uuid4", valid: []string{"9b2e1c4f-7a52-4d8e-9f0a-3c6d5e7f8a9b"}, bad: []string{"not-a-uuid"}},
+		{tag: "ulid", valid: []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV"}, bad: []string{"01ARZ3NDEKTSV4RRFFQ69G5FA"}},
+		{tag: "semver", valid: []string{"1.2.3", "1.0.0-rc.1"}, bad: []string{"1.2"}},
+		{tag: "timezone", valid: []string{"Europe/Berlin"}, bad: []string{"Mars/Olympus"}},
+		{tag: "iso4217", valid: []string{"EUR"}, bad: []string{"EURO"}},
+		{tag: "iso3166_1_alpha2", valid: []string{"DE"}, bad: []string{"XX"}},
+	}
+
+	validator := New()
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			for _, value := range tt.valid {
+				if err := validator.Engine().Var(value, tt.tag); err != nil {
+					t.Fatalf("expected %q to pass %s, got %v", value, tt.tag, err)
+				}
+			}
+			for _, value := range tt.bad {
+				if err := validator.Engine().Var(value, tt.tag); err == nil {
+					t.Fatalf("expected %q to fail %s", value, tt.tag)
+				}
+			}
+		})
+	}
+}
+
+func TestBuiltinMessages(t *testing.T) {
+	t.Parallel()
+
+	type upload struct {
+		Filename string `json:"filename" validate:"safe_filename"`
+	}
+
+	validator := New()
+	if err := validator.EnableTranslations(); err != nil {
+		t.Fatalf("enable translations: %v", err)
+	}
+
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{acceptLanguage: "", want: "filename must be a safe file name"},
+		{acceptLanguage: "en", want: "filename must be a safe file name"},
+		{acceptLanguage: "fr", want: "filename doit être un nom de fichier sûr"},
+		{acceptLanguage: "pt-BR", want: "filename deve ser um nome de arquivo seguro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			problem := validator.ValidateLocalized(context.Background(), upload{Filename: "../secret"}, tt.acceptLanguage)
+			if problem == nil {
+				t.Fatal("expected validation problem, got nil")
+			}
+			details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+			if details[0].Message != tt.want {
+				t.Fatalf("expected message %q, got %q", tt.want, details[0].Message)
+			}
+		})
+	}
+}
+
+func TestMaxScaleValidation(t *testing.T) {
+	t.Parallel()
+
+	type payment struct {
+		Rate   httpsuite.Decimal  `json:"rate" validate:"max_scale=4"`
+		Tip    *httpsuite.Decimal `json:"tip" validate:"omitempty,max_scale=2"`
+		Price  httpsuite.Money    `json:"price" validate:"max_scale=2"`
+		Amount string             `json:"amount" validate:"max_scale=0"`
+	}
+	valid := func() payment {
+		return payment{
+			Rate:   httpsuite.MustParseDecimal("0.0825"),
+			Price:  httpsuite.Money{Amount: httpsuite.MustParseDecimal("9.90"), Currency: "USD"},
+			Amount: "100",
+		}
+	}
+	tip := httpsuite.MustParseDecimal("1.005")
+
+	tests := []struct {
+		name      string
+		mutate    func(*payment)
+		wantField string
+	}{
+		{name: "valid", mutate: func(*payment) {}},
+		{name: "trailing zeros", mutate: func(p *payment) { p.Rate = httpsuite.MustParseDecimal("0.082500") }},
+		{name: "decimal", mutate: func(p *payment) { p.Rate = httpsuite.MustParseDecimal("0.08251") }, wantField: "rate"},
+		{name: "pointer", mutate: func(p *payment) { p.Tip = &tip }, wantField: "tip"},
+		{name: "money", mutate: func(p *payment) { p.Price.Amount = httpsuite.MustParseDecimal("9.999") }, wantField: "price"},
+		{name: "string", mutate: func(p *payment) { p.Amount = "100.5" }, wantField: "amount"},
+		{name: "invalid string", mutate: func(p *payment) { p.Amount = "ten" }, wantField: "amount"},
+	}
+
+	validator := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid()
+			tt.mutate(&request)
+			problem := validator.Validate(request)
+			if tt.wantField == "" {
+				if problem != nil {
+					t.Fatalf("expected no problem, got %+v", problem.Extensions)
+				}
+				return
+			}
+			if problem == nil {
+				t.Fatal("expected validation problem, got nil")
+			}
+			details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+			if len(details) != 1 || details[0].Field != tt.wantField {
+				t.Fatalf("expected one error on %q, got %#v", tt.wantField, details)
+			}
+		})
+	}
+
+	if err := validator.EnableTranslations(); err != nil {
+		t.Fatalf("enable translations: %v", err)
+	}
+	request := valid()
+	request.Rate = httpsuite.MustParseDecimal("0.00001")
+	problem := validator.ValidateLocalized(context.Background(), request, "es")
+	if problem == nil {
+		t.Fatal("expected validation problem, got nil")
+	}
+	details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+	if details[0].Message != "rate debe tener como máximo 4 decimales" {
+		t.Fatalf("expected translated message with the limit, got %q", details[0].Message)
+	}
+}
+
+func TestTimeValidation(t *testing.T) {
+	t.Parallel()
+
+	type booking struct {
+		Day      httpsuite.DateOnly   `json:"day" validate:"required,after=now-1d,before=now+30d"`
+		Opens    httpsuite.TimeOnly   `json:"opens" validate:"after=08:00,before=18:00"`
+		Created  httpsuite.UnixMillis `json:"created" validate:"after=2020-01-01"`
+		Expires  *httpsuite.Timestamp `json:"expires" validate:"omitempty,after=now"`
+		Duration httpsuite.Duration   `json:"duration" validate:"gte=15m,lte=4h"`
+	}
+	valid := func() booking {
+		return booking{
+			Day:      httpsuite.DateOf(time.Now().AddDate(0, 0, 2)),
+			Opens:    httpsuite.NewTimeOnly(9, 0, 0),
+			Created:  httpsuite.UnixMillis{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			Duration: httpsuite.Duration(time.Hour),
+		}
+	}
+	past := httpsuite.Timestamp{Time: time.Now().Add(-time.Hour)}
+
+	tests := []struct {
+		name      string
+		mutate    func(*booking)
+		wantField string
+	}{
+		{name: "valid", mutate: func(*booking) {}},
+		{name: "missing day", mutate: func(b *booking) { b.Day = httpsuite.DateOnly{} }, wantField: "day"},
+		{name: "day too far", mutate: func(b *booking) { b.Day = b.Day.AddDays(60) }, wantField: "day"},
+		{name: "opens too early", mutate: func(b *booking) { b.Opens = httpsuite.NewTimeOnly(7, 59, 0) }, wantField: "opens"},
+		{name: "created too old", mutate: func(b *booking) { b.Created.Time = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC) }, wantField: "created"},
+		{name: "expired", mutate: func(b *booking) { b.Expires = &past }, wantField: "expires"},
+		{name: "duration too short", mutate: func(b *booking) { b.Duration = httpsuite.Duration(time.Minute) }, wantField: "duration"},
+	}
+
+	validator := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid()
+			tt.mutate(&request)
+			problem := validator.Validate(request)
+			if tt.wantField == "" {
+				if problem != nil {
+					t.Fatalf("expected no problem, got %+v", problem.Extensions)
+				}
+				return
+			}
+			if problem == nil {
+				t.Fatal("expected validation problem, got nil")
+			}
+			details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+			if len(details) != 1 || details[0].Field != tt.wantField {
+				t.Fatalf("expected one error on %q, got %#v", tt.wantField, details)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package playground
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	playgroundvalidator "github.com/go-playground/validator/v10"
+	"github.com/rluders/httpsuite/v3"
+)
+
+const fallbackMessage = "{field} failed {tag} validation"
+
+var (
+	messagesMu sync.RWMutex
+	messages   = map[string]string{
+		"required": "{field} is required",
+		"min":      "{field} must be at least {param}",
+		"max":      "{field} must be at most {param}",
+		"len":      "{field} must have length {param}",
+		"gt":       "{field} must be greater than {param}",
+		"gte":      "{field} must be greater than or equal to {param}",
+		"lt":       "{field} must be less than {param}",
+		"lte":      "{field} must be less than or equal to {param}",
+		"eq":       "{field} must be equal to {param}",
+		"ne":       "{field} must not be equal to {param}",
+		"oneof":    "{field} must be one of [{param}]",
+		"email":    "{field} must be a valid email address",
+		"url":      "{field} must be a valid URL",
+		"uuid":     "{field} must be a valid UUID",
+
+		"uuid4":            "{field} must be a valid version 4 UUID",
+		"ulid":             "{field} must be a valid ULID",
+		"semver":           "{field} must be a valid semantic version",
+		"timezone":         "{field} must be a valid time zone",
+		"iso4217":          "{field} must be a valid ISO 4217 currency code",
+		"iso3166_1_alpha2": "{field} must be a valid ISO 3166-1 alpha-2 country code",
+		TagSlug:            "{field} must be a valid slug",
+		TagRFC3339:         "{field} must be an RFC 3339 timestamp",
+		TagRFC3339Range:    "{field} must be an RFC 3339 range whose start is not after its end",
+		TagSafeFilename:    "{field} must be a safe file name",
+		TagMaxScale:        "{field} must have at most {param} decimal places",
+		TagBefore:          "{field} must be before {param}",
+		TagAfter:           "{field} must be after {param}",
+
+		"required_if":      "{field} is required when {param}",
+		"required_unless":  "{field} is required unless {param}",
+		"required_with":    "{field} is required when {param} is present",
+		"required_without": "{field} is required when {param} is missing",
+		"excluded_with":    "{field} must be empty when {param} is present",
+		"eqfield":          "{field} must match {param}",
+		"nefield":          "{field} must differ from {param}",
+		"gtfield":          "{field} must be greater than {param}",
+		"gtefield":         "{field} must be greater than or equal to {param}",
+		"ltfield":          "{field} must be less than {param}",
+		"ltefield":         "{field} must be less than or equal to {param}",
+	}
+
+	// timeMessages word the field comparison tags for dates and times. They
+	// apply until a tag's message is overridden.
+	timeMessages = map[string]string{
+		"gtfield":  "{field} must be after {param}",
+		"gtefield": "{field} must not be before {param}",
+		"ltfield":  "{field} must be before {param}",
+		"ltefield": "{field} must not be after {param}",
+	}
+)
+
+// SetValidationMessage registers the package-wide message template for a tag.
+// Templates may reference {field}, {path}, {tag}, {param}, and {value}.
+func SetValidationMessage(tag, template string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	messages[tag] = template
+	delete(timeMessages, tag)
+}
+
+// SetMessage registers a message template for a tag on this validator only.
+func (v *Validator) SetMessage(tag, template string) *Validator {
+	v.messagesMu.Lock()
+	defer v.messagesMu.Unlock()
+	if v.messages == nil {
+		v.messages = make(map[string]string)
+	}
+	v.messages[tag] = template
+	return v
+}
+
+func (v *Validator) message(fieldErr playgroundvalidator.FieldError) string {
+	return renderMessage(v.template(fieldErr.Tag(), isTimeValue(fieldErr.Value())), fieldErr)
+}
+
+func (v *Validator) template(tag string, timeValued bool) string {
+	v.messagesMu.RLock()
+	template, ok := v.messages[tag]
+	v.messagesMu.RUnlock()
+	if ok {
+		return template
+	}
+
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+	if template, ok := timeMessages[tag]; ok && timeValued {
+		return template
+	}
+	if template, ok := messages[tag]; ok {
+		return template
+	}
+	return fallbackMessage
+}
+
+func renderMessage(template string, fieldErr playgroundvalidator.FieldError) string {
+	return strings.NewReplacer(
+		"{field}", fieldErr.Field(),
+		"{path}", fieldPath(fieldErr),
+		"{tag}", fieldErr.Tag(),
+		"{param}", fieldErr.Param(),
+		"{value}", fmt.Sprint(fieldErr.Value()),
+	).Replace(template)
+}
+
+// isTimeValue reports whether a failing value is a point in time, including
+// the httpsuite time types.
+func isTimeValue(value any) bool {
+	switch value.(type) {
+	case time.Time, *time.Time, httpsuite.Timestamp, *httpsuite.Timestamp, httpsuite.DateOnly, *httpsuite.DateOnly,
+		httpsuite.TimeOnly, *httpsuite.TimeOnly, httpsuite.UnixMillis, *httpsuite.UnixMillis:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package playground
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+func TestValidationMessages(t *testing.T) {
+	t.Parallel()
+
+	type signup struct {
+		Name string `json:"name" validate:"required"`
+		Age  int    `json:"age" validate:"min=18"`
+		Code string `json:"code" validate:"hexadecimal"`
+	}
+
+	validator := New().SetMessage("hexadecimal", "{field} must be hex, got {value}")
+	problem := validator.Validate(signup{Age: 17, Code: "zz"})
+	if problem == nil {
+		t.Fatal("expected validation problem, got nil")
+	}
+
+	details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+	want := []string{
+		"name is required",
+		"age must be at least 18",
+		"code must be hex, got zz",
+	}
+	if len(details) != len(want) {
+		t.Fatalf("expected %d details, got %#v", len(want), details)
+	}
+	for i, message := range want {
+		if details[i].Message != message {
+			t.Fatalf("expected message %q, got %q", message, details[i].Message)
+		}
+	}
+}
+
+func TestValidationMessageFallback(t *testing.T) {
+	t.Parallel()
+
+	type payload struct {
+		Color string `json:"color" validate:"hexcolor"`
+	}
+
+	problem := New().Validate(payload{Color: "blue"})
+	details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+	if details[0].Message != "color failed hexcolor validation" {
+		t.Fatalf("unexpected fallback message %q", details[0].Message)
+	}
+}
+
+func TestFieldComparisonMessages(t *testing.T) {
+	t.Parallel()
+
+	type booking struct {
+		Start     time.Time           `json:"start"`
+		End       time.Time           `json:"end" validate:"gtfield=Start"`
+		Opens     httpsuite.Timestamp `json:"opens"`
+		Closes    httpsuite.Timestamp `json:"closes" validate:"ltefield=Opens"`
+		MinGuests int                 `json:"min_guests"`
+		MaxGuests int                 `json:"max_guests" validate:"gtfield=MinGuests"`
+	}
+
+	now := time.Now()
+	problem := New().Validate(booking{
+		Start:     now,
+		End:       now.Add(-time.Hour),
+		Opens:     httpsuite.Timestamp{Time: now},
+		Closes:    httpsuite.Timestamp{Time: now.Add(time.Hour)},
+		MinGuests: 4,
+		MaxGuests: 2,
+	})
+	if problem == nil {
+		t.Fatal("expected validation problem, got nil")
+	}
+
+	details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+	want := []string{
+		"end must be after Start",
+		"closes must not be after Opens",
+		"max_guests must be greater than MinGuests",
+	}
+	if len(details) != len(want) {
+		t.Fatalf("expected %d details, got %#v", len(want), details)
+	}
+	for i, message := range want {
+		if details[i].Message != message {
+			t.Fatalf("expected message %q, got %q", message, details[i].Message)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package playground
+
+import playgroundvalidator "github.com/go-playground/validator/v10"
+
+// RegisterValidation adds a custom validation tag, e.g. `validate:"iban"`.
+func (v *Validator) RegisterValidation(tag string, fn playgroundvalidator.Func, callValidationEvenIfNull ...bool) error {
+	return v.register(func(validate *playgroundvalidator.Validate) error {
+		return validate.RegisterValidation(tag, fn, callValidationEvenIfNull...)
+	})
+}
+
+// RegisterValidationCtx adds a custom validation tag whose function receives
+// the request context passed to ValidateContext.
+func (v *Validator) RegisterValidationCtx(tag string, fn playgroundvalidator.FuncCtx, callValidationEvenIfNull ...bool) error {
+	return v.register(func(validate *playgroundvalidator.Validate) error {
+		return validate.RegisterValidationCtx(tag, fn, callValidationEvenIfNull...)
+	})
+}
+
+// RegisterStructValidation adds a struct-level validation for the given types.
+// Struct-level validations run once per request, regardless of scenario.
+func (v *Validator) RegisterStructValidation(fn playgroundvalidator.StructLevelFunc, types ...any) {
+	v.validate.RegisterStructValidation(fn, types...)
+}
+
+// RegisterAlias maps an alias to a set of tags, e.g. RegisterAlias("username", "required,min=3,max=32").
+func (v *Validator) RegisterAlias(alias, tags string) {
+	_ = v.register(func(validate *playgroundvalidator.Validate) error {
+		validate.RegisterAlias(alias, tags)
+		return nil
+	})
+}
+
+// Engine returns the underlying go-playground validator for advanced configuration.
+// Registrations made directly on the engine do not apply to scenario validators.
+func (v *Validator) Engine() *playgroundvalidator.Validate {
+	return v.validate
+}
@@ -0,0 +1,104 @@
+package playground
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	playgroundvalidator "github.com/go-playground/validator/v10"
+	"github.com/rluders/httpsuite/v3"
+)
+
+func TestRegisterCustomValidations(t *testing.T) {
+	t.Parallel()
+
+	type transfer struct {
+		IBAN     string `json:"iban" validate:"iban"`
+		Username string `json:"username" validate:"username"`
+		From     string `json:"from"`
+		To       string `json:"to"`
+	}
+
+	validator := New()
+	if err := validator.RegisterValidation("iban", func(fl playgroundvalidator.FieldLevel) bool {
+		return strings.HasPrefix(fl.Field().String(), "DE")
+	}); err != nil {
+		t.Fatalf("register validation: %v", err)
+	}
+	validator.RegisterAlias("username", "required,min=3")
+	validator.RegisterStructValidation(func(sl playgroundvalidator.StructLevel) {
+		value := sl.Current().Interface().(transfer)
+		if value.From == value.To {
+			sl.ReportError(value.To, "to", "To", "nefield", "from")
+		}
+	}, transfer{})
+	validator.SetMessage("iban", "{field} must be a valid IBAN")
+
+	problem := validator.Validate(transfer{IBAN: "FR00", Username: "ab", From: "a", To: "a"})
+	if problem == nil {
+		t.Fatal("expected validation problem, got nil")
+	}
+	details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+	got := make([]string, len(details))
+	for i, detail := range details {
+		got[i] = detail.Field + ":" + detail.Message
+	}
+	want := "iban:iban must be a valid IBAN|username:username failed username validation|to:to must differ from from"
+	if strings.Join(got, "|") != want {
+		t.Fatalf("unexpected details %q", strings.Join(got, "|"))
+	}
+}
+
+func TestNewWithValidatorIsolatesInstances(t *testing.T) {
+	t.Parallel()
+
+	engine := playgroundvalidator.New()
+	strict := NewWithValidator(engine, nil)
+	if strict.Engine() != engine {
+		t.Fatal("expected injected engine to be used")
+	}
+	if err := strict.RegisterValidation("even", func(fl playgroundvalidator.FieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}); err != nil {
+		t.Fatalf("register validation: %v", err)
+	}
+
+	type payload struct {
+		Count int `json:"count" validate:"omitempty,even"`
+	}
+	if strict.Validate(payload{Count: 3}) == nil {
+		t.Fatal("expected strict validator to apply custom rule")
+	}
+	if New().Engine() == engine {
+		t.Fatal("expected New to create an isolated engine")
+	}
+}
+
+type tenantContextKey struct{}
+
+func TestRegisterValidationCtx(t *testing.T) {
+	t.Parallel()
+
+	validator := New()
+	if err := validator.RegisterValidationCtx("tenant_plan", func(ctx context.Context, fl playgroundvalidator.FieldLevel) bool {
+		allowed, _ := ctx.Value(tenantContextKey{}).(string)
+		return fl.Field().String() == allowed
+	}); err != nil {
+		t.Fatalf("register validation: %v", err)
+	}
+
+	type upgrade struct {
+		Plan string `json:"plan" validate:"tenant_plan"`
+	}
+
+	ctx := context.WithValue(context.Background(), tenantContextKey{}, "pro")
+	if problem := validator.ValidateContext(ctx, upgrade{Plan: "pro"}); problem != nil {
+		t.Fatalf("expected plan allowed for tenant, got %#v", problem)
+	}
+	if validator.ValidateContext(ctx, upgrade{Plan: "enterprise"}) == nil {
+		t.Fatal("expected plan rejected for tenant")
+	}
+	if validator.Scenario("update").ValidateContext(ctx, upgrade{Plan: "enterprise"}) == nil {
+		t.Fatal("expected scenario validator to use the request context")
+	}
+}
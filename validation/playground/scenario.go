@@ -0,0 +1,188 @@
+package playground
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	playgroundvalidator "github.com/go-playground/validator/v10"
+	"github.com/rluders/httpsuite/v3"
+)
+
+// ScenarioValidator validates requests for a named operation such as "create"
+// or "update". Fields tagged `validate_<scenario>` use that rule set instead of
+// their `validate` tag; other fields keep their `validate` rules.
+type ScenarioValidator struct {
+	parent   *Validator
+	name     string
+	tag      string
+	validate *playgroundvalidator.Validate
+}
+
+// Scenario returns a validator for the named scenario, suitable for
+// httpsuite.ParseOptions.Validator.
+func (v *Validator) Scenario(name string) *ScenarioValidator {
+	return &ScenarioValidator{
+		parent:   v,
+		name:     name,
+		tag:      "validate_" + name,
+		validate: v.scenarioEngine(name),
+	}
+}
+
+// Name returns the scenario name.
+func (s *ScenarioValidator) Name() string {
+	return s.name
+}
+
+// Validate validates the request for the scenario.
+func (s *ScenarioValidator) Validate(request any) *httpsuite.ProblemDetails {
+	return s.ValidateContext(context.Background(), request)
+}
+
+// ValidateContext validates the request for the scenario with the request context.
+func (s *ScenarioValidator) ValidateContext(ctx context.Context, request any) *httpsuite.ProblemDetails {
+	if err := s.validateStruct(ctx, request); err != nil {
+		return s.parent.problemDetails(ctx, err)
+	}
+	return nil
+}
+
+// ValidateLocalized validates the request for the scenario with localized messages.
+func (s *ScenarioValidator) ValidateLocalized(ctx context.Context, request any, acceptLanguage string) *httpsuite.ProblemDetails {
+	if err := s.validateStruct(ctx, request); err != nil {
+		return s.parent.problemDetailsFor(ctx, err, s.parent.translator(acceptLanguage))
+	}
+	return nil
+}
+
+func (s *ScenarioValidator) validateStruct(ctx context.Context, request any) error {
+	var targets []scenarioTarget
+	collectScenarioTargets(reflect.ValueOf(request), s.tag, "", "", &targets)
+
+	var overridden []string
+	for _, target := range targets {
+		for _, field := range target.fields {
+			overridden = append(overridden, target.prefix+field)
+		}
+	}
+
+	var validationErrors playgroundvalidator.ValidationErrors
+	if err := s.parent.validate.StructExceptCtx(ctx, request, overridden...); err != nil {
+		if !errors.As(err, &validationErrors) {
+			return err
+		}
+	}
+	for _, target := range targets {
+		err := s.validate.StructPartialCtx(ctx, target.owner.Interface(), target.fields...)
+		var scenarioErrors playgroundvalidator.ValidationErrors
+		if err != nil && !errors.As(err, &scenarioErrors) {
+			return err
+		}
+		for _, fieldErr := range scenarioErrors {
+			validationErrors = append(validationErrors, scenarioFieldError{FieldError: fieldErr, jsonPath: target.jsonPath})
+		}
+	}
+	if len(validationErrors) == 0 {
+		return nil
+	}
+	return validationErrors
+}
+
+// scenarioEngine returns the engine reading `validate_<name>` tags, replaying
+// every registration made through the Validator.
+func (v *Validator) scenarioEngine(name string) *playgroundvalidator.Validate {
+	v.scenariosMu.Lock()
+	defer v.scenariosMu.Unlock()
+
+	if engine, ok := v.scenarios[name]; ok {
+		return engine
+	}
+	engine := playgroundvalidator.New()
+	engine.SetTagName("validate_" + name)
+	registerJSONTagNames(engine)
+	for _, registration := range v.registrations {
+		_ = registration(engine)
+	}
+	if v.scenarios == nil {
+		v.scenarios = make(map[string]*playgroundvalidator.Validate)
+	}
+	v.scenarios[name] = engine
+	return engine
+}
+
+// register applies a registration to the base engine and every scenario engine.
+func (v *Validator) register(registration func(*playgroundvalidator.Validate) error) error {
+	v.scenariosMu.Lock()
+	defer v.scenariosMu.Unlock()
+
+	if err := registration(v.validate); err != nil {
+		return err
+	}
+	for _, engine := range v.scenarios {
+		if err := registration(engine); err != nil {
+			return err
+		}
+	}
+	v.registrations = append(v.registrations, registration)
+	return nil
+}
+
+// scenarioTarget is a struct value whose direct fields carry scenario tags.
+type scenarioTarget struct {
+	owner    reflect.Value
+	prefix   string
+	jsonPath string
+	fields   []string
+}
+
+// scenarioFieldError reports a failure found on a nested owner struct with its
+// namespace rebased onto the request.
+type scenarioFieldError struct {
+	playgroundvalidator.FieldError
+	jsonPath string
+}
+
+func (e scenarioFieldError) Namespace() string {
+	_, rest, _ := strings.Cut(e.FieldError.Namespace(), ".")
+	return "request." + e.jsonPath + rest
+}
+
+// collectScenarioTargets finds every struct reachable from value that declares
+// scenario-tagged fields, recording their namespaced Go paths.
+func collectScenarioTargets(value reflect.Value, tag, prefix, jsonPath string, targets *[]scenarioTarget) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		target := scenarioTarget{owner: value, prefix: prefix, jsonPath: jsonPath}
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if _, ok := field.Tag.Lookup(tag); ok {
+				target.fields = append(target.fields, field.Name)
+				continue
+			}
+			collectScenarioTargets(value.Field(i), tag, prefix+field.Name+".", jsonPath+fieldName(field)+".", targets)
+		}
+		if len(target.fields) > 0 {
+			*targets = append(*targets, target)
+		}
+	case reflect.Slice, reflect.Array:
+		base := strings.TrimSuffix(prefix, ".")
+		jsonBase := strings.TrimSuffix(jsonPath, ".")
+		for i := 0; i < value.Len(); i++ {
+			index := "[" + strconv.Itoa(i) + "]."
+			collectScenarioTargets(value.Index(i), tag, base+index, jsonBase+index, targets)
+		}
+	}
+}
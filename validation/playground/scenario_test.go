@@ -0,0 +1,51 @@
+package playground
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+func TestScenarioValidator(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		SKU      string `json:"sku" validate:"required"`
+		Quantity int    `json:"quantity" validate:"required,min=1" validate_update:"omitempty,min=1"`
+	}
+	type order struct {
+		Name  string `json:"name" validate:"required" validate_update:"omitempty,min=2"`
+		Email string `json:"email" validate:"required,email"`
+		Items []item `json:"items" validate:"dive"`
+	}
+
+	validator := New()
+
+	create := validator.Validate(order{Email: "ada@example.com", Items: []item{{SKU: "a"}}})
+	if got := problemFields(create); got != "name,items[0].quantity" {
+		t.Fatalf("expected create rules to require name and quantity, got %q", got)
+	}
+
+	update := validator.Scenario("update")
+	if problem := update.Validate(order{Email: "ada@example.com", Items: []item{{SKU: "a"}}}); problem != nil {
+		t.Fatalf("expected partial update to pass, got %#v", problem.Extensions["errors"])
+	}
+
+	problem := update.Validate(order{Name: "A", Items: []item{{SKU: "a", Quantity: -1}}})
+	if got := problemFields(problem); got != "email,items[0].quantity,name" {
+		t.Fatalf("expected update rules to apply, got %q", got)
+	}
+}
+
+func problemFields(problem *httpsuite.ProblemDetails) string {
+	if problem == nil {
+		return ""
+	}
+	details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+	fields := make([]string, len(details))
+	for i, detail := range details {
+		fields[i] = detail.Field
+	}
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,113 @@
+package playground
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/pt_BR"
+	ut "github.com/go-playground/universal-translator"
+	playgroundvalidator "github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	estranslations "github.com/go-playground/validator/v10/translations/es"
+	frtranslations "github.com/go-playground/validator/v10/translations/fr"
+	ptbrtranslations "github.com/go-playground/validator/v10/translations/pt_BR"
+	"github.com/rluders/httpsuite/v3"
+)
+
+// TranslationRegistrar registers validation message translations for a locale.
+type TranslationRegistrar func(validate *playgroundvalidator.Validate, translator ut.Translator) error
+
+// EnableTranslations registers the built-in locales: en, es, fr, and pt_BR,
+// including messages for the built-in API tags.
+func (v *Validator) EnableTranslations() error {
+	builtins := []struct {
+		locale   locales.Translator
+		register TranslationRegistrar
+	}{
+		{en.New(), entranslations.RegisterDefaultTranslations},
+		{es.New(), estranslations.RegisterDefaultTranslations},
+		{fr.New(), frtranslations.RegisterDefaultTranslations},
+		{pt_BR.New(), ptbrtranslations.RegisterDefaultTranslations},
+	}
+	for _, builtin := range builtins {
+		register := withBuiltinTranslations(builtin.locale.Locale(), builtin.register)
+		if err := v.RegisterLocale(builtin.locale, register); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterLocale adds or replaces a locale used by ValidateLocalized.
+func (v *Validator) RegisterLocale(locale locales.Translator, register TranslationRegistrar) error {
+	v.translationsMu.Lock()
+	defer v.translationsMu.Unlock()
+
+	if v.universal == nil {
+		v.universal = ut.New(locale)
+	}
+	if err := v.universal.AddTranslator(locale, true); err != nil {
+		return err
+	}
+	translator, _ := v.universal.GetTranslator(locale.Locale())
+	if register == nil {
+		return nil
+	}
+	return v.register(func(validate *playgroundvalidator.Validate) error {
+		return register(validate, translator)
+	})
+}
+
+// ValidateLocalized validates the request and localizes messages for the best
+// registered match of acceptLanguage. Unmatched locales use message templates.
+func (v *Validator) ValidateLocalized(ctx context.Context, request any, acceptLanguage string) *httpsuite.ProblemDetails {
+	err := v.validate.StructCtx(ctx, request)
+	if err == nil {
+		return nil
+	}
+	return v.problemDetailsFor(ctx, err, v.translator(acceptLanguage))
+}
+
+func (v *Validator) translator(acceptLanguage string) ut.Translator {
+	v.translationsMu.RLock()
+	defer v.translationsMu.RUnlock()
+	if v.universal == nil {
+		return nil
+	}
+
+	translator, found := v.universal.FindTranslator(acceptedLocales(acceptLanguage)...)
+	if !found {
+		return nil
+	}
+	return translator
+}
+
+func translate(fieldErr playgroundvalidator.FieldError, translator ut.Translator) (string, bool) {
+	if translator == nil {
+		return "", false
+	}
+	message := fieldErr.Translate(translator)
+	if message == fieldErr.Error() {
+		return "", false
+	}
+	return message, true
+}
+
+// acceptedLocales returns locale candidates from an Accept-Language header in
+// preference order, normalized to the "pt_BR" form and followed by their base language.
+func acceptedLocales(header string) []string {
+	tags := httpsuite.AcceptedLanguages(header)
+	result := make([]string, 0, len(tags)*2)
+	for _, tag := range tags {
+		tag = strings.ReplaceAll(tag, "-", "_")
+		result = append(result, tag)
+		if base, _, ok := strings.Cut(tag, "_"); ok {
+			result = append(result, base)
+		}
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package playground
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rluders/httpsuite/v3"
+)
+
+func TestValidateLocalized(t *testing.T) {
+	t.Parallel()
+
+	validator := New()
+	if err := validator.EnableTranslations(); err != nil {
+		t.Fatalf("enable translations: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "portuguese", acceptLanguage: "pt-BR,pt;q=0.9,en;q=0.8", want: "name é um campo obrigatório"},
+		{name: "spanish by quality", acceptLanguage: "en;q=0.5, es", want: "name es un campo requerido"},
+		{name: "unsupported locale uses templates", acceptLanguage: "ja", want: "name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := validator.ValidateLocalized(context.Background(), request{Age: 18}, tt.acceptLanguage)
+			if problem == nil {
+				t.Fatal("expected validation problem, got nil")
+			}
+			details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+			if details[0].Message != tt.want {
+				t.Fatalf("expected message %q, got %q", tt.want, details[0].Message)
+			}
+		})
+	}
+}
+
+func TestAcceptedLocales(t *testing.T) {
+	t.Parallel()
+
+	got := strings.Join(acceptedLocales("fr;q=0.2, pt-BR, *, de;q=0"), ",")
+	if got != "pt_BR,pt,fr" {
+		t.Fatalf("unexpected locale order %q", got)
+	}
+}
@@ -1,19 +1,32 @@
 package playground
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 
+	ut "github.com/go-playground/universal-translator"
 	playgroundvalidator "github.com/go-playground/validator/v10"
 	"github.com/rluders/httpsuite/v3"
 )
 
 // Validator adapts go-playground/validator to the httpsuite.Validator interface.
 type Validator struct {
-	validate *playgroundvalidator.Validate
-	problems httpsuite.ProblemConfig
+	validate   *playgroundvalidator.Validate
+	problems   *httpsuite.ProblemConfig
+	status     int
+	messagesMu sync.RWMutex
+	messages   map[string]string
+
+	translationsMu sync.RWMutex
+	universal      *ut.UniversalTranslator
+
+	scenariosMu   sync.Mutex
+	registrations []func(*playgroundvalidator.Validate) error
+	scenarios     map[string]*playgroundvalidator.Validate
 }
 
 // New returns a validator with the default go-playground configuration.
@@ -29,46 +42,77 @@ func RegisterDefault() *Validator {
 }
 
 // NewWithValidator returns a validator using a custom go-playground validator.
+// The built-in API tags (slug, rfc3339, rfc3339_range, safe_filename,
+// max_scale, before, after) are registered on validate and replace any
+// existing tags with those names.
 func NewWithValidator(validate *playgroundvalidator.Validate, problems *httpsuite.ProblemConfig) *Validator {
 	if validate == nil {
 		validate = playgroundvalidator.New()
 	}
 	registerJSONTagNames(validate)
 
-	return &Validator{
+	validator := &Validator{
 		validate: validate,
-		problems: mergeProblems(problems),
+		problems: cloneProblems(problems),
+		status:   http.StatusBadRequest,
+	}
+	validator.registerBuiltins()
+	return validator
+}
+
+// SetStatus sets the status of validation problems, e.g. 422 Unprocessable Entity.
+func (v *Validator) SetStatus(code int) *Validator {
+	if code >= 400 && code <= 499 {
+		v.status = code
 	}
+	return v
 }
 
+// fieldNameTags lists the struct tags consulted, in order, for the wire name
+// reported in validation errors.
+var fieldNameTags = []string{"json", "form", "query"}
+
 func registerJSONTagNames(validate *playgroundvalidator.Validate) {
-	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
-		name := field.Tag.Get("json")
-		if name == "" {
-			return field.Name
-		}
-		name = strings.Split(name, ",")[0]
-		if name == "-" || name == "" {
-			return field.Name
+	validate.RegisterTagNameFunc(fieldName)
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range fieldNameTags {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name != "" && name != "-" {
+			return name
 		}
-		return name
-	})
+	}
+	return field.Name
 }
 
 // Validate validates the request and converts errors into ProblemDetails.
 func (v *Validator) Validate(request any) *httpsuite.ProblemDetails {
-	if err := v.validate.Struct(request); err != nil {
-		return v.problemDetails(err)
+	return v.ValidateContext(context.Background(), request)
+}
+
+// ValidateContext validates the request with StructCtx so custom validations
+// registered with RegisterValidationCtx can use the request context.
+func (v *Validator) ValidateContext(ctx context.Context, request any) *httpsuite.ProblemDetails {
+	if err := v.validate.StructCtx(ctx, request); err != nil {
+		return v.problemDetails(ctx, err)
 	}
 	return nil
 }
 
-func (v *Validator) problemDetails(err error) *httpsuite.ProblemDetails {
+func (v *Validator) problemDetails(ctx context.Context, err error) *httpsuite.ProblemDetails {
+	return v.problemDetailsFor(ctx, err, nil)
+}
+
+// problemDetailsFor converts err into a problem typed with the config scoped
+// to ctx, so UseProblemConfig reaches validation problems too.
+func (v *Validator) problemDetailsFor(ctx context.Context, err error, translator ut.Translator) *httpsuite.ProblemDetails {
+	problems := mergeProblems(ctx, v.problems)
 	var validationErrors playgroundvalidator.ValidationErrors
 	if !errors.As(err, &validationErrors) {
 		return httpsuite.NewProblemDetails(
 			http.StatusBadRequest,
-			v.problems.TypeURL("bad_request_error"),
+			problems.TypeURL("bad_request_error"),
 			"Invalid Request",
 			"Invalid data format or structure",
 		)
@@ -76,16 +120,20 @@ func (v *Validator) problemDetails(err error) *httpsuite.ProblemDetails {
 
 	errorDetails := make([]httpsuite.ValidationErrorDetail, len(validationErrors))
 	for i, validationErr := range validationErrors {
+		message, ok := translate(validationErr, translator)
+		if !ok {
+			message = v.message(validationErr)
+		}
 		errorDetails[i] = httpsuite.ValidationErrorDetail{
-			Field:   validationErr.Field(),
-			Message: validationErr.Field() + " failed " + validationErr.Tag() + " validation",
+			Field:   fieldPath(validationErr),
+			Message: message,
 		}
 	}
 
 	return &httpsuite.ProblemDetails{
-		Type:   v.problems.TypeURL("validation_error"),
+		Type:   problems.TypeURL("validation_error"),
 		Title:  "Validation Error",
-		Status: http.StatusBadRequest,
+		Status: v.status,
 		Detail: "One or more fields failed validation.",
 		Extensions: map[string]interface{}{
 			"errors": errorDetails,
@@ -93,8 +141,29 @@ func (v *Validator) problemDetails(err error) *httpsuite.ProblemDetails {
 	}
 }
 
-func mergeProblems(problems *httpsuite.ProblemConfig) httpsuite.ProblemConfig {
-	config := httpsuite.DefaultProblemConfig()
+// fieldPath returns the JSON path of a failed field relative to the request,
+// such as "items[2].price" or "address.zip".
+func fieldPath(fieldErr playgroundvalidator.FieldError) string {
+	_, path, ok := strings.Cut(fieldErr.Namespace(), ".")
+	if !ok || path == "" {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+// cloneProblems copies the caller's overrides; they are merged with the
+// request's problem config when a problem is built, so later changes made with
+// httpsuite.SetProblemBaseURL or httpsuite.UseProblemConfig still apply.
+func cloneProblems(problems *httpsuite.ProblemConfig) *httpsuite.ProblemConfig {
+	if problems == nil {
+		return nil
+	}
+	clone := problems.Clone()
+	return &clone
+}
+
+func mergeProblems(ctx context.Context, problems *httpsuite.ProblemConfig) httpsuite.ProblemConfig {
+	config := httpsuite.ProblemConfigFromContext(ctx)
 	if problems == nil {
 		return config
 	}
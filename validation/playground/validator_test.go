@@ -1,6 +1,10 @@
 package playground
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/rluders/httpsuite/v3"
@@ -31,6 +35,30 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateUsesWireFieldNames(t *testing.T) {
+	t.Parallel()
+
+	type search struct {
+		Query string `form:"q" validate:"required"`
+		Page  int    `query:"page" validate:"min=1"`
+		Size  int    `json:"-" validate:"min=1"`
+	}
+
+	problem := New().Validate(search{})
+	if problem == nil {
+		t.Fatal("expected validation problem, got nil")
+	}
+	details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+	got := make([]string, len(details))
+	for i, detail := range details {
+		got[i] = detail.Field
+	}
+	want := []string{"q", "page", "Size"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected fields %v, got %v", want, got)
+	}
+}
+
 func TestRegisterDefault(t *testing.T) {
 	httpsuite.ClearValidator()
 	t.Cleanup(httpsuite.ClearValidator)
@@ -43,3 +71,104 @@ func TestRegisterDefault(t *testing.T) {
 		t.Fatal("expected default validator to be registered")
 	}
 }
+
+func TestValidateReportsNestedPaths(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		Price int `json:"price" validate:"min=1"`
+	}
+	type address struct {
+		Zip string `json:"zip" validate:"required"`
+	}
+	type order struct {
+		Items   []item          `json:"items" validate:"dive"`
+		Address address         `json:"address"`
+		Labels  map[string]item `json:"labels" validate:"dive"`
+	}
+
+	problem := New().Validate(&order{
+		Items:  []item{{Price: 1}, {Price: 0}},
+		Labels: map[string]item{"gift": {}},
+	})
+	details := problem.Extensions["errors"].([]httpsuite.ValidationErrorDetail)
+	got := make([]string, len(details))
+	for i, detail := range details {
+		got[i] = detail.Field
+	}
+	want := "items[1].price,address.zip,labels[gift].price"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expected paths %q, got %q", want, strings.Join(got, ","))
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	t.Parallel()
+
+	problem := New().SetStatus(http.StatusUnprocessableEntity).Validate(request{})
+	if problem == nil || problem.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 validation problem, got %#v", problem)
+	}
+	if problem := New().SetStatus(http.StatusOK).Validate(request{}); problem.Status != http.StatusBadRequest {
+		t.Fatalf("expected invalid status to be ignored, got %d", problem.Status)
+	}
+}
+
+func TestValidateFollowsProblemBaseURL(t *testing.T) {
+	validator := New()
+	httpsuite.SetProblemBaseURL("https://api.example.com")
+	t.Cleanup(func() { httpsuite.SetProblemBaseURL("") })
+
+	problem := validator.Validate(request{Age: 17})
+	if problem.Type != "https://api.example.com/errors/validation-error" {
+		t.Fatalf("expected configured base URL, got %q", problem.Type)
+	}
+}
+
+func TestParseRequestUsesScopedProblemConfig(t *testing.T) {
+	t.Parallel()
+
+	handler := httpsuite.UseProblemConfig(httpsuite.ProblemConfig{BaseURL: "https://billing.example.com"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = httpsuite.ParseRequest[*request](w, r, nil, &httpsuite.ParseOptions{Validator: New()})
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Ada","age":17}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var problem httpsuite.ProblemDetails
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Type != "https://billing.example.com/errors/validation-error" {
+		t.Fatalf("expected scoped base URL, got %q", problem.Type)
+	}
+}
+
+func TestParseRequestDivesIntoQueryParams(t *testing.T) {
+	httpsuite.ClearValidator()
+	t.Cleanup(httpsuite.ClearValidator)
+
+	type search struct {
+		IDs    []int             `json:"-" query:"id" validate:"max=3,dive,min=1"`
+		Filter map[string]string `json:"-" query:"filter" validate:"dive,keys,oneof=status region,endkeys,required"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/items?id=1,0&filter[color]=red", nil)
+	w := httptest.NewRecorder()
+	if _, err := httpsuite.ParseRequest[*search](w, req, nil, &httpsuite.ParseOptions{Validator: New()}); err == nil {
+		t.Fatal("expected validation error")
+	}
+	var problem struct {
+		Errors []httpsuite.ValidationErrorDetail `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	got := make([]string, len(problem.Errors))
+	for i, detail := range problem.Errors {
+		got[i] = detail.Field
+	}
+	if want := "id[1],filter[color]"; strings.Join(got, ",") != want {
+		t.Fatalf("expected fields %q, got %q", want, strings.Join(got, ","))
+	}
+}
@@ -0,0 +1,302 @@
+package httpsuite
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWebhookMaxAttempts    = 5
+	defaultWebhookInitialBackoff = 500 * time.Millisecond
+	defaultWebhookMaxBackoff     = 30 * time.Second
+	// webhookDrainBytes bounds how much of a receiver's reply is read and
+	// discarded to keep the connection reusable.
+	webhookDrainBytes = 64 << 10
+)
+
+// ErrWebhookDeliveryFailed is returned when a webhook could not be delivered.
+var ErrWebhookDeliveryFailed = errors.New("webhook delivery failed")
+
+// WebhookDeliveryStatus describes the lifecycle state of an outbound webhook.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookAttempt records a single delivery attempt.
+type WebhookAttempt struct {
+	Number     int           `json:"number"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	StartedAt  time.Time     `json:"started_at"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// WebhookDelivery is the delivery status resource, suitable for use as Response data.
+type WebhookDelivery struct {
+	ID       string                `json:"id"`
+	URL      string                `json:"url"`
+	Status   WebhookDeliveryStatus `json:"status"`
+	Attempts []WebhookAttempt      `json:"attempts"`
+	Problem  *ProblemDetails       `json:"problem,omitempty"`
+}
+
+// WebhookStore persists delivery state after every attempt.
+type WebhookStore interface {
+	SaveDelivery(ctx context.Context, delivery WebhookDelivery) error
+}
+
+// MemoryWebhookStore is an in-memory WebhookStore intended for tests and small deployments.
+type MemoryWebhookStore struct {
+	mu         sync.RWMutex
+	deliveries map[string]WebhookDelivery
+}
+
+// NewMemoryWebhookStore returns an empty in-memory delivery store.
+func NewMemoryWebhookStore() *MemoryWebhookStore {
+	return &MemoryWebhookStore{deliveries: make(map[string]WebhookDelivery)}
+}
+
+// SaveDelivery stores a copy of the delivery.
+func (s *MemoryWebhookStore) SaveDelivery(_ context.Context, delivery WebhookDelivery) error {
+	delivery.Attempts = append([]WebhookAttempt(nil), delivery.Attempts...)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+// Delivery returns a stored delivery by ID.
+func (s *MemoryWebhookStore) Delivery(id string) (WebhookDelivery, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	delivery, ok := s.deliveries[id]
+	return delivery, ok
+}
+
+// WebhookSenderOptions configures outbound webhook delivery.
+type WebhookSenderOptions struct {
+	Scheme         WebhookScheme
+	Secret         []byte
+	Client         *http.Client
+	Store          WebhookStore
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Problems       *ProblemConfig
+	Now            func() time.Time
+}
+
+// WebhookSender signs and delivers outbound webhooks with retries.
+type WebhookSender struct {
+	options WebhookSenderOptions
+}
+
+// NewWebhookSender returns a sender with defaults applied for missing options.
+func NewWebhookSender(opts *WebhookSenderOptions) *WebhookSender {
+	options := WebhookSenderOptions{
+		Scheme:         WebhookSchemeHMAC,
+		Client:         http.DefaultClient,
+		MaxAttempts:    defaultWebhookMaxAttempts,
+		InitialBackoff: defaultWebhookInitialBackoff,
+		MaxBackoff:     defaultWebhookMaxBackoff,
+		Now:            time.Now,
+	}
+	if opts != nil {
+		if opts.Scheme != "" {
+			options.Scheme = opts.Scheme
+		}
+		options.Secret = opts.Secret
+		if opts.Client != nil {
+			options.Client = opts.Client
+		}
+		options.Store = opts.Store
+		options.Problems = opts.Problems
+		if opts.MaxAttempts > 0 {
+			options.MaxAttempts = opts.MaxAttempts
+		}
+		if opts.InitialBackoff > 0 {
+			options.InitialBackoff = opts.InitialBackoff
+		}
+		if opts.MaxBackoff > 0 {
+			options.MaxBackoff = opts.MaxBackoff
+		}
+		if opts.Now != nil {
+			options.Now = opts.Now
+		}
+	}
+	return &WebhookSender{options: options}
+}
+
+// Send encodes payload as JSON, signs it, and delivers it to url. Network
+// errors, 429, and 5xx responses are retried with exponential backoff and
+// jitter, or after the response's Retry-After capped by MaxBackoff; other
+// non-2xx responses fail immediately. The returned delivery carries a
+// ProblemDetails when delivery ultimately fails. Without a Secret nothing
+// is sent and ErrMissingWebhookSecret is returned.
+func (s *WebhookSender) Send(ctx context.Context, url string, payload any) (*WebhookDelivery, error) {
+	if len(s.options.Secret) == 0 {
+		return nil, ErrMissingWebhookSecret
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &WebhookDelivery{
+		ID:     newRandomID(),
+		URL:    url,
+		Status: WebhookDeliveryPending,
+	}
+
+	var retryAfter time.Duration
+	for attempt := 1; attempt <= s.options.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			delay := s.backoff(attempt - 1)
+			if retryAfter > 0 {
+				delay = min(retryAfter, s.options.MaxBackoff)
+			}
+			if err := sleepContext(ctx, delay); err != nil {
+				return s.fail(ctx, delivery, err.Error())
+			}
+		}
+
+		record, retry, wait := s.attempt(ctx, url, body, attempt)
+		retryAfter = wait
+		delivery.Attempts = append(delivery.Attempts, record)
+		if record.Error == "" && !retry {
+			delivery.Status = WebhookDeliverySucceeded
+			return delivery, s.save(ctx, delivery)
+		}
+		if err := s.save(ctx, delivery); err != nil {
+			return delivery, err
+		}
+		if !retry {
+			break
+		}
+	}
+
+	last := delivery.Attempts[len(delivery.Attempts)-1]
+	return s.fail(ctx, delivery, fmt.Sprintf("delivery failed after %d attempts: %s", len(delivery.Attempts), last.Error))
+}
+
+// attempt makes one delivery and reports whether to retry, and after how
+// long the receiver asked to be retried, if it did.
+func (s *WebhookSender) attempt(ctx context.Context, url string, body []byte, number int) (WebhookAttempt, bool, time.Duration) {
+	started := s.options.Now()
+	record := WebhookAttempt{Number: number, StartedAt: started}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		record.Error = err.Error()
+		return record, false, 0
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	signWebhookRequest(req, s.options.Scheme, s.options.Secret, started, body)
+
+	resp, err := s.options.Client.Do(req)
+	record.Duration = s.options.Now().Sub(started)
+	if err != nil {
+		record.Error = err.Error()
+		return record, ctx.Err() == nil, 0
+	}
+	// Drain what the receiver sent, up to a limit, so the connection can be
+	// reused for the next delivery.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookDrainBytes))
+	_ = resp.Body.Close()
+
+	record.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return record, false, 0
+	}
+	record.Error = "unexpected status " + strconv.Itoa(resp.StatusCode)
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return record, retry, s.retryAfter(resp.Header.Get("Retry-After"))
+}
+
+// retryAfter parses a Retry-After value in seconds or as an HTTP date.
+func (s *WebhookSender) retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(0, at.Sub(s.options.Now()))
+	}
+	return 0
+}
+
+func (s *WebhookSender) fail(ctx context.Context, delivery *WebhookDelivery, detail string) (*WebhookDelivery, error) {
+	delivery.Status = WebhookDeliveryFailed
+	problems := mergeProblemConfig(s.options.Problems)
+	delivery.Problem = NewProblemDetails(
+		http.StatusBadGateway,
+		problems.TypeURL("server_error"),
+		"Webhook Delivery Failed",
+		detail,
+	)
+	if err := s.save(ctx, delivery); err != nil {
+		return delivery, err
+	}
+	return delivery, fmt.Errorf("%w: %s", ErrWebhookDeliveryFailed, detail)
+}
+
+func (s *WebhookSender) save(ctx context.Context, delivery *WebhookDelivery) error {
+	if s.options.Store == nil {
+		return nil
+	}
+	return s.options.Store.SaveDelivery(ctx, *delivery)
+}
+
+// backoff returns the exponential delay, jittered within its upper half, before the given retry.
+func (s *WebhookSender) backoff(retry int) time.Duration {
+	delay := s.options.InitialBackoff << (retry - 1)
+	if delay <= 0 || delay > s.options.MaxBackoff {
+		delay = s.options.MaxBackoff
+	}
+	half := int64(delay / 2)
+	jitter, err := rand.Int(rand.Reader, big.NewInt(half+1))
+	if err != nil {
+		return delay
+	}
+	return time.Duration(half + jitter.Int64())
+}
+
+func signWebhookRequest(req *http.Request, scheme WebhookScheme, secret []byte, now time.Time, body []byte) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	switch scheme {
+	case WebhookSchemeGitHub:
+		req.Header.Set("X-Hub-Signature-256", "sha256="+WebhookSignature(secret, "", body))
+	case WebhookSchemeStripe:
+		req.Header.Set("Stripe-Signature", "t="+timestamp+",v1="+WebhookSignature(secret, timestamp, body))
+	default:
+		req.Header.Set(defaultWebhookSignatureHeader, WebhookSignature(secret, timestamp, body))
+		req.Header.Set(defaultWebhookTimestampHeader, timestamp)
+	}
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+package httpsuite
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookSenderSend(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("whsec_test")
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if _, err := VerifyWebhookSignature(r, &WebhookOptions{Secret: secret}); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	store := NewMemoryWebhookStore()
+	sender := NewWebhookSender(&WebhookSenderOptions{
+		Secret:         secret,
+		Store:          store,
+		InitialBackoff: time.Millisecond,
+	})
+
+	delivery, err := sender.Send(context.Background(), server.URL, map[string]string{"event": "user.created"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if delivery.Status != WebhookDeliverySucceeded {
+		t.Fatalf("expected succeeded delivery, got %q", delivery.Status)
+	}
+	if len(delivery.Attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(delivery.Attempts))
+	}
+
+	stored, ok := store.Delivery(delivery.ID)
+	if !ok || stored.Status != WebhookDeliverySucceeded {
+		t.Fatalf("expected stored succeeded delivery, got %#v", stored)
+	}
+}
+
+func TestWebhookSenderPermanentFailure(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+
+	sender := NewWebhookSender(&WebhookSenderOptions{Secret: []byte("whsec_test"), InitialBackoff: time.Millisecond})
+	delivery, err := sender.Send(context.Background(), server.URL, map[string]string{})
+	if !errors.Is(err, ErrWebhookDeliveryFailed) {
+		t.Fatalf("expected delivery failure, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt for 4xx, got %d", calls.Load())
+	}
+	if delivery.Problem == nil || delivery.Problem.Status != http.StatusBadGateway {
+		t.Fatalf("expected bad gateway problem, got %#v", delivery.Problem)
+	}
+}
+
+func TestWebhookSenderRequiresSecret(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	t.Cleanup(server.Close)
+
+	for _, opts := range []*WebhookSenderOptions{nil, {Secret: []byte{}}} {
+		delivery, err := NewWebhookSender(opts).Send(context.Background(), server.URL, map[string]string{})
+		if !errors.Is(err, ErrMissingWebhookSecret) || delivery != nil {
+			t.Fatalf("expected ErrMissingWebhookSecret, got %v, %#v", err, delivery)
+		}
+	}
+	if calls.Load() != 0 {
+		t.Fatalf("expected nothing to be sent, got %d requests", calls.Load())
+	}
+}
+
+func TestWebhookSenderHonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	var first atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			first.Store(time.Now().UnixNano())
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if waited := time.Duration(time.Now().UnixNano() - first.Load()); waited < 40*time.Millisecond {
+			t.Errorf("expected the retry to wait for Retry-After, waited %v", waited)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	// Retry-After asks for a second; MaxBackoff caps the wait at 50ms, well
+	// above the exponential backoff.
+	sender := NewWebhookSender(&WebhookSenderOptions{Secret: []byte("whsec_test"), InitialBackoff: time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+	started := time.Now()
+	delivery, err := sender.Send(context.Background(), server.URL, map[string]string{})
+	if err != nil || delivery.Status != WebhookDeliverySucceeded {
+		t.Fatalf("expected delivery to succeed, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected MaxBackoff to cap Retry-After, took %v", elapsed)
+	}
+}
+
+func TestWebhookSenderReusesConnections(t *testing.T) {
+	t.Parallel()
+
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"received":true}`)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	transport := &http.Transport{}
+	t.Cleanup(transport.CloseIdleConnections)
+	sender := NewWebhookSender(&WebhookSenderOptions{Secret: []byte("whsec_test"), Client: &http.Client{Transport: transport}})
+	for range 3 {
+		if _, err := sender.Send(context.Background(), server.URL, map[string]string{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := connections.Load(); got != 1 {
+		t.Fatalf("expected deliveries to share one connection, got %d", got)
+	}
+}
@@ -0,0 +1,293 @@
+package httpsuite
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookScheme identifies how an inbound webhook carries its signature.
+type WebhookScheme string
+
+const (
+	// WebhookSchemeHMAC signs "timestamp.body" and sends the hex digest and
+	// timestamp in separate headers.
+	WebhookSchemeHMAC WebhookScheme = "hmac"
+	// WebhookSchemeGitHub signs the body and sends "sha256=<hex>" in X-Hub-Signature-256.
+	WebhookSchemeGitHub WebhookScheme = "github"
+	// WebhookSchemeStripe signs "timestamp.body" and sends "t=<ts>,v1=<hex>" in Stripe-Signature.
+	WebhookSchemeStripe WebhookScheme = "stripe"
+)
+
+const (
+	defaultWebhookSignatureHeader = "X-Webhook-Signature"
+	defaultWebhookTimestampHeader = "X-Webhook-Timestamp"
+	defaultWebhookTolerance       = 5 * time.Minute
+)
+
+// ErrMissingWebhookSecret is returned when WebhookOptions has no Secret. An
+// HMAC keyed with an empty secret can be computed by anyone, so every request
+// is rejected instead.
+var ErrMissingWebhookSecret = errors.New("webhook secret is not configured")
+
+// WebhookErrorKind identifies the webhook verification failure category.
+type WebhookErrorKind string
+
+const (
+	WebhookErrorMissingSignature WebhookErrorKind = "missing_signature"
+	WebhookErrorInvalidSignature WebhookErrorKind = "invalid_signature"
+	WebhookErrorInvalidTimestamp WebhookErrorKind = "invalid_timestamp"
+	WebhookErrorExpiredTimestamp WebhookErrorKind = "expired_timestamp"
+)
+
+// WebhookError represents a webhook signature verification error.
+type WebhookError struct {
+	Kind WebhookErrorKind
+	Err  error
+}
+
+func (e *WebhookError) Error() string {
+	switch e.Kind {
+	case WebhookErrorMissingSignature:
+		return "missing webhook signature"
+	case WebhookErrorInvalidTimestamp:
+		return "invalid webhook timestamp"
+	case WebhookErrorExpiredTimestamp:
+		return "webhook timestamp outside the allowed tolerance"
+	default:
+		if e.Err != nil {
+			return fmt.Sprintf("invalid webhook signature: %v", e.Err)
+		}
+		return "invalid webhook signature"
+	}
+}
+
+func (e *WebhookError) Unwrap() error {
+	return e.Err
+}
+
+// WebhookOptions configures inbound webhook verification.
+type WebhookOptions struct {
+	Scheme WebhookScheme
+	Secret []byte
+	// Tolerance bounds the accepted clock skew for timestamped schemes.
+	Tolerance time.Duration
+	// SignatureHeader and TimestampHeader override the WebhookSchemeHMAC headers.
+	SignatureHeader string
+	TimestampHeader string
+	MaxBodyBytes    int64
+	Problems        *ProblemConfig
+	Now             func() time.Time
+}
+
+// WebhookSignature returns the hex HMAC-SHA256 of "timestamp.body", or of the
+// body alone when timestamp is empty.
+func WebhookSignature(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	if timestamp != "" {
+		mac.Write([]byte(timestamp))
+		mac.Write([]byte("."))
+	}
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature verifies an inbound webhook signature without writing
+// HTTP responses. The raw body is returned and r.Body is restored so the payload
+// can still be decoded with ParseRequest.
+func VerifyWebhookSignature(r *http.Request, opts *WebhookOptions) ([]byte, error) {
+	options := normalizeWebhookOptions(opts)
+	if len(options.Secret) == 0 {
+		return nil, ErrMissingWebhookSecret
+	}
+
+	raw, err := readRequestBody(r, options.MaxBodyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch options.Scheme {
+	case WebhookSchemeGitHub:
+		err = verifyGitHubSignature(r, options, raw)
+	case WebhookSchemeStripe:
+		err = verifyStripeSignature(r, options, raw)
+	default:
+		err = verifyHMACSignature(r, options, raw)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// VerifyWebhook verifies an inbound webhook signature and writes a problem
+// response whenever it returns an error, so callers can just return.
+func VerifyWebhook(w http.ResponseWriter, r *http.Request, opts *WebhookOptions) ([]byte, error) {
+	raw, err := VerifyWebhookSignature(r, opts)
+	if err == nil {
+		return raw, nil
+	}
+
+	var explicit *ProblemConfig
+	if opts != nil {
+		explicit = opts.Problems
+	}
+	problems := resolveProblemConfig(r.Context(), explicit)
+	var webhookErr *WebhookError
+	var decodeErr *BodyDecodeError
+	switch {
+	case errors.As(err, &webhookErr):
+		status := http.StatusUnauthorized
+		sendRequestProblem(w, r, status, NewProblemDetails(
+			status,
+			problems.TypeURL("unauthorized_error"),
+			"Invalid Webhook Signature",
+			webhookErr.Error(),
+		))
+	case errors.As(err, &decodeErr):
+		problem, status := problemFromDecodeError(err, &problems)
+		sendRequestProblem(w, r, status, problem)
+	case errors.Is(err, ErrMissingWebhookSecret):
+		log.Printf("Rejected webhook: %v", err)
+		status := http.StatusInternalServerError
+		sendRequestProblem(w, r, status, NewProblemDetails(
+			status,
+			problems.TypeURL("server_error"),
+			"Internal Server Error",
+			"webhook verification is not configured",
+		))
+	default:
+		status := http.StatusBadRequest
+		sendRequestProblem(w, r, status, NewProblemDetails(
+			status,
+			problems.TypeURL("bad_request_error"),
+			"Invalid Request",
+			sanitizedDetail(err, "Request body could not be read"),
+		))
+	}
+	return nil, err
+}
+
+func normalizeWebhookOptions(opts *WebhookOptions) WebhookOptions {
+	normalized := WebhookOptions{
+		Scheme:          WebhookSchemeHMAC,
+		Tolerance:       defaultWebhookTolerance,
+		SignatureHeader: defaultWebhookSignatureHeader,
+		TimestampHeader: defaultWebhookTimestampHeader,
+		MaxBodyBytes:    defaultMaxBodyBytes,
+		Now:             time.Now,
+	}
+	if opts == nil {
+		return normalized
+	}
+	if opts.Scheme != "" {
+		normalized.Scheme = opts.Scheme
+	}
+	normalized.Secret = opts.Secret
+	if opts.Tolerance > 0 {
+		normalized.Tolerance = opts.Tolerance
+	}
+	if opts.SignatureHeader != "" {
+		normalized.SignatureHeader = opts.SignatureHeader
+	}
+	if opts.TimestampHeader != "" {
+		normalized.TimestampHeader = opts.TimestampHeader
+	}
+	if opts.MaxBodyBytes > 0 {
+		normalized.MaxBodyBytes = opts.MaxBodyBytes
+	}
+	if opts.Now != nil {
+		normalized.Now = opts.Now
+	}
+	return normalized
+}
+
+func verifyHMACSignature(r *http.Request, opts WebhookOptions, body []byte) error {
+	signature := strings.TrimPrefix(r.Header.Get(opts.SignatureHeader), "sha256=")
+	if signature == "" {
+		return &WebhookError{Kind: WebhookErrorMissingSignature}
+	}
+	timestamp := r.Header.Get(opts.TimestampHeader)
+	if err := checkWebhookTimestamp(timestamp, opts); err != nil {
+		return err
+	}
+	return compareWebhookSignature(WebhookSignature(opts.Secret, timestamp, body), signature)
+}
+
+func verifyGitHubSignature(r *http.Request, opts WebhookOptions, body []byte) error {
+	header := r.Header.Get("X-Hub-Signature-256")
+	if header == "" {
+		return &WebhookError{Kind: WebhookErrorMissingSignature}
+	}
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return &WebhookError{Kind: WebhookErrorInvalidSignature, Err: errors.New("unsupported signature algorithm")}
+	}
+	return compareWebhookSignature(WebhookSignature(opts.Secret, "", body), signature)
+}
+
+func verifyStripeSignature(r *http.Request, opts WebhookOptions, body []byte) error {
+	header := r.Header.Get("Stripe-Signature")
+	if header == "" {
+		return &WebhookError{Kind: WebhookErrorMissingSignature}
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if len(signatures) == 0 {
+		return &WebhookError{Kind: WebhookErrorMissingSignature}
+	}
+	if err := checkWebhookTimestamp(timestamp, opts); err != nil {
+		return err
+	}
+
+	expected := WebhookSignature(opts.Secret, timestamp, body)
+	for _, signature := range signatures {
+		if compareWebhookSignature(expected, signature) == nil {
+			return nil
+		}
+	}
+	return &WebhookError{Kind: WebhookErrorInvalidSignature}
+}
+
+func checkWebhookTimestamp(timestamp string, opts WebhookOptions) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return &WebhookError{Kind: WebhookErrorInvalidTimestamp, Err: err}
+	}
+
+	skew := opts.Now().Sub(time.Unix(seconds, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > opts.Tolerance {
+		return &WebhookError{Kind: WebhookErrorExpiredTimestamp}
+	}
+	return nil
+}
+
+func compareWebhookSignature(expected, signature string) error {
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return &WebhookError{Kind: WebhookErrorInvalidSignature}
+	}
+	return nil
+}
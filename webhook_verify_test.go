@@ -0,0 +1,167 @@
+package httpsuite
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("whsec_test")
+	body := `{"id":42,"name":"Hook"}`
+	now := time.Unix(1700000000, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name     string
+		opts     *WebhookOptions
+		headers  map[string]string
+		wantKind WebhookErrorKind
+	}{
+		{
+			name: "hmac scheme",
+			opts: &WebhookOptions{Secret: secret, Now: func() time.Time { return now }},
+			headers: map[string]string{
+				"X-Webhook-Signature": WebhookSignature(secret, timestamp, []byte(body)),
+				"X-Webhook-Timestamp": timestamp,
+			},
+		},
+		{
+			name: "github scheme",
+			opts: &WebhookOptions{Scheme: WebhookSchemeGitHub, Secret: secret},
+			headers: map[string]string{
+				"X-Hub-Signature-256": "sha256=" + WebhookSignature(secret, "", []byte(body)),
+			},
+		},
+		{
+			name: "stripe scheme with rotated secret",
+			opts: &WebhookOptions{Scheme: WebhookSchemeStripe, Secret: secret, Now: func() time.Time { return now }},
+			headers: map[string]string{
+				"Stripe-Signature": "t=" + timestamp + ",v1=deadbeef,v1=" + WebhookSignature(secret, timestamp, []byte(body)),
+			},
+		},
+		{
+			name:     "missing signature",
+			opts:     &WebhookOptions{Secret: secret},
+			wantKind: WebhookErrorMissingSignature,
+		},
+		{
+			name: "wrong secret",
+			opts: &WebhookOptions{Scheme: WebhookSchemeGitHub, Secret: secret},
+			headers: map[string]string{
+				"X-Hub-Signature-256": "sha256=" + WebhookSignature([]byte("other"), "", []byte(body)),
+			},
+			wantKind: WebhookErrorInvalidSignature,
+		},
+		{
+			name: "expired timestamp",
+			opts: &WebhookOptions{Secret: secret, Now: func() time.Time { return now.Add(time.Hour) }},
+			headers: map[string]string{
+				"X-Webhook-Signature": WebhookSignature(secret, timestamp, []byte(body)),
+				"X-Webhook-Timestamp": timestamp,
+			},
+			wantKind: WebhookErrorExpiredTimestamp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/hooks", bytes.NewBufferString(body))
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			raw, err := VerifyWebhookSignature(req, tt.opts)
+			if tt.wantKind != "" {
+				var webhookErr *WebhookError
+				if !errors.As(err, &webhookErr) || webhookErr.Kind != tt.wantKind {
+					t.Fatalf("expected webhook error %q, got %v", tt.wantKind, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(raw) != body {
+				t.Fatalf("expected raw body %q, got %q", body, raw)
+			}
+
+			decoded, err := DecodeRequestBody[*testRequest](req, defaultMaxBodyBytes)
+			if err != nil {
+				t.Fatalf("expected body to remain decodable, got %v", err)
+			}
+			if decoded.ID != 42 {
+				t.Fatalf("unexpected decoded request: %#v", decoded)
+			}
+		})
+	}
+}
+
+func TestVerifyWebhookWritesProblem(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/hooks", bytes.NewBufferString(`{}`))
+	req.Header.Set("X-Hub-Signature-256", "sha256=invalid")
+	w := httptest.NewRecorder()
+
+	_, err := VerifyWebhook(w, req, &WebhookOptions{Scheme: WebhookSchemeGitHub, Secret: []byte("secret")})
+	if err == nil {
+		t.Fatal("expected verification error, got nil")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/problem+json; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", got)
+	}
+}
+
+func TestVerifyWebhookWritesProblemOnReadFailure(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/hooks", readerFunc(func([]byte) (int, error) {
+		return 0, errors.New("connection reset")
+	}))
+	req.Header.Set("X-Hub-Signature-256", "sha256=invalid")
+	w := httptest.NewRecorder()
+
+	_, err := VerifyWebhook(w, req, &WebhookOptions{Scheme: WebhookSchemeGitHub, Secret: []byte("secret")})
+	if err == nil {
+		t.Fatal("expected read error, got nil")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/problem+json; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", got)
+	}
+}
+
+func TestVerifyWebhookRejectsEmptySecret(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{}`)
+	for _, secret := range [][]byte{nil, {}} {
+		req := httptest.NewRequest(http.MethodPost, "/hooks", bytes.NewReader(body))
+		// A signature anyone can compute with an empty key.
+		req.Header.Set("X-Hub-Signature-256", "sha256="+WebhookSignature(secret, "", body))
+		w := httptest.NewRecorder()
+
+		_, err := VerifyWebhook(w, req, &WebhookOptions{Scheme: WebhookSchemeGitHub, Secret: secret})
+		if !errors.Is(err, ErrMissingWebhookSecret) {
+			t.Fatalf("expected ErrMissingWebhookSecret, got %v", err)
+		}
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+		}
+	}
+	if _, err := VerifyWebhookSignature(httptest.NewRequest(http.MethodPost, "/hooks", nil), nil); !errors.Is(err, ErrMissingWebhookSecret) {
+		t.Fatalf("expected nil options to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket message types and close codes from RFC 6455.
+const (
+	WebSocketTextMessage   = 1
+	WebSocketBinaryMessage = 2
+
+	WebSocketCloseNormal          = 1000
+	WebSocketCloseGoingAway       = 1001
+	WebSocketClosePolicyViolation = 1008
+	WebSocketCloseInternalError   = 1011
+)
+
+const (
+	defaultWebSocketPingInterval = 30 * time.Second
+	defaultWebSocketWriteTimeout = 10 * time.Second
+)
+
+// WebSocketConn is the connection surface httpsuite manages. Adapters wrap a
+// WebSocket library so the core package stays free of dependencies; see the
+// websocket/gorilla module.
+type WebSocketConn interface {
+	ReadMessage() (messageType int, data []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	WritePing(deadline time.Time) error
+	SetPongHandler(handler func())
+	SetReadDeadline(deadline time.Time) error
+	SetWriteDeadline(deadline time.Time) error
+	// Close sends a close frame with code and reason, then closes the connection.
+	Close(code int, reason string) error
+}
+
+// WebSocketUpgrader switches an HTTP request to the WebSocket protocol.
+type WebSocketUpgrader interface {
+	Upgrade(w http.ResponseWriter, r *http.Request, responseHeader http.Header) (WebSocketConn, error)
+}
+
+// WebSocketOptions configures UpgradeWebSocket.
+type WebSocketOptions struct {
+	// Parse configures how the handshake request is bound and validated.
+	Parse *ParseOptions
+	// ResponseHeader is sent with the 101 Switching Protocols response.
+	ResponseHeader http.Header
+	// PingInterval is how often pings are sent. Defaults to 30s.
+	PingInterval time.Duration
+	// PongWait is how long to wait for any pong before the connection is
+	// considered dead. Defaults to twice PingInterval.
+	PongWait time.Duration
+	// WriteTimeout bounds each write. Defaults to 10s.
+	WriteTimeout time.Duration
+}
+
+// UpgradeWebSocket binds and validates the handshake request like
+// ParseRequest, replying with problem details before the upgrade when it is
+// not a WebSocket handshake or fails validation. On success it returns the
+// bound request and a managed connection that keeps itself alive with pings.
+func UpgradeWebSocket[T any](w http.ResponseWriter, r *http.Request, upgrader WebSocketUpgrader, paramExtractor ParamExtractor, opts *WebSocketOptions, pathParams ...string) (T, *WebSocket, error) {
+	var empty T
+	if r == nil {
+		return empty, nil, errNilHTTPRequest
+	}
+	options := normalizeWebSocketOptions(opts)
+
+	if !isWebSocketHandshake(r) {
+		var explicit *ProblemConfig
+		if options.Parse != nil {
+			explicit = options.Parse.Problems
+		}
+		problems := resolveProblemConfig(r.Context(), explicit)
+		status := http.StatusUpgradeRequired
+		w.Header().Set("Upgrade", "websocket")
+		sendRequestProblem(w, r, status, NewProblemDetails(
+			status,
+			problems.TypeURL("upgrade_required_error"),
+			"Upgrade Required",
+			errNotWebSocketHandshake.Error(),
+		))
+		return empty, nil, errNotWebSocketHandshake
+	}
+
+	request, err := ParseRequest[T](w, r, paramExtractor, options.Parse, pathParams...)
+	if err != nil {
+		return empty, nil, err
+	}
+
+	conn, err := upgrader.Upgrade(w, r, options.ResponseHeader)
+	if err != nil {
+		return empty, nil, err
+	}
+	return request, newWebSocket(context.WithoutCancel(r.Context()), conn, options), nil
+}
+
+var errNotWebSocketHandshake = errors.New("request is not a WebSocket handshake")
+
+func normalizeWebSocketOptions(opts *WebSocketOptions) WebSocketOptions {
+	options := WebSocketOptions{
+		PingInterval: defaultWebSocketPingInterval,
+		WriteTimeout: defaultWebSocketWriteTimeout,
+	}
+	if opts != nil {
+		options.Parse = opts.Parse
+		options.ResponseHeader = opts.ResponseHeader
+		if opts.PingInterval > 0 {
+			options.PingInterval = opts.PingInterval
+		}
+		if opts.PongWait > 0 {
+			options.PongWait = opts.PongWait
+		}
+		if opts.WriteTimeout > 0 {
+			options.WriteTimeout = opts.WriteTimeout
+		}
+	}
+	if options.PongWait == 0 {
+		options.PongWait = 2 * options.PingInterval
+	}
+	return options
+}
+
+func isWebSocketHandshake(r *http.Request) bool {
+	return r.Method == http.MethodGet &&
+		headerHasToken(r.Header, "Connection", "upgrade") &&
+		headerHasToken(r.Header, "Upgrade", "websocket")
+}
+
+func headerHasToken(header http.Header, key, token string) bool {
+	for _, value := range header.Values(key) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WebSocket is a managed connection. It pings the peer, drops the connection
+// when pongs stop arriving, and serializes writes so handlers may write from
+// several goroutines.
+type WebSocket struct {
+	conn         WebSocketConn
+	ctx          context.Context
+	cancel       context.CancelFunc
+	writeMu      sync.Mutex
+	closeOnce    sync.Once
+	closeErr     error
+	pongWait     time.Duration
+	writeTimeout time.Duration
+}
+
+func newWebSocket(parent context.Context, conn WebSocketConn, options WebSocketOptions) *WebSocket {
+	ctx, cancel := context.WithCancel(parent)
+	socket := &WebSocket{
+		conn:         conn,
+		ctx:          ctx,
+		cancel:       cancel,
+		pongWait:     options.PongWait,
+		writeTimeout: options.WriteTimeout,
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(socket.pongWait))
+	conn.SetPongHandler(func() {
+		_ = conn.SetReadDeadline(time.Now().Add(socket.pongWait))
+	})
+	go socket.keepAlive(options.PingInterval)
+	return socket
+}
+
+// Context is canceled once the connection closes. It keeps the values of the
+// handshake request context.
+func (s *WebSocket) Context() context.Context {
+	return s.ctx
+}
+
+// Read returns the next data message. Any error closes the connection.
+func (s *WebSocket) Read() (int, []byte, error) {
+	messageType, data, err := s.conn.ReadMessage()
+	if err != nil {
+		_ = s.Close(WebSocketCloseGoingAway, "")
+	}
+	return messageType, data, err
+}
+
+// ReadJSON decodes the next message into v.
+func (s *WebSocket) ReadJSON(v any) error {
+	_, data, err := s.Read()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Write sends a data message.
+func (s *WebSocket) Write(messageType int, data []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	if err := s.conn.SetWriteDeadline(time.Now().Add(s.writeTimeout)); err != nil {
+		return err
+	}
+	return s.conn.WriteMessage(messageType, data)
+}
+
+// WriteJSON encodes v and sends it as a text message.
+func (s *WebSocket) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Write(WebSocketTextMessage, data)
+}
+
+// Close sends a close frame and releases the connection. Later calls return
+// the result of the first one.
+func (s *WebSocket) Close(code int, reason string) error {
+	s.closeOnce.Do(func() {
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+		s.closeErr = s.conn.Close(code, reason)
+		s.cancel()
+	})
+	return s.closeErr
+}
+
+func (s *WebSocket) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.writeMu.Lock()
+			err := s.ctx.Err()
+			if err == nil {
+				err = s.conn.WritePing(time.Now().Add(s.writeTimeout))
+			}
+			s.writeMu.Unlock()
+			if err != nil {
+				_ = s.Close(WebSocketCloseGoingAway, "")
+				return
+			}
+		}
+	}
+}
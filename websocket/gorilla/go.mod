@@ -0,0 +1,10 @@
+module github.com/rluders/httpsuite/websocket/gorilla
+
+go 1.25.0
+
+require (
+	github.com/gorilla/websocket v1.5.3
+	github.com/rluders/httpsuite/v3 v3.0.0
+)
+
+replace github.com/rluders/httpsuite/v3 => ../..
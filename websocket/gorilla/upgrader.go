@@ -0,0 +1,80 @@
+// Package gorilla adapts gorilla/websocket to httpsuite.WebSocketUpgrader.
+package gorilla
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rluders/httpsuite/v3"
+)
+
+const closeWriteTimeout = time.Second
+
+// Upgrader implements httpsuite.WebSocketUpgrader with gorilla/websocket.
+type Upgrader struct {
+	upgrader *websocket.Upgrader
+}
+
+// New wraps upgrader. A nil upgrader uses gorilla's defaults, which only
+// accept same-origin handshakes.
+func New(upgrader *websocket.Upgrader) *Upgrader {
+	if upgrader == nil {
+		upgrader = &websocket.Upgrader{}
+	}
+	return &Upgrader{upgrader: upgrader}
+}
+
+// Upgrade performs the handshake and returns the connection for httpsuite to manage.
+func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request, responseHeader http.Header) (httpsuite.WebSocketConn, error) {
+	conn, err := u.upgrader.Upgrade(w, r, responseHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn: conn}, nil
+}
+
+// Conn adapts *websocket.Conn to httpsuite.WebSocketConn.
+type Conn struct {
+	conn *websocket.Conn
+}
+
+// Underlying exposes the gorilla connection for features httpsuite does not wrap.
+func (c *Conn) Underlying() *websocket.Conn {
+	return c.conn
+}
+
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	return c.conn.ReadMessage()
+}
+
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	return c.conn.WriteMessage(messageType, data)
+}
+
+func (c *Conn) WritePing(deadline time.Time) error {
+	return c.conn.WriteControl(websocket.PingMessage, nil, deadline)
+}
+
+func (c *Conn) SetPongHandler(handler func()) {
+	c.conn.SetPongHandler(func(string) error {
+		handler()
+		return nil
+	})
+}
+
+func (c *Conn) SetReadDeadline(deadline time.Time) error {
+	return c.conn.SetReadDeadline(deadline)
+}
+
+func (c *Conn) SetWriteDeadline(deadline time.Time) error {
+	return c.conn.SetWriteDeadline(deadline)
+}
+
+// Close sends a close frame, ignoring failures on an already broken
+// connection, and closes the network connection.
+func (c *Conn) Close(code int, reason string) error {
+	message := websocket.FormatCloseMessage(code, reason)
+	_ = c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(closeWriteTimeout))
+	return c.conn.Close()
+}
@@ -0,0 +1,100 @@
+package gorilla
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rluders/httpsuite/v3"
+)
+
+type roomRequest struct {
+	Room string `json:"-"`
+}
+
+func (r *roomRequest) SetParam(fieldName, value string) error {
+	if fieldName != "room" {
+		return errors.New("unknown parameter")
+	}
+	if value == "" {
+		return errors.New("room is required")
+	}
+	r.Room = value
+	return nil
+}
+
+func roomParam(r *http.Request, key string) string {
+	return strings.TrimPrefix(r.URL.Path, "/rooms/")
+}
+
+func TestUpgradeWebSocketWithGorilla(t *testing.T) {
+	t.Parallel()
+
+	upgrader := New(nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request, socket, err := httpsuite.UpgradeWebSocket[*roomRequest](w, r, upgrader, roomParam, &httpsuite.WebSocketOptions{
+			PingInterval: 10 * time.Millisecond,
+		}, "room")
+		if err != nil {
+			return
+		}
+		defer socket.Close(httpsuite.WebSocketCloseNormal, "done")
+
+		var message map[string]string
+		if err := socket.ReadJSON(&message); err != nil {
+			return
+		}
+		_ = socket.WriteJSON(map[string]string{"room": request.Room, "echo": message["text"]})
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/rooms/lobby"
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.WriteJSON(map[string]string{"text": "hi"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var reply map[string]string
+	if err := client.ReadJSON(&reply); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if reply["room"] != "lobby" || reply["echo"] != "hi" {
+		t.Fatalf("unexpected reply %#v", reply)
+	}
+
+	_, _, err = client.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseNormalClosure || closeErr.Text != "done" {
+		t.Fatalf("expected normal close, got %v", err)
+	}
+}
+
+func TestUpgradeWebSocketRejectsPlainRequests(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, _ = httpsuite.UpgradeWebSocket[*roomRequest](w, r, New(nil), roomParam, nil, "room")
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/rooms/lobby")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Fatalf("expected status %d, got %d", http.StatusUpgradeRequired, resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Type") != "application/problem+json; charset=utf-8" {
+		t.Fatalf("expected problem response, got %q", resp.Header.Get("Content-Type"))
+	}
+}
@@ -0,0 +1,185 @@
+package httpsuite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeWebSocketConn struct {
+	mu       sync.Mutex
+	pings    int
+	written  [][]byte
+	closed   int
+	incoming chan []byte
+	onPong   func()
+	pingErr  error
+}
+
+func newFakeWebSocketConn() *fakeWebSocketConn {
+	return &fakeWebSocketConn{incoming: make(chan []byte, 1)}
+}
+
+func (c *fakeWebSocketConn) ReadMessage() (int, []byte, error) {
+	data, ok := <-c.incoming
+	if !ok {
+		return 0, nil, errors.New("connection closed")
+	}
+	return WebSocketTextMessage, data, nil
+}
+
+func (c *fakeWebSocketConn) WriteMessage(_ int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.written = append(c.written, data)
+	return nil
+}
+
+func (c *fakeWebSocketConn) WritePing(time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pings++
+	return c.pingErr
+}
+
+func (c *fakeWebSocketConn) SetPongHandler(handler func())    { c.onPong = handler }
+func (c *fakeWebSocketConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *fakeWebSocketConn) SetWriteDeadline(time.Time) error { return nil }
+func (c *fakeWebSocketConn) Close(code int, reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = code
+	return nil
+}
+
+func (c *fakeWebSocketConn) snapshot() (pings, closed int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pings, c.closed
+}
+
+type fakeUpgrader struct {
+	conn     *fakeWebSocketConn
+	upgraded bool
+}
+
+func (u *fakeUpgrader) Upgrade(w http.ResponseWriter, r *http.Request, _ http.Header) (WebSocketConn, error) {
+	u.upgraded = true
+	return u.conn, nil
+}
+
+func newHandshakeRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	return req
+}
+
+func TestUpgradeWebSocketRejectsBeforeUpgrade(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		wantType   string
+	}{
+		{name: "plain request", req: httptest.NewRequest(http.MethodGet, "/rooms/1", nil), wantStatus: http.StatusUpgradeRequired, wantType: GetProblemTypeURL("upgrade_required_error")},
+		{name: "invalid path param", req: newHandshakeRequest("/rooms/abc"), wantStatus: http.StatusBadRequest, wantType: GetProblemTypeURL("bad_request_error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upgrader := &fakeUpgrader{conn: newFakeWebSocketConn()}
+			w := httptest.NewRecorder()
+
+			_, socket, err := UpgradeWebSocket[*testRequest](w, tt.req, upgrader, testParamExtractor, nil, "id")
+			if err == nil || socket != nil {
+				t.Fatalf("expected handshake to be rejected, got %v", err)
+			}
+			if upgrader.upgraded {
+				t.Fatal("expected no upgrade after rejection")
+			}
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			var problem ProblemDetails
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if problem.Type != tt.wantType {
+				t.Fatalf("expected type %q, got %q", tt.wantType, problem.Type)
+			}
+		})
+	}
+}
+
+func TestUpgradeWebSocketManagesConnection(t *testing.T) {
+	t.Parallel()
+
+	conn := newFakeWebSocketConn()
+	upgrader := &fakeUpgrader{conn: conn}
+	w := httptest.NewRecorder()
+
+	request, socket, err := UpgradeWebSocket[*testRequest](w, newHandshakeRequest("/rooms/7"), upgrader, testParamExtractor, &WebSocketOptions{
+		PingInterval: 5 * time.Millisecond,
+	}, "id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.ID != 7 {
+		t.Fatalf("expected bound ID 7, got %d", request.ID)
+	}
+
+	conn.incoming <- []byte(`{"name":"hello"}`)
+	var message testRequest
+	if err := socket.ReadJSON(&message); err != nil || message.Name != "hello" {
+		t.Fatalf("unexpected message %#v (%v)", message, err)
+	}
+	if err := socket.WriteJSON(map[string]string{"echo": message.Name}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for pings, _ := conn.snapshot(); pings < 2; pings, _ = conn.snapshot() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected periodic pings")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := socket.Close(WebSocketCloseNormal, "bye"); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, closed := conn.snapshot(); closed != WebSocketCloseNormal {
+		t.Fatalf("expected close code %d, got %d", WebSocketCloseNormal, closed)
+	}
+	if socket.Context().Err() == nil {
+		t.Fatal("expected context to be canceled after close")
+	}
+	if err := socket.Write(WebSocketTextMessage, []byte("late")); err == nil {
+		t.Fatal("expected write after close to fail")
+	}
+}
+
+func TestWebSocketClosesWhenPingFails(t *testing.T) {
+	t.Parallel()
+
+	conn := newFakeWebSocketConn()
+	conn.pingErr = errors.New("broken pipe")
+	socket := newWebSocket(context.Background(), conn, normalizeWebSocketOptions(&WebSocketOptions{PingInterval: time.Millisecond}))
+
+	select {
+	case <-socket.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected failed ping to close the connection")
+	}
+	if _, closed := conn.snapshot(); closed != WebSocketCloseGoingAway {
+		t.Fatalf("expected going-away close, got %d", closed)
+	}
+}